@@ -162,8 +162,8 @@ type ExternalSecretSpec struct {
 
 	// RefreshInterval is the amount of time before the values are read again from the SecretStore provider
 	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h"
-	// May be set to zero to fetch and create it once. Defaults to 1h.
-	// +kubebuilder:default="1h"
+	// May be set to zero to fetch and create it once. Defaults to the
+	// --default-refresh-interval of the controller, 1h.
 	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
 
 	// Data defines the connection between the Kubernetes Secret keys and the Provider data
@@ -49,6 +49,18 @@ const (
 	CreatePolicyNone ExternalSecretCreationPolicy = "None"
 )
 
+// ExternalSecretSizeLimitPolicy defines what happens if the Secret data exceeds the size limit of a Secret.
+// +kubebuilder:validation:Enum=Fail;Split
+type ExternalSecretSizeLimitPolicy string
+
+const (
+	// SizeLimitPolicyFail fails the sync and reports the largest keys.
+	SizeLimitPolicyFail ExternalSecretSizeLimitPolicy = "Fail"
+
+	// SizeLimitPolicySplit distributes the keys across the Secrets <name>-0, <name>-1, ...
+	SizeLimitPolicySplit ExternalSecretSizeLimitPolicy = "Split"
+)
+
 // ExternalSecretDeletionPolicy defines rules on how to delete the resulting Secret.
 // +kubebuilder:validation:Enum=Delete;Merge;Retain
 type ExternalSecretDeletionPolicy string
@@ -79,6 +91,11 @@ type ExternalSecretTemplateMetadata struct {
 
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// Render renders the label and annotation values as templates of the sync context,
+	// e.g. the name of the store. Without it the values are copied as they are.
+	// +optional
+	Render bool `json:"render,omitempty"`
 }
 
 // ExternalSecretTemplate defines a blueprint for the created Secret resource.
@@ -101,6 +118,49 @@ type ExternalSecretTemplate struct {
 
 	// +optional
 	TemplateFrom []TemplateFrom `json:"templateFrom,omitempty"`
+
+	// Files assembles Secret keys from an ordered list of
+	// provider values and template fragments.
+	// Files take precedence over .data and .templateFrom[].
+	// +optional
+	Files []TemplateFile `json:"files,omitempty"`
+
+	// StringData is written to the Secret as-is, like the stringData of a Secret.
+	// Values are not rendered and take precedence over all other template data.
+	// +optional
+	StringData map[string]string `json:"stringData,omitempty"`
+
+	// PruneKeys are removed from the Secret after the template has been applied.
+	// With creationPolicy=Merge this removes keys of the existing Secret.
+	// +optional
+	PruneKeys []string `json:"pruneKeys,omitempty"`
+}
+
+// TemplateFile defines a single Secret key that is assembled from several parts.
+type TemplateFile struct {
+	// Key is the Secret key the assembled file is written to.
+	Key string `json:"key"`
+
+	// Separator is inserted between the parts.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+
+	// Parts are concatenated in the given order.
+	Parts []TemplateFilePart `json:"parts"`
+}
+
+// TemplateFilePart is either a provider value or a template fragment.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type TemplateFilePart struct {
+	// SecretKey copies the value of a key fetched from the provider as-is.
+	// The value is not interpreted, hence binary values are preserved.
+	// +optional
+	SecretKey string `json:"secretKey,omitempty"`
+
+	// Template is rendered with the template engine of this template.
+	// +optional
+	Template string `json:"template,omitempty"`
 }
 
 type TemplateEngineVersion string
@@ -152,6 +212,44 @@ type ExternalSecretTarget struct {
 	// Immutable defines if the final secret will be immutable
 	// +optional
 	Immutable bool `json:"immutable,omitempty"`
+
+	// Validation defines rules the rendered Secret data must satisfy.
+	// The Secret is not written if a rule fails.
+	// +optional
+	Validation *ExternalSecretValidation `json:"validation,omitempty"`
+
+	// SizeLimitPolicy defines what happens if the Secret data exceeds the 1MiB size limit of a Secret.
+	// Fail reports the largest keys without writing the Secret.
+	// Split distributes the keys across the Secrets <name>-0, <name>-1, ...
+	// if the data does not fit into a single Secret, it requires creationPolicy Owner or Orphan.
+	// Defaults to 'Fail'
+	// +optional
+	// +kubebuilder:default="Fail"
+	SizeLimitPolicy ExternalSecretSizeLimitPolicy `json:"sizeLimitPolicy,omitempty"`
+}
+
+// ExternalSecretValidation defines rules to validate the rendered Secret data.
+type ExternalSecretValidation struct {
+	// Rules is a list of CEL expressions that must evaluate to true.
+	// The Secret data is available as map of strings in the variable data,
+	// e.g. "data['url'].startsWith('postgres://')".
+	// +optional
+	Rules []ExternalSecretValidationRule `json:"rules,omitempty"`
+
+	// JSONSchema is an OpenAPI v3 schema in JSON format the Secret data must match.
+	// The Secret data is validated as an object with string values.
+	// +optional
+	JSONSchema string `json:"jsonSchema,omitempty"`
+}
+
+// ExternalSecretValidationRule defines a CEL expression to validate the Secret data.
+type ExternalSecretValidationRule struct {
+	// Rule is the CEL expression.
+	Rule string `json:"rule"`
+
+	// Message is reported if the rule evaluates to false.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ExternalSecretData defines the connection between the Kubernetes Secret key (spec.data.<key>) and the Provider data.
@@ -159,6 +257,11 @@ type ExternalSecretData struct {
 	SecretKey string `json:"secretKey"`
 
 	RemoteRef ExternalSecretDataRemoteRef `json:"remoteRef"`
+
+	// Optional skips the entry if the remote key does not exist at the provider
+	// instead of failing the sync. Skipped entries are listed in status.missingKeys.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
 }
 
 // ExternalSecretDataRemoteRef defines Provider data location.
@@ -251,6 +354,12 @@ type ExternalSecretFind struct {
 	// +optional
 	Tags map[string]string `json:"tags,omitempty"`
 
+	// MaxResults is the maximum number of secrets the find operation may return.
+	// The sync fails with reason TooManyResults if more secrets match.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxResults *int `json:"maxResults,omitempty"`
+
 	// +optional
 	// Used to define a conversion Strategy
 	// +kubebuilder:default="Default"
@@ -277,10 +386,18 @@ type ExternalSecretSpec struct {
 
 	// RefreshInterval is the amount of time before the values are read again from the SecretStore provider
 	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h"
-	// May be set to zero to fetch and create it once. Defaults to 1h.
-	// +kubebuilder:default="1h"
+	// May be set to zero to fetch and create it once. Defaults to the
+	// --default-refresh-interval of the controller, 1h.
 	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
 
+	// RefreshJitterPercent delays every refresh by a random amount of up to the given
+	// percentage of the refresh interval, to spread the refreshes of ExternalSecrets
+	// created at the same time. Overrides the --refresh-jitter-percent flag of the controller.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	RefreshJitterPercent *int32 `json:"refreshJitterPercent,omitempty"`
+
 	// Data defines the connection between the Kubernetes Secret keys and the Provider data
 	// +optional
 	Data []ExternalSecretData `json:"data,omitempty"`
@@ -289,6 +406,22 @@ type ExternalSecretSpec struct {
 	// If multiple entries are specified, the Secret keys are merged in the specified order
 	// +optional
 	DataFrom []ExternalSecretDataFromRemoteRef `json:"dataFrom,omitempty"`
+
+	// DependsOn lists ExternalSecrets in the same namespace that must be Ready
+	// before this ExternalSecret is synced, e.g. a CA before a certificate rendered from it.
+	// +optional
+	DependsOn []ExternalSecretDependency `json:"dependsOn,omitempty"`
+
+	// Suspend pauses the reconciliation of the ExternalSecret, the target Secret
+	// is neither updated nor deleted while it is suspended.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// ExternalSecretDependency references an ExternalSecret an ExternalSecret depends on.
+type ExternalSecretDependency struct {
+	// Name of the ExternalSecret in the same namespace.
+	Name string `json:"name"`
 }
 
 type ExternalSecretConditionType string
@@ -296,6 +429,8 @@ type ExternalSecretConditionType string
 const (
 	ExternalSecretReady   ExternalSecretConditionType = "Ready"
 	ExternalSecretDeleted ExternalSecretConditionType = "Deleted"
+	// ExternalSecretSuspended is True while spec.suspend is set.
+	ExternalSecretSuspended ExternalSecretConditionType = "Suspended"
 )
 
 type ExternalSecretStatusCondition struct {
@@ -320,13 +455,25 @@ const (
 	// ConditionReasonSecretDeleted indicates that the secret has been deleted.
 	ConditionReasonSecretDeleted = "SecretDeleted"
 
-	ReasonInvalidStoreRef      = "InvalidStoreRef"
-	ReasonUnavailableStore     = "UnavailableStore"
-	ReasonProviderClientConfig = "InvalidProviderClientConfig"
-	ReasonUpdateFailed         = "UpdateFailed"
-	ReasonDeprecated           = "ParameterDeprecated"
-	ReasonUpdated              = "Updated"
-	ReasonDeleted              = "Deleted"
+	ReasonInvalidStoreRef          = "InvalidStoreRef"
+	ReasonUnavailableStore         = "UnavailableStore"
+	ReasonProviderClientConfig     = "InvalidProviderClientConfig"
+	ReasonUpdateFailed             = "UpdateFailed"
+	ReasonTemplateValidationFailed = "TemplateValidationFailed"
+	ReasonTooManyResults           = "TooManyResults"
+	ReasonDeprecated               = "ParameterDeprecated"
+	ReasonUpdated                  = "Updated"
+	ReasonDeleted                  = "Deleted"
+	ReasonRefreshIntervalClamped   = "RefreshIntervalClamped"
+	ReasonKeyNotAllowed            = "KeyNotAllowed"
+	ReasonLimitExceeded            = "LimitExceeded"
+	ReasonMissingOptionalKey       = "MissingOptionalKey"
+	ReasonDependencyNotReady       = "DependencyNotReady"
+	ReasonDependencyCycle          = "DependencyCycle"
+	ReasonSuspended                = "Suspended"
+	ReasonResumed                  = "Resumed"
+	ReasonDriftDetected            = "DriftDetected"
+	ReasonSecretTooLarge           = "SecretTooLarge"
 )
 
 type ExternalSecretStatus struct {
@@ -340,6 +487,54 @@ type ExternalSecretStatus struct {
 
 	// +optional
 	Conditions []ExternalSecretStatusCondition `json:"conditions,omitempty"`
+
+	// ErrorHistory holds the most recent sync errors, oldest first.
+	// +optional
+	ErrorHistory []ExternalSecretSyncError `json:"errorHistory,omitempty"`
+
+	// SyncedVersions holds the provider version of every spec.data entry
+	// as of the last successful sync.
+	// Entries are only present for providers that report secret versions.
+	// +optional
+	SyncedVersions []ExternalSecretSyncedVersion `json:"syncedVersions,omitempty"`
+
+	// MissingKeys holds the secretKey of every optional spec.data entry
+	// that did not exist at the provider during the last sync.
+	// +optional
+	MissingKeys []string `json:"missingKeys,omitempty"`
+}
+
+// ExternalSecretSyncError describes a failed sync of an ExternalSecret.
+type ExternalSecretSyncError struct {
+	// Time is the time the error first occurred.
+	Time metav1.Time `json:"time"`
+
+	// Reason is the reason of the failure, e.g. UpdateFailed.
+	Reason string `json:"reason"`
+
+	// MessageHash is the hash of the error message reported by the provider.
+	// Equal hashes indicate that the same error occurred again.
+	MessageHash string `json:"messageHash"`
+
+	// Count is the number of times the error was recorded in a row.
+	// Repeats within a minute of the last record are not counted.
+	// +optional
+	Count int32 `json:"count,omitempty"`
+
+	// LastTime is the time the error was last recorded.
+	// +optional
+	LastTime *metav1.Time `json:"lastTime,omitempty"`
+}
+
+// ExternalSecretSyncedVersion describes the provider version
+// a spec.data entry was synced from.
+type ExternalSecretSyncedVersion struct {
+	// SecretKey is the key of the spec.data entry.
+	SecretKey string `json:"secretKey"`
+
+	// Version is the version reported by the provider,
+	// e.g. the GCP version number, the AWS VersionId or the Vault KV v2 version.
+	Version string `json:"version"`
 }
 
 // +kubebuilder:object:root=true
@@ -362,6 +557,10 @@ type ExternalSecret struct {
 const (
 	// AnnotationDataHash is used to ensure consistency.
 	AnnotationDataHash = "reconcile.external-secrets.io/data-hash"
+	// AnnotationChunkIndex is the index of a Secret written with sizeLimitPolicy=Split.
+	AnnotationChunkIndex = "reconcile.external-secrets.io/chunk-index"
+	// AnnotationChunkCount is the number of Secrets written with sizeLimitPolicy=Split.
+	AnnotationChunkCount = "reconcile.external-secrets.io/chunk-count"
 )
 
 // +kubebuilder:object:root=true
@@ -20,14 +20,29 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-type ExternalSecretValidator struct{}
+const (
+	errStoreRefImmutable   = "spec.secretStoreRef is immutable"
+	errTargetNameImmutable = "spec.target.name is immutable"
+)
+
+type ExternalSecretValidator struct {
+	// ImmutableStoreAndTarget rejects updates that change spec.secretStoreRef or spec.target.name,
+	// e.g. to prevent tenants from reading secrets through another store.
+	ImmutableStoreAndTarget bool
+}
 
 func (esv *ExternalSecretValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
 	return validateExternalSecret(obj)
 }
 
 func (esv *ExternalSecretValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
-	return validateExternalSecret(newObj)
+	if err := validateExternalSecret(newObj); err != nil {
+		return err
+	}
+	if esv.ImmutableStoreAndTarget {
+		return validateImmutableFields(oldObj, newObj)
+	}
+	return nil
 }
 
 func (esv *ExternalSecretValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
@@ -48,5 +63,61 @@ func validateExternalSecret(obj runtime.Object) error {
 	if es.Spec.Target.DeletionPolicy == DeletionPolicyMerge && es.Spec.Target.CreationPolicy == CreatePolicyNone {
 		return fmt.Errorf("deletionPolicy=Merge must not be used with creationPolcy=None. There is no Secret to merge with")
 	}
+
+	if es.Spec.Target.SizeLimitPolicy == SizeLimitPolicySplit &&
+		(es.Spec.Target.CreationPolicy == CreatePolicyMerge || es.Spec.Target.CreationPolicy == CreatePolicyNone) {
+		return fmt.Errorf("sizeLimitPolicy=Split must be used with creationPolicy=Owner or creationPolicy=Orphan")
+	}
+
+	seen := make(map[string]bool, len(es.Spec.DependsOn))
+	for _, dep := range es.Spec.DependsOn {
+		if dep.Name == es.Name {
+			return fmt.Errorf("dependsOn must not reference the ExternalSecret itself")
+		}
+		if seen[dep.Name] {
+			return fmt.Errorf("dependsOn must not contain %q more than once", dep.Name)
+		}
+		seen[dep.Name] = true
+	}
+
+	if tpl := es.Spec.Target.Template; tpl != nil {
+		for _, key := range tpl.PruneKeys {
+			if _, ok := tpl.StringData[key]; ok {
+				return fmt.Errorf("template.pruneKeys must not contain key %q of template.stringData", key)
+			}
+		}
+	}
+	return nil
+}
+
+// validateImmutableFields rejects changes of the store and the target Secret,
+// unset fields are compared with their defaults.
+func validateImmutableFields(oldObj, newObj runtime.Object) error {
+	oldES, ok := oldObj.(*ExternalSecret)
+	if !ok {
+		return fmt.Errorf("unexpected type")
+	}
+	newES := newObj.(*ExternalSecret)
+	if storeRefOf(oldES) != storeRefOf(newES) {
+		return fmt.Errorf(errStoreRefImmutable)
+	}
+	if targetNameOf(oldES) != targetNameOf(newES) {
+		return fmt.Errorf(errTargetNameImmutable)
+	}
 	return nil
 }
+
+func storeRefOf(es *ExternalSecret) SecretStoreRef {
+	ref := es.Spec.SecretStoreRef
+	if ref.Kind == "" {
+		ref.Kind = SecretStoreKind
+	}
+	return ref
+}
+
+func targetNameOf(es *ExternalSecret) string {
+	if es.Spec.Target.Name == "" {
+		return es.Name
+	}
+	return es.Spec.Target.Name
+}
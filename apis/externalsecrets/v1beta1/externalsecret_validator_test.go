@@ -0,0 +1,92 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateUpdateImmutableFields(t *testing.T) {
+	es := func(storeKind, storeName, targetName string) *ExternalSecret {
+		return &ExternalSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+			Spec: ExternalSecretSpec{
+				SecretStoreRef: SecretStoreRef{Kind: storeKind, Name: storeName},
+				Target:         ExternalSecretTarget{Name: targetName},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		immutable bool
+		oldES     *ExternalSecret
+		newES     *ExternalSecret
+		wantErr   string
+	}{
+		{
+			name:  "changes are allowed without the policy",
+			oldES: es("", "team-a", ""),
+			newES: es(ClusterSecretStoreKind, "shared", "other"),
+		},
+		{
+			name:      "unchanged fields are allowed",
+			immutable: true,
+			oldES:     es("", "team-a", ""),
+			newES:     es("", "team-a", ""),
+		},
+		{
+			name:      "defaults are equal to unset fields",
+			immutable: true,
+			oldES:     es("", "team-a", ""),
+			newES:     es(SecretStoreKind, "team-a", "app"),
+		},
+		{
+			name:      "store name must not change",
+			immutable: true,
+			oldES:     es("", "team-a", ""),
+			newES:     es("", "team-b", ""),
+			wantErr:   errStoreRefImmutable,
+		},
+		{
+			name:      "store kind must not change",
+			immutable: true,
+			oldES:     es("", "team-a", ""),
+			newES:     es(ClusterSecretStoreKind, "team-a", ""),
+			wantErr:   errStoreRefImmutable,
+		},
+		{
+			name:      "target name must not change",
+			immutable: true,
+			oldES:     es("", "team-a", ""),
+			newES:     es("", "team-a", "other"),
+			wantErr:   errTargetNameImmutable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &ExternalSecretValidator{ImmutableStoreAndTarget: tt.immutable}
+			err := v.ValidateUpdate(context.Background(), tt.oldES, tt.newES)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ValidateUpdate() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("ValidateUpdate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -19,8 +19,13 @@ import (
 )
 
 func (r *ExternalSecret) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return r.SetupWebhookWithValidator(mgr, &ExternalSecretValidator{})
+}
+
+// SetupWebhookWithValidator registers the webhook with a configured validator.
+func (r *ExternalSecret) SetupWebhookWithValidator(mgr ctrl.Manager, validator *ExternalSecretValidator) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
-		WithValidator(&ExternalSecretValidator{}).
+		WithValidator(validator).
 		Complete()
 }
@@ -16,6 +16,8 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -51,6 +53,21 @@ type Provider interface {
 
 	// ValidateStore checks if the provided store is valid
 	ValidateStore(store GenericStore) error
+
+	// Capabilities returns what the provider supports with the given store
+	Capabilities(store GenericStore) SecretStoreCapabilities
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// ClientCacheInvalidator is implemented by Providers that reuse SecretsClients across reconciles.
+type ClientCacheInvalidator interface {
+	// InvalidateClients drops all cached clients of the given store,
+	// e.g. because a Secret holding its credentials has changed.
+	InvalidateClients(ctx context.Context, store GenericStore)
 }
 
 // +kubebuilder:object:root=false
@@ -79,6 +96,89 @@ type SecretsClient interface {
 	Close(ctx context.Context) error
 }
 
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// VersionedSecretsClient is implemented by SecretsClients that can report
+// the provider version of a secret.
+type VersionedSecretsClient interface {
+	// GetSecretWithVersion works like GetSecret and additionally returns
+	// the version of the secret the value was read from.
+	// An empty version indicates that the provider did not report one.
+	GetSecretWithVersion(ctx context.Context, ref ExternalSecretDataRemoteRef) ([]byte, string, error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretMetadata describes the version of a secret a value was read from.
+// Fields the provider does not report are left empty.
+type SecretMetadata struct {
+	// Version is the version reported by the provider.
+	Version string
+	// CreatedTime is the time the version was created at the provider.
+	CreatedTime time.Time
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// MetadataSecretsClient is implemented by SecretsClients that can report
+// the metadata of a secret in the same round trip as its value.
+// It takes precedence over VersionedSecretsClient.
+type MetadataSecretsClient interface {
+	// GetSecretWithMetadata works like GetSecret and additionally returns
+	// the metadata of the version the value was read from.
+	GetSecretWithMetadata(ctx context.Context, ref ExternalSecretDataRemoteRef) ([]byte, SecretMetadata, error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretKeysClient is implemented by SecretsClients that can list
+// the keys of a secret without reading its values.
+type SecretKeysClient interface {
+	// GetSecretKeys returns the sorted top-level keys of the secret,
+	// or of the nested object selected by ref.Property.
+	GetSecretKeys(ctx context.Context, ref ExternalSecretDataRemoteRef) ([]string, error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretVersion describes a version of a secret returned by SecretVersionLister.
+type SecretVersion struct {
+	// Version is the version as accepted by ExternalSecretDataRemoteRef.Version.
+	Version string
+	// Labels are additional names of the version, e.g. AWS version stages.
+	Labels []string
+	// CreatedTime is the time the version was created at the provider.
+	CreatedTime time.Time
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretVersionLister is implemented by SecretsClients that can list
+// the versions of a secret. It is required to resolve version expressions
+// like latest-1 in ExternalSecretDataRemoteRef.Version.
+type SecretVersionLister interface {
+	// ListSecretVersions returns the readable versions of the secret ref.Key, newest first.
+	ListSecretVersions(ctx context.Context, ref ExternalSecretDataRemoteRef) ([]SecretVersion, error)
+}
+
 var NoSecretErr = NoSecretError{}
 
 // NoSecretError shall be returned when a GetSecret can not find the
@@ -88,3 +188,55 @@ type NoSecretError struct{}
 func (NoSecretError) Error() string {
 	return "Secret does not exist"
 }
+
+// TooManyResultsError shall be returned by GetAllSecrets when
+// more secrets than ExternalSecretFind.MaxResults match the find operation.
+type TooManyResultsError struct {
+	MaxResults int
+}
+
+func (e TooManyResultsError) Error() string {
+	return fmt.Sprintf("find matches more than %d secrets", e.MaxResults)
+}
+
+// PermissionDeniedError shall be returned if the provider denied access
+// and was able to determine the permission that is required.
+// Unlike other errors its message is shown in the Ready condition of the ExternalSecret.
+type PermissionDeniedError struct {
+	// Permission is the name of the required permission.
+	Permission string
+	// Resource is the resource the permission is required on.
+	Resource string
+	// Granted is true if the permission is granted but access was denied anyway,
+	// e.g. because of a condition attached to the grant.
+	Granted bool
+	Err     error
+}
+
+func (e PermissionDeniedError) Error() string {
+	if e.Granted {
+		return fmt.Sprintf("permission %s is granted on %s but access was denied, check the conditions of the grant and deny policies", e.Permission, e.Resource)
+	}
+	return fmt.Sprintf("permission %s is missing on %s", e.Permission, e.Resource)
+}
+
+func (e PermissionDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfterError shall be returned for errors that are expected to resolve
+// on their own shortly, e.g. while newly granted permissions propagate.
+// The ExternalSecret is retried after RetryAfter instead of the default interval
+// and the error is not counted as a failure of the store.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RetryAfterError) Unwrap() error {
+	return e.Err
+}
@@ -17,6 +17,9 @@ package v1beta1
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -88,6 +91,26 @@ func GetProvider(s GenericStore) (Provider, error) {
 	return f, nil
 }
 
+// CheckProviderRegistry returns an error if a provider
+// of the SecretStoreProvider spec has no registered implementation.
+func CheckProviderRegistry() error {
+	buildlock.RLock()
+	defer buildlock.RUnlock()
+	var missing []string
+	t := reflect.TypeOf(SecretStoreProvider{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if _, ok := builder[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("providers not registered: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // getProviderName returns the name of the configured provider
 // or an error if the provider is not configured.
 func getProviderName(storeSpec *SecretStoreProvider) (string, error) {
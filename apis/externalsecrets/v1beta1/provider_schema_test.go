@@ -58,6 +58,10 @@ func (p *PP) ValidateStore(store GenericStore) error {
 	return nil
 }
 
+func (p *PP) Capabilities(store GenericStore) SecretStoreCapabilities {
+	return SecretStoreCapabilities{Access: SecretStoreReadOnly}
+}
+
 // TestRegister tests if the Register function
 // (1) panics if it tries to register something invalid
 // (2) stores the correct provider.
@@ -182,3 +186,10 @@ func TestRegisterGCP(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, testProvider, p2)
 }
+
+func TestCheckProviderRegistry(t *testing.T) {
+	ForceRegister(&PP{}, &SecretStoreProvider{AWS: &AWSProvider{}})
+	err := CheckProviderRegistry()
+	assert.ErrorContains(t, err, "vault")
+	assert.NotContains(t, err.Error(), "aws")
+}
@@ -0,0 +1,83 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProviderConfigSpec holds the provider tuning shared by all stores referencing the ProviderConfig.
+// Fields set in the ProviderConfig replace the fields of the stores.
+type ProviderConfigSpec struct {
+	// RetrySettings replace the retrySettings of the stores.
+	// +optional
+	RetrySettings *SecretStoreRetrySettings `json:"retrySettings,omitempty"`
+
+	// CABundle is a PEM encoded CA bundle that replaces the caBundle and caProvider
+	// of the vault, webhook, kubernetes, etcd and cyberarkccp providers.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// Endpoints replace the endpoints of the providers.
+	// Endpoints of providers a store does not use are ignored.
+	// +optional
+	Endpoints *ProviderConfigEndpoints `json:"endpoints,omitempty"`
+}
+
+// ProviderConfigEndpoints are the provider endpoints a ProviderConfig can replace.
+type ProviderConfigEndpoints struct {
+	// Vault replaces the server of the Vault provider.
+	// +optional
+	Vault *string `json:"vault,omitempty"`
+
+	// IBM replaces the serviceUrl of the IBM provider.
+	// +optional
+	IBM *string `json:"ibm,omitempty"`
+
+	// Alibaba replaces the KMS endpoint of the Alibaba provider.
+	// +optional
+	Alibaba *string `json:"alibaba,omitempty"`
+
+	// Yandex replaces the apiEndpoint of the Yandex Lockbox and Certificate Manager providers.
+	// +optional
+	Yandex *string `json:"yandex,omitempty"`
+}
+
+// ProviderConfigRef references the ProviderConfig of a store.
+type ProviderConfigRef struct {
+	// Name of the ProviderConfig.
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProviderConfig holds provider tuning shared by many SecretStores and ClusterSecretStores.
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={externalsecrets},shortName=pc
+type ProviderConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProviderConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProviderConfigList contains a list of ProviderConfig resources.
+type ProviderConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProviderConfig `json:"items"`
+}
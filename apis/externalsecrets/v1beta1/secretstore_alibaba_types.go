@@ -21,6 +21,19 @@ import (
 // AlibabaAuth contains a secretRef for credentials.
 type AlibabaAuth struct {
 	SecretRef AlibabaAuthSecretRef `json:"secretRef"`
+
+	// RAMRole is assumed with the access key through STS.
+	// +optional
+	RAMRole *AlibabaRAMRole `json:"ramRole,omitempty"`
+}
+
+// AlibabaRAMRole configures the RAM role to assume.
+type AlibabaRAMRole struct {
+	// RoleARN is the ARN of the RAM role, e.g. acs:ram::123456789012:role/external-secrets.
+	RoleARN string `json:"roleARN"`
+	// RoleSessionName is the name of the STS session, defaults to external-secrets.
+	// +optional
+	RoleSessionName string `json:"roleSessionName,omitempty"`
 }
 
 // AlibabaAuthSecretRef holds secret references for Alibaba credentials.
@@ -34,8 +47,17 @@ type AlibabaAuthSecretRef struct {
 // AlibabaProvider configures a store to sync secrets using the Alibaba Secret Manager provider.
 type AlibabaProvider struct {
 	Auth *AlibabaAuth `json:"auth"`
+	// Endpoint is the KMS endpoint of the region, e.g. the VPC endpoint
+	// kms-vpc.cn-hangzhou.aliyuncs.com. Defaults to the public endpoint of the region.
 	// +optional
 	Endpoint string `json:"endpoint"`
+	// STSEndpoint is the STS endpoint used to assume the RAM role, e.g. the VPC endpoint
+	// sts-vpc.cn-hangzhou.aliyuncs.com. Defaults to sts.aliyuncs.com.
+	// +optional
+	STSEndpoint string `json:"stsEndpoint,omitempty"`
 	// Alibaba Region to be used for the provider
 	RegionID string `json:"regionID"`
+	// ResourceGroupID restricts dataFrom.find to the secrets of the resource group.
+	// +optional
+	ResourceGroupID string `json:"resourceGroupID,omitempty"`
 }
@@ -19,13 +19,18 @@ import (
 )
 
 // AWSAuth tells the controller how to do authentication with aws.
-// Only one of secretRef or jwt can be specified.
+// Only one of secretRef, jwt or exec can be specified.
 // if none is specified the controller will load credentials using the aws sdk defaults.
 type AWSAuth struct {
 	// +optional
 	SecretRef *AWSAuthSecretRef `json:"secretRef,omitempty"`
 	// +optional
 	JWTAuth *AWSJWTAuth `json:"jwt,omitempty"`
+	// Exec runs a binary that returns the credentials.
+	// It must return the keys accessKeyID and secretAccessKey and may return sessionToken.
+	// Can only be used with ClusterSecretStores.
+	// +optional
+	Exec *ExecCredentialSource `json:"exec,omitempty"`
 }
 
 // AWSAuthSecretRef holds secret references for AWS credentials
@@ -41,6 +46,18 @@ type AWSAuthSecretRef struct {
 // Authenticate against AWS using service account tokens.
 type AWSJWTAuth struct {
 	ServiceAccountRef *esmeta.ServiceAccountSelector `json:"serviceAccountRef,omitempty"`
+
+	// Audience is the audience of the service account token, it must match the
+	// audience of the IAM OIDC identity provider.
+	// Defaults to the eks.amazonaws.com/audience annotation of the service account or sts.amazonaws.com.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// ExpirationSeconds is the requested validity of the service account token.
+	// Defaults to the TokenRequest default of one hour.
+	// +kubebuilder:validation:Minimum=600
+	// +optional
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
 }
 
 // AWSServiceType is a enum that defines the service/API that is used to fetch the secrets.
@@ -56,6 +73,18 @@ const (
 	AWSServiceParameterStore AWSServiceType = "ParameterStore"
 )
 
+// AWSRetryMode is a enum that defines how failed requests to AWS are retried.
+// +kubebuilder:validation:Enum=Standard;Adaptive
+type AWSRetryMode string
+
+const (
+	// AWSRetryModeStandard retries failed requests with exponential backoff.
+	AWSRetryModeStandard AWSRetryMode = "Standard"
+	// AWSRetryModeAdaptive retries like Standard and additionally rate limits
+	// requests on the client side once AWS starts throttling them.
+	AWSRetryModeAdaptive AWSRetryMode = "Adaptive"
+)
+
 // AWSProvider configures a store to sync secrets with AWS.
 type AWSProvider struct {
 	// Service defines which service should be used to fetch the secrets
@@ -73,4 +102,9 @@ type AWSProvider struct {
 
 	// AWS Region to be used for the provider
 	Region string `json:"region"`
+
+	// RetryMode defines how failed requests are retried. Defaults to Standard.
+	// The maximum number of retries is configured with spec.retrySettings.maxRetries.
+	// +optional
+	RetryMode AWSRetryMode `json:"retryMode,omitempty"`
 }
@@ -84,6 +84,12 @@ type AzureKVProvider struct {
 	// If multiple Managed Identity is assigned to the pod, you can select the one to be used
 	// +optional
 	IdentityID *string `json:"identityId,omitempty"`
+
+	// MaxConcurrentRequests limits the number of concurrent requests to Key Vault
+	// made through this store. Unlimited if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentRequests *int `json:"maxConcurrentRequests,omitempty"`
 }
 
 // Configuration used to authenticate with Azure.
@@ -95,4 +101,14 @@ type AzureKVAuth struct {
 	// The Azure ClientSecret of the service principle used for authentication.
 	// +optional
 	ClientSecret *smmeta.SecretKeySelector `json:"clientSecret,omitempty"`
+
+	// The Azure ClientCertificate of the service principle used for authentication,
+	// a PKCS#12 (PFX) archive holding the certificate and its RSA private key.
+	// Only one of clientSecret and clientCertificate may be set.
+	// +optional
+	ClientCertificate *smmeta.SecretKeySelector `json:"clientCertificate,omitempty"`
+
+	// The password of the ClientCertificate archive, if it is encrypted.
+	// +optional
+	ClientCertificatePassword *smmeta.SecretKeySelector `json:"clientCertificatePassword,omitempty"`
 }
@@ -0,0 +1,58 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// CyberArkCCPProvider configures a store to sync secrets
+// using the GetPassword REST API of the CyberArk Central Credential Provider.
+type CyberArkCCPProvider struct {
+	// URL of the CCP web service, e.g. https://ccp.example.com.
+	URL string `json:"url"`
+
+	// Path of the GetPassword API on the web service.
+	// +kubebuilder:default=/AIMWebService/api/Accounts
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// AppID of the application that is authorized to retrieve the passwords.
+	AppID string `json:"appID"`
+
+	// Safe the passwords are retrieved from, if the remoteRef key is an object name.
+	// +optional
+	Safe string `json:"safe,omitempty"`
+
+	// Folder the passwords are retrieved from, if the remoteRef key is an object name.
+	// +optional
+	Folder string `json:"folder,omitempty"`
+
+	// CABundle is a PEM encoded CA certificate used to validate
+	// the certificate of the web service.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// CAProvider points to a Secret or ConfigMap that contains the CA certificate
+	// used to validate the certificate of the web service.
+	// +optional
+	CAProvider *CAProvider `json:"caProvider,omitempty"`
+
+	// Auth configures how the operator authenticates with CCP.
+	Auth CyberArkCCPAuth `json:"auth"`
+}
+
+// CyberArkCCPAuth configures the authentication with CCP.
+type CyberArkCCPAuth struct {
+	// Cert authenticates with a TLS client certificate
+	// that is allowed for the AppID.
+	Cert *CertAuth `json:"cert"`
+}
@@ -0,0 +1,45 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// EtcdProvider configures a store to sync secrets from an etcd v3 cluster.
+type EtcdProvider struct {
+	// Endpoints of the etcd cluster, e.g. https://etcd-0.etcd:2379.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+
+	// Prefix is prepended to every key that is read from etcd.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// CABundle is a PEM encoded CA certificate used to validate
+	// the certificate of the etcd server.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// CAProvider points to a Secret or ConfigMap that contains the CA certificate
+	// used to validate the certificate of the etcd server.
+	// +optional
+	CAProvider *CAProvider `json:"caProvider,omitempty"`
+
+	// Auth configures how the operator authenticates with etcd.
+	Auth EtcdAuth `json:"auth"`
+}
+
+// EtcdAuth configures the authentication with etcd.
+type EtcdAuth struct {
+	// Cert authenticates with a TLS client certificate.
+	Cert *CertAuth `json:"cert"`
+}
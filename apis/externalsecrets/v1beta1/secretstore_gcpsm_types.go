@@ -23,6 +23,18 @@ type GCPSMAuth struct {
 	SecretRef *GCPSMAuthSecretRef `json:"secretRef,omitempty"`
 	// +optional
 	WorkloadIdentity *GCPWorkloadIdentity `json:"workloadIdentity,omitempty"`
+	// CredentialConfigSecretRef references a credential configuration file,
+	// e.g. an external account configuration for workload identity federation.
+	// The token source is built from the configuration, no metadata server is required.
+	// Can only be used with ClusterSecretStores.
+	// +optional
+	CredentialConfigSecretRef *esmeta.SecretKeySelector `json:"credentialConfigSecretRef,omitempty"`
+	// CredentialsFile is the path of an Application Default Credentials file
+	// mounted into the controller. It overrides the GOOGLE_APPLICATION_CREDENTIALS
+	// environment variable and can only be used with ClusterSecretStores.
+	// It must be located in the directory set with --credential-file-dir.
+	// +optional
+	CredentialsFile string `json:"credentialsFile,omitempty"`
 }
 
 type GCPSMAuthSecretRef struct {
@@ -46,4 +58,16 @@ type GCPSMProvider struct {
 
 	// ProjectID project where secret is located
 	ProjectID string `json:"projectID,omitempty"`
+
+	// ListPageSize sets the number of secrets requested per page when listing secrets
+	// for dataFrom.find. Defaults to the Secret Manager API default when unset.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=25000
+	ListPageSize int32 `json:"listPageSize,omitempty"`
+
+	// AllowedProjects lists the projects other than projectID that remoteRef keys
+	// may reference as projects/<project>/secrets/<secret>, by project ID or number.
+	// +optional
+	AllowedProjects []string `json:"allowedProjects,omitempty"`
 }
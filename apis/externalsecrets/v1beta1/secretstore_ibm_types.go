@@ -26,6 +26,18 @@ type IBMProvider struct {
 
 	// ServiceURL is the Endpoint URL that is specific to the Secrets Manager service instance
 	ServiceURL *string `json:"serviceUrl,omitempty"`
+
+	// PrivateEndpoint connects through the IBM Cloud private network, e.g. from clusters
+	// without public egress. The public serviceUrl of the instance is rewritten to its private
+	// endpoint and IAM tokens are requested from the private IAM endpoint.
+	// URLs of virtual private endpoints are used as they are.
+	// +optional
+	PrivateEndpoint bool `json:"privateEndpoint,omitempty"`
+
+	// IAMEndpoint is the URL of the IAM token service used by all auth methods.
+	// Defaults to https://iam.cloud.ibm.com, or https://private.iam.cloud.ibm.com with privateEndpoint.
+	// +optional
+	IAMEndpoint string `json:"iamEndpoint,omitempty"`
 }
 
 // +kubebuilder:validation:MinProperties=1
@@ -48,5 +60,6 @@ type IBMAuthContainerAuth struct {
 	// Location the token is mounted on the pod
 	TokenLocation string `json:"tokenLocation,omitempty"`
 
+	// IAMEndpoint overrides the IAM endpoint of the provider for container auth.
 	IAMEndpoint string `json:"iamEndpoint,omitempty"`
 }
@@ -46,8 +46,25 @@ type KubernetesProvider struct {
 	// +kubebuilder:default= default
 	// +optional
 	RemoteNamespace string `json:"remoteNamespace"`
+
+	// SourceType is the kind of the objects to fetch from the remote namespace,
+	// either Secret or ConfigMap. ConfigMaps allow to mirror non-sensitive configuration.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +kubebuilder:default=Secret
+	// +optional
+	SourceType KubernetesSourceType `json:"sourceType,omitempty"`
 }
 
+// KubernetesSourceType is the kind of the objects a Kubernetes store fetches.
+type KubernetesSourceType string
+
+const (
+	// KubernetesSourceSecret fetches Secrets.
+	KubernetesSourceSecret KubernetesSourceType = "Secret"
+	// KubernetesSourceConfigMap fetches ConfigMaps.
+	KubernetesSourceConfigMap KubernetesSourceType = "ConfigMap"
+)
+
 // +kubebuilder:validation:MinProperties=1
 // +kubebuilder:validation:MaxProperties=1
 type KubernetesAuth struct {
@@ -25,6 +25,11 @@ type OracleProvider struct {
 	// Vault is the vault's OCID of the specific vault where secret is located.
 	Vault string `json:"vault"`
 
+	// Compartment is the compartment OCID of the vault, it is required
+	// to find secrets with dataFrom.find.
+	// +optional
+	Compartment string `json:"compartment,omitempty"`
+
 	// Auth configures how secret-manager authenticates with the Oracle Vault.
 	// If empty, use the instance principal, otherwise the user credentials specified in Auth.
 	// +optional
@@ -0,0 +1,47 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// PassboltProvider configures a store to sync secrets
+// from the resources of a Passbolt server.
+type PassboltProvider struct {
+	// Host of the Passbolt server, e.g. https://passbolt.example.com.
+	Host string `json:"host"`
+
+	// Folder the resources are looked up in by name, e.g. "Team/Databases".
+	// Resources are looked up in all folders shared with the user if not set.
+	// +optional
+	Folder string `json:"folder,omitempty"`
+
+	// Auth configures how the operator authenticates with Passbolt.
+	Auth PassboltAuth `json:"auth"`
+}
+
+// PassboltAuth configures the GPG based authentication with Passbolt.
+type PassboltAuth struct {
+	// UserID is the ID of the Passbolt user.
+	UserID string `json:"userID"`
+
+	// PrivateKeySecretRef references the armored private GPG key of the user.
+	PrivateKeySecretRef esmeta.SecretKeySelector `json:"privateKeySecretRef"`
+
+	// PassphraseSecretRef references the passphrase of the private key.
+	// +optional
+	PassphraseSecretRef *esmeta.SecretKeySelector `json:"passphraseSecretRef,omitempty"`
+}
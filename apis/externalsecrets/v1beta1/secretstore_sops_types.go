@@ -0,0 +1,82 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// SOPSProvider configures a store to sync secrets from SOPS encrypted documents.
+// The documents are decrypted with the KMS or age keys available to the controller.
+type SOPSProvider struct {
+	// Source configures where the encrypted documents are fetched from.
+	Source SOPSSource `json:"source"`
+
+	// Format of the encrypted documents.
+	// If empty, the format is detected from the file extension of the key.
+	// +kubebuilder:validation:Enum=json;yaml
+	// +optional
+	Format SOPSFormat `json:"format,omitempty"`
+}
+
+type SOPSFormat string
+
+const (
+	SOPSFormatJSON SOPSFormat = "json"
+	SOPSFormatYAML SOPSFormat = "yaml"
+)
+
+// SOPSSource configures where the encrypted documents are fetched from.
+// The remoteRef key is appended to the URL or prefix of the source.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type SOPSSource struct {
+	// HTTP fetches the documents from an HTTP(S) URL,
+	// e.g. the raw file endpoint of a Git server.
+	// +optional
+	HTTP *SOPSHTTPSource `json:"http,omitempty"`
+
+	// S3 fetches the documents from an AWS S3 bucket
+	// using the AWS credentials of the controller.
+	// +optional
+	S3 *SOPSBucketSource `json:"s3,omitempty"`
+
+	// GCS fetches the documents from a Google Cloud Storage bucket
+	// using the Google application default credentials of the controller.
+	// +optional
+	GCS *SOPSBucketSource `json:"gcs,omitempty"`
+}
+
+type SOPSHTTPSource struct {
+	// URL the remoteRef key is appended to.
+	URL string `json:"url"`
+
+	// BearerToken is sent in the Authorization header.
+	// +optional
+	BearerToken *esmeta.SecretKeySelector `json:"bearerToken,omitempty"`
+}
+
+type SOPSBucketSource struct {
+	// Bucket name.
+	Bucket string `json:"bucket"`
+
+	// Prefix the remoteRef key is appended to.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Region of the bucket, only used for S3.
+	// +optional
+	Region string `json:"region,omitempty"`
+}
@@ -16,6 +16,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,8 +27,20 @@ type SecretStoreSpec struct {
 	// +optional
 	Controller string `json:"controller"`
 
-	// Used to configure the provider. Only one provider may be set
-	Provider *SecretStoreProvider `json:"provider"`
+	// Used to configure the provider. Only one provider may be set.
+	// Required unless the store inherits the provider with InheritFrom.
+	// +optional
+	Provider *SecretStoreProvider `json:"provider,omitempty"`
+
+	// InheritFrom uses the provider configuration of a ClusterSecretStore as base of a SecretStore.
+	// Mutually exclusive with Provider. Not supported by ClusterSecretStores.
+	// +optional
+	InheritFrom *SecretStoreInheritance `json:"inheritFrom,omitempty"`
+
+	// ProviderConfigRef references a ProviderConfig holding provider tuning shared by many stores,
+	// e.g. retry settings, CA bundles and endpoints. Fields set in the ProviderConfig replace those of the store.
+	// +optional
+	ProviderConfigRef *ProviderConfigRef `json:"providerConfigRef,omitempty"`
 
 	// Used to configure http retries if failed
 	// +optional
@@ -36,6 +49,43 @@ type SecretStoreSpec struct {
 	// Used to configure store refresh interval in seconds. Empty or 0 will default to the controller config.
 	// +optional
 	RefreshInterval int `json:"refreshInterval"`
+
+	// CredentialsRefreshInterval is the maximum time provider clients and their tokens
+	// are cached, e.g. 1h. Cached clients are built again with fresh credentials afterwards.
+	// Empty reuses cached clients until the store or its credentials change.
+	// +optional
+	CredentialsRefreshInterval *metav1.Duration `json:"credentialsRefreshInterval,omitempty"`
+
+	// AllowedKeyPrefixes restricts the remote keys ExternalSecrets may read with this store,
+	// e.g. teams/team-a/. Every remoteRef key and find path must start with one of the prefixes.
+	// A SecretStore inheriting from a ClusterSecretStore may only narrow its prefixes.
+	// Empty allows all keys.
+	// +optional
+	AllowedKeyPrefixes []string `json:"allowedKeyPrefixes,omitempty"`
+
+	// Limits protects the cluster from oversized secrets synced with this store.
+	// A SecretStore inheriting from a ClusterSecretStore uses its limits unless it sets its own.
+	// +optional
+	Limits *SecretStoreLimits `json:"limits,omitempty"`
+}
+
+// SecretStoreLimits restricts the data an ExternalSecret may sync with a store.
+// ExternalSecrets exceeding a limit are not synced and their Ready condition
+// has the reason LimitExceeded.
+type SecretStoreLimits struct {
+	// MaxSecretSize is the maximum size of a single value fetched from the provider, e.g. 64Ki.
+	// +optional
+	MaxSecretSize *resource.Quantity `json:"maxSecretSize,omitempty"`
+
+	// MaxKeys is the maximum number of keys an ExternalSecret may fetch from the provider.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxKeys *int `json:"maxKeys,omitempty"`
+
+	// MaxTotalSize is the maximum size of all values an ExternalSecret fetches
+	// from the provider, e.g. 512Ki.
+	// +optional
+	MaxTotalSize *resource.Quantity `json:"maxTotalSize,omitempty"`
 }
 
 // SecretStoreProvider contains the provider-specific configuration.
@@ -109,6 +159,23 @@ type SecretStoreProvider struct {
 	// Doppler configures this store to sync secrets using the Doppler provider
 	// +optional
 	Doppler *DopplerProvider `json:"doppler,omitempty"`
+
+	// Etcd configures this store to sync secrets from an etcd v3 cluster
+	// +optional
+	Etcd *EtcdProvider `json:"etcd,omitempty"`
+
+	// SOPS configures this store to sync secrets from SOPS encrypted documents.
+	// Can only be used with ClusterSecretStores.
+	// +optional
+	SOPS *SOPSProvider `json:"sops,omitempty"`
+
+	// CyberArkCCP configures this store to sync secrets using the CyberArk Central Credential Provider
+	// +optional
+	CyberArkCCP *CyberArkCCPProvider `json:"cyberarkccp,omitempty"`
+
+	// Passbolt configures this store to sync secrets from a Passbolt server
+	// +optional
+	Passbolt *PassboltProvider `json:"passbolt,omitempty"`
 }
 
 type CAProviderType string
@@ -139,6 +206,66 @@ type CAProvider struct {
 	Namespace *string `json:"namespace,omitempty"`
 }
 
+// ExecCredentialSource runs a binary that returns short-lived provider credentials.
+// The binary must be installed by the cluster administrator in the directory
+// configured with --exec-credential-plugin-dir, it is looked up by name only.
+// It must print a JSON object to stdout:
+// {"credentials": {"<key>": "<value>", ...}, "expirationTimestamp": "<RFC3339>"}.
+type ExecCredentialSource struct {
+	// Command is the name of the binary in the plugin directory.
+	// It must not contain a path.
+	Command string `json:"command"`
+
+	// Args are passed to the binary.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env is the environment of the binary.
+	// The environment of the controller is not passed on. Variables that change
+	// how binaries and libraries are loaded, e.g. LD_PRELOAD or PATH, are rejected.
+	// +optional
+	Env []ExecEnvVar `json:"env,omitempty"`
+
+	// Timeout is the maximum time the binary may run. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ExecEnvVar is an environment variable of an exec credential binary.
+type ExecEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SecretStoreInheritance references the ClusterSecretStore a SecretStore inherits from.
+// The provider is taken from the ClusterSecretStore, retrySettings and refreshInterval
+// only if they are not set in the SecretStore. Credentials of the inherited provider
+// are resolved in the namespace of the SecretStore.
+type SecretStoreInheritance struct {
+	// ClusterSecretStoreName is the name of the ClusterSecretStore to inherit from.
+	ClusterSecretStoreName string `json:"clusterSecretStoreName"`
+
+	// Overrides replace fields of the inherited provider.
+	// +optional
+	Overrides *SecretStoreOverrides `json:"overrides,omitempty"`
+}
+
+// SecretStoreOverrides are the provider fields a SecretStore can override when inheriting
+// from a ClusterSecretStore. An override that does not apply to the inherited provider is an error.
+type SecretStoreOverrides struct {
+	// Path replaces the mount path of the Vault provider.
+	// +optional
+	Path *string `json:"path,omitempty"`
+
+	// ProjectID replaces the project of the GCP Secret Manager provider.
+	// +optional
+	ProjectID *string `json:"projectID,omitempty"`
+
+	// Region replaces the region of the AWS provider.
+	// +optional
+	Region *string `json:"region,omitempty"`
+}
+
 type SecretStoreRetrySettings struct {
 	MaxRetries    *int32  `json:"maxRetries,omitempty"`
 	RetryInterval *string `json:"retryInterval,omitempty"`
@@ -153,6 +280,15 @@ const (
 	ReasonInvalidProviderConfig = "InvalidProviderConfig"
 	ReasonValidationFailed      = "ValidationFailed"
 	ReasonStoreValid            = "Valid"
+	ReasonStoreUnhealthy        = "Unhealthy"
+	ReasonStoreValidationRun    = "ValidationRun"
+)
+
+const (
+	// AnnotationValidate triggers a one-shot validation of a store.
+	// The validation runs once for every new value of the annotation,
+	// the results are reported in status.validation.
+	AnnotationValidate = "external-secrets.io/validate"
 )
 
 type SecretStoreStatusCondition struct {
@@ -169,10 +305,92 @@ type SecretStoreStatusCondition struct {
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
+// SecretStoreAccess defines whether secrets can be read from or written to a store.
+// +kubebuilder:validation:Enum=ReadOnly;WriteOnly;ReadWrite
+type SecretStoreAccess string
+
+const (
+	SecretStoreReadOnly  SecretStoreAccess = "ReadOnly"
+	SecretStoreWriteOnly SecretStoreAccess = "WriteOnly"
+	SecretStoreReadWrite SecretStoreAccess = "ReadWrite"
+)
+
+// SecretStoreFeature is an optional feature a provider supports.
+type SecretStoreFeature string
+
+const (
+	// FeatureFind indicates that secrets can be fetched with dataFrom.find.
+	FeatureFind SecretStoreFeature = "Find"
+
+	// FeaturePush indicates that secrets can be pushed to the provider.
+	FeaturePush SecretStoreFeature = "Push"
+
+	// FeatureMetadata indicates that secret metadata can be fetched with metadataPolicy=Fetch.
+	FeatureMetadata SecretStoreFeature = "Metadata"
+)
+
+// SecretStoreCapabilities describes what the provider of a store supports.
+type SecretStoreCapabilities struct {
+	Access SecretStoreAccess `json:"access"`
+
+	// +optional
+	Features []SecretStoreFeature `json:"features,omitempty"`
+
+	// ProviderVersion is the version of the provider API used by the store, if known.
+	// +optional
+	ProviderVersion string `json:"providerVersion,omitempty"`
+}
+
 // SecretStoreStatus defines the observed state of the SecretStore.
 type SecretStoreStatus struct {
 	// +optional
 	Conditions []SecretStoreStatusCondition `json:"conditions"`
+
+	// Capabilities of the store provider, set once the store has been validated.
+	// +optional
+	Capabilities *SecretStoreCapabilities `json:"capabilities,omitempty"`
+
+	// Validation holds the results of the last validation requested with the
+	// external-secrets.io/validate annotation.
+	// +optional
+	Validation *SecretStoreValidation `json:"validation,omitempty"`
+}
+
+// SecretStoreValidationResult is the result of a single validation check.
+type SecretStoreValidationResult string
+
+const (
+	ValidationCheckPassed  SecretStoreValidationResult = "Passed"
+	ValidationCheckFailed  SecretStoreValidationResult = "Failed"
+	ValidationCheckUnknown SecretStoreValidationResult = "Unknown"
+	ValidationCheckSkipped SecretStoreValidationResult = "Skipped"
+)
+
+// SecretStoreValidation describes the outcome of a requested validation.
+type SecretStoreValidation struct {
+	// Request is the value of the external-secrets.io/validate annotation the validation ran for.
+	Request string `json:"request"`
+
+	// Time is when the validation ran.
+	Time metav1.Time `json:"time"`
+
+	// Result is Passed if no check failed.
+	Result SecretStoreValidationResult `json:"result"`
+
+	// Checks holds the result of every validation step, in order.
+	// +optional
+	Checks []SecretStoreValidationCheck `json:"checks,omitempty"`
+}
+
+// SecretStoreValidationCheck is a single step of a requested validation.
+type SecretStoreValidationCheck struct {
+	// Name of the check, e.g. ValidateStore, NewClient or Validate.
+	Name string `json:"name"`
+
+	Result SecretStoreValidationResult `json:"result"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -182,6 +400,7 @@ type SecretStoreStatus struct {
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Capabilities",type=string,JSONPath=`.status.capabilities.access`
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced,categories={externalsecrets},shortName=ss
 type SecretStore struct {
@@ -208,6 +427,7 @@ type SecretStoreList struct {
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Capabilities",type=string,JSONPath=`.status.capabilities.access`
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={externalsecrets},shortName=css
 type ClusterSecretStore struct {
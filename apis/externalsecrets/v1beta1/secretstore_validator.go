@@ -25,7 +25,12 @@ import (
 var _ admission.CustomValidator = &GenericStoreValidator{}
 
 const (
-	errInvalidStore = "invalid store"
+	errInvalidStore            = "invalid store"
+	errInheritFromClusterStore = "inheritFrom is not supported by ClusterSecretStores"
+	errInheritFromProvider     = "provider and inheritFrom are mutually exclusive"
+	errInheritFromName         = "inheritFrom.clusterSecretStoreName must be set"
+	errEmptyKeyPrefix          = "allowedKeyPrefixes[%d] must not be empty"
+	errProviderConfigName      = "providerConfigRef.name must be set"
 )
 
 type GenericStoreValidator struct{}
@@ -54,9 +59,36 @@ func (r *GenericStoreValidator) ValidateDelete(ctx context.Context, obj runtime.
 }
 
 func validateStore(store GenericStore) error {
+	for i, prefix := range store.GetSpec().AllowedKeyPrefixes {
+		if prefix == "" {
+			return fmt.Errorf(errEmptyKeyPrefix, i)
+		}
+	}
+	if ref := store.GetSpec().ProviderConfigRef; ref != nil && ref.Name == "" {
+		return fmt.Errorf(errProviderConfigName)
+	}
+	// the inherited provider is validated by the controller,
+	// once the ClusterSecretStore has been resolved.
+	if store.GetSpec().InheritFrom != nil {
+		return validateInheritance(store)
+	}
 	provider, err := GetProvider(store)
 	if err != nil {
 		return err
 	}
 	return provider.ValidateStore(store)
 }
+
+func validateInheritance(store GenericStore) error {
+	if _, ok := store.(*ClusterSecretStore); ok {
+		return fmt.Errorf(errInheritFromClusterStore)
+	}
+	spec := store.GetSpec()
+	if spec.Provider != nil {
+		return fmt.Errorf(errInheritFromProvider)
+	}
+	if spec.InheritFrom.ClusterSecretStoreName == "" {
+		return fmt.Errorf(errInheritFromName)
+	}
+	return nil
+}
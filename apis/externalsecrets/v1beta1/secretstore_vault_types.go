@@ -65,6 +65,11 @@ type VaultProvider struct {
 	// +optional
 	CAProvider *CAProvider `json:"caProvider,omitempty"`
 
+	// ClientTLS is the client certificate presented to the Vault server
+	// if its listener requires mutual TLS.
+	// +optional
+	ClientTLS *VaultClientTLS `json:"clientTLS,omitempty"`
+
 	// ReadYourWrites ensures isolated read-after-write semantics by
 	// providing discovered cluster replication states in each request.
 	// More information about eventual consistency in Vault can be found here
@@ -74,7 +79,7 @@ type VaultProvider struct {
 
 	// ForwardInconsistent tells Vault to forward read-after-write requests to the Vault
 	// leader instead of simply retrying within a loop. This can increase performance if
-	// the option is enabled serverside.
+	// the option is enabled serverside. Enabling it implies ReadYourWrites.
 	// https://www.vaultproject.io/docs/configuration/replication#allow_forwarding_via_header
 	// +optional
 	ForwardInconsistent bool `json:"forwardInconsistent,omitempty"`
@@ -112,6 +117,22 @@ type VaultAuth struct {
 	// Cert authentication method
 	// +optional
 	Cert *VaultCertAuth `json:"cert,omitempty"`
+
+	// TokenFile authenticates with Vault by presenting the token in a sink
+	// file maintained by a Vault Agent running next to the controller.
+	// Only supported by ClusterSecretStores.
+	// +optional
+	TokenFile *VaultTokenFileAuth `json:"tokenFile,omitempty"`
+}
+
+// VaultTokenFileAuth reads the Vault token from a file on the controller filesystem,
+// e.g. the sink of a Vault Agent auto-auth configuration.
+// The file is read whenever a client is created, so tokens renewed or
+// re-issued by the agent are picked up.
+type VaultTokenFileAuth struct {
+	// Path is the absolute path of the token file.
+	// It must be located in the directory set with --credential-file-dir.
+	Path string `json:"path"`
 }
 
 // VaultAppRole authenticates with Vault using the App Role auth mechanism,
@@ -137,7 +158,8 @@ type VaultAppRole struct {
 // a Secret.
 type VaultKubernetesAuth struct {
 	// Path where the Kubernetes authentication backend is mounted in Vault, e.g:
-	// "kubernetes"
+	// "kubernetes". It may contain the template "{{ .namespace }}", which is
+	// replaced with the namespace of the ExternalSecret.
 	// +kubebuilder:default=kubernetes
 	Path string `json:"mountPath"`
 
@@ -157,6 +179,8 @@ type VaultKubernetesAuth struct {
 
 	// A required field containing the Vault Role to assume. A Role binds a
 	// Kubernetes ServiceAccount with a set of Vault policies.
+	// It may contain the template "{{ .namespace }}", which is replaced
+	// with the namespace of the ExternalSecret, e.g. "eso-{{ .namespace }}".
 	Role string `json:"role"`
 }
 
@@ -225,6 +249,17 @@ type VaultJwtAuth struct {
 	KubernetesServiceAccountToken *VaultKubernetesServiceAccountTokenAuth `json:"kubernetesServiceAccountToken,omitempty"`
 }
 
+// VaultClientTLS references the client certificate and private key used for mutual TLS,
+// e.g. the tls.crt and tls.key of a Secret maintained by cert-manager.
+// The Vault client is rebuilt when the referenced Secrets change.
+type VaultClientTLS struct {
+	// CertSecretRef is a reference to the PEM encoded client certificate.
+	CertSecretRef esmeta.SecretKeySelector `json:"certSecretRef"`
+
+	// KeySecretRef is a reference to the PEM encoded private key of the client certificate.
+	KeySecretRef esmeta.SecretKeySelector `json:"keySecretRef"`
+}
+
 // VaultJwtAuth authenticates with Vault using the JWT/OIDC authentication
 // method, with the role name and token stored in a Kubernetes Secret resource.
 type VaultCertAuth struct {
@@ -26,9 +26,14 @@ type WebhookProvider struct {
 	// +optional, default GET
 	Method string `json:"method,omitempty"`
 
-	// Webhook url to call
+	// Webhook url to call, with grpc the host:port of the gRPC server
 	URL string `json:"url"`
 
+	// GRPC calls a unary gRPC method instead of an HTTP endpoint.
+	// The headers are sent as gRPC metadata, the body is not used.
+	// +optional
+	GRPC *WebhookGRPC `json:"grpc,omitempty"`
+
 	// Headers
 	// +optional
 	Headers map[string]string `json:"headers,omitempty"`
@@ -59,6 +64,77 @@ type WebhookProvider struct {
 	// The provider for the CA bundle to use to validate webhook server certificate.
 	// +optional
 	CAProvider *WebhookCAProvider `json:"caProvider,omitempty"`
+
+	// Cache enables caching of webhook responses.
+	// Cached responses are reused as long as they are fresh according to
+	// their Cache-Control header and revalidated with their ETag afterwards.
+	// +optional
+	Cache *WebhookCache `json:"cache,omitempty"`
+}
+
+// WebhookCache configures the response cache of a webhook store.
+type WebhookCache struct {
+	// MaxEntries is the maximum number of responses cached per store.
+	// Defaults to 100.
+	// +optional
+	MaxEntries int `json:"maxEntries,omitempty"`
+
+	// DefaultTTL is used for responses without Cache-Control max-age.
+	// Defaults to 0, i.e. such responses are always revalidated.
+	// +optional
+	DefaultTTL *metav1.Duration `json:"defaultTTL,omitempty"`
+
+	// MaxStaleness allows to serve expired responses for up to this duration
+	// after they expired. The expired response is returned right away and
+	// refreshed in the background, so slow endpoints do not delay reconciles.
+	// Defaults to 0, i.e. expired responses are refreshed before they are returned.
+	// +optional
+	MaxStaleness *metav1.Duration `json:"maxStaleness,omitempty"`
+}
+
+// WebhookGRPC configures a unary gRPC call. Messages are encoded without
+// a protobuf descriptor, fields are addressed by their field numbers.
+type WebhookGRPC struct {
+	// Method is the full name of the method, e.g. /secrets.v1.Secrets/GetSecret.
+	Method string `json:"method"`
+
+	// Request lists the fields of the request message.
+	// +optional
+	Request []WebhookGRPCField `json:"request,omitempty"`
+
+	// ResponseFieldPath is the dot separated path of field numbers to the value
+	// in the response message, e.g. 1.2 for field 2 of the message in field 1.
+	// Defaults to the whole response message.
+	// +optional
+	ResponseFieldPath string `json:"responseFieldPath,omitempty"`
+
+	// Insecure connects without TLS.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// WebhookGRPCFieldType is the protobuf type of a request field.
+type WebhookGRPCFieldType string
+
+const (
+	WebhookGRPCFieldTypeString WebhookGRPCFieldType = "string"
+	WebhookGRPCFieldTypeInt    WebhookGRPCFieldType = "int"
+	WebhookGRPCFieldTypeBool   WebhookGRPCFieldType = "bool"
+)
+
+// WebhookGRPCField is a field of a gRPC request message.
+type WebhookGRPCField struct {
+	// Number is the field number in the request message.
+	// +kubebuilder:validation:Minimum=1
+	Number int32 `json:"number"`
+
+	// Type is the protobuf type of the field, int is encoded as int64 and bool as a varint.
+	// +kubebuilder:validation:Enum="string";"int";"bool"
+	// +optional
+	Type WebhookGRPCFieldType `json:"type,omitempty"`
+
+	// Value of the field, it is templated like the url.
+	Value string `json:"value"`
 }
 
 type WebhookCAProviderType string
@@ -0,0 +1,89 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReasonSelfTestRun is the reason of the event written once a SelfTest finished.
+const ReasonSelfTestRun = "SelfTestRun"
+
+// SelfTestSpec defines the store a SelfTest exercises.
+type SelfTestSpec struct {
+	// SecretStoreRef is the SecretStore or ClusterSecretStore to test.
+	SecretStoreRef SecretStoreRef `json:"secretStoreRef"`
+
+	// RemoteRef is an existing secret that is read from the store.
+	// The Read check is skipped if it is not set.
+	// +optional
+	RemoteRef *SelfTestRemoteRef `json:"remoteRef,omitempty"`
+}
+
+// SelfTestRemoteRef references a secret in the store.
+type SelfTestRemoteRef struct {
+	// Key is the key of the secret in the store.
+	Key string `json:"key"`
+
+	// Version of the secret, defaults to the latest version.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Property of the secret to read.
+	// +optional
+	Property string `json:"property,omitempty"`
+}
+
+// SelfTestStatus holds the results of a SelfTest.
+type SelfTestStatus struct {
+	// CompletionTime is when the test finished.
+	// A SelfTest runs once, recreate it to run it again.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Result is Passed if no check failed.
+	// +optional
+	Result SecretStoreValidationResult `json:"result,omitempty"`
+
+	// Checks holds the result of every test step, in order:
+	// ValidateStore, NewClient, Validate, Write, Read and Delete.
+	// +optional
+	Checks []SecretStoreValidationCheck `json:"checks,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SelfTest exercises a SecretStore or ClusterSecretStore once and records the result of every step in status.
+// +kubebuilder:printcolumn:name="Store",type=string,JSONPath=`.spec.secretStoreRef.name`
+// +kubebuilder:printcolumn:name="Result",type=string,JSONPath=`.status.result`
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories={externalsecrets},shortName=st
+type SelfTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SelfTestSpec   `json:"spec,omitempty"`
+	Status SelfTestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SelfTestList contains a list of SelfTest resources.
+type SelfTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SelfTest `json:"items"`
+}
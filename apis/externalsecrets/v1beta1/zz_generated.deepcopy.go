@@ -38,6 +38,11 @@ func (in *AWSAuth) DeepCopyInto(out *AWSAuth) {
 		*out = new(AWSJWTAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecCredentialSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAuth.
@@ -75,6 +80,11 @@ func (in *AWSJWTAuth) DeepCopyInto(out *AWSJWTAuth) {
 		*out = new(metav1.ServiceAccountSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSJWTAuth.
@@ -196,6 +206,11 @@ func (in *AkeylessProvider) DeepCopy() *AkeylessProvider {
 func (in *AlibabaAuth) DeepCopyInto(out *AlibabaAuth) {
 	*out = *in
 	in.SecretRef.DeepCopyInto(&out.SecretRef)
+	if in.RAMRole != nil {
+		in, out := &in.RAMRole, &out.RAMRole
+		*out = new(AlibabaRAMRole)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlibabaAuth.
@@ -245,6 +260,21 @@ func (in *AlibabaProvider) DeepCopy() *AlibabaProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlibabaRAMRole) DeepCopyInto(out *AlibabaRAMRole) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlibabaRAMRole.
+func (in *AlibabaRAMRole) DeepCopy() *AlibabaRAMRole {
+	if in == nil {
+		return nil
+	}
+	out := new(AlibabaRAMRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureKVAuth) DeepCopyInto(out *AzureKVAuth) {
 	*out = *in
@@ -258,6 +288,16 @@ func (in *AzureKVAuth) DeepCopyInto(out *AzureKVAuth) {
 		*out = new(metav1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(metav1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificatePassword != nil {
+		in, out := &in.ClientCertificatePassword, &out.ClientCertificatePassword
+		*out = new(metav1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKVAuth.
@@ -303,6 +343,11 @@ func (in *AzureKVProvider) DeepCopyInto(out *AzureKVProvider) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MaxConcurrentRequests != nil {
+		in, out := &in.MaxConcurrentRequests, &out.MaxConcurrentRequests
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKVProvider.
@@ -552,6 +597,52 @@ func (in *ClusterSecretStoreList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CyberArkCCPAuth) DeepCopyInto(out *CyberArkCCPAuth) {
+	*out = *in
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(CertAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CyberArkCCPAuth.
+func (in *CyberArkCCPAuth) DeepCopy() *CyberArkCCPAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(CyberArkCCPAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CyberArkCCPProvider) DeepCopyInto(out *CyberArkCCPProvider) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.CAProvider != nil {
+		in, out := &in.CAProvider, &out.CAProvider
+		*out = new(CAProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CyberArkCCPProvider.
+func (in *CyberArkCCPProvider) DeepCopy() *CyberArkCCPProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(CyberArkCCPProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DopplerAuth) DeepCopyInto(out *DopplerAuth) {
 	*out = *in
@@ -604,6 +695,102 @@ func (in *DopplerProvider) DeepCopy() *DopplerProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdAuth) DeepCopyInto(out *EtcdAuth) {
+	*out = *in
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(CertAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdAuth.
+func (in *EtcdAuth) DeepCopy() *EtcdAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdProvider) DeepCopyInto(out *EtcdProvider) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.CAProvider != nil {
+		in, out := &in.CAProvider, &out.CAProvider
+		*out = new(CAProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdProvider.
+func (in *EtcdProvider) DeepCopy() *EtcdProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecCredentialSource) DeepCopyInto(out *ExecCredentialSource) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]ExecEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecCredentialSource.
+func (in *ExecCredentialSource) DeepCopy() *ExecCredentialSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecCredentialSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecEnvVar) DeepCopyInto(out *ExecEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecEnvVar.
+func (in *ExecEnvVar) DeepCopy() *ExecEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecret) DeepCopyInto(out *ExternalSecret) {
 	*out = *in
@@ -694,6 +881,21 @@ func (in *ExternalSecretDataRemoteRef) DeepCopy() *ExternalSecretDataRemoteRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretDependency) DeepCopyInto(out *ExternalSecretDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretDependency.
+func (in *ExternalSecretDependency) DeepCopy() *ExternalSecretDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretFind) DeepCopyInto(out *ExternalSecretFind) {
 	*out = *in
@@ -714,6 +916,11 @@ func (in *ExternalSecretFind) DeepCopyInto(out *ExternalSecretFind) {
 			(*out)[key] = val
 		}
 	}
+	if in.MaxResults != nil {
+		in, out := &in.MaxResults, &out.MaxResults
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretFind.
@@ -803,6 +1010,11 @@ func (in *ExternalSecretSpec) DeepCopyInto(out *ExternalSecretSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.RefreshJitterPercent != nil {
+		in, out := &in.RefreshJitterPercent, &out.RefreshJitterPercent
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
 		*out = make([]ExternalSecretData, len(*in))
@@ -815,6 +1027,11 @@ func (in *ExternalSecretSpec) DeepCopyInto(out *ExternalSecretSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]ExternalSecretDependency, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretSpec.
@@ -838,6 +1055,23 @@ func (in *ExternalSecretStatus) DeepCopyInto(out *ExternalSecretStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ErrorHistory != nil {
+		in, out := &in.ErrorHistory, &out.ErrorHistory
+		*out = make([]ExternalSecretSyncError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SyncedVersions != nil {
+		in, out := &in.SyncedVersions, &out.SyncedVersions
+		*out = make([]ExternalSecretSyncedVersion, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingKeys != nil {
+		in, out := &in.MissingKeys, &out.MissingKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretStatus.
@@ -866,6 +1100,41 @@ func (in *ExternalSecretStatusCondition) DeepCopy() *ExternalSecretStatusConditi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretSyncError) DeepCopyInto(out *ExternalSecretSyncError) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.LastTime != nil {
+		in, out := &in.LastTime, &out.LastTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretSyncError.
+func (in *ExternalSecretSyncError) DeepCopy() *ExternalSecretSyncError {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretSyncError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretSyncedVersion) DeepCopyInto(out *ExternalSecretSyncedVersion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretSyncedVersion.
+func (in *ExternalSecretSyncedVersion) DeepCopy() *ExternalSecretSyncedVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretSyncedVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretTarget) DeepCopyInto(out *ExternalSecretTarget) {
 	*out = *in
@@ -874,6 +1143,11 @@ func (in *ExternalSecretTarget) DeepCopyInto(out *ExternalSecretTarget) {
 		*out = new(ExternalSecretTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ExternalSecretValidation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTarget.
@@ -904,6 +1178,25 @@ func (in *ExternalSecretTemplate) DeepCopyInto(out *ExternalSecretTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]TemplateFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StringData != nil {
+		in, out := &in.StringData, &out.StringData
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PruneKeys != nil {
+		in, out := &in.PruneKeys, &out.PruneKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTemplate.
@@ -945,6 +1238,41 @@ func (in *ExternalSecretTemplateMetadata) DeepCopy() *ExternalSecretTemplateMeta
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretValidation) DeepCopyInto(out *ExternalSecretValidation) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ExternalSecretValidationRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretValidation.
+func (in *ExternalSecretValidation) DeepCopy() *ExternalSecretValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretValidationRule) DeepCopyInto(out *ExternalSecretValidationRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretValidationRule.
+func (in *ExternalSecretValidationRule) DeepCopy() *ExternalSecretValidationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretValidationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretValidator) DeepCopyInto(out *ExternalSecretValidator) {
 	*out = *in
@@ -1032,6 +1360,11 @@ func (in *GCPSMAuth) DeepCopyInto(out *GCPSMAuth) {
 		*out = new(GCPWorkloadIdentity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CredentialConfigSecretRef != nil {
+		in, out := &in.CredentialConfigSecretRef, &out.CredentialConfigSecretRef
+		*out = new(metav1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPSMAuth.
@@ -1064,6 +1397,11 @@ func (in *GCPSMAuthSecretRef) DeepCopy() *GCPSMAuthSecretRef {
 func (in *GCPSMProvider) DeepCopyInto(out *GCPSMProvider) {
 	*out = *in
 	in.Auth.DeepCopyInto(&out.Auth)
+	if in.AllowedProjects != nil {
+		in, out := &in.AllowedProjects, &out.AllowedProjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPSMProvider.
@@ -1428,38 +1766,364 @@ func (in *OracleSecretRef) DeepCopy() *OracleSecretRef {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretStore) DeepCopyInto(out *SecretStore) {
+func (in *PassboltAuth) DeepCopyInto(out *PassboltAuth) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.PrivateKeySecretRef.DeepCopyInto(&out.PrivateKeySecretRef)
+	if in.PassphraseSecretRef != nil {
+		in, out := &in.PassphraseSecretRef, &out.PassphraseSecretRef
+		*out = new(metav1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStore.
-func (in *SecretStore) DeepCopy() *SecretStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassboltAuth.
+func (in *PassboltAuth) DeepCopy() *PassboltAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretStore)
+	out := new(PassboltAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SecretStore) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretStoreList) DeepCopyInto(out *SecretStoreList) {
+func (in *PassboltProvider) DeepCopyInto(out *PassboltProvider) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassboltProvider.
+func (in *PassboltProvider) DeepCopy() *PassboltProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(PassboltProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfig.
+func (in *ProviderConfig) DeepCopy() *ProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProviderConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigEndpoints) DeepCopyInto(out *ProviderConfigEndpoints) {
+	*out = *in
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(string)
+		**out = **in
+	}
+	if in.IBM != nil {
+		in, out := &in.IBM, &out.IBM
+		*out = new(string)
+		**out = **in
+	}
+	if in.Alibaba != nil {
+		in, out := &in.Alibaba, &out.Alibaba
+		*out = new(string)
+		**out = **in
+	}
+	if in.Yandex != nil {
+		in, out := &in.Yandex, &out.Yandex
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigEndpoints.
+func (in *ProviderConfigEndpoints) DeepCopy() *ProviderConfigEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigList) DeepCopyInto(out *ProviderConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProviderConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigList.
+func (in *ProviderConfigList) DeepCopy() *ProviderConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProviderConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigRef) DeepCopyInto(out *ProviderConfigRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigRef.
+func (in *ProviderConfigRef) DeepCopy() *ProviderConfigRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigSpec) DeepCopyInto(out *ProviderConfigSpec) {
+	*out = *in
+	if in.RetrySettings != nil {
+		in, out := &in.RetrySettings, &out.RetrySettings
+		*out = new(SecretStoreRetrySettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = new(ProviderConfigEndpoints)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigSpec.
+func (in *ProviderConfigSpec) DeepCopy() *ProviderConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOPSBucketSource) DeepCopyInto(out *SOPSBucketSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOPSBucketSource.
+func (in *SOPSBucketSource) DeepCopy() *SOPSBucketSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SOPSBucketSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOPSHTTPSource) DeepCopyInto(out *SOPSHTTPSource) {
+	*out = *in
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(metav1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOPSHTTPSource.
+func (in *SOPSHTTPSource) DeepCopy() *SOPSHTTPSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SOPSHTTPSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOPSProvider) DeepCopyInto(out *SOPSProvider) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOPSProvider.
+func (in *SOPSProvider) DeepCopy() *SOPSProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SOPSProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOPSSource) DeepCopyInto(out *SOPSSource) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(SOPSHTTPSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(SOPSBucketSource)
+		**out = **in
+	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(SOPSBucketSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOPSSource.
+func (in *SOPSSource) DeepCopy() *SOPSSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SOPSSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStore) DeepCopyInto(out *SecretStore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStore.
+func (in *SecretStore) DeepCopy() *SecretStore {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretStore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreCapabilities) DeepCopyInto(out *SecretStoreCapabilities) {
+	*out = *in
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]SecretStoreFeature, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreCapabilities.
+func (in *SecretStoreCapabilities) DeepCopy() *SecretStoreCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreInheritance) DeepCopyInto(out *SecretStoreInheritance) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(SecretStoreOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreInheritance.
+func (in *SecretStoreInheritance) DeepCopy() *SecretStoreInheritance {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreInheritance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreLimits) DeepCopyInto(out *SecretStoreLimits) {
+	*out = *in
+	if in.MaxSecretSize != nil {
+		in, out := &in.MaxSecretSize, &out.MaxSecretSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxKeys != nil {
+		in, out := &in.MaxKeys, &out.MaxKeys
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxTotalSize != nil {
+		in, out := &in.MaxTotalSize, &out.MaxTotalSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreLimits.
+func (in *SecretStoreLimits) DeepCopy() *SecretStoreLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreList) DeepCopyInto(out *SecretStoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]SecretStore, len(*in))
 		for i := range *in {
@@ -1486,6 +2150,36 @@ func (in *SecretStoreList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreOverrides) DeepCopyInto(out *SecretStoreOverrides) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProjectID != nil {
+		in, out := &in.ProjectID, &out.ProjectID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreOverrides.
+func (in *SecretStoreOverrides) DeepCopy() *SecretStoreOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretStoreProvider) DeepCopyInto(out *SecretStoreProvider) {
 	*out = *in
@@ -1574,6 +2268,26 @@ func (in *SecretStoreProvider) DeepCopyInto(out *SecretStoreProvider) {
 		*out = new(DopplerProvider)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(EtcdProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SOPS != nil {
+		in, out := &in.SOPS, &out.SOPS
+		*out = new(SOPSProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CyberArkCCP != nil {
+		in, out := &in.CyberArkCCP, &out.CyberArkCCP
+		*out = new(CyberArkCCPProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Passbolt != nil {
+		in, out := &in.Passbolt, &out.Passbolt
+		*out = new(PassboltProvider)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreProvider.
@@ -1634,11 +2348,36 @@ func (in *SecretStoreSpec) DeepCopyInto(out *SecretStoreSpec) {
 		*out = new(SecretStoreProvider)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InheritFrom != nil {
+		in, out := &in.InheritFrom, &out.InheritFrom
+		*out = new(SecretStoreInheritance)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(ProviderConfigRef)
+		**out = **in
+	}
 	if in.RetrySettings != nil {
 		in, out := &in.RetrySettings, &out.RetrySettings
 		*out = new(SecretStoreRetrySettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CredentialsRefreshInterval != nil {
+		in, out := &in.CredentialsRefreshInterval, &out.CredentialsRefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AllowedKeyPrefixes != nil {
+		in, out := &in.AllowedKeyPrefixes, &out.AllowedKeyPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(SecretStoreLimits)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreSpec.
@@ -1661,6 +2400,11 @@ func (in *SecretStoreStatus) DeepCopyInto(out *SecretStoreStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(SecretStoreCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreStatus.
@@ -1689,6 +2433,161 @@ func (in *SecretStoreStatusCondition) DeepCopy() *SecretStoreStatusCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreValidation) DeepCopyInto(out *SecretStoreValidation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]SecretStoreValidationCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreValidation.
+func (in *SecretStoreValidation) DeepCopy() *SecretStoreValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreValidationCheck) DeepCopyInto(out *SecretStoreValidationCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreValidationCheck.
+func (in *SecretStoreValidationCheck) DeepCopy() *SecretStoreValidationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreValidationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTest) DeepCopyInto(out *SelfTest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTest.
+func (in *SelfTest) DeepCopy() *SelfTest {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SelfTest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTestList) DeepCopyInto(out *SelfTestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SelfTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTestList.
+func (in *SelfTestList) DeepCopy() *SelfTestList {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SelfTestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTestRemoteRef) DeepCopyInto(out *SelfTestRemoteRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTestRemoteRef.
+func (in *SelfTestRemoteRef) DeepCopy() *SelfTestRemoteRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTestRemoteRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTestSpec) DeepCopyInto(out *SelfTestSpec) {
+	*out = *in
+	out.SecretStoreRef = in.SecretStoreRef
+	if in.RemoteRef != nil {
+		in, out := &in.RemoteRef, &out.RemoteRef
+		*out = new(SelfTestRemoteRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTestSpec.
+func (in *SelfTestSpec) DeepCopy() *SelfTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfTestStatus) DeepCopyInto(out *SelfTestStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]SecretStoreValidationCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfTestStatus.
+func (in *SelfTestStatus) DeepCopy() *SelfTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SenhaseguraAuth) DeepCopyInto(out *SenhaseguraAuth) {
 	*out = *in
@@ -1721,6 +2620,41 @@ func (in *SenhaseguraProvider) DeepCopy() *SenhaseguraProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateFile) DeepCopyInto(out *TemplateFile) {
+	*out = *in
+	if in.Parts != nil {
+		in, out := &in.Parts, &out.Parts
+		*out = make([]TemplateFilePart, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateFile.
+func (in *TemplateFile) DeepCopy() *TemplateFile {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateFilePart) DeepCopyInto(out *TemplateFilePart) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateFilePart.
+func (in *TemplateFilePart) DeepCopy() *TemplateFilePart {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateFilePart)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateFrom) DeepCopyInto(out *TemplateFrom) {
 	*out = *in
@@ -1797,6 +2731,21 @@ func (in *TokenAuth) DeepCopy() *TokenAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TooManyResultsError) DeepCopyInto(out *TooManyResultsError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TooManyResultsError.
+func (in *TooManyResultsError) DeepCopy() *TooManyResultsError {
+	if in == nil {
+		return nil
+	}
+	out := new(TooManyResultsError)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultAppRole) DeepCopyInto(out *VaultAppRole) {
 	*out = *in
@@ -1846,6 +2795,11 @@ func (in *VaultAuth) DeepCopyInto(out *VaultAuth) {
 		*out = new(VaultCertAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TokenFile != nil {
+		in, out := &in.TokenFile, &out.TokenFile
+		*out = new(VaultTokenFileAuth)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuth.
@@ -1875,6 +2829,23 @@ func (in *VaultCertAuth) DeepCopy() *VaultCertAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultClientTLS) DeepCopyInto(out *VaultClientTLS) {
+	*out = *in
+	in.CertSecretRef.DeepCopyInto(&out.CertSecretRef)
+	in.KeySecretRef.DeepCopyInto(&out.KeySecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultClientTLS.
+func (in *VaultClientTLS) DeepCopy() *VaultClientTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultClientTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultJwtAuth) DeepCopyInto(out *VaultJwtAuth) {
 	*out = *in
@@ -1995,6 +2966,11 @@ func (in *VaultProvider) DeepCopyInto(out *VaultProvider) {
 		*out = new(CAProvider)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientTLS != nil {
+		in, out := &in.ClientTLS, &out.ClientTLS
+		*out = new(VaultClientTLS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultProvider.
@@ -2007,6 +2983,21 @@ func (in *VaultProvider) DeepCopy() *VaultProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultTokenFileAuth) DeepCopyInto(out *VaultTokenFileAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultTokenFileAuth.
+func (in *VaultTokenFileAuth) DeepCopy() *VaultTokenFileAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultTokenFileAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookCAProvider) DeepCopyInto(out *WebhookCAProvider) {
 	*out = *in
@@ -2027,9 +3018,74 @@ func (in *WebhookCAProvider) DeepCopy() *WebhookCAProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookCache) DeepCopyInto(out *WebhookCache) {
+	*out = *in
+	if in.DefaultTTL != nil {
+		in, out := &in.DefaultTTL, &out.DefaultTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxStaleness != nil {
+		in, out := &in.MaxStaleness, &out.MaxStaleness
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookCache.
+func (in *WebhookCache) DeepCopy() *WebhookCache {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookGRPC) DeepCopyInto(out *WebhookGRPC) {
+	*out = *in
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = make([]WebhookGRPCField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookGRPC.
+func (in *WebhookGRPC) DeepCopy() *WebhookGRPC {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookGRPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookGRPCField) DeepCopyInto(out *WebhookGRPCField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookGRPCField.
+func (in *WebhookGRPCField) DeepCopy() *WebhookGRPCField {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookGRPCField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookProvider) DeepCopyInto(out *WebhookProvider) {
 	*out = *in
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(WebhookGRPC)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
 		*out = make(map[string]string, len(*in))
@@ -2060,6 +3116,11 @@ func (in *WebhookProvider) DeepCopyInto(out *WebhookProvider) {
 		*out = new(WebhookCAProvider)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(WebhookCache)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookProvider.
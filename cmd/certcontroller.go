@@ -74,6 +74,7 @@ var certcontrollerCmd = &cobra.Command{
 				"clustersecretstores.external-secrets.io",
 				"secretstores.external-secrets.io",
 			})
+		crdctrl.ExternalCerts = externalCerts
 		if err := crdctrl.SetupWithManager(mgr, controller.Options{
 			MaxConcurrentReconciles: concurrent,
 		}); err != nil {
@@ -102,6 +103,11 @@ var certcontrollerCmd = &cobra.Command{
 			setupLog.Error(err, "unable to add webhook readyz check")
 			os.Exit(1)
 		}
+		err = mgr.AddReadyzCheck("crd-certs", crdctrl.CertCheck)
+		if err != nil {
+			setupLog.Error(err, "unable to add crd certs readyz check")
+			os.Exit(1)
+		}
 
 		setupLog.Info("starting manager")
 		if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -124,5 +130,6 @@ func init() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	certcontrollerCmd.Flags().StringVar(&loglevel, "loglevel", "info", "loglevel to use, one of: debug, info, warn, error, dpanic, panic, fatal")
+	certcontrollerCmd.Flags().BoolVar(&externalCerts, "external-certs", false, "Do not generate the webhook certificates but use the ones provided in the secret, e.g. by cert-manager. Only the CA bundle is injected.")
 	certcontrollerCmd.Flags().DurationVar(&crdRequeueInterval, "crd-requeue-interval", time.Minute*5, "Time duration between reconciling CRDs for new certs")
 }
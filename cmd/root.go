@@ -16,6 +16,8 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
+	"net/http"
 	"os"
 	"time"
 
@@ -27,18 +29,28 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	// To allow using gcp auth.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/circuitbreaker"
 	"github.com/external-secrets/external-secrets/pkg/controllers/clusterexternalsecret"
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/credentialfile"
+	"github.com/external-secrets/external-secrets/pkg/diagnostics"
+	"github.com/external-secrets/external-secrets/pkg/execcredential"
 	awsauth "github.com/external-secrets/external-secrets/pkg/provider/aws/auth"
+	gcpsm "github.com/external-secrets/external-secrets/pkg/provider/gcp/secretmanager"
 	"github.com/external-secrets/external-secrets/pkg/provider/vault"
+	templatev2 "github.com/external-secrets/external-secrets/pkg/template/v2"
+	"github.com/external-secrets/external-secrets/pkg/useragent"
 )
 
 var (
@@ -48,8 +60,13 @@ var (
 	certDir                               string
 	metricsAddr                           string
 	healthzAddr                           string
+	debugAddr                             string
 	controllerClass                       string
 	enableLeaderElection                  bool
+	leaderElectionID                      string
+	leaderElectionLeaseDuration           time.Duration
+	leaderElectionRenewDeadline           time.Duration
+	leaderElectionRetryPeriod             time.Duration
 	enableSecretsCache                    bool
 	enableConfigMapsCache                 bool
 	concurrent                            int
@@ -58,24 +75,45 @@ var (
 	clientBurst                           int
 	loglevel                              string
 	namespace                             string
+	namespaces                            []string
 	enableClusterStoreReconciler          bool
 	enableClusterExternalSecretReconciler bool
 	enableFloodGate                       bool
 	storeRequeueInterval                  time.Duration
+	defaultRefreshInterval                time.Duration
+	minRefreshInterval                    time.Duration
+	refreshJitterPercent                  int
+	namespaceSyncQPS                      float64
+	namespaceSyncBurst                    int
 	serviceName, serviceNamespace         string
 	secretName, secretNamespace           string
 	crdRequeueInterval                    time.Duration
 	certCheckInterval                     time.Duration
 	certLookaheadInterval                 time.Duration
+	externalCerts                         bool
 	enableAWSSession                      bool
 	enableVaultTokenCache                 bool
 	vaultTokenCacheSize                   int
 	tlsCiphers                            string
 	tlsMinVersion                         string
+	immutableStoreAndTarget               bool
+	circuitBreakerThreshold               int
+	circuitBreakerBackoff                 time.Duration
+	circuitBreakerMaxBackoff              time.Duration
+	metricsAggregation                    string
+	templateTimeout                       time.Duration
+	templateMaxOutputSize                 int
+	enableDriftDetection                  bool
+	execCredentialPluginDir               string
+	credentialFileDir                     string
+	gcpDiagnosePermissions                bool
+	gcpClientIdleTimeout                  time.Duration
+	clusterName                           string
 )
 
 const (
-	errCreateController = "unable to create controller"
+	errCreateController     = "unable to create controller"
+	defaultLeaderElectionID = "external-secrets-controller"
 )
 
 func init() {
@@ -114,25 +152,47 @@ var rootCmd = &cobra.Command{
 		config := ctrl.GetConfigOrDie()
 		config.QPS = clientQPS
 		config.Burst = clientBurst
-		mgr, err := ctrl.NewManager(config, ctrl.Options{
-			Scheme:                scheme,
-			MetricsBindAddress:    metricsAddr,
-			Port:                  9443,
-			LeaderElection:        enableLeaderElection,
-			LeaderElectionID:      "external-secrets-controller",
-			ClientDisableCacheFor: cacheList,
-			Namespace:             namespace,
-		})
+		if namespace != "" && len(namespaces) > 0 {
+			setupLog.Error(errors.New("--namespace and --namespaces are mutually exclusive"), "invalid flags")
+			os.Exit(1)
+		}
+		mgrOpts := ctrl.Options{
+			Scheme:                 scheme,
+			MetricsBindAddress:     metricsAddr,
+			HealthProbeBindAddress: healthzAddr,
+			Port:                   9443,
+			LeaderElection:         enableLeaderElection,
+			LeaderElectionID:       leaderElectionID,
+			// configmapsleases, the default of controller-runtime, maintains a lease as well,
+			// so replicas of older versions agree on the leader during an upgrade.
+			LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+			LeaseDuration:              &leaderElectionLeaseDuration,
+			RenewDeadline:              &leaderElectionRenewDeadline,
+			RetryPeriod:                &leaderElectionRetryPeriod,
+			// the process exits right after the manager stopped,
+			// releasing the lease lets a standby take over immediately.
+			LeaderElectionReleaseOnCancel: true,
+			ClientDisableCacheFor:         cacheList,
+			Namespace:                     namespace,
+		}
+		// watching a list of namespaces requires one cache per namespace.
+		// Cluster-scoped resources like ClusterSecretStores are still watched cluster-wide.
+		if len(namespaces) > 0 {
+			mgrOpts.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
+		}
+		mgr, err := ctrl.NewManager(config, mgrOpts)
 		if err != nil {
 			setupLog.Error(err, "unable to start manager")
 			os.Exit(1)
 		}
+		breakers := circuitbreaker.NewRegistry(circuitBreakerThreshold, circuitBreakerBackoff, circuitBreakerMaxBackoff)
 		if err = (&secretstore.StoreReconciler{
 			Client:          mgr.GetClient(),
 			Log:             ctrl.Log.WithName("controllers").WithName("SecretStore"),
 			Scheme:          mgr.GetScheme(),
 			ControllerClass: controllerClass,
 			RequeueInterval: storeRequeueInterval,
+			CircuitBreakers: breakers,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, errCreateController, "controller", "SecretStore")
 			os.Exit(1)
@@ -144,19 +204,38 @@ var rootCmd = &cobra.Command{
 				Scheme:          mgr.GetScheme(),
 				ControllerClass: controllerClass,
 				RequeueInterval: storeRequeueInterval,
+				CircuitBreakers: breakers,
 			}).SetupWithManager(mgr); err != nil {
 				setupLog.Error(err, errCreateController, "controller", "ClusterSecretStore")
 				os.Exit(1)
 			}
 		}
+		if err = (&secretstore.SelfTestReconciler{
+			Client:          mgr.GetClient(),
+			Log:             ctrl.Log.WithName("controllers").WithName("SelfTest"),
+			Scheme:          mgr.GetScheme(),
+			ControllerClass: controllerClass,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, errCreateController, "controller", "SelfTest")
+			os.Exit(1)
+		}
+		if err = externalsecret.SetUpMetrics(externalsecret.MetricsAggregation(metricsAggregation)); err != nil {
+			setupLog.Error(err, "unable to configure metrics")
+			os.Exit(1)
+		}
 		if err = (&externalsecret.Reconciler{
 			Client:                    mgr.GetClient(),
 			Log:                       ctrl.Log.WithName("controllers").WithName("ExternalSecret"),
 			Scheme:                    mgr.GetScheme(),
 			ControllerClass:           controllerClass,
-			RequeueInterval:           time.Hour,
+			RequeueInterval:           defaultRefreshInterval,
+			MinRefreshInterval:        minRefreshInterval,
+			RefreshJitterPercent:      refreshJitterPercent,
 			ClusterSecretStoreEnabled: enableClusterStoreReconciler,
 			EnableFloodGate:           enableFloodGate,
+			CircuitBreakers:           breakers,
+			NamespaceRateLimiter:      externalsecret.NewNamespaceRateLimiter(namespaceSyncQPS, namespaceSyncBurst),
+			EnableDriftDetection:      enableDriftDetection,
 		}).SetupWithManager(mgr, controller.Options{
 			MaxConcurrentReconciles: concurrent,
 		}); err != nil {
@@ -176,6 +255,13 @@ var rootCmd = &cobra.Command{
 				os.Exit(1)
 			}
 		}
+		templatev2.ExecutionTimeout = templateTimeout
+		templatev2.MaxOutputSize = templateMaxOutputSize
+		execcredential.PluginDir = execCredentialPluginDir
+		credentialfile.Dir = credentialFileDir
+		gcpsm.DiagnosePermissions = gcpDiagnosePermissions
+		gcpsm.ClientIdleTimeout = gcpClientIdleTimeout
+		useragent.ClusterName = clusterName
 		if enableAWSSession {
 			awsauth.EnableCache = true
 		}
@@ -183,6 +269,23 @@ var rootCmd = &cobra.Command{
 			vault.EnableCache = true
 			vault.VaultClientCache.Size = vaultTokenCacheSize
 		}
+		if debugAddr != "" {
+			if err = mgr.Add(diagnostics.NewServer(debugAddr)); err != nil {
+				setupLog.Error(err, "unable to add diagnostics server")
+				os.Exit(1)
+			}
+		}
+		if err = mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+			setupLog.Error(err, "unable to add healthz check")
+			os.Exit(1)
+		}
+		err = mgr.AddReadyzCheck("provider-registry", func(_ *http.Request) error {
+			return esv1beta1.CheckProviderRegistry()
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to add provider registry readyz check")
+			os.Exit(1)
+		}
 		setupLog.Info("starting manager")
 		if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 			setupLog.Error(err, "problem running manager")
@@ -198,21 +301,45 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	rootCmd.Flags().StringVar(&healthzAddr, "healthz-addr", ":8081", "The address the health endpoint binds to.")
+	rootCmd.Flags().StringVar(&debugAddr, "debug-addr", "", "The address the pprof and /debug/state diagnostics endpoints bind to. Disabled if empty.")
+	rootCmd.Flags().StringVar(&metricsAggregation, "metrics-aggregation", "object", "Granularity of the ExternalSecret sync metrics, one of: object, namespace, store. With namespace or store no per-object metrics are emitted.")
 	rootCmd.Flags().StringVar(&controllerClass, "controller-class", "default", "the controller is instantiated with a specific controller name and filters ES based on this property")
 	rootCmd.Flags().BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	rootCmd.Flags().StringVar(&leaderElectionID, "leader-election-id", defaultLeaderElectionID, "Name of the leader election lease.")
+	rootCmd.Flags().DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "Duration that standby replicas wait before taking over a lease that was not renewed.")
+	rootCmd.Flags().DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 10*time.Second, "Duration the leader retries to renew the lease before giving up leadership.")
+	rootCmd.Flags().DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 2*time.Second, "Duration between leader election attempts.")
 	rootCmd.Flags().IntVar(&concurrent, "concurrent", 1, "The number of concurrent ExternalSecret reconciles.")
 	rootCmd.Flags().Float32Var(&clientQPS, "client-qps", 0, "QPS configuration to be passed to rest.Client")
 	rootCmd.Flags().IntVar(&clientBurst, "client-burst", 0, "Maximum Burst allowed to be passed to rest.Client")
 	rootCmd.Flags().StringVar(&loglevel, "loglevel", "info", "loglevel to use, one of: debug, info, warn, error, dpanic, panic, fatal")
 	rootCmd.Flags().StringVar(&namespace, "namespace", "", "watch external secrets scoped in the provided namespace only. ClusterSecretStore can be used but only work if it doesn't reference resources from other namespaces")
+	rootCmd.Flags().StringSliceVar(&namespaces, "namespaces", nil, "watch external secrets scoped in the provided comma-separated list of namespaces only. Can not be combined with --namespace")
 	rootCmd.Flags().BoolVar(&enableClusterStoreReconciler, "enable-cluster-store-reconciler", true, "Enable cluster store reconciler.")
 	rootCmd.Flags().BoolVar(&enableClusterExternalSecretReconciler, "enable-cluster-external-secret-reconciler", true, "Enable cluster external secret reconciler.")
 	rootCmd.Flags().BoolVar(&enableSecretsCache, "enable-secrets-caching", false, "Enable secrets caching for external-secrets pod.")
 	rootCmd.Flags().BoolVar(&enableConfigMapsCache, "enable-configmaps-caching", false, "Enable secrets caching for external-secrets pod.")
 	rootCmd.Flags().DurationVar(&storeRequeueInterval, "store-requeue-interval", time.Minute*5, "Default Time duration between reconciling (Cluster)SecretStores")
+	rootCmd.Flags().DurationVar(&defaultRefreshInterval, "default-refresh-interval", time.Hour, "Default refreshInterval of ExternalSecrets that do not set one.")
+	rootCmd.Flags().DurationVar(&minRefreshInterval, "min-refresh-interval", 0, "Minimum refreshInterval of ExternalSecrets. Shorter intervals are raised to the minimum. Set to 0 to disable.")
+	rootCmd.Flags().IntVar(&refreshJitterPercent, "refresh-jitter-percent", 0, "Delay every ExternalSecret refresh by a random amount of up to this percentage of its refreshInterval. Set to 0 to disable.")
+	rootCmd.Flags().Float64Var(&namespaceSyncQPS, "namespace-sync-qps", 0, "Maximum rate of ExternalSecret syncs per second and namespace. Syncs above the rate are deferred. Set to 0 to disable.")
+	rootCmd.Flags().IntVar(&namespaceSyncBurst, "namespace-sync-burst", 100, "Maximum burst of ExternalSecret syncs per namespace. Only used if --namespace-sync-qps is set.")
+	rootCmd.Flags().BoolVar(&enableDriftDetection, "enable-drift-detection", false, "Watch all Secrets and restore target Secrets right away if they are changed or deleted out-of-band, instead of waiting for the next refresh.")
 	rootCmd.Flags().BoolVar(&enableFloodGate, "enable-flood-gate", true, "Enable flood gate. External secret will be reconciled only if the ClusterStore or Store have an healthy or unknown state.")
+	rootCmd.Flags().IntVar(&circuitBreakerThreshold, "circuit-breaker-threshold", 0, "Number of consecutive provider errors after which a store is marked unhealthy and syncs are paused. Set to 0 to disable.")
+	rootCmd.Flags().DurationVar(&circuitBreakerBackoff, "circuit-breaker-backoff", time.Second*30, "Time syncs are paused after a store has been marked unhealthy. Doubles while the provider keeps failing.")
+	rootCmd.Flags().DurationVar(&circuitBreakerMaxBackoff, "circuit-breaker-max-backoff", time.Minute*10, "Maximum time syncs are paused after a store has been marked unhealthy.")
+	rootCmd.Flags().DurationVar(&templateTimeout, "template-timeout", 10*time.Second, "Maximum time a v2 template may take to render. Set to 0 to disable.")
+	rootCmd.Flags().IntVar(&templateMaxOutputSize, "template-max-output-size", 1<<20, "Maximum size in bytes of a rendered v2 template. Set to 0 to disable.")
+	rootCmd.Flags().StringVar(&credentialFileDir, "credential-file-dir", "", "Directory of the credential files ClusterSecretStores may read with Vault auth.tokenFile and GCP auth.credentialsFile. Credential files are disabled if not set.")
+	rootCmd.Flags().StringVar(&execCredentialPluginDir, "exec-credential-plugin-dir", "", "Directory of the binaries that stores may run with auth.exec to retrieve credentials. Exec credentials are disabled if not set.")
+	rootCmd.Flags().StringVar(&clusterName, "cluster-name", "", "Name of the cluster that is included in the user agent of provider requests, so audit logs can be attributed to the cluster.")
+	rootCmd.Flags().DurationVar(&gcpClientIdleTimeout, "gcp-client-idle-timeout", 2*time.Hour, "Time after which a GCP Secret Manager client that was not used by any reconcile of its store is closed.")
+	rootCmd.Flags().BoolVar(&gcpDiagnosePermissions, "gcp-diagnose-permission-denied", false, "Test the IAM permissions of GCP secrets that can not be accessed and report the missing permission instead of the PERMISSION_DENIED error.")
 	rootCmd.Flags().BoolVar(&enableAWSSession, "experimental-enable-aws-session-cache", false, "Enable experimental AWS session cache. External secret will reuse the AWS session without creating a new one on each request.")
 	rootCmd.Flags().BoolVar(&enableVaultTokenCache, "experimental-enable-vault-token-cache", false, "Enable experimental Vault token cache. External secrets will reuse the Vault token without creating a new one on each request.")
 	rootCmd.Flags().IntVar(&vaultTokenCacheSize, "experimental-vault-token-cache-size", 100, "Maximum size of Vault token cache. Only used if --experimental-enable-vault-token-cache is set.")
@@ -121,7 +121,8 @@ var webhookCmd = &cobra.Command{
 			setupLog.Error(err, "unable to start manager")
 			os.Exit(1)
 		}
-		if err = (&esv1beta1.ExternalSecret{}).SetupWebhookWithManager(mgr); err != nil {
+		esValidator := &esv1beta1.ExternalSecretValidator{ImmutableStoreAndTarget: immutableStoreAndTarget}
+		if err = (&esv1beta1.ExternalSecret{}).SetupWebhookWithValidator(mgr, esValidator); err != nil {
 			setupLog.Error(err, errCreateWebhook, "webhook", "ExternalSecret-v1beta1")
 			os.Exit(1)
 		}
@@ -222,4 +223,5 @@ func init() {
 		" Full lists of available ciphers can be found at https://pkg.go.dev/crypto/tls#pkg-constants."+
 		" E.g. 'TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256'")
 	webhookCmd.Flags().StringVar(&tlsMinVersion, "tls-min-version", "1.2", "minimum version of TLS supported. Defaults to 1.2")
+	webhookCmd.Flags().BoolVar(&immutableStoreAndTarget, "immutable-store-and-target", false, "Reject updates of ExternalSecrets that change spec.secretStoreRef or spec.target.name.")
 }
@@ -23,6 +23,17 @@ If release name contains chart name it will be used as a full name.
 {{- end }}
 {{- end }}
 
+{{/*
+Comma-separated list of the namespaces the controller is scoped to.
+*/}}
+{{- define "external-secrets.scopedNamespaces" -}}
+{{- $namespaces := .Values.scopedNamespaces | default list -}}
+{{- if .Values.scopedNamespace -}}
+{{- $namespaces = prepend $namespaces .Values.scopedNamespace -}}
+{{- end -}}
+{{- join "," (uniq $namespaces) -}}
+{{- end }}
+
 {{/*
 Create chart name and version as used by the chart label.
 */}}
@@ -29,7 +29,9 @@ func uninstallCRDs(cfg *Config) error {
 		"clusterexternalsecrets.external-secrets.io",
 		"clustersecretstores.external-secrets.io",
 		"externalsecrets.external-secrets.io",
+		"providerconfigs.external-secrets.io",
 		"secretstores.external-secrets.io",
+		"selftests.external-secrets.io",
 	} {
 		crd := &apiextensionsv1.CustomResourceDefinition{
 			ObjectMeta: metav1.ObjectMeta{
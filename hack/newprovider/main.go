@@ -0,0 +1,204 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// newprovider scaffolds a new provider:
+//
+//	go run ./hack/newprovider --name acme [--kind Acme]
+//
+// It creates the provider package with its fake API and tests, the store types
+// and registers the provider in SecretStoreProvider and pkg/provider/register.
+// Run make generate afterwards to update the deepcopy functions and CRDs.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	typesFile    = "apis/externalsecrets/v1beta1/secretstore_types.go"
+	registerFile = "pkg/provider/register/register.go"
+	modulePath   = "github.com/external-secrets/external-secrets"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var (
+	namePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	kindPattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+)
+
+// provider holds the names used in the templates.
+type provider struct {
+	// Name is the package name and the json name of the store field, e.g. acme.
+	Name string
+	// Kind is the prefix of the exported type names, e.g. Acme.
+	Kind string
+}
+
+// outputs maps the templates to the files they are rendered to.
+func (p provider) outputs() map[string]string {
+	return map[string]string{
+		"types.go.tmpl":         fmt.Sprintf("apis/externalsecrets/v1beta1/secretstore_%s_types.go", p.Name),
+		"provider.go.tmpl":      fmt.Sprintf("pkg/provider/%s/provider.go", p.Name),
+		"client.go.tmpl":        fmt.Sprintf("pkg/provider/%s/client.go", p.Name),
+		"provider_test.go.tmpl": fmt.Sprintf("pkg/provider/%s/provider_test.go", p.Name),
+		"fake.go.tmpl":          fmt.Sprintf("pkg/provider/%s/fake/fake.go", p.Name),
+	}
+}
+
+func main() {
+	var p provider
+	var root string
+	flag.StringVar(&p.Name, "name", "", "package name of the provider, e.g. acme")
+	flag.StringVar(&p.Kind, "kind", "", "prefix of the exported type names, defaults to the capitalized name")
+	flag.StringVar(&root, "root", ".", "root directory of the repository")
+	flag.Parse()
+	if p.Kind == "" && p.Name != "" {
+		p.Kind = strings.ToUpper(p.Name[:1]) + p.Name[1:]
+	}
+	if err := scaffold(root, p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf(`created provider %s. Next steps:
+  1. run make generate to update the deepcopy functions and CRDs
+  2. implement newAPI in pkg/provider/%s/client.go
+  3. document the provider in docs/provider/%s.md and add it to hack/api-docs/mkdocs.yml
+`, p.Name, p.Name, p.Name)
+}
+
+func scaffold(root string, p provider) error {
+	if !namePattern.MatchString(p.Name) {
+		return fmt.Errorf("name %q must match %s", p.Name, namePattern)
+	}
+	if !kindPattern.MatchString(p.Kind) {
+		return fmt.Errorf("kind %q must match %s", p.Kind, kindPattern)
+	}
+	if _, err := os.Stat(filepath.Join(root, "pkg/provider", p.Name)); err == nil {
+		return fmt.Errorf("provider %q already exists", p.Name)
+	}
+
+	files, err := render(p)
+	if err != nil {
+		return err
+	}
+	typesPath := filepath.Join(root, typesFile)
+	types, err := os.ReadFile(typesPath)
+	if err != nil {
+		return err
+	}
+	types, err = addStoreField(types, p)
+	if err != nil {
+		return err
+	}
+	registerPath := filepath.Join(root, registerFile)
+	register, err := os.ReadFile(registerPath)
+	if err != nil {
+		return err
+	}
+	register, err = addImport(register, modulePath+"/pkg/provider/"+p.Name)
+	if err != nil {
+		return err
+	}
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil { //nolint:gosec
+			return err
+		}
+	}
+	if err := os.WriteFile(typesPath, types, 0o644); err != nil { //nolint:gosec
+		return err
+	}
+	return os.WriteFile(registerPath, register, 0o644) //nolint:gosec
+}
+
+// render returns the formatted content of all scaffolded files by their path.
+func render(p provider) (map[string][]byte, error) {
+	tpl, err := template.ParseFS(templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for name, path := range p.outputs() {
+		var buf bytes.Buffer
+		if err := tpl.ExecuteTemplate(&buf, name, p); err != nil {
+			return nil, err
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+		files[path] = src
+	}
+	return files, nil
+}
+
+// addStoreField adds the provider to the end of the SecretStoreProvider struct.
+func addStoreField(src []byte, p provider) ([]byte, error) {
+	start := bytes.Index(src, []byte("type SecretStoreProvider struct {"))
+	if start < 0 {
+		return nil, errors.New("SecretStoreProvider struct not found")
+	}
+	end := bytes.Index(src[start:], []byte("\n}\n"))
+	if end < 0 {
+		return nil, errors.New("end of SecretStoreProvider struct not found")
+	}
+	end += start
+	field := fmt.Sprintf(`
+
+	// %[1]s configures this store to sync secrets using the %[1]s provider
+	// +optional
+	%[1]s *%[1]sProvider `+"`json:\"%[2]s,omitempty\"`", p.Kind, p.Name)
+	out := append([]byte{}, src[:end]...)
+	out = append(out, field...)
+	out = append(out, src[end:]...)
+	return format.Source(out)
+}
+
+// addImport adds a blank import to the sorted import block of the register package.
+func addImport(src []byte, pkg string) ([]byte, error) {
+	start := bytes.Index(src, []byte("import ("))
+	if start < 0 {
+		return nil, errors.New("import block not found")
+	}
+	start += len("import (\n")
+	end := bytes.Index(src[start:], []byte(")"))
+	if end < 0 {
+		return nil, errors.New("end of import block not found")
+	}
+	end += start
+	lines := strings.Split(strings.TrimRight(string(src[start:end]), "\n"), "\n")
+	lines = append(lines, fmt.Sprintf("\t_ %q", pkg))
+	sort.Strings(lines)
+	out := append([]byte{}, src[:start]...)
+	out = append(out, strings.Join(lines, "\n")+"\n"...)
+	out = append(out, src[end:]...)
+	return format.Source(out)
+}
@@ -0,0 +1,93 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testTypes = `package v1beta1
+
+type SecretStoreProvider struct {
+	// Zeta configures this store
+	// +optional
+	Zeta *ZetaProvider ` + "`json:\"zeta,omitempty\"`" + `
+}
+`
+	testRegister = `package register
+
+import (
+	_ "github.com/external-secrets/external-secrets/pkg/provider/alpha"
+	_ "github.com/external-secrets/external-secrets/pkg/provider/zeta"
+)
+`
+)
+
+func writeTestRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range map[string]string{typesFile: testTypes, registerFile: testRegister} {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestScaffold(t *testing.T) {
+	root := writeTestRepo(t)
+	p := provider{Name: "acme", Kind: "Acme"}
+	if err := scaffold(root, p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, path := range p.outputs() {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("missing %s: %v", path, err)
+		}
+	}
+
+	types, _ := os.ReadFile(filepath.Join(root, typesFile))
+	if !strings.Contains(string(types), "Acme *AcmeProvider `json:\"acme,omitempty\"`\n}") {
+		t.Errorf("store field not added:\n%s", types)
+	}
+	register, _ := os.ReadFile(filepath.Join(root, registerFile))
+	alpha := strings.Index(string(register), "provider/alpha")
+	acme := strings.Index(string(register), "provider/acme")
+	zeta := strings.Index(string(register), "provider/zeta")
+	if acme < 0 || acme > alpha || alpha > zeta {
+		t.Errorf("import not added in order:\n%s", register)
+	}
+
+	if err := scaffold(root, p); err == nil {
+		t.Errorf("expected an error if the provider already exists")
+	}
+}
+
+func TestScaffoldInvalidName(t *testing.T) {
+	root := writeTestRepo(t)
+	for _, p := range []provider{{Name: "Acme", Kind: "Acme"}, {Name: "acme-corp", Kind: "Acme"}, {Name: "acme", Kind: "acme"}} {
+		if err := scaffold(root, p); err == nil {
+			t.Errorf("expected an error for %+v", p)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// Breaker tracks consecutive provider failures of a single store.
+// After threshold consecutive failures the breaker opens and rejects calls
+// for a backoff period. Once the backoff elapsed calls are allowed again:
+// a success closes the breaker, a failure re-opens it with doubled backoff.
+type Breaker struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	threshold  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	// storeUID is the UID of the store the breaker was created for.
+	storeUID types.UID
+
+	failures int
+	backoff  time.Duration
+	openedAt time.Time
+}
+
+// Allow reports whether calls to the provider are allowed.
+// If they are not, it returns the time until the breaker allows calls again.
+func (b *Breaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return true, 0
+	}
+	remaining := b.openedAt.Add(b.backoff).Sub(b.now())
+	if remaining > 0 {
+		return false, remaining
+	}
+	return true, 0
+}
+
+// Success resets the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.backoff = 0
+	b.openedAt = time.Time{}
+}
+
+// Failure records a failed call and opens the breaker
+// once the failure threshold has been reached.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+	switch {
+	case b.backoff == 0:
+		b.backoff = b.minBackoff
+	case !b.openedAt.IsZero() && b.now().Sub(b.openedAt) >= b.backoff:
+		// the first call after the backoff failed as well
+		b.backoff *= 2
+		if b.backoff > b.maxBackoff {
+			b.backoff = b.maxBackoff
+		}
+	default:
+		// failures of calls that were in flight while the breaker opened
+		return
+	}
+	b.openedAt = b.now()
+}
+
+// Registry holds one Breaker per store.
+// A nil Registry disables circuit breaking.
+type Registry struct {
+	mu         sync.Mutex
+	threshold  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	breakers   map[string]*Breaker
+}
+
+// NewRegistry returns a Registry whose breakers open after threshold
+// consecutive failures. The backoff starts at minBackoff and doubles
+// up to maxBackoff while the provider keeps failing.
+// It returns nil if threshold is not positive.
+func NewRegistry(threshold int, minBackoff, maxBackoff time.Duration) *Registry {
+	if threshold <= 0 {
+		return nil
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+	return &Registry{
+		threshold:  threshold,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		breakers:   make(map[string]*Breaker),
+	}
+}
+
+// ForStore returns the Breaker of the given store.
+// A store that was recreated under the same name gets a new Breaker.
+// It returns nil if the Registry is nil.
+func (r *Registry) ForStore(store esv1beta1.GenericStore) *Breaker {
+	if r == nil {
+		return nil
+	}
+	key := store.GetNamespacedName()
+	uid := store.GetObjectMeta().UID
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.breakers[key]
+	if !ok || b.storeUID != uid {
+		b = &Breaker{
+			now:        time.Now,
+			threshold:  r.threshold,
+			minBackoff: r.minBackoff,
+			maxBackoff: r.maxBackoff,
+			storeUID:   uid,
+		}
+		r.breakers[key] = b
+	}
+	return b
+}
+
+// Remove drops the Breaker of a deleted store.
+func (r *Registry) Remove(name types.NamespacedName) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.breakers, name.String())
+}
+
+// Wrap returns a SecretsClient that records the result of every call in b.
+// Missing secrets are not considered a failure, errors that are retried
+// shortly by the provider are not recorded at all.
+func Wrap(client esv1beta1.SecretsClient, b *Breaker) esv1beta1.SecretsClient {
+	if b == nil {
+		return client
+	}
+	wrapped := &secretsClient{
+		SecretsClient: client,
+		breaker:       b,
+	}
+	// callers detect support for version expressions with a type assertion,
+	// the wrapper only lists versions if the client does.
+	if lister, ok := client.(esv1beta1.SecretVersionLister); ok {
+		return &versionListingClient{secretsClient: wrapped, lister: lister}
+	}
+	return wrapped
+}
+
+type secretsClient struct {
+	esv1beta1.SecretsClient
+	breaker *Breaker
+}
+
+type versionListingClient struct {
+	*secretsClient
+	lister esv1beta1.SecretVersionLister
+}
+
+func (c *secretsClient) record(err error) {
+	var retryErr esv1beta1.RetryAfterError
+	if errors.As(err, &retryErr) {
+		return
+	}
+	if err == nil || errors.Is(err, esv1beta1.NoSecretErr) {
+		c.breaker.Success()
+		return
+	}
+	c.breaker.Failure()
+}
+
+func (c *secretsClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	data, err := c.SecretsClient.GetSecret(ctx, ref)
+	c.record(err)
+	return data, err
+}
+
+func (c *secretsClient) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := c.SecretsClient.GetSecretMap(ctx, ref)
+	c.record(err)
+	return data, err
+}
+
+func (c *secretsClient) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	data, err := c.SecretsClient.GetAllSecrets(ctx, ref)
+	c.record(err)
+	return data, err
+}
+
+func (c *secretsClient) GetSecretWithVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, string, error) {
+	versioned, ok := c.SecretsClient.(esv1beta1.VersionedSecretsClient)
+	if !ok {
+		data, err := c.GetSecret(ctx, ref)
+		return data, "", err
+	}
+	data, version, err := versioned.GetSecretWithVersion(ctx, ref)
+	c.record(err)
+	return data, version, err
+}
+
+func (c *secretsClient) GetSecretWithMetadata(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, esv1beta1.SecretMetadata, error) {
+	withMetadata, ok := c.SecretsClient.(esv1beta1.MetadataSecretsClient)
+	if !ok {
+		data, version, err := c.GetSecretWithVersion(ctx, ref)
+		return data, esv1beta1.SecretMetadata{Version: version}, err
+	}
+	data, metadata, err := withMetadata.GetSecretWithMetadata(ctx, ref)
+	c.record(err)
+	return data, metadata, err
+}
+
+func (c *secretsClient) GetSecretKeys(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]string, error) {
+	lister, ok := c.SecretsClient.(esv1beta1.SecretKeysClient)
+	if !ok {
+		data, err := c.GetSecretMap(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys, nil
+	}
+	keys, err := lister.GetSecretKeys(ctx, ref)
+	c.record(err)
+	return keys, err
+}
+
+func (c *versionListingClient) ListSecretVersions(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]esv1beta1.SecretVersion, error) {
+	versions, err := c.lister.ListSecretVersions(ctx, ref)
+	c.record(err)
+	return versions, err
+}
@@ -0,0 +1,214 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := &Breaker{
+		now:        func() time.Time { return now },
+		threshold:  3,
+		minBackoff: time.Minute,
+		maxBackoff: 3 * time.Minute,
+	}
+
+	b.Failure()
+	b.Failure()
+	if ok, _ := b.Allow(); !ok {
+		t.Fatalf("breaker must not open before the threshold is reached")
+	}
+	b.Failure()
+	ok, retryIn := b.Allow()
+	if ok || retryIn != time.Minute {
+		t.Fatalf("expected breaker to open for 1m, got allowed=%v retryIn=%v", ok, retryIn)
+	}
+
+	// failures of in-flight calls don't extend the backoff
+	now = now.Add(30 * time.Second)
+	b.Failure()
+	if _, retryIn = b.Allow(); retryIn != 30*time.Second {
+		t.Fatalf("expected 30s remaining backoff, got %v", retryIn)
+	}
+
+	// a failing trial call doubles the backoff
+	now = now.Add(30 * time.Second)
+	if ok, _ = b.Allow(); !ok {
+		t.Fatalf("breaker must allow calls after the backoff")
+	}
+	b.Failure()
+	if _, retryIn = b.Allow(); retryIn != 2*time.Minute {
+		t.Fatalf("expected 2m backoff, got %v", retryIn)
+	}
+
+	// the backoff is capped
+	now = now.Add(2 * time.Minute)
+	b.Failure()
+	if _, retryIn = b.Allow(); retryIn != 3*time.Minute {
+		t.Fatalf("expected 3m backoff, got %v", retryIn)
+	}
+
+	// a single success closes the breaker
+	now = now.Add(3 * time.Minute)
+	b.Success()
+	b.Failure()
+	if ok, _ = b.Allow(); !ok {
+		t.Fatalf("breaker must be closed after a success")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	if NewRegistry(0, time.Second, time.Minute) != nil {
+		t.Fatalf("threshold 0 must disable the registry")
+	}
+	var disabled *Registry
+	if disabled.ForStore(&esv1beta1.SecretStore{}) != nil {
+		t.Fatalf("nil registry must not return breakers")
+	}
+
+	r := NewRegistry(1, time.Second, time.Minute)
+	storeA := &esv1beta1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "foo"}}
+	storeB := &esv1beta1.ClusterSecretStore{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+	if r.ForStore(storeA) != r.ForStore(storeA) {
+		t.Errorf("expected the same breaker for the same store")
+	}
+	if r.ForStore(storeA) == r.ForStore(storeB) {
+		t.Errorf("expected different breakers for different stores")
+	}
+
+	// a store recreated under the same name does not inherit an open breaker
+	storeA.UID = "uid-a"
+	opened := r.ForStore(storeA)
+	opened.Failure()
+	if ok, _ := r.ForStore(storeA).Allow(); ok {
+		t.Fatalf("expected the breaker to open")
+	}
+	recreated := storeA.DeepCopy()
+	recreated.UID = "uid-a2"
+	if ok, _ := r.ForStore(recreated).Allow(); !ok {
+		t.Errorf("expected a closed breaker for the recreated store")
+	}
+
+	r.Remove(types.NamespacedName{Namespace: "foo", Name: "a"})
+	r.Remove(types.NamespacedName{Name: "a"})
+	if len(r.breakers) != 0 {
+		t.Errorf("expected the breakers of deleted stores to be removed, got %d", len(r.breakers))
+	}
+	disabled.Remove(types.NamespacedName{Name: "a"})
+}
+
+func TestWrap(t *testing.T) {
+	b := &Breaker{
+		now:        time.Now,
+		threshold:  2,
+		minBackoff: time.Minute,
+		maxBackoff: time.Minute,
+	}
+	fakeClient := fake.New()
+	client := Wrap(fakeClient, b)
+	ref := esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"}
+
+	fakeClient.WithGetSecret(nil, esv1beta1.NoSecretErr)
+	_, _ = client.GetSecret(context.Background(), ref)
+	_, _ = client.GetSecret(context.Background(), ref)
+	if ok, _ := b.Allow(); !ok {
+		t.Fatalf("missing secrets must not open the breaker")
+	}
+
+	fakeClient.WithGetSecret(nil, esv1beta1.RetryAfterError{Err: errors.New("denied"), RetryAfter: time.Second})
+	_, _ = client.GetSecret(context.Background(), ref)
+	_, _ = client.GetSecret(context.Background(), ref)
+	if ok, _ := b.Allow(); !ok {
+		t.Fatalf("errors retried by the provider must not open the breaker")
+	}
+
+	fakeClient.WithGetSecret(nil, errors.New("boom"))
+	_, _ = client.GetSecret(context.Background(), ref)
+	fakeClient.WithGetSecretMap(nil, errors.New("boom"))
+	_, _ = client.GetSecretMap(context.Background(), ref)
+	if ok, _ := b.Allow(); ok {
+		t.Fatalf("expected breaker to open after consecutive errors")
+	}
+
+	if Wrap(fakeClient, nil) != esv1beta1.SecretsClient(fakeClient) {
+		t.Errorf("expected client to be returned as-is without breaker")
+	}
+}
+
+func TestWrapGetSecretKeys(t *testing.T) {
+	fakeClient := fake.New()
+	client := Wrap(fakeClient, &Breaker{now: time.Now, threshold: 1, minBackoff: time.Minute, maxBackoff: time.Minute})
+	lister, ok := client.(esv1beta1.SecretKeysClient)
+	if !ok {
+		t.Fatalf("expected wrapped client to list secret keys")
+	}
+
+	// clients without support for listing keys fall back to GetSecretMap
+	fakeClient.WithGetSecretMap(map[string][]byte{"b": nil, "a": nil}, nil)
+	keys, err := lister.GetSecretKeys(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("expected sorted keys [a b], got %v", keys)
+	}
+}
+
+type versionListingFake struct {
+	*fake.Client
+	versions []esv1beta1.SecretVersion
+	err      error
+}
+
+func (c *versionListingFake) ListSecretVersions(context.Context, esv1beta1.ExternalSecretDataRemoteRef) ([]esv1beta1.SecretVersion, error) {
+	return c.versions, c.err
+}
+
+func TestWrapListSecretVersions(t *testing.T) {
+	b := &Breaker{now: time.Now, threshold: 1, minBackoff: time.Minute, maxBackoff: time.Minute}
+	if _, ok := Wrap(fake.New(), b).(esv1beta1.SecretVersionLister); ok {
+		t.Fatalf("wrapped client must not list versions if the provider does not")
+	}
+
+	fakeClient := &versionListingFake{Client: fake.New(), versions: []esv1beta1.SecretVersion{{Version: "2"}, {Version: "1"}}}
+	lister, ok := Wrap(fakeClient, b).(esv1beta1.SecretVersionLister)
+	if !ok {
+		t.Fatalf("expected wrapped client to list versions")
+	}
+	ref := esv1beta1.ExternalSecretDataRemoteRef{Key: "foo", Version: "latest-1"}
+	versions, err := lister.ListSecretVersions(context.Background(), ref)
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("unexpected result: %v, %v", versions, err)
+	}
+
+	fakeClient.err = errors.New("boom")
+	if _, err := lister.ListSecretVersions(context.Background(), ref); err == nil {
+		t.Fatalf("expected the error of the provider")
+	}
+	if ok, _ := b.Allow(); ok {
+		t.Errorf("expected failed version listing to open the breaker")
+	}
+}
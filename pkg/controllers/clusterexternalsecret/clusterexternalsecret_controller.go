@@ -16,6 +16,7 @@ package clusterexternalsecret
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"time"
 
@@ -53,6 +54,7 @@ const (
 	errSecretAlreadyExists  = "external secret already exists in namespace"
 	errNamespacesFailed     = "one or more namespaces failed"
 	errFailedToDelete       = "external secret in non matching namespace could not be deleted"
+	errNamespaceVariables   = "could not substitute namespace variables: %v"
 )
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -144,12 +146,17 @@ func (r *Reconciler) resolveExternalSecret(ctx context.Context, clusterExternalS
 		return errSetCtrlReference, err
 	}
 
+	spec, err := substituteNamespaceVariables(clusterExternalSecret.Spec.ExternalSecretSpec, &namespace)
+	if err != nil {
+		return fmt.Sprintf(errNamespaceVariables, err), err
+	}
+
 	externalSecret := esv1beta1.ExternalSecret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      esName,
 			Namespace: namespace.Name,
 		},
-		Spec: clusterExternalSecret.Spec.ExternalSecretSpec,
+		Spec: spec,
 	}
 
 	if err := controllerutil.SetControllerReference(clusterExternalSecret, &externalSecret, r.Scheme); err != nil {
@@ -157,7 +164,7 @@ func (r *Reconciler) resolveExternalSecret(ctx context.Context, clusterExternalS
 	}
 
 	mutateFunc := func() error {
-		externalSecret.Spec = clusterExternalSecret.Spec.ExternalSecretSpec
+		externalSecret.Spec = spec
 		return nil
 	}
 
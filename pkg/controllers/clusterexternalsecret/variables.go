@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errUnknownVariable = "unknown variable ${%s}"
+	errMissingLabel    = "namespace %s has no label %q"
+	errMissingAnnot    = "namespace %s has no annotation %q"
+)
+
+// variableRegexp matches variables like ${namespace.labels.team}.
+// Label and annotation keys can not contain a closing brace.
+var variableRegexp = regexp.MustCompile(`\$\{(namespace\.[^}]+)\}`)
+
+// substituteNamespaceVariables returns a copy of spec with the variables
+// ${namespace.name}, ${namespace.labels.<key>} and ${namespace.annotations.<key>}
+// in all string values replaced by the name, labels and annotations of the namespace.
+// It is an error to reference a label or annotation the namespace does not have.
+func substituteNamespaceVariables(spec esv1beta1.ExternalSecretSpec, namespace *v1.Namespace) (esv1beta1.ExternalSecretSpec, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return spec, err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return spec, err
+	}
+	obj, err = substituteValue(obj, namespace)
+	if err != nil {
+		return spec, err
+	}
+	raw, err = json.Marshal(obj)
+	if err != nil {
+		return spec, err
+	}
+	var out esv1beta1.ExternalSecretSpec
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return spec, err
+	}
+	return out, nil
+}
+
+func substituteValue(value interface{}, namespace *v1.Namespace) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return substituteString(v, namespace)
+	case map[string]interface{}:
+		for key, elem := range v {
+			out, err := substituteValue(elem, namespace)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = out
+		}
+	case []interface{}:
+		for i, elem := range v {
+			out, err := substituteValue(elem, namespace)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = out
+		}
+	}
+	return value, nil
+}
+
+func substituteString(s string, namespace *v1.Namespace) (string, error) {
+	var err error
+	out := variableRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		name := variableRegexp.FindStringSubmatch(match)[1]
+		value, resolveErr := resolveVariable(name, namespace)
+		if resolveErr != nil && err == nil {
+			err = resolveErr
+		}
+		return value
+	})
+	return out, err
+}
+
+func resolveVariable(name string, namespace *v1.Namespace) (string, error) {
+	switch {
+	case name == "namespace.name":
+		return namespace.Name, nil
+	case strings.HasPrefix(name, "namespace.labels."):
+		key := strings.TrimPrefix(name, "namespace.labels.")
+		value, ok := namespace.Labels[key]
+		if !ok {
+			return "", fmt.Errorf(errMissingLabel, namespace.Name, key)
+		}
+		return value, nil
+	case strings.HasPrefix(name, "namespace.annotations."):
+		key := strings.TrimPrefix(name, "namespace.annotations.")
+		value, ok := namespace.Annotations[key]
+		if !ok {
+			return "", fmt.Errorf(errMissingAnnot, namespace.Name, key)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf(errUnknownVariable, name)
+}
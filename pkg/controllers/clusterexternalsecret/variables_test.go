@@ -0,0 +1,86 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestSubstituteNamespaceVariables(t *testing.T) {
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a-prod",
+			Labels:      map[string]string{"example.com/team": "team-a"},
+			Annotations: map[string]string{"cost-center": "1234"},
+		},
+	}
+	spec := esv1beta1.ExternalSecretSpec{
+		RefreshInterval: &metav1.Duration{Duration: time.Hour},
+		SecretStoreRef:  esv1beta1.SecretStoreRef{Name: "vault", Kind: "ClusterSecretStore"},
+		Target: esv1beta1.ExternalSecretTarget{
+			Name: "db",
+			Template: &esv1beta1.ExternalSecretTemplate{
+				Data: map[string]string{
+					"dsn": "postgres://{{ .user }}@${namespace.name}.db",
+				},
+			},
+		},
+		Data: []esv1beta1.ExternalSecretData{{
+			SecretKey: "user",
+			RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{
+				Key: "teams/${namespace.labels.example.com/team}/${namespace.annotations.cost-center}/db",
+			},
+		}},
+	}
+
+	got, err := substituteNamespaceVariables(spec, namespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := spec.DeepCopy()
+	want.Target.Template.Data["dsn"] = "postgres://{{ .user }}@team-a-prod.db"
+	want.Data[0].RemoteRef.Key = "teams/team-a/1234/db"
+	if !cmp.Equal(got, *want) {
+		t.Errorf("unexpected spec: %s", cmp.Diff(*want, got))
+	}
+	if spec.Data[0].RemoteRef.Key != "teams/${namespace.labels.example.com/team}/${namespace.annotations.cost-center}/db" {
+		t.Errorf("the spec of the ClusterExternalSecret must not be modified")
+	}
+}
+
+func TestSubstituteNamespaceVariablesErrors(t *testing.T) {
+	namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	tests := map[string]string{
+		"${namespace.labels.team}":       `has no label "team"`,
+		"${namespace.annotations.owner}": `has no annotation "owner"`,
+		"${namespace.uid}":               "unknown variable",
+	}
+	for key, wantErr := range tests {
+		spec := esv1beta1.ExternalSecretSpec{
+			Data: []esv1beta1.ExternalSecretData{{RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: key}}},
+		}
+		if _, err := substituteNamespaceVariables(spec, namespace); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", key, wantErr, err)
+		}
+	}
+}
@@ -54,6 +54,8 @@ const (
 	errResNotReady       = "resource not ready: %s"
 	errSubsetsNotReady   = "subsets not ready"
 	errAddressesNotReady = "addresses not ready"
+	errCertNotValid      = "certificate in secret %s/%s is not valid: %w"
+	errSecretMalformed   = "cert secret is not well-formed, missing %s"
 )
 
 type Reconciler struct {
@@ -70,6 +72,9 @@ type Reconciler struct {
 	CAName          string
 	CAOrganization  string
 	RequeueInterval time.Duration
+	// ExternalCerts disables the certificate generation. The certificates
+	// are expected to be provided in the secret, e.g. by cert-manager.
+	ExternalCerts bool
 
 	// the controller is ready when all crds are injected
 	rdyMu          *sync.Mutex
@@ -157,6 +162,25 @@ func (r *Reconciler) ReadyCheck(_ *http.Request) error {
 	return nil
 }
 
+// CertCheck reviews if the webhook certificates stored in the secret
+// are valid until the next reconcile, when they would be refreshed.
+func (r *Reconciler) CertCheck(_ *http.Request) error {
+	var secret corev1.Secret
+	err := r.Get(context.TODO(), types.NamespacedName{
+		Name:      r.SecretName,
+		Namespace: r.SecretNamespace,
+	}, &secret)
+	if err != nil {
+		return err
+	}
+	at := time.Now().Add(r.RequeueInterval)
+	dnsName := fmt.Sprintf("%v.%v.svc", r.SvcName, r.SvcNamespace)
+	if _, err := ValidCert(secret.Data[caCertName], secret.Data[certName], secret.Data[keyName], dnsName, at); err != nil {
+		return fmt.Errorf(errCertNotValid, r.SecretNamespace, r.SecretName, err)
+	}
+	return nil
+}
+
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("custom-resource-definition")
 	return ctrl.NewControllerManagedBy(mgr).
@@ -187,6 +211,16 @@ func (r *Reconciler) updateCRD(ctx context.Context, req ctrl.Request) error {
 		return err
 	}
 	r.dnsName = fmt.Sprintf("%v.%v.svc", r.SvcName, r.SvcNamespace)
+	if r.ExternalCerts {
+		caCert, ok := secret.Data[caCertName]
+		if !ok {
+			return fmt.Errorf(errSecretMalformed, caCertName)
+		}
+		if err := injectCert(&updatedResource, caCert); err != nil {
+			return err
+		}
+		return r.Update(ctx, &updatedResource)
+	}
 	need, err := r.refreshCertIfNeeded(&secret)
 	if err != nil {
 		return err
@@ -353,11 +387,11 @@ func (r *Reconciler) refreshCerts(refreshCA bool, secret *corev1.Secret) error {
 func buildArtifactsFromSecret(secret *corev1.Secret) (*KeyPairArtifacts, error) {
 	caPem, ok := secret.Data[caCertName]
 	if !ok {
-		return nil, fmt.Errorf("cert secret is not well-formed, missing %s", caCertName)
+		return nil, fmt.Errorf(errSecretMalformed, caCertName)
 	}
 	keyPem, ok := secret.Data[caKeyName]
 	if !ok {
-		return nil, fmt.Errorf("cert secret is not well-formed, missing %s", caKeyName)
+		return nil, fmt.Errorf(errSecretMalformed, caKeyName)
 	}
 	caDer, _ := pem.Decode(caPem)
 	if caDer == nil {
@@ -109,6 +109,46 @@ func TestUpdateCRD(t *testing.T) {
 	}
 }
 
+func TestUpdateCRDExternalCerts(t *testing.T) {
+	rec := newReconciler()
+	rec.ExternalCerts = true
+	svc := newService()
+	secret := newSecret()
+	crd := newCRD()
+	c := client.NewClientBuilder().WithObjects(&svc, &secret, &crd).Build()
+	rec.Client = c
+	ctx := context.Background()
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name: "one",
+		},
+	}
+	if err := rec.updateCRD(ctx, req); err == nil {
+		t.Error("expected failure due to missing ca cert, got success")
+	}
+
+	secret.Data = map[string][]byte{caCertName: []byte("ca-bundle")}
+	if err := c.Update(ctx, &secret); err != nil {
+		t.Fatalf("could not update secret: %v", err)
+	}
+	if err := rec.updateCRD(ctx, req); err != nil {
+		t.Errorf("Failed updating CRD: %v", err)
+	}
+	var updated apiextensionsv1.CustomResourceDefinition
+	if err := c.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("could not get CRD: %v", err)
+	}
+	if !bytes.Equal(updated.Spec.Conversion.Webhook.ClientConfig.CABundle, []byte("ca-bundle")) {
+		t.Errorf("expected ca bundle to be injected, got %q", updated.Spec.Conversion.Webhook.ClientConfig.CABundle)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, &secret); err != nil {
+		t.Fatalf("could not get secret: %v", err)
+	}
+	if len(secret.Data) != 1 {
+		t.Errorf("expected secret to be left untouched, got keys: %v", len(secret.Data))
+	}
+}
+
 func TestInjectSvcToConversionWebhook(t *testing.T) {
 	svc := newService()
 	crd := newCRD()
@@ -242,6 +282,35 @@ func TestRefreshCertIfNeeded(t *testing.T) {
 	}
 }
 
+func TestCertCheck(t *testing.T) {
+	rec := newReconciler()
+	secret := newSecret()
+	rec.Client = client.NewClientBuilder().WithObjects(&secret).Build()
+	rec.RequeueInterval = time.Hour
+	if err := rec.CertCheck(nil); err == nil {
+		t.Error("expected failure due to missing certificate, got success")
+	}
+
+	rec.dnsName = "foo.default.svc"
+	caArtifacts, err := rec.CreateCACert(time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf(failedCreateCaCerts, err)
+	}
+	certPEM, keyPEM, err := rec.CreateCertPEM(caArtifacts, time.Now().AddDate(0, 0, -1), time.Now().Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf(failedCreateServerCerts, err)
+	}
+	populateSecret(certPEM, keyPEM, caArtifacts, &secret)
+	rec.Client = client.NewClientBuilder().WithObjects(&secret).Build()
+	if err := rec.CertCheck(nil); err != nil {
+		t.Errorf("error checking valid cert: %v", err)
+	}
+	rec.RequeueInterval = 3 * time.Hour
+	if err := rec.CertCheck(nil); err == nil {
+		t.Error("expected failure due to certificate expiring before the next reconcile, got success")
+	}
+}
+
 func TestCheckCerts(t *testing.T) {
 	rec := newReconciler()
 	rec.dnsName = dnsName
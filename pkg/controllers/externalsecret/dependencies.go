@@ -0,0 +1,146 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	// dependsOnIndex indexes ExternalSecrets by the names of the ExternalSecrets they depend on.
+	dependsOnIndex = "spec.dependsOn.name"
+
+	errGetDependency      = "could not get dependency %q: %w"
+	errDependencyNotFound = "waiting for dependency %q, it does not exist"
+	errDependencyNotReady = "waiting for dependency %q to become ready"
+	errDependencyCycle    = "dependency cycle: %s"
+)
+
+// dependencyError is returned if an ExternalSecret must not be synced yet.
+type dependencyError struct {
+	reason string
+	msg    string
+}
+
+func (e *dependencyError) Error() string {
+	return e.msg
+}
+
+// checkDependencies returns a dependencyError if the dependencies of the ExternalSecret
+// form a cycle or if one of them is not Ready.
+func checkDependencies(ctx context.Context, cl client.Client, es *esv1beta1.ExternalSecret) error {
+	if len(es.Spec.DependsOn) == 0 {
+		return nil
+	}
+	deps := map[string]*esv1beta1.ExternalSecret{es.Name: es}
+	if cycle, err := findDependencyCycle(ctx, cl, es.Namespace, es.Name, deps, nil); err != nil {
+		return err
+	} else if cycle != nil {
+		return &dependencyError{
+			reason: esv1beta1.ReasonDependencyCycle,
+			msg:    fmt.Sprintf(errDependencyCycle, strings.Join(cycle, " -> ")),
+		}
+	}
+	for _, dep := range es.Spec.DependsOn {
+		obj := deps[dep.Name]
+		if obj == nil {
+			return &dependencyError{reason: esv1beta1.ReasonDependencyNotReady, msg: fmt.Sprintf(errDependencyNotFound, dep.Name)}
+		}
+		cond := GetExternalSecretCondition(obj.Status, esv1beta1.ExternalSecretReady)
+		if cond == nil || cond.Status != v1.ConditionTrue {
+			return &dependencyError{reason: esv1beta1.ReasonDependencyNotReady, msg: fmt.Sprintf(errDependencyNotReady, dep.Name)}
+		}
+	}
+	return nil
+}
+
+// findDependencyCycle walks the dependencies of name depth-first and returns the first cycle
+// that leads back to an ExternalSecret on the current path. The root is passed in deps,
+// visited ExternalSecrets are added to it, missing ones are added as nil.
+func findDependencyCycle(ctx context.Context, cl client.Client, namespace, name string,
+	deps map[string]*esv1beta1.ExternalSecret, path []string) ([]string, error) {
+	for i, p := range path {
+		if p == name {
+			return append(append([]string(nil), path[i:]...), name), nil
+		}
+	}
+	obj, visited := deps[name]
+	if visited && len(path) > 0 {
+		// already walked from another branch without a cycle
+		return nil, nil
+	}
+	if !visited {
+		var dep esv1beta1.ExternalSecret
+		err := cl.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &dep)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf(errGetDependency, name, err)
+		}
+		if err == nil {
+			obj = &dep
+		}
+		deps[name] = obj
+	}
+	if obj == nil {
+		return nil, nil
+	}
+	path = append(path, name)
+	for _, dep := range obj.Spec.DependsOn {
+		cycle, err := findDependencyCycle(ctx, cl, namespace, dep.Name, deps, path)
+		if err != nil || cycle != nil {
+			return cycle, err
+		}
+	}
+	return nil, nil
+}
+
+func indexDependsOn(obj client.Object) []string {
+	es := obj.(*esv1beta1.ExternalSecret)
+	names := make([]string, 0, len(es.Spec.DependsOn))
+	for _, dep := range es.Spec.DependsOn {
+		names = append(names, dep.Name)
+	}
+	return names
+}
+
+// dependentsHandler enqueues the ExternalSecrets that depend on the changed ExternalSecret.
+func dependentsHandler(cl client.Client, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+		var list esv1beta1.ExternalSecretList
+		err := cl.List(context.Background(), &list, client.InNamespace(obj.GetNamespace()), client.MatchingFields{dependsOnIndex: obj.GetName()})
+		if err != nil {
+			log.Error(err, "unable to list dependent external secrets", "ExternalSecret", client.ObjectKeyFromObject(obj))
+			return nil
+		}
+		requests := make([]ctrl.Request, 0, len(list.Items))
+		for i := range list.Items {
+			requests = append(requests, ctrl.Request{
+				NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name},
+			})
+		}
+		return requests
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	es := func(name string, ready bool, deps ...string) *esv1beta1.ExternalSecret {
+		obj := &esv1beta1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		for _, dep := range deps {
+			obj.Spec.DependsOn = append(obj.Spec.DependsOn, esv1beta1.ExternalSecretDependency{Name: dep})
+		}
+		if ready {
+			SetExternalSecretCondition(obj, *NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretSynced, ""))
+		}
+		return obj
+	}
+
+	scheme := runtime.NewScheme()
+	if err := esv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		es("ca", true),
+		es("pending", false),
+		es("leaf", true, "ca"),
+		es("cycle-a", true, "cycle-b"),
+		es("cycle-b", true, "cycle-c"),
+		es("cycle-c", true, "cycle-a"),
+	).Build()
+
+	tests := []struct {
+		name       string
+		es         *esv1beta1.ExternalSecret
+		wantReason string
+	}{
+		{
+			name: "no dependencies",
+			es:   es("app", false),
+		},
+		{
+			name: "ready dependencies",
+			es:   es("app", false, "ca", "leaf"),
+		},
+		{
+			name:       "dependency not ready",
+			es:         es("app", false, "ca", "pending"),
+			wantReason: esv1beta1.ReasonDependencyNotReady,
+		},
+		{
+			name:       "missing dependency",
+			es:         es("app", false, "missing"),
+			wantReason: esv1beta1.ReasonDependencyNotReady,
+		},
+		{
+			name:       "direct cycle",
+			es:         es("cycle-a", true, "cycle-b"),
+			wantReason: esv1beta1.ReasonDependencyCycle,
+		},
+		{
+			name:       "cycle between dependencies",
+			es:         es("app", false, "ca", "cycle-b"),
+			wantReason: esv1beta1.ReasonDependencyCycle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDependencies(context.Background(), kube, tt.es)
+			var depErr *dependencyError
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &depErr) {
+				t.Fatalf("expected dependencyError, got %v", err)
+			}
+			if depErr.reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q (%v)", depErr.reason, tt.wantReason, depErr)
+			}
+		})
+	}
+}
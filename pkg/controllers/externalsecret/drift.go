@@ -0,0 +1,91 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	// targetNameIndex indexes ExternalSecrets by the name of their target Secret.
+	targetNameIndex = "spec.target.name"
+
+	msgDriftDetected = "target secret was changed or deleted, restoring it"
+)
+
+func indexTargetName(obj client.Object) []string {
+	es := obj.(*esv1beta1.ExternalSecret)
+	if es.Spec.Target.Name != "" {
+		return []string{es.Spec.Target.Name}
+	}
+	return []string{es.Name}
+}
+
+// targetSecretHandler enqueues the ExternalSecrets that target the changed Secret,
+// regardless of whether they own it. A Secret named <name>-N also enqueues
+// the ExternalSecrets that split their target <name> into chunks.
+func targetSecretHandler(cl client.Client, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+		requests := listTargeting(cl, log, obj, obj.GetName(), false)
+		if name, ok := splitSecretName(obj.GetName()); ok {
+			requests = append(requests, listTargeting(cl, log, obj, name, true)...)
+		}
+		return requests
+	})
+}
+
+// listTargeting returns the ExternalSecrets in the namespace of obj with the given target name.
+func listTargeting(cl client.Client, log logr.Logger, obj client.Object, name string, splitOnly bool) []ctrl.Request {
+	var list esv1beta1.ExternalSecretList
+	err := cl.List(context.Background(), &list, client.InNamespace(obj.GetNamespace()), client.MatchingFields{targetNameIndex: name})
+	if err != nil {
+		log.Error(err, "unable to list external secrets targeting secret", "Secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	requests := make([]ctrl.Request, 0, len(list.Items))
+	for i := range list.Items {
+		if splitOnly && list.Items[i].Spec.Target.SizeLimitPolicy != esv1beta1.SizeLimitPolicySplit {
+			continue
+		}
+		requests = append(requests, ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name},
+		})
+	}
+	return requests
+}
+
+// isDrifted reports whether the target Secret of a synced ExternalSecret
+// was changed or deleted by someone else.
+// Secrets written with creationPolicy=Merge are shared with other writers,
+// only Secrets created by the controller are considered.
+func isDrifted(es esv1beta1.ExternalSecret, existingSecret v1.Secret) bool {
+	switch es.Spec.Target.CreationPolicy {
+	case esv1beta1.CreatePolicyMerge, esv1beta1.CreatePolicyNone:
+		return false
+	}
+	if es.Status.SyncedResourceVersion == "" || !hasSyncedCondition(es) {
+		return false
+	}
+	return !isSecretValid(existingSecret)
+}
@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+func TestIsDrifted(t *testing.T) {
+	data := map[string][]byte{"password": []byte("secret")}
+	synced := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:         types.UID("uid"),
+			Annotations: map[string]string{esv1beta1.AnnotationDataHash: utils.ObjectHash(data)},
+		},
+		Data: data,
+	}
+	changed := *synced.DeepCopy()
+	changed.Data["password"] = []byte("changed")
+
+	es := func(policy esv1beta1.ExternalSecretCreationPolicy, isSynced bool) esv1beta1.ExternalSecret {
+		obj := esv1beta1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "es"}}
+		obj.Spec.Target.CreationPolicy = policy
+		if isSynced {
+			obj.Status.SyncedResourceVersion = getResourceVersion(obj)
+			SetExternalSecretCondition(&obj, *NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretSynced, ""))
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name   string
+		es     esv1beta1.ExternalSecret
+		secret v1.Secret
+		want   bool
+	}{
+		{name: "unchanged", es: es(esv1beta1.CreatePolicyOwner, true), secret: synced},
+		{name: "changed data", es: es(esv1beta1.CreatePolicyOwner, true), secret: changed, want: true},
+		{name: "deleted", es: es(esv1beta1.CreatePolicyOwner, true), secret: v1.Secret{}, want: true},
+		{name: "orphan changed data", es: es(esv1beta1.CreatePolicyOrphan, true), secret: changed, want: true},
+		{name: "merge is shared", es: es(esv1beta1.CreatePolicyMerge, true), secret: changed},
+		{name: "never synced", es: es(esv1beta1.CreatePolicyOwner, false), secret: v1.Secret{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDrifted(tt.es, tt.secret); got != tt.want {
+				t.Errorf("isDrifted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexTargetName(t *testing.T) {
+	es := &esv1beta1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "es"}}
+	if got := indexTargetName(es); len(got) != 1 || got[0] != "es" {
+		t.Errorf("expected the ExternalSecret name, got %v", got)
+	}
+	es.Spec.Target.Name = "target"
+	if got := indexTargetName(es); len(got) != 1 || got[0] != "target" {
+		t.Errorf("expected the target name, got %v", got)
+	}
+}
@@ -36,12 +36,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/circuitbreaker"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/diagnostics"
+	"github.com/external-secrets/external-secrets/pkg/redact"
 	// Loading registered providers.
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
 	"github.com/external-secrets/external-secrets/pkg/utils"
+	"github.com/external-secrets/external-secrets/pkg/versionexpr"
 )
 
 const (
@@ -61,6 +69,7 @@ const (
 	errStoreUsability        = "could not use store reference"
 	errStoreProvider         = "could not get store provider"
 	errStoreClient           = "could not get provider client"
+	errStoreCircuitOpen      = "too many consecutive provider errors for store %s, retrying in %s"
 	errGetExistingSecret     = "could not get existing secret: %w"
 	errCloseStoreClient      = "could not close provider client"
 	errSetCtrlReference      = "could not set ExternalSecret controller reference: %w"
@@ -68,6 +77,7 @@ const (
 	errGetSecretData         = "could not get secret data from provider"
 	errDeleteSecret          = "could not delete secret"
 	errApplyTemplate         = "could not apply template: %w"
+	errValidateSecret        = "secret data failed validation"
 	errExecTpl               = "could not execute template: %w"
 	errInvalidCreatePolicy   = "invalid creationPolicy=%s. Can not delete secret i do not own"
 	errPolicyMergeNotFound   = "the desired secret %s was not found. With creationPolicy=Merge the secret won't be created"
@@ -76,6 +86,20 @@ const (
 	errPolicyMergePatch      = "unable to patch secret %s: %w"
 	errTplCMMissingKey       = "error in configmap %s: missing key %s"
 	errTplSecMissingKey      = "error in secret %s: missing key %s"
+	errAssembleFile          = "could not assemble file %s: %w"
+	errFileMissingKey        = "missing key %s in provider data"
+	errRenderMetadata        = "could not render template metadata: %w"
+	errKeyNotAllowed         = "%s key %q is not allowed by the allowedKeyPrefixes of store %s"
+	errFindPathRequired      = "spec.dataFrom[%d].find.path is required by the allowedKeyPrefixes of store %s"
+	errVersionsUnsupported   = "version expression %q of key %s is not supported by the provider"
+	errListVersions          = "could not list versions of key %s: %w"
+
+	// reservedMetadataPrefix marks labels and annotations owned by the controller.
+	reservedMetadataPrefix = "reconcile.external-secrets.io/"
+
+	msgRefreshIntervalClamped = "refreshInterval %s is below the minimum of %s, using the minimum"
+	msgSuspended              = "reconciliation is suspended"
+	msgResumed                = "reconciliation is resumed"
 )
 
 // Reconciler reconciles a ExternalSecret object.
@@ -85,8 +109,13 @@ type Reconciler struct {
 	Scheme                    *runtime.Scheme
 	ControllerClass           string
 	RequeueInterval           time.Duration
+	MinRefreshInterval        time.Duration
+	RefreshJitterPercent      int
 	ClusterSecretStoreEnabled bool
 	EnableFloodGate           bool
+	CircuitBreakers           *circuitbreaker.Registry
+	NamespaceRateLimiter      *NamespaceRateLimiter
+	EnableDriftDetection      bool
 	recorder                  record.EventRecorder
 }
 
@@ -96,18 +125,26 @@ type Reconciler struct {
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("ExternalSecret", req.NamespacedName)
 
-	syncCallsMetricLabels := prometheus.Labels{"name": req.Name, "namespace": req.Namespace}
+	syncCallsMetricLabels := syncMetricLabels(req.Name, req.Namespace, esv1beta1.SecretStoreRef{})
 
 	start := time.Now()
 
-	defer externalSecretReconcileDuration.With(prometheus.Labels{
-		"name":      req.Name,
-		"namespace": req.Namespace,
-	}).Set(float64(time.Since(start)))
+	defer func() {
+		if !perObjectMetricsEnabled() {
+			return
+		}
+		externalSecretReconcileDuration.With(prometheus.Labels{
+			"name":      req.Name,
+			"namespace": req.Namespace,
+		}).Set(float64(time.Since(start)))
+	}()
 
 	var externalSecret esv1beta1.ExternalSecret
 
 	err := r.Get(ctx, req.NamespacedName, &externalSecret)
+	if err == nil {
+		syncCallsMetricLabels = syncMetricLabels(req.Name, req.Namespace, externalSecret.Spec.SecretStoreRef)
+	}
 	if apierrors.IsNotFound(err) {
 		syncCallsTotal.With(syncCallsMetricLabels).Inc()
 		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretDeleted, v1.ConditionFalse, esv1beta1.ConditionReasonSecretDeleted, "Secret was deleted")
@@ -129,6 +166,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// the refresh interval is only changed in memory to protect the provider,
+	// the spec of the ExternalSecret is not updated.
+	if requested, ok := clampRefreshInterval(&externalSecret, r.RequeueInterval, r.MinRefreshInterval); ok {
+		msg := fmt.Sprintf(msgRefreshIntervalClamped, requested, r.MinRefreshInterval)
+		log.V(1).Info(msg)
+		// the event is written once per change of the ExternalSecret, not on every refresh
+		if externalSecret.Status.SyncedResourceVersion != getResourceVersion(externalSecret) {
+			r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonRefreshIntervalClamped, msg)
+		}
+	}
+
 	// patch status when done processing
 	p := client.MergeFrom(externalSecret.DeepCopy())
 	defer func() {
@@ -138,10 +186,51 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}()
 
+	// a suspended ExternalSecret keeps its Secret as it is
+	// and is reconciled again once spec.suspend is unset.
+	if externalSecret.Spec.Suspend {
+		if !isSuspended(&externalSecret) {
+			log.Info(msgSuspended)
+			r.recorder.Event(&externalSecret, v1.EventTypeNormal, esv1beta1.ReasonSuspended, msgSuspended)
+		}
+		conditionSuspended := NewExternalSecretCondition(esv1beta1.ExternalSecretSuspended, v1.ConditionTrue, esv1beta1.ReasonSuspended, msgSuspended)
+		SetExternalSecretCondition(&externalSecret, *conditionSuspended)
+		return ctrl.Result{}, nil
+	}
+	if isSuspended(&externalSecret) {
+		log.Info(msgResumed)
+		r.recorder.Event(&externalSecret, v1.EventTypeNormal, esv1beta1.ReasonResumed, msgResumed)
+		conditionSuspended := NewExternalSecretCondition(esv1beta1.ExternalSecretSuspended, v1.ConditionFalse, esv1beta1.ReasonResumed, msgResumed)
+		SetExternalSecretCondition(&externalSecret, *conditionSuspended)
+	}
+
+	// dependencies are synced first, a blocked ExternalSecret keeps its Secret as it is
+	// and is reconciled again once a dependency changes.
+	err = checkDependencies(ctx, r.Client, &externalSecret)
+	var depErr *dependencyError
+	if errors.As(err, &depErr) {
+		log.V(1).Info(depErr.Error())
+		eventType := v1.EventTypeNormal
+		if depErr.reason == esv1beta1.ReasonDependencyCycle {
+			eventType = v1.EventTypeWarning
+			AppendSyncError(&externalSecret, depErr.reason, depErr)
+		}
+		r.recorder.Event(&externalSecret, eventType, depErr.reason, depErr.Error())
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, depErr.reason, depErr.Error())
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		log.Error(err, "could not check dependencies")
+		syncCallsError.With(syncCallsMetricLabels).Inc()
+		return ctrl.Result{}, err
+	}
+
 	store, err := r.getStore(ctx, &externalSecret)
 	if err != nil {
 		log.Error(err, errStoreRef)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonInvalidStoreRef, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonInvalidStoreRef, err)
 		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreRef)
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		syncCallsError.With(syncCallsMetricLabels).Inc()
@@ -156,10 +245,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// the prefixes are checked before a client is created,
+	// so no request with a forbidden key reaches the provider.
+	if err = checkKeyPrefixes(store, &externalSecret); err != nil {
+		log.Error(err, "key not allowed")
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonKeyNotAllowed, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonKeyNotAllowed, err)
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ReasonKeyNotAllowed, err.Error())
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		syncCallsError.With(syncCallsMetricLabels).Inc()
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
 	if r.EnableFloodGate {
 		if err = assertStoreIsUsable(store); err != nil {
 			log.Error(err, errStoreUsability)
 			r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUnavailableStore, err.Error())
+			AppendSyncError(&externalSecret, esv1beta1.ReasonUnavailableStore, err)
 			conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreUsability)
 			SetExternalSecretCondition(&externalSecret, *conditionSynced)
 			syncCallsError.With(syncCallsMetricLabels).Inc()
@@ -178,6 +280,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if externalSecret.Spec.RefreshInterval != nil {
 		refreshInt = externalSecret.Spec.RefreshInterval.Duration
 	}
+	// the next refresh is delayed, not the check below,
+	// so a delayed requeue always finds the refresh due.
+	refreshInt = jitterRefreshInterval(&externalSecret, refreshInt, r.RefreshJitterPercent)
 
 	// Target Secret Name should default to the ExternalSecret name if not explicitly specified
 	secretName := externalSecret.Spec.Target.Name
@@ -191,10 +296,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		Name:      secretName,
 		Namespace: externalSecret.Namespace,
 	}, &existingSecret)
+	// a split Secret is valid if all of its chunks are
+	if apierrors.IsNotFound(err) && externalSecret.Spec.Target.SizeLimitPolicy == esv1beta1.SizeLimitPolicySplit {
+		existingSecret, err = r.getSplitSecret(ctx, externalSecret.Namespace, secretName)
+	}
 	if err != nil && !apierrors.IsNotFound(err) {
 		log.Error(err, errGetExistingSecret)
 	}
 
+	// a Secret that was changed or deleted out-of-band is restored right away,
+	// the refresh below is forced by the invalid Secret.
+	if r.EnableDriftDetection && !shouldRefresh(externalSecret) && isDrifted(externalSecret, existingSecret) {
+		log.Info(msgDriftDetected, "Secret", secretName)
+		r.recorder.Event(&externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDriftDetected, msgDriftDetected)
+		driftDetected.WithLabelValues(req.Namespace).Inc()
+	}
+
 	// refresh should be skipped if
 	// 1. resource generation hasn't changed
 	// 2. refresh interval is 0
@@ -211,20 +328,56 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}, nil
 	}
 
+	// syncs of a namespace that exceeds its rate are deferred
+	// without touching the status to keep other namespaces responsive.
+	if delay := r.NamespaceRateLimiter.Reserve(req.NamespacedName); delay > 0 {
+		log.V(1).Info("namespace rate limit exceeded, deferring sync", "delay", delay)
+		namespaceThrottled.WithLabelValues(req.Namespace).Inc()
+		namespaceThrottleDelay.WithLabelValues(req.Namespace).Observe(delay.Seconds())
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// short-circuit while the provider keeps failing
+	breaker := r.CircuitBreakers.ForStore(store)
+	if breaker != nil {
+		if ok, retryIn := breaker.Allow(); !ok {
+			msg := fmt.Sprintf(errStoreCircuitOpen, store.GetName(), retryIn.Round(time.Second))
+			log.Info(msg)
+			r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUnavailableStore, msg)
+			AppendSyncError(&externalSecret, esv1beta1.ReasonUnavailableStore, errors.New(msg))
+			conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, msg)
+			SetExternalSecretCondition(&externalSecret, *conditionSynced)
+			syncCallsError.With(syncCallsMetricLabels).Inc()
+			return ctrl.Result{RequeueAfter: retryIn}, nil
+		}
+	}
+
+	// the store is in use by this reconcile until the client is closed
+	defer diagnostics.TrackRequest(store)()
+
 	// secret client is created only if we are going to refresh
 	// this skip an unnecessary check/request in the case we are not going to do anything
 	secretClient, err := storeProvider.NewClient(ctx, store, r.Client, req.Namespace)
+	// provider errors may contain secret material, they are redacted
+	// before they are written to conditions, events or logs.
+	err = redact.Error(err)
 	if err != nil {
+		if breaker != nil {
+			breaker.Failure()
+		}
 		log.Error(err, errStoreClient)
 		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreClient)
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonProviderClientConfig, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonProviderClientConfig, err)
 		syncCallsError.With(syncCallsMetricLabels).Inc()
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
+	secretClient = circuitbreaker.Wrap(secretClient, breaker)
+
 	defer func() {
-		err = secretClient.Close(ctx)
+		err = redact.Error(secretClient.Close(ctx))
 		if err != nil {
 			log.Error(err, errCloseStoreClient)
 		}
@@ -239,11 +392,46 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		Data:      make(map[string][]byte),
 	}
 
-	dataMap, err := r.getProviderSecretData(ctx, secretClient, &externalSecret)
+	dataMap, secretMetadata, err := r.getProviderSecretData(ctx, store, secretClient, &externalSecret)
+	err = redact.Error(err)
+	var tooManyResultsErr esv1beta1.TooManyResultsError
+	if errors.As(err, &tooManyResultsErr) {
+		log.Error(err, errGetSecretData)
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonTooManyResults, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonTooManyResults, err)
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ReasonTooManyResults, err.Error())
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		syncCallsError.With(syncCallsMetricLabels).Inc()
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 	if err != nil {
 		log.Error(err, errGetSecretData)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
-		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errGetSecretData)
+		AppendSyncError(&externalSecret, esv1beta1.ReasonUpdateFailed, err)
+		message := errGetSecretData
+		// the missing permission is no secret material and helps to fix the error
+		var permErr esv1beta1.PermissionDeniedError
+		if errors.As(err, &permErr) {
+			message = fmt.Sprintf("%s: %s", errGetSecretData, permErr.Error())
+		}
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, message)
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		syncCallsError.With(syncCallsMetricLabels).Inc()
+		// the provider expects the error to resolve shortly
+		var retryErr esv1beta1.RetryAfterError
+		if errors.As(err, &retryErr) && retryErr.RetryAfter < requeueAfter {
+			return ctrl.Result{RequeueAfter: retryErr.RetryAfter}, nil
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// the limits are checked before the data is written,
+	// an oversized Secret is never sent to the API server.
+	if err = secretstore.CheckLimits(store, dataMap); err != nil {
+		log.Error(err, "limit exceeded")
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonLimitExceeded, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonLimitExceeded, err)
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ReasonLimitExceeded, err.Error())
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		syncCallsError.With(syncCallsMetricLabels).Inc()
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
@@ -260,18 +448,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				err := fmt.Errorf(errInvalidCreatePolicy, externalSecret.Spec.Target.CreationPolicy)
 				log.Error(err, errDeleteSecret)
 				r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
+				AppendSyncError(&externalSecret, esv1beta1.ReasonUpdateFailed, err)
 				conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errDeleteSecret)
 				SetExternalSecretCondition(&externalSecret, *conditionSynced)
 				syncCallsError.With(syncCallsMetricLabels).Inc()
 				return ctrl.Result{RequeueAfter: requeueAfter}, nil
 			}
-			err = r.Delete(ctx, secret)
-			if err != nil && !apierrors.IsNotFound(err) {
+			// the chunks of a split Secret are deleted as well
+			err = r.deleteSecret(ctx, &externalSecret, secret)
+			if err != nil {
 				log.Error(err, errDeleteSecret)
 				r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
+				AppendSyncError(&externalSecret, esv1beta1.ReasonUpdateFailed, err)
 				conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errDeleteSecret)
 				SetExternalSecretCondition(&externalSecret, *conditionSynced)
 				syncCallsError.With(syncCallsMetricLabels).Inc()
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
 			}
 
 			conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretDeleted, "secret deleted due to DeletionPolicy")
@@ -297,7 +489,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		if secret.Data == nil {
 			secret.Data = make(map[string][]byte)
 		}
-		err = r.applyTemplate(ctx, &externalSecret, secret, dataMap)
+		err = r.applyTemplate(ctx, &externalSecret, secret, dataMap, secretMetadata)
 		if err != nil {
 			return fmt.Errorf(errApplyTemplate, err)
 		}
@@ -314,23 +506,53 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				}
 			}
 		}
+		if err := validateSecretData(&externalSecret, secret.Data); err != nil {
+			return err
+		}
+		// the size is checked before the API server rejects the write with a less precise error
+		if externalSecret.Spec.Target.SizeLimitPolicy != esv1beta1.SizeLimitPolicySplit {
+			return checkSecretSize(secret.Data)
+		}
 		return nil
 	}
 
 	//nolint
-	switch externalSecret.Spec.Target.CreationPolicy {
-	case esv1beta1.CreatePolicyMerge:
+	switch {
+	case externalSecret.Spec.Target.SizeLimitPolicy == esv1beta1.SizeLimitPolicySplit:
+		err = r.writeSplitSecret(ctx, &externalSecret, secret, mutationFunc)
+	case externalSecret.Spec.Target.CreationPolicy == esv1beta1.CreatePolicyMerge:
 		err = patchSecret(ctx, r.Client, r.Scheme, secret, mutationFunc, externalSecret.Name)
-	case esv1beta1.CreatePolicyNone:
+	case externalSecret.Spec.Target.CreationPolicy == esv1beta1.CreatePolicyNone:
 		log.V(1).Info("secret creation skipped due to creationPolicy=None")
 		err = nil
 	default:
 		_, err = ctrl.CreateOrUpdate(ctx, r.Client, secret, mutationFunc)
 	}
 
+	var sizeErr *sizeError
+	if errors.As(err, &sizeErr) {
+		log.Error(err, errUpdateSecret)
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonSecretTooLarge, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonSecretTooLarge, err)
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ReasonSecretTooLarge, err.Error())
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		syncCallsError.With(syncCallsMetricLabels).Inc()
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	var validationErr *validationError
+	if errors.As(err, &validationErr) {
+		log.Error(err, errValidateSecret)
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonTemplateValidationFailed, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonTemplateValidationFailed, err)
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ReasonTemplateValidationFailed, err.Error())
+		SetExternalSecretCondition(&externalSecret, *conditionSynced)
+		syncCallsError.With(syncCallsMetricLabels).Inc()
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 	if err != nil {
 		log.Error(err, errUpdateSecret)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
+		AppendSyncError(&externalSecret, esv1beta1.ReasonUpdateFailed, err)
 		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errUpdateSecret)
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		syncCallsError.With(syncCallsMetricLabels).Inc()
@@ -343,6 +565,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	SetExternalSecretCondition(&externalSecret, *conditionSynced)
 	externalSecret.Status.RefreshTime = metav1.NewTime(time.Now())
 	externalSecret.Status.SyncedResourceVersion = getResourceVersion(externalSecret)
+	externalSecret.Status.SyncedVersions = syncedVersions(&externalSecret, secretMetadata)
 	syncCallsTotal.With(syncCallsMetricLabels).Inc()
 	if currCond == nil || currCond.Status != conditionSynced.Status {
 		log.Info("reconciled secret") // Log once if on success in any verbosity
@@ -512,7 +735,11 @@ func (r *Reconciler) getStore(ctx context.Context, externalSecret *esv1beta1.Ext
 		if err != nil {
 			return nil, fmt.Errorf(errGetClusterSecretStore, ref.Name, err)
 		}
-		return &store, nil
+		resolved, err := secretstore.ResolveProviderConfig(ctx, r.Client, &store)
+		if err != nil {
+			return nil, fmt.Errorf(errGetClusterSecretStore, ref.Name, err)
+		}
+		return resolved, nil
 	}
 
 	ref.Namespace = externalSecret.Namespace
@@ -522,12 +749,20 @@ func (r *Reconciler) getStore(ctx context.Context, externalSecret *esv1beta1.Ext
 	if err != nil {
 		return nil, fmt.Errorf(errGetSecretStore, ref.Name, err)
 	}
-	return &store, nil
+	// stores inheriting from a ClusterSecretStore or referencing
+	// a ProviderConfig are used with the resolved provider
+	resolved, err := secretstore.ResolveStore(ctx, r.Client, &store)
+	if err != nil {
+		return nil, fmt.Errorf(errGetSecretStore, ref.Name, err)
+	}
+	return resolved, nil
 }
 
-// getProviderSecretData returns the provider's secret data with the provided ExternalSecret.
-func (r *Reconciler) getProviderSecretData(ctx context.Context, providerClient esv1beta1.SecretsClient, externalSecret *esv1beta1.ExternalSecret) (map[string][]byte, error) {
+// getProviderSecretData returns the provider's secret data with the provided ExternalSecret
+// along with the metadata of the spec.data entries by secretKey, if the provider reports it.
+func (r *Reconciler) getProviderSecretData(ctx context.Context, store esv1beta1.GenericStore, providerClient esv1beta1.SecretsClient, externalSecret *esv1beta1.ExternalSecret) (map[string][]byte, map[string]esv1beta1.SecretMetadata, error) {
 	providerData := make(map[string][]byte)
+	secretMetadata := make(map[string]esv1beta1.SecretMetadata)
 
 	for i, remoteRef := range externalSecret.Spec.DataFrom {
 		var secretMap map[string][]byte
@@ -539,26 +774,32 @@ func (r *Reconciler) getProviderSecretData(ctx context.Context, providerClient e
 				continue
 			}
 			if err != nil {
-				return nil, err
+				return nil, nil, err
+			}
+			secretMap = filterAllowedKeys(store, secretMap)
+			// providers stop listing once the limit is exceeded,
+			// this check covers providers that do not enforce it.
+			if maxResults := remoteRef.Find.MaxResults; maxResults != nil && len(secretMap) > *maxResults {
+				return nil, nil, esv1beta1.TooManyResultsError{MaxResults: *maxResults}
 			}
 			secretMap, err = utils.RewriteMap(remoteRef.Rewrite, secretMap)
 			if err != nil {
-				return nil, fmt.Errorf(errRewrite, i, err)
+				return nil, nil, fmt.Errorf(errRewrite, i, err)
 			}
 			if len(remoteRef.Rewrite) == 0 {
 				// ConversionStrategy is deprecated. Use RewriteMap instead.
 				r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1beta1.ReasonDeprecated, fmt.Sprintf("dataFrom[%d].find.conversionStrategy=%v is deprecated and will be removed in further releases. Use dataFrom.rewrite instead", i, remoteRef.Find.ConversionStrategy))
 				secretMap, err = utils.ConvertKeys(remoteRef.Find.ConversionStrategy, secretMap)
 				if err != nil {
-					return nil, fmt.Errorf(errConvert, err)
+					return nil, nil, fmt.Errorf(errConvert, err)
 				}
 			}
 			if !utils.ValidateKeys(secretMap) {
-				return nil, fmt.Errorf(errInvalidKeys, "find", i)
+				return nil, nil, fmt.Errorf(errInvalidKeys, "find", i)
 			}
 			secretMap, err = utils.DecodeMap(remoteRef.Find.DecodingStrategy, secretMap)
 			if err != nil {
-				return nil, fmt.Errorf(errDecode, "spec.dataFrom", i, err)
+				return nil, nil, fmt.Errorf(errDecode, "spec.dataFrom", i, err)
 			}
 		} else if remoteRef.Extract != nil {
 			secretMap, err = providerClient.GetSecretMap(ctx, *remoteRef.Extract)
@@ -567,55 +808,154 @@ func (r *Reconciler) getProviderSecretData(ctx context.Context, providerClient e
 				continue
 			}
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			secretMap, err = utils.RewriteMap(remoteRef.Rewrite, secretMap)
 			if err != nil {
-				return nil, fmt.Errorf(errRewrite, i, err)
+				return nil, nil, fmt.Errorf(errRewrite, i, err)
 			}
 			if len(remoteRef.Rewrite) == 0 {
 				secretMap, err = utils.ConvertKeys(remoteRef.Extract.ConversionStrategy, secretMap)
 				if err != nil {
-					return nil, fmt.Errorf(errConvert, err)
+					return nil, nil, fmt.Errorf(errConvert, err)
 				}
 			}
 			if !utils.ValidateKeys(secretMap) {
-				return nil, fmt.Errorf(errInvalidKeys, "extract", i)
+				return nil, nil, fmt.Errorf(errInvalidKeys, "extract", i)
 			}
 			secretMap, err = utils.DecodeMap(remoteRef.Extract.DecodingStrategy, secretMap)
 			if err != nil {
-				return nil, fmt.Errorf(errDecode, "spec.dataFrom", i, err)
+				return nil, nil, fmt.Errorf(errDecode, "spec.dataFrom", i, err)
 			}
 		}
 		providerData = utils.MergeByteMap(providerData, secretMap)
 	}
 
+	externalSecret.Status.MissingKeys = nil
 	for i, secretRef := range externalSecret.Spec.Data {
-		secretData, err := providerClient.GetSecret(ctx, secretRef.RemoteRef)
+		secretData, metadata, err := getSecretWithMetadata(ctx, providerClient, secretRef.RemoteRef)
+		// optional entries are skipped regardless of the deletion policy
+		if errors.Is(err, esv1beta1.NoSecretErr) && secretRef.Optional {
+			r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonMissingOptionalKey, fmt.Sprintf("optional secret does not exist at provider using .data[%d] key=%s", i, secretRef.RemoteRef.Key))
+			externalSecret.Status.MissingKeys = append(externalSecret.Status.MissingKeys, secretRef.SecretKey)
+			continue
+		}
 		if errors.Is(err, esv1beta1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain {
 			r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .data[%d] key=%s", i, secretRef.RemoteRef.Key))
 			continue
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		secretData, err = utils.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
 		if err != nil {
-			return nil, fmt.Errorf(errDecode, "spec.data", i, err)
+			return nil, nil, fmt.Errorf(errDecode, "spec.data", i, err)
 		}
 		providerData[secretRef.SecretKey] = secretData
+		if metadata != (esv1beta1.SecretMetadata{}) {
+			secretMetadata[secretRef.SecretKey] = metadata
+		}
+	}
+
+	return providerData, secretMetadata, nil
+}
+
+// getSecretWithMetadata fetches a single secret and its metadata
+// in one round trip if the provider client is able to report it.
+// Version expressions like latest-1 are resolved to a provider version first.
+func getSecretWithMetadata(ctx context.Context, providerClient esv1beta1.SecretsClient, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, esv1beta1.SecretMetadata, error) {
+	if versionexpr.IsExpression(ref.Version) {
+		version, err := resolveVersion(ctx, providerClient, ref)
+		if err != nil {
+			return nil, esv1beta1.SecretMetadata{}, err
+		}
+		ref.Version = version
+	}
+	if withMetadata, ok := providerClient.(esv1beta1.MetadataSecretsClient); ok {
+		return withMetadata.GetSecretWithMetadata(ctx, ref)
+	}
+	if versioned, ok := providerClient.(esv1beta1.VersionedSecretsClient); ok {
+		data, version, err := versioned.GetSecretWithVersion(ctx, ref)
+		return data, esv1beta1.SecretMetadata{Version: version}, err
+	}
+	data, err := providerClient.GetSecret(ctx, ref)
+	return data, esv1beta1.SecretMetadata{}, err
+}
+
+// resolveVersion returns the provider version selected by the version expression of ref.
+func resolveVersion(ctx context.Context, providerClient esv1beta1.SecretsClient, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	lister, ok := providerClient.(esv1beta1.SecretVersionLister)
+	if !ok {
+		return "", fmt.Errorf(errVersionsUnsupported, ref.Version, ref.Key)
+	}
+	versions, err := lister.ListSecretVersions(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf(errListVersions, ref.Key, err)
 	}
+	return versionexpr.Resolve(ref.Version, versions)
+}
 
-	return providerData, nil
+// syncedVersions returns the versions of the spec.data entries in the order of the spec.
+func syncedVersions(externalSecret *esv1beta1.ExternalSecret, secretMetadata map[string]esv1beta1.SecretMetadata) []esv1beta1.ExternalSecretSyncedVersion {
+	var versions []esv1beta1.ExternalSecretSyncedVersion
+	for _, secretRef := range externalSecret.Spec.Data {
+		version := secretMetadata[secretRef.SecretKey].Version
+		if version == "" {
+			continue
+		}
+		versions = append(versions, esv1beta1.ExternalSecretSyncedVersion{
+			SecretKey: secretRef.SecretKey,
+			Version:   version,
+		})
+	}
+	return versions
 }
 
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("external-secrets")
 
-	return ctrl.NewControllerManagedBy(mgr).
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &esv1beta1.ExternalSecret{}, dependsOnIndex, indexDependsOn)
+	if err != nil {
+		return err
+	}
+
+	// create events are handled by staleFirstHandler, which orders the initial
+	// reconciles by staleness. The other watches are held back until then.
+	staleFirst := newStaleFirstHandler()
+	b := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
-		For(&esv1beta1.ExternalSecret{}).
-		Owns(&v1.Secret{}, builder.OnlyMetadata).
-		Complete(r)
+		For(&esv1beta1.ExternalSecret{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(event.CreateEvent) bool { return false },
+		})).
+		Watches(&source.Kind{Type: &esv1beta1.ExternalSecret{}}, staleFirst).
+		Watches(
+			&source.Kind{Type: &esv1beta1.ExternalSecret{}},
+			staleFirst.afterFlush(dependentsHandler(r.Client, r.Log)),
+		)
+	if !r.EnableDriftDetection {
+		// same as Owns(&v1.Secret{}), which can not be held back. The wrapped
+		// handler is hidden from the controller, so it is injected here.
+		owner := &handler.EnqueueRequestForOwner{OwnerType: &esv1beta1.ExternalSecret{}, IsController: true}
+		if err := mgr.SetFields(owner); err != nil {
+			return err
+		}
+		return b.Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			staleFirst.afterFlush(owner),
+			builder.OnlyMetadata,
+		).Complete(r)
+	}
+
+	// with drift detection all Secrets are watched,
+	// also those written with creationPolicy=Orphan.
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &esv1beta1.ExternalSecret{}, targetNameIndex, indexTargetName)
+	if err != nil {
+		return err
+	}
+	return b.Watches(
+		&source.Kind{Type: &v1.Secret{}},
+		staleFirst.afterFlush(targetSecretHandler(r.Client, r.Log)),
+		builder.OnlyMetadata,
+	).Complete(r)
 }
@@ -15,8 +15,12 @@ limitations under the License.
 package externalsecret
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
+	tpltext "text/template"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,7 +37,7 @@ import (
 // * template.Data (highest precedence)
 // * template.templateFrom
 // * secret via es.data or es.dataFrom.
-func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSecret, secret *v1.Secret, dataMap map[string][]byte) error {
+func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSecret, secret *v1.Secret, dataMap map[string][]byte, secretMetadata map[string]esv1beta1.SecretMetadata) error {
 	mergeMetadata(secret, es)
 
 	// no template: copy data and return
@@ -65,15 +69,81 @@ func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSe
 	}
 
 	// if no data was provided by template fallback
-	// to value from the provider
-	if len(es.Spec.Target.Template.Data) == 0 && len(es.Spec.Target.Template.TemplateFrom) == 0 {
-		secret.Data = dataMap
+	// to value from the provider, the map is copied as keys may be pruned
+	if len(es.Spec.Target.Template.Data) == 0 && len(es.Spec.Target.Template.TemplateFrom) == 0 && len(es.Spec.Target.Template.Files) == 0 {
+		secret.Data = make(map[string][]byte, len(dataMap))
+		for k, v := range dataMap {
+			secret.Data[k] = v
+		}
+	}
+
+	// files are assembled last and take precedence over template data
+	for _, file := range es.Spec.Target.Template.Files {
+		val, err := assembleFile(file, execute, dataMap)
+		if err != nil {
+			return fmt.Errorf(errAssembleFile, file.Key, err)
+		}
+		secret.Data[file.Key] = val
+	}
+
+	// stringData is not rendered and overrides all other keys
+	for k, v := range es.Spec.Target.Template.StringData {
+		secret.Data[k] = []byte(v)
+	}
+	pruneKeys(secret, es)
+
+	// metadata is rendered last, it may refer to the hash of the final data
+	hash := utils.ObjectHash(secret.Data)
+	if err := applyTemplateMetadata(secret, es, hash, secretMetadata); err != nil {
+		return fmt.Errorf(errRenderMetadata, err)
 	}
-	secret.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
+	secret.Annotations[esv1beta1.AnnotationDataHash] = hash
 
 	return nil
 }
 
+// pruneKeys removes the keys listed in template.pruneKeys from the secret.
+// With creationPolicy=Merge the Secret is patched, keys of the existing Secret
+// are removed by setting them to nil.
+func pruneKeys(secret *v1.Secret, es *esv1beta1.ExternalSecret) {
+	for _, key := range es.Spec.Target.Template.PruneKeys {
+		if es.Spec.Target.CreationPolicy == esv1beta1.CreatePolicyMerge {
+			secret.Data[key] = nil
+			continue
+		}
+		delete(secret.Data, key)
+	}
+}
+
+// assembleFile concatenates the parts of a file in order.
+// Provider values are copied byte by byte, template fragments
+// are rendered with the given template engine.
+func assembleFile(file esv1beta1.TemplateFile, execute template.ExecFunc, dataMap map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, part := range file.Parts {
+		if i > 0 {
+			buf.WriteString(file.Separator)
+		}
+		switch {
+		case part.SecretKey != "":
+			val, ok := dataMap[part.SecretKey]
+			if !ok {
+				return nil, fmt.Errorf(errFileMissingKey, part.SecretKey)
+			}
+			buf.Write(val)
+		case part.Template != "":
+			out := &v1.Secret{Data: make(map[string][]byte)}
+			key := fmt.Sprintf("%s[%d]", file.Key, i)
+			err := execute(map[string][]byte{key: []byte(part.Template)}, dataMap, out)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(out.Data[key])
+		}
+	}
+	return buf.Bytes(), nil
+}
+
 // we do not want to force-override the label/annotations
 // and only copy the necessary key/value pairs.
 func mergeMetadata(secret *v1.Secret, externalSecret *esv1beta1.ExternalSecret) {
@@ -88,10 +158,76 @@ func mergeMetadata(secret *v1.Secret, externalSecret *esv1beta1.ExternalSecret)
 		utils.MergeStringMap(secret.ObjectMeta.Annotations, externalSecret.ObjectMeta.Annotations)
 		return
 	}
-	// if template is defined: use those labels/annotations
+	// if template is defined: labels/annotations are set by applyTemplateMetadata
 	secret.Type = externalSecret.Spec.Target.Template.Type
-	utils.MergeStringMap(secret.ObjectMeta.Labels, externalSecret.Spec.Target.Template.Metadata.Labels)
-	utils.MergeStringMap(secret.ObjectMeta.Annotations, externalSecret.Spec.Target.Template.Metadata.Annotations)
+}
+
+// applyTemplateMetadata sets the labels and annotations of template.metadata.
+// With template.metadata.render, values may refer to the sync context, e.g. "{{ .storeName }}",
+// or to the provider metadata of a spec.data entry, e.g. "{{ .versions.password }}". Keys with the
+// reconcile.external-secrets.io/ prefix are owned by the controller and are not overridden.
+func applyTemplateMetadata(secret *v1.Secret, es *esv1beta1.ExternalSecret, hash string, secretMetadata map[string]esv1beta1.SecretMetadata) error {
+	storeKind := es.Spec.SecretStoreRef.Kind
+	if storeKind == "" {
+		storeKind = esv1beta1.SecretStoreKind
+	}
+	versions := make(map[string]string)
+	createdTimes := make(map[string]string)
+	for key, metadata := range secretMetadata {
+		if metadata.Version != "" {
+			versions[key] = metadata.Version
+		}
+		if !metadata.CreatedTime.IsZero() {
+			createdTimes[key] = metadata.CreatedTime.UTC().Format(time.RFC3339)
+		}
+	}
+	vars := map[string]interface{}{
+		"externalSecretName": es.Name,
+		"storeName":          es.Spec.SecretStoreRef.Name,
+		"storeKind":          storeKind,
+		"contentHash":        hash,
+		"versions":           versions,
+		"createdTimes":       createdTimes,
+	}
+	meta := es.Spec.Target.Template.Metadata
+	for _, m := range []struct {
+		in  map[string]string
+		out map[string]string
+	}{
+		{meta.Labels, secret.ObjectMeta.Labels},
+		{meta.Annotations, secret.ObjectMeta.Annotations},
+	} {
+		for k, v := range m.in {
+			if strings.HasPrefix(k, reservedMetadataPrefix) {
+				continue
+			}
+			if !meta.Render {
+				m.out[k] = v
+				continue
+			}
+			val, err := renderMetadataValue(v, vars)
+			if err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			m.out[k] = val
+		}
+	}
+	return nil
+}
+
+func renderMetadataValue(tpl string, vars map[string]interface{}) (string, error) {
+	if !strings.Contains(tpl, "{{") {
+		return tpl, nil
+	}
+	t, err := tpltext.New("metadata").Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := t.Execute(&out, vars); err != nil {
+		return "", err
+	}
+	return out.String(), nil
 }
 
 func (r *Reconciler) getTemplateData(ctx context.Context, externalSecret *esv1beta1.ExternalSecret) (map[string][]byte, error) {
@@ -0,0 +1,210 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	v2 "github.com/external-secrets/external-secrets/pkg/template/v2"
+)
+
+func TestAssembleFile(t *testing.T) {
+	dataMap := map[string][]byte{
+		"cert": {0x00, 0xff, 0x10},
+		"user": []byte("admin"),
+	}
+	tests := []struct {
+		name    string
+		file    esv1beta1.TemplateFile
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "ordered parts with separator",
+			file: esv1beta1.TemplateFile{
+				Key:       "application.properties",
+				Separator: "\n",
+				Parts: []esv1beta1.TemplateFilePart{
+					{Template: "user={{ .user }}"},
+					{Template: "mode=prod"},
+				},
+			},
+			want: []byte("user=admin\nmode=prod"),
+		},
+		{
+			name: "binary values are copied as-is",
+			file: esv1beta1.TemplateFile{
+				Key: "bundle",
+				Parts: []esv1beta1.TemplateFilePart{
+					{SecretKey: "cert"},
+					{SecretKey: "cert"},
+				},
+			},
+			want: []byte{0x00, 0xff, 0x10, 0x00, 0xff, 0x10},
+		},
+		{
+			name: "missing provider key",
+			file: esv1beta1.TemplateFile{
+				Key: "bundle",
+				Parts: []esv1beta1.TemplateFilePart{
+					{SecretKey: "missing"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid template",
+			file: esv1beta1.TemplateFile{
+				Key: "bundle",
+				Parts: []esv1beta1.TemplateFilePart{
+					{Template: "{{ .user "},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := assembleFile(tt.file, v2.Execute, dataMap)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyTemplateStringDataAndPruneKeys(t *testing.T) {
+	dataMap := map[string][]byte{
+		"password": []byte("secret"),
+		"user":     []byte("admin"),
+	}
+	tests := []struct {
+		name   string
+		policy esv1beta1.ExternalSecretCreationPolicy
+		want   map[string][]byte
+	}{
+		{
+			name:   "keys are removed",
+			policy: esv1beta1.CreatePolicyOwner,
+			want: map[string][]byte{
+				"password": []byte("secret"),
+				"config":   []byte("{{ .user }}"),
+			},
+		},
+		{
+			name:   "keys are set to nil when merging",
+			policy: esv1beta1.CreatePolicyMerge,
+			want: map[string][]byte{
+				"password": []byte("secret"),
+				"config":   []byte("{{ .user }}"),
+				"user":     nil,
+				"legacy":   nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1beta1.ExternalSecret{
+				Spec: esv1beta1.ExternalSecretSpec{
+					Target: esv1beta1.ExternalSecretTarget{
+						CreationPolicy: tt.policy,
+						Template: &esv1beta1.ExternalSecretTemplate{
+							StringData: map[string]string{"config": "{{ .user }}"},
+							PruneKeys:  []string{"user", "legacy"},
+						},
+					},
+				},
+			}
+			secret := &v1.Secret{Data: map[string][]byte{"legacy": []byte("old")}}
+			r := &Reconciler{Log: logr.Discard()}
+			if err := r.applyTemplate(context.Background(), es, secret, dataMap, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(secret.Data, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, secret.Data)
+			}
+		})
+	}
+}
+
+func TestApplyTemplateMetadata(t *testing.T) {
+	es := &esv1beta1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Spec: esv1beta1.ExternalSecretSpec{
+			SecretStoreRef: esv1beta1.SecretStoreRef{Name: "vault", Kind: esv1beta1.ClusterSecretStoreKind},
+			Target: esv1beta1.ExternalSecretTarget{
+				Template: &esv1beta1.ExternalSecretTemplate{
+					Metadata: esv1beta1.ExternalSecretTemplateMetadata{
+						Render: true,
+						Labels: map[string]string{
+							"app":     "db",
+							"store":   "{{ .storeKind }}-{{ .storeName }}",
+							"version": "{{ .versions.password }}",
+						},
+						Annotations: map[string]string{
+							"content-hash":               "{{ .contentHash }}",
+							"password-created":           "{{ .createdTimes.password }}",
+							esv1beta1.AnnotationDataHash: "user-value",
+						},
+					},
+				},
+			},
+		},
+	}
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Labels:      map[string]string{},
+		Annotations: map[string]string{esv1beta1.AnnotationDataHash: "controller-value"},
+	}}
+	secretMetadata := map[string]esv1beta1.SecretMetadata{
+		"password": {Version: "3", CreatedTime: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	if err := applyTemplateMetadata(secret, es, "abc", secretMetadata); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantLabels := map[string]string{"app": "db", "store": "ClusterSecretStore-vault", "version": "3"}
+	if !reflect.DeepEqual(secret.Labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", secret.Labels, wantLabels)
+	}
+	wantAnnotations := map[string]string{"content-hash": "abc", "password-created": "2023-01-02T03:04:05Z", esv1beta1.AnnotationDataHash: "controller-value"}
+	if !reflect.DeepEqual(secret.Annotations, wantAnnotations) {
+		t.Errorf("annotations = %v, want %v", secret.Annotations, wantAnnotations)
+	}
+
+	es.Spec.Target.Template.Metadata.Labels["invalid"] = "{{ .unknown }}"
+	if err := applyTemplateMetadata(secret, es, "abc", secretMetadata); err == nil {
+		t.Errorf("expected error for unknown variable")
+	}
+
+	// values are copied as they are unless rendering is enabled
+	es.Spec.Target.Template.Metadata.Render = false
+	if err := applyTemplateMetadata(secret, es, "abc", secretMetadata); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := secret.Labels["store"]; got != "{{ .storeKind }}-{{ .storeName }}" {
+		t.Errorf("label store = %q, want the value as it is", got)
+	}
+}
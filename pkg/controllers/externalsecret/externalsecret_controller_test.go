@@ -479,6 +479,31 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// files should be assembled from provider values and template fragments in order
+	syncWithTemplateFiles := func(tc *testCase) {
+		const secretVal = "someValue"
+		const fileKey = "application.properties"
+		tc.externalSecret.Spec.Target.Template = &esv1beta1.ExternalSecretTemplate{
+			Type: v1.SecretTypeOpaque,
+			Files: []esv1beta1.TemplateFile{
+				{
+					Key:       fileKey,
+					Separator: "\n",
+					Parts: []esv1beta1.TemplateFilePart{
+						{Template: "# generated"},
+						{Template: "password={{ .targetProperty | upper }}"},
+						{SecretKey: targetProp},
+					},
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(secretVal), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[fileKey])).To(Equal("# generated\npassword=SOMEVALUE\nsomeValue"))
+			Expect(secret.Data).ToNot(HaveKey(targetProp))
+		}
+	}
+
 	// secret should be synced with correct value precedence:
 	// * template
 	// * templateFrom
@@ -923,6 +948,31 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// optional entries that do not exist at the provider are skipped
+	// and reported in the status
+	syncOptionalMissingKey := func(tc *testCase) {
+		const secretVal = "someValue"
+		const optionalKey = "optional-key"
+		tc.externalSecret.Spec.Data = append(tc.externalSecret.Spec.Data, esv1beta1.ExternalSecretData{
+			SecretKey: optionalKey,
+			RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: "missing"},
+			Optional:  true,
+		})
+		fakeProvider.GetSecretFn = func(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+			if ref.Key == "missing" {
+				return nil, esv1beta1.NoSecretErr
+			}
+			return []byte(secretVal), nil
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			Expect(es.Status.MissingKeys).To(Equal([]string{optionalKey}))
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(secretVal))
+			Expect(secret.Data).ToNot(HaveKey(optionalKey))
+		}
+	}
+
 	// orphan the secret after the external secret has been deleted
 	createSecretPolicyOrphan := func(tc *testCase) {
 		const secretVal = "someValue"
@@ -1084,6 +1134,37 @@ var _ = Describe("ExternalSecret controller", func() {
 
 	}
 
+	// find returns more secrets than maxResults allows
+	tooManyFindResultsErrCondition := func(tc *testCase) {
+		maxResults := 1
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Find: &esv1beta1.ExternalSecretFind{
+					Name: &esv1beta1.FindName{
+						RegExp: ".*",
+					},
+					MaxResults: &maxResults,
+				},
+			},
+		}
+		fakeProvider.WithGetAllSecrets(map[string][]byte{
+			"foo": []byte(FooValue),
+			"bar": []byte(BarValue),
+		}, nil)
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ReasonTooManyResults {
+				return false
+			}
+			return true
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			Expect(es.Status.ErrorHistory).ToNot(BeEmpty())
+			Expect(es.Status.ErrorHistory[0].Reason).To(Equal(esv1beta1.ReasonTooManyResults))
+		}
+	}
+
 	// with dataFrom all properties from the specified secret
 	// should be put into the secret
 	syncWithDataFrom := func(tc *testCase) {
@@ -1450,6 +1531,7 @@ var _ = Describe("ExternalSecret controller", func() {
 		Entry("should sync with template", syncWithTemplate),
 		Entry("should sync with template engine v2", syncWithTemplateV2),
 		Entry("should sync template with correct value precedence", syncWithTemplatePrecedence),
+		Entry("should assemble files from template parts", syncWithTemplateFiles),
 		Entry("should refresh secret from template", refreshWithTemplate),
 		Entry("should be able to use only metadata from template", onlyMetadataFromTemplate),
 		Entry("should refresh secret value when provider secret changes", refreshSecretValue),
@@ -1462,6 +1544,7 @@ var _ = Describe("ExternalSecret controller", func() {
 		Entry("should fetch secret using dataFrom.find", syncDataFromFind),
 		Entry("should rewrite secret using dataFrom.find", syncAndRewriteDataFromFind),
 		Entry("should not automatically convert from find if rewrite is used", invalidFindKeysErrCondition),
+		Entry("should set an error condition when find exceeds maxResults", tooManyFindResultsErrCondition),
 		Entry("should fetch secret using dataFrom and a template", syncWithDataFromTemplate),
 		Entry("should set error condition when provider errors", providerErrCondition),
 		Entry("should set an error condition when store does not exist", storeMissingErrCondition),
@@ -1471,6 +1554,7 @@ var _ = Describe("ExternalSecret controller", func() {
 		Entry("should eventually delete target secret with deletionPolicy=Delete", deleteSecretPolicy),
 		Entry("should not delete target secret with deletionPolicy=Retain", deleteSecretPolicyRetain),
 		Entry("should not delete pre-existing secret with deletionPolicy=Merge", deleteSecretPolicyMerge),
+		Entry("should skip optional keys that do not exist at the provider", syncOptionalMissingKey),
 	)
 })
 
@@ -0,0 +1,179 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/ext"
+	lru "github.com/hashicorp/golang-lru"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errValidationRuleCompile = "could not compile validation rule %q: %s"
+	errValidationRuleType    = "validation rule %q must evaluate to bool"
+	errValidationRuleEval    = "could not evaluate validation rule %q: %w"
+	errValidationRuleFailed  = "validation rule %q failed"
+	errValidationSchema      = "could not parse validation jsonSchema: %w"
+	errValidationSchemaFail  = "secret data does not match validation jsonSchema: %w"
+
+	// validationRuleCostLimit is the cost a single rule may use per evaluation,
+	// it is the per call limit the API server applies to CRD validation rules.
+	validationRuleCostLimit = 1000000
+	// maxCompiledValidations bounds the number of ExternalSecrets whose compiled rules are kept.
+	maxCompiledValidations = 1000
+)
+
+var (
+	compiledValidationsMu sync.Mutex
+	// compiledValidations holds the compiled rules of each ExternalSecret by UID,
+	// so they are only compiled once per generation. lru.New only fails for a size below 1.
+	compiledValidations, _ = lru.New(maxCompiledValidations)
+)
+
+type compiledValidation struct {
+	generation int64
+	rules      []compiledRule
+	err        error
+}
+
+type compiledRule struct {
+	rule    esv1beta1.ExternalSecretValidationRule
+	program cel.Program
+}
+
+// validationError is returned if the rendered Secret data
+// does not satisfy the validation rules of the ExternalSecret.
+type validationError struct {
+	err error
+}
+
+func (e *validationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
+}
+
+// validateSecretData validates the rendered Secret data
+// against the CEL rules and the JSON schema of the validation spec.
+func validateSecretData(es *esv1beta1.ExternalSecret, data map[string][]byte) error {
+	validation := es.Spec.Target.Validation
+	if validation == nil {
+		return nil
+	}
+	strData := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		// nil values are keys that are removed from the secret
+		if v == nil {
+			continue
+		}
+		strData[k] = string(v)
+	}
+	if err := validateRules(es, strData); err != nil {
+		return &validationError{err: err}
+	}
+	if err := validateJSONSchema(validation.JSONSchema, strData); err != nil {
+		return &validationError{err: err}
+	}
+	return nil
+}
+
+func validateRules(es *esv1beta1.ExternalSecret, data map[string]interface{}) error {
+	if len(es.Spec.Target.Validation.Rules) == 0 {
+		return nil
+	}
+	rules, err := compileRules(es.UID, es.Generation, es.Spec.Target.Validation.Rules)
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		out, _, err := r.program.Eval(map[string]interface{}{"data": data})
+		if err != nil {
+			return fmt.Errorf(errValidationRuleEval, r.rule.Rule, err)
+		}
+		if ok, _ := out.Value().(bool); !ok {
+			if r.rule.Message != "" {
+				return fmt.Errorf("%s", r.rule.Message)
+			}
+			return fmt.Errorf(errValidationRuleFailed, r.rule.Rule)
+		}
+	}
+	return nil
+}
+
+// compileRules returns the programs of the rules of an ExternalSecret.
+// They are compiled again only if the generation of the ExternalSecret changed.
+func compileRules(uid types.UID, generation int64, rules []esv1beta1.ExternalSecretValidationRule) ([]compiledRule, error) {
+	compiledValidationsMu.Lock()
+	defer compiledValidationsMu.Unlock()
+	if v, ok := compiledValidations.Get(uid); ok && v.(*compiledValidation).generation == generation {
+		return v.(*compiledValidation).rules, v.(*compiledValidation).err
+	}
+	compiled, err := newCompiledRules(rules)
+	compiledValidations.Add(uid, &compiledValidation{generation: generation, rules: compiled, err: err})
+	return compiled, err
+}
+
+func newCompiledRules(rules []esv1beta1.ExternalSecretValidationRule) ([]compiledRule, error) {
+	env, err := cel.NewEnv(
+		cel.Declarations(decls.NewVar("data", decls.NewMapType(decls.String, decls.String))),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		ast, iss := env.Compile(rule.Rule)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf(errValidationRuleCompile, rule.Rule, iss.Err())
+		}
+		if ast.ResultType().GetPrimitive() != decls.Bool.GetPrimitive() {
+			return nil, fmt.Errorf(errValidationRuleType, rule.Rule)
+		}
+		prg, err := env.Program(ast, cel.CostLimit(validationRuleCostLimit))
+		if err != nil {
+			return nil, fmt.Errorf(errValidationRuleEval, rule.Rule, err)
+		}
+		compiled = append(compiled, compiledRule{rule: rule, program: prg})
+	}
+	return compiled, nil
+}
+
+func validateJSONSchema(jsonSchema string, data map[string]interface{}) error {
+	if jsonSchema == "" {
+		return nil
+	}
+	var schema spec.Schema
+	if err := json.Unmarshal([]byte(jsonSchema), &schema); err != nil {
+		return fmt.Errorf(errValidationSchema, err)
+	}
+	if err := validate.AgainstSchema(&schema, data, strfmt.Default); err != nil {
+		return fmt.Errorf(errValidationSchemaFail, err)
+	}
+	return nil
+}
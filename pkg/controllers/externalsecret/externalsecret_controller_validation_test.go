@@ -0,0 +1,167 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestValidateSecretData(t *testing.T) {
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("s3cr3t-value"),
+		"removed":  nil,
+	}
+	schema := `{
+		"type": "object",
+		"required": ["username", "password"],
+		"properties": {
+			"password": {"type": "string", "minLength": 8}
+		}
+	}`
+	// nested loops over the list cost more than validationRuleCostLimit
+	list := "[" + strings.Repeat("0,", 999) + "0]"
+	tests := []struct {
+		name       string
+		validation *esv1beta1.ExternalSecretValidation
+		wantErr    string
+	}{
+		{
+			name: "no validation",
+		},
+		{
+			name: "rules pass",
+			validation: &esv1beta1.ExternalSecretValidation{
+				Rules: []esv1beta1.ExternalSecretValidationRule{
+					{Rule: `"username" in data && size(data.password) >= 8`},
+					{Rule: `!("removed" in data)`},
+				},
+			},
+		},
+		{
+			name: "rule fails with message",
+			validation: &esv1beta1.ExternalSecretValidation{
+				Rules: []esv1beta1.ExternalSecretValidationRule{
+					{Rule: `data.username != "admin"`, Message: "admin user is not allowed"},
+				},
+			},
+			wantErr: "admin user is not allowed",
+		},
+		{
+			name: "rule fails without message",
+			validation: &esv1beta1.ExternalSecretValidation{
+				Rules: []esv1beta1.ExternalSecretValidationRule{
+					{Rule: `"token" in data`},
+				},
+			},
+			wantErr: `validation rule "\"token\" in data" failed`,
+		},
+		{
+			name: "rule is not a bool",
+			validation: &esv1beta1.ExternalSecretValidation{
+				Rules: []esv1beta1.ExternalSecretValidationRule{
+					{Rule: `data.username`},
+				},
+			},
+			wantErr: "must evaluate to bool",
+		},
+		{
+			name: "rule does not compile",
+			validation: &esv1beta1.ExternalSecretValidation{
+				Rules: []esv1beta1.ExternalSecretValidationRule{
+					{Rule: `data.username ==`},
+				},
+			},
+			wantErr: "could not compile validation rule",
+		},
+		{
+			name: "rule exceeds the cost limit",
+			validation: &esv1beta1.ExternalSecretValidation{
+				Rules: []esv1beta1.ExternalSecretValidationRule{
+					{Rule: fmt.Sprintf(`%[1]s.all(x, %[1]s.all(y, x == y))`, list)},
+				},
+			},
+			wantErr: "cost limit exceeded",
+		},
+		{
+			name: "json schema passes",
+			validation: &esv1beta1.ExternalSecretValidation{
+				JSONSchema: schema,
+			},
+		},
+		{
+			name: "json schema fails",
+			validation: &esv1beta1.ExternalSecretValidation{
+				JSONSchema: `{"type": "object", "required": ["token"]}`,
+			},
+			wantErr: "secret data does not match validation jsonSchema",
+		},
+		{
+			name: "invalid json schema",
+			validation: &esv1beta1.ExternalSecretValidation{
+				JSONSchema: `{`,
+			},
+			wantErr: "could not parse validation jsonSchema",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1beta1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{UID: types.UID(tt.name)},
+				Spec: esv1beta1.ExternalSecretSpec{
+					Target: esv1beta1.ExternalSecretTarget{Validation: tt.validation},
+				},
+			}
+			err := validateSecretData(es, data)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			var validationErr *validationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a validationError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestCompileRulesPerGeneration(t *testing.T) {
+	rules := []esv1beta1.ExternalSecretValidationRule{{Rule: `"username" in data`}}
+	first, err := compileRules("uid", 1, rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, _ := compileRules("uid", 1, rules)
+	if again[0].program != first[0].program {
+		t.Errorf("rules were compiled again for the same generation")
+	}
+	changed := []esv1beta1.ExternalSecretValidationRule{{Rule: `data.username ==`}}
+	if _, err := compileRules("uid", 2, changed); err == nil {
+		t.Errorf("rules were not compiled again for a new generation")
+	}
+}
@@ -15,6 +15,8 @@ limitations under the License.
 package externalsecret
 
 import (
+	"fmt"
+
 	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -28,20 +30,31 @@ const (
 	SyncCallsErrorKey                  = "sync_calls_error"
 	externalSecretStatusConditionKey   = "status_condition"
 	externalSecretReconcileDurationKey = "reconcile_duration"
+	namespaceThrottledKey              = "namespace_throttled_total"
+	namespaceThrottleDelayKey          = "namespace_throttle_delay_seconds"
+	driftDetectedKey                   = "drift_detected_total"
+
+	errUnknownMetricsAggregation = "unknown metrics aggregation %q, must be one of object, namespace or store"
 )
 
-var (
-	syncCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
-		Subsystem: ExternalSecretSubsystem,
-		Name:      SyncCallsKey,
-		Help:      "Total number of the External Secret sync calls",
-	}, []string{"name", "namespace"})
+// MetricsAggregation defines the granularity of the sync metrics.
+type MetricsAggregation string
 
-	syncCallsError = prometheus.NewCounterVec(prometheus.CounterOpts{
-		Subsystem: ExternalSecretSubsystem,
-		Name:      SyncCallsErrorKey,
-		Help:      "Total number of the External Secret sync errors",
-	}, []string{"name", "namespace"})
+const (
+	// MetricsAggregationObject emits metrics for every ExternalSecret.
+	MetricsAggregationObject MetricsAggregation = "object"
+	// MetricsAggregationNamespace aggregates the sync metrics per namespace.
+	MetricsAggregationNamespace MetricsAggregation = "namespace"
+	// MetricsAggregationStore aggregates the sync metrics per referenced store.
+	// ClusterSecretStores are aggregated across namespaces.
+	MetricsAggregationStore MetricsAggregation = "store"
+)
+
+var metricsAggregation = MetricsAggregationObject
+
+var (
+	syncCallsTotal = newSyncCallsTotal(MetricsAggregationObject)
+	syncCallsError = newSyncCallsError(MetricsAggregationObject)
 
 	externalSecretCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: ExternalSecretSubsystem,
@@ -54,25 +67,126 @@ var (
 		Name:      externalSecretReconcileDurationKey,
 		Help:      "The duration time to reconcile the External Secret",
 	}, []string{"name", "namespace"})
+
+	namespaceThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      namespaceThrottledKey,
+		Help:      "Total number of External Secret syncs deferred by the namespace rate limit",
+	}, []string{"namespace"})
+
+	namespaceThrottleDelay = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      namespaceThrottleDelayKey,
+		Help:      "Delay of External Secret syncs deferred by the namespace rate limit",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"namespace"})
+
+	driftDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      driftDetectedKey,
+		Help:      "Total number of target Secrets restored after they were changed or deleted out-of-band",
+	}, []string{"namespace"})
 )
 
+func newSyncCallsTotal(aggregation MetricsAggregation) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      SyncCallsKey,
+		Help:      "Total number of the External Secret sync calls",
+	}, syncCallsLabelNames(aggregation))
+}
+
+func newSyncCallsError(aggregation MetricsAggregation) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      SyncCallsErrorKey,
+		Help:      "Total number of the External Secret sync errors",
+	}, syncCallsLabelNames(aggregation))
+}
+
+func syncCallsLabelNames(aggregation MetricsAggregation) []string {
+	switch aggregation {
+	case MetricsAggregationNamespace:
+		return []string{"namespace"}
+	case MetricsAggregationStore:
+		return []string{"namespace", "store_kind", "store"}
+	default:
+		return []string{"name", "namespace"}
+	}
+}
+
+// SetUpMetrics registers the ExternalSecret metrics with the given aggregation.
+// With namespace or store aggregation the sync counters drop the per-object
+// name label and the per-object gauges (status condition and reconcile duration)
+// are not emitted at all. It must be called once before the controller is started.
+func SetUpMetrics(aggregation MetricsAggregation) error {
+	if err := setMetricsAggregation(aggregation); err != nil {
+		return err
+	}
+	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition, externalSecretReconcileDuration,
+		namespaceThrottled, namespaceThrottleDelay, driftDetected)
+	return nil
+}
+
+func setMetricsAggregation(aggregation MetricsAggregation) error {
+	switch aggregation {
+	case MetricsAggregationObject, MetricsAggregationNamespace, MetricsAggregationStore:
+	default:
+		return fmt.Errorf(errUnknownMetricsAggregation, aggregation)
+	}
+	syncCallsTotal = newSyncCallsTotal(aggregation)
+	syncCallsError = newSyncCallsError(aggregation)
+	metricsAggregation = aggregation
+	return nil
+}
+
+// syncMetricLabels returns the sync metric labels of an ExternalSecret
+// according to the configured aggregation.
+func syncMetricLabels(name, namespace string, storeRef esv1beta1.SecretStoreRef) prometheus.Labels {
+	switch metricsAggregation {
+	case MetricsAggregationNamespace:
+		return prometheus.Labels{"namespace": namespace}
+	case MetricsAggregationStore:
+		kind := storeRef.Kind
+		if kind == "" {
+			kind = esv1beta1.SecretStoreKind
+		}
+		if kind == esv1beta1.ClusterSecretStoreKind {
+			namespace = ""
+		}
+		return prometheus.Labels{"namespace": namespace, "store_kind": kind, "store": storeRef.Name}
+	default:
+		return prometheus.Labels{"name": name, "namespace": namespace}
+	}
+}
+
+// perObjectMetricsEnabled reports whether metrics labeled with single ExternalSecrets are emitted.
+func perObjectMetricsEnabled() bool {
+	return metricsAggregation == MetricsAggregationObject
+}
+
 // updateExternalSecretCondition updates the ExternalSecret conditions.
 func updateExternalSecretCondition(es *esv1beta1.ExternalSecret, condition *esv1beta1.ExternalSecretStatusCondition, value float64) {
+	if !perObjectMetricsEnabled() {
+		return
+	}
 	switch condition.Type {
 	case esv1beta1.ExternalSecretDeleted:
-		// Remove condition=Ready metrics when the object gets deleted.
-		externalSecretCondition.Delete(prometheus.Labels{
-			"name":      es.Name,
-			"namespace": es.Namespace,
-			"condition": string(esv1beta1.ExternalSecretReady),
-			"status":    string(v1.ConditionFalse),
-		})
-		externalSecretCondition.Delete(prometheus.Labels{
-			"name":      es.Name,
-			"namespace": es.Namespace,
-			"condition": string(esv1beta1.ExternalSecretReady),
-			"status":    string(v1.ConditionTrue),
-		})
+		// Remove condition=Ready and condition=Suspended metrics when the object gets deleted.
+		for _, condType := range []esv1beta1.ExternalSecretConditionType{esv1beta1.ExternalSecretReady, esv1beta1.ExternalSecretSuspended} {
+			externalSecretCondition.Delete(prometheus.Labels{
+				"name":      es.Name,
+				"namespace": es.Namespace,
+				"condition": string(condType),
+				"status":    string(v1.ConditionFalse),
+			})
+			externalSecretCondition.Delete(prometheus.Labels{
+				"name":      es.Name,
+				"namespace": es.Namespace,
+				"condition": string(condType),
+				"status":    string(v1.ConditionTrue),
+			})
+		}
 
 	case esv1beta1.ExternalSecretReady:
 		// Remove condition=Deleted metrics when the object gets ready.
@@ -121,7 +235,3 @@ func updateExternalSecretCondition(es *esv1beta1.ExternalSecret, condition *esv1
 		"status":    string(condition.Status),
 	}).Set(value)
 }
-
-func init() {
-	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition, externalSecretReconcileDuration)
-}
@@ -0,0 +1,113 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestSyncMetricLabels(t *testing.T) {
+	defer func() {
+		if err := setMetricsAggregation(MetricsAggregationObject); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	tbl := []struct {
+		aggregation MetricsAggregation
+		storeRef    esv1beta1.SecretStoreRef
+		expected    prometheus.Labels
+	}{
+		{
+			aggregation: MetricsAggregationObject,
+			expected:    prometheus.Labels{"name": "es", "namespace": "ns"},
+		},
+		{
+			aggregation: MetricsAggregationNamespace,
+			expected:    prometheus.Labels{"namespace": "ns"},
+		},
+		{
+			aggregation: MetricsAggregationStore,
+			storeRef:    esv1beta1.SecretStoreRef{Name: "store"},
+			expected:    prometheus.Labels{"namespace": "ns", "store_kind": esv1beta1.SecretStoreKind, "store": "store"},
+		},
+		{
+			aggregation: MetricsAggregationStore,
+			storeRef:    esv1beta1.SecretStoreRef{Name: "store", Kind: esv1beta1.ClusterSecretStoreKind},
+			expected:    prometheus.Labels{"namespace": "", "store_kind": esv1beta1.ClusterSecretStoreKind, "store": "store"},
+		},
+	}
+	for _, row := range tbl {
+		if err := setMetricsAggregation(row.aggregation); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		labels := syncMetricLabels("es", "ns", row.storeRef)
+		if !reflect.DeepEqual(labels, row.expected) {
+			t.Errorf("%s: expected labels %v, got %v", row.aggregation, row.expected, labels)
+		}
+		// labels must match the label names of the registered metrics
+		if _, err := syncCallsTotal.GetMetricWith(labels); err != nil {
+			t.Errorf("%s: unexpected error: %v", row.aggregation, err)
+		}
+	}
+
+	if err := setMetricsAggregation("pod"); err == nil {
+		t.Errorf("expected error for unknown aggregation")
+	}
+}
+
+func TestSuspendedConditionMetric(t *testing.T) {
+	es := &esv1beta1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "suspended", Namespace: "ns"}}
+	labels := func(status v1.ConditionStatus) prometheus.Labels {
+		return prometheus.Labels{
+			"name":      es.Name,
+			"namespace": es.Namespace,
+			"condition": string(esv1beta1.ExternalSecretSuspended),
+			"status":    string(status),
+		}
+	}
+
+	SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1beta1.ExternalSecretSuspended, v1.ConditionTrue, esv1beta1.ReasonSuspended, msgSuspended))
+	if !isSuspended(es) {
+		t.Fatalf("expected ExternalSecret to be suspended")
+	}
+	if v := testutil.ToFloat64(externalSecretCondition.With(labels(v1.ConditionTrue))); v != 1 {
+		t.Errorf("expected suspended=True to be 1, got %v", v)
+	}
+
+	SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1beta1.ExternalSecretSuspended, v1.ConditionFalse, esv1beta1.ReasonResumed, msgResumed))
+	if isSuspended(es) {
+		t.Fatalf("expected ExternalSecret to be resumed")
+	}
+	if v := testutil.ToFloat64(externalSecretCondition.With(labels(v1.ConditionTrue))); v != 0 {
+		t.Errorf("expected suspended=True to be 0, got %v", v)
+	}
+	if v := testutil.ToFloat64(externalSecretCondition.With(labels(v1.ConditionFalse))); v != 1 {
+		t.Errorf("expected suspended=False to be 1, got %v", v)
+	}
+
+	// the metrics of a deleted ExternalSecret are removed
+	SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1beta1.ExternalSecretDeleted, v1.ConditionFalse, esv1beta1.ConditionReasonSecretDeleted, "Secret was deleted"))
+	if externalSecretCondition.Delete(labels(v1.ConditionTrue)) || externalSecretCondition.Delete(labels(v1.ConditionFalse)) {
+		t.Errorf("expected suspended metrics to be removed")
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// staleReservation is the time after which a reservation whose request did not
+// come back, e.g. because the ExternalSecret was deleted, is forgotten.
+const staleReservation = time.Minute
+
+// NamespaceRateLimiter limits the rate of provider syncs per namespace,
+// so that a namespace creating thousands of ExternalSecrets at once
+// does not starve the refreshes of the other namespaces.
+// A nil NamespaceRateLimiter disables rate limiting.
+type NamespaceRateLimiter struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	limit    rate.Limit
+	burst    int
+	limiters map[string]*namespaceLimiter
+	// reserved holds the time at which a deferred request may sync,
+	// its token is already taken from the limiter of the namespace.
+	reserved  map[types.NamespacedName]time.Time
+	lastSweep time.Time
+}
+
+type namespaceLimiter struct {
+	*rate.Limiter
+	// full is the time at which all tokens taken so far are refilled.
+	full time.Time
+}
+
+// NewNamespaceRateLimiter returns a NamespaceRateLimiter that allows qps syncs
+// per second and namespace with bursts of up to burst syncs.
+// It returns nil if qps is not positive.
+func NewNamespaceRateLimiter(qps float64, burst int) *NamespaceRateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &NamespaceRateLimiter{
+		now:      time.Now,
+		limit:    rate.Limit(qps),
+		burst:    burst,
+		limiters: make(map[string]*namespaceLimiter),
+		reserved: make(map[types.NamespacedName]time.Time),
+	}
+}
+
+// Reserve takes a token of the namespace of req and returns 0 if one is available.
+// Otherwise the next free token is reserved for req and the time until it is
+// available is returned, the caller is expected to requeue the request after that delay.
+// Deferred requests keep their place, so they are spread over the following tokens
+// instead of competing for the next one.
+func (l *NamespaceRateLimiter) Reserve(req types.NamespacedName) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+	if at, ok := l.reserved[req]; ok {
+		if delay := at.Sub(now); delay > 0 {
+			return delay
+		}
+		delete(l.reserved, req)
+		return 0
+	}
+
+	lim, ok := l.limiters[req.Namespace]
+	if !ok {
+		lim = &namespaceLimiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[req.Namespace] = lim
+	}
+	delay := lim.ReserveN(now, 1).DelayFrom(now)
+	lim.full = now.Add(delay + time.Duration(float64(l.burst)/float64(l.limit)*float64(time.Second)))
+	if delay > 0 {
+		l.reserved[req] = now.Add(delay)
+	}
+	return delay
+}
+
+// sweep forgets stale reservations and the limiters of namespaces
+// that are full again, it must be called with the lock held.
+func (l *NamespaceRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < staleReservation {
+		return
+	}
+	l.lastSweep = now
+	reserved := make(map[string]bool)
+	for req, at := range l.reserved {
+		if now.Sub(at) > staleReservation {
+			delete(l.reserved, req)
+			continue
+		}
+		reserved[req.Namespace] = true
+	}
+	// a new limiter of a namespace starts with a full bucket as well
+	for namespace, lim := range l.limiters {
+		if !reserved[namespace] && !now.Before(lim.full) {
+			delete(l.limiters, namespace)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNamespaceRateLimiter(t *testing.T) {
+	if l := NewNamespaceRateLimiter(0, 10); l != nil {
+		t.Fatalf("expected nil limiter for qps 0")
+	}
+	var disabled *NamespaceRateLimiter
+	if d := disabled.Reserve(types.NamespacedName{Namespace: "ns", Name: "es"}); d != 0 {
+		t.Fatalf("nil limiter must not delay, got %v", d)
+	}
+
+	now := time.Unix(0, 0)
+	l := NewNamespaceRateLimiter(1, 2)
+	l.now = func() time.Time { return now }
+	busy := func(name string) types.NamespacedName {
+		return types.NamespacedName{Namespace: "busy", Name: name}
+	}
+
+	for i := 0; i < 2; i++ {
+		if d := l.Reserve(busy(fmt.Sprintf("burst-%d", i))); d != 0 {
+			t.Fatalf("burst request %d delayed by %v", i, d)
+		}
+	}
+	// deferred requests are spread over the following tokens
+	if d := l.Reserve(busy("a")); d != time.Second {
+		t.Fatalf("expected a delay of 1s once the burst is used, got %v", d)
+	}
+	if d := l.Reserve(busy("b")); d != 2*time.Second {
+		t.Fatalf("expected the next request to be delayed by 2s, got %v", d)
+	}
+	// a request that comes back early keeps its reservation
+	if d := l.Reserve(busy("a")); d != time.Second {
+		t.Fatalf("expected the reservation to be kept, got delay %v", d)
+	}
+	// other namespaces are not affected
+	if d := l.Reserve(types.NamespacedName{Namespace: "quiet", Name: "a"}); d != 0 {
+		t.Fatalf("other namespace delayed by %v", d)
+	}
+
+	now = now.Add(time.Second)
+	if d := l.Reserve(busy("a")); d != 0 {
+		t.Fatalf("expected the reserved token after 1s, got delay %v", d)
+	}
+	if d := l.Reserve(busy("b")); d != time.Second {
+		t.Fatalf("expected b to wait for its reservation, got delay %v", d)
+	}
+
+	// reservations of requests that never come back are forgotten
+	now = now.Add(time.Second + 2*staleReservation)
+	l.Reserve(busy("c"))
+	if _, ok := l.reserved[busy("b")]; ok {
+		t.Errorf("stale reservation was not removed")
+	}
+	// the limiters of namespaces that are full again are forgotten
+	if _, ok := l.limiters["quiet"]; ok {
+		t.Errorf("limiter of an idle namespace was not removed")
+	}
+	if _, ok := l.limiters["busy"]; !ok {
+		t.Errorf("limiter of a namespace that just synced was removed")
+	}
+}
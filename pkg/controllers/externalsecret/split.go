@@ -0,0 +1,276 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errSecretDataTooLarge = "secret data is %d bytes and exceeds the limit of %d bytes, largest keys: %s"
+	errKeyTooLarge        = "key %q is %d bytes and exceeds the limit of %d bytes of a single secret"
+	errWriteChunk         = "could not write secret %s: %w"
+	errDeleteChunk        = "could not delete secret %s: %w"
+	errChunkNotOwned      = "secret %s exists and is not a chunk of the ExternalSecret"
+	largestKeysReported   = 3
+)
+
+// maxSecretSize is the maximum size of the data of a Secret accepted by the API server.
+var maxSecretSize = v1.MaxSecretSize
+
+// sizeError is returned if the Secret data can not be written
+// because it exceeds the size limit of a Secret.
+type sizeError struct {
+	msg string
+}
+
+func (e *sizeError) Error() string {
+	return e.msg
+}
+
+func secretSize(data map[string][]byte) int {
+	size := 0
+	for _, v := range data {
+		size += len(v)
+	}
+	return size
+}
+
+// checkSecretSize returns a sizeError that reports the largest keys
+// if the data does not fit into a single Secret.
+func checkSecretSize(data map[string][]byte) error {
+	size := secretSize(data)
+	if size <= maxSecretSize {
+		return nil
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(data[keys[i]]) != len(data[keys[j]]) {
+			return len(data[keys[i]]) > len(data[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > largestKeysReported {
+		keys = keys[:largestKeysReported]
+	}
+	largest := make([]string, 0, len(keys))
+	for _, k := range keys {
+		largest = append(largest, fmt.Sprintf("%s (%d bytes)", k, len(data[k])))
+	}
+	return &sizeError{msg: fmt.Sprintf(errSecretDataTooLarge, size, maxSecretSize, strings.Join(largest, ", "))}
+}
+
+// splitData distributes the keys in sorted order across chunks that fit into a single Secret.
+// A key is never split, a value that exceeds the limit on its own is an error.
+func splitData(data map[string][]byte) ([]map[string][]byte, error) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var chunks []map[string][]byte
+	chunk := make(map[string][]byte)
+	size := 0
+	for _, k := range keys {
+		v := data[k]
+		if len(v) > maxSecretSize {
+			return nil, &sizeError{msg: fmt.Sprintf(errKeyTooLarge, k, len(v), maxSecretSize)}
+		}
+		if size+len(v) > maxSecretSize && len(chunk) > 0 {
+			chunks = append(chunks, chunk)
+			chunk = make(map[string][]byte)
+			size = 0
+		}
+		chunk[k] = v
+		size += len(v)
+	}
+	return append(chunks, chunk), nil
+}
+
+func chunkName(name string, index int) string {
+	return fmt.Sprintf("%s-%d", name, index)
+}
+
+// splitSecretName returns the name of the split Secret a chunk name <name>-N belongs to.
+func splitSecretName(name string) (string, bool) {
+	i := strings.LastIndex(name, "-")
+	if i <= 0 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(name[i+1:], 10, 32); err != nil {
+		return "", false
+	}
+	return name[:i], true
+}
+
+// getSplitSecret returns the first chunk of a split Secret if every chunk
+// up to the chunk count of the first one is valid. Otherwise, an empty Secret
+// is returned, so the Secret is written again.
+func (r *Reconciler) getSplitSecret(ctx context.Context, namespace, name string) (v1.Secret, error) {
+	var first v1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: chunkName(name, 0), Namespace: namespace}, &first); err != nil {
+		return v1.Secret{}, err
+	}
+	count, err := strconv.Atoi(first.Annotations[esv1beta1.AnnotationChunkCount])
+	if err != nil || count < 1 {
+		return v1.Secret{}, nil
+	}
+	for i := 0; i < count; i++ {
+		chunk := first
+		if i > 0 {
+			err := r.Get(ctx, types.NamespacedName{Name: chunkName(name, i), Namespace: namespace}, &chunk)
+			if apierrors.IsNotFound(err) {
+				return v1.Secret{}, nil
+			}
+			if err != nil {
+				return v1.Secret{}, err
+			}
+		}
+		if chunk.Annotations[esv1beta1.AnnotationChunkIndex] != strconv.Itoa(i) ||
+			chunk.Annotations[esv1beta1.AnnotationChunkCount] != strconv.Itoa(count) ||
+			chunk.Annotations[esv1beta1.AnnotationDataHash] != utils.ObjectHash(chunk.Data) {
+			return v1.Secret{}, nil
+		}
+	}
+	return first, nil
+}
+
+// writeSplitSecret renders the Secret and writes it as it is if it fits into a single Secret.
+// Otherwise, the data is written to the Secrets <name>-0, <name>-1, ...
+// Chunks that are no longer needed are deleted, as is the unsplit Secret if it is owned by the ExternalSecret.
+func (r *Reconciler) writeSplitSecret(ctx context.Context, es *esv1beta1.ExternalSecret, secret *v1.Secret, mutationFunc func() error) error {
+	// the Secret is rendered once to find out whether it must be split,
+	// mutationFunc renders it again when the Secret is written.
+	initial := secret.DeepCopy()
+	if err := mutationFunc(); err != nil {
+		return err
+	}
+	rendered := secret.DeepCopy()
+	*secret = *initial
+
+	if secretSize(rendered.Data) <= maxSecretSize {
+		if _, err := ctrl.CreateOrUpdate(ctx, r.Client, secret, mutationFunc); err != nil {
+			return err
+		}
+		return r.deleteChunks(ctx, es, secret.Name, 0)
+	}
+
+	chunks, err := splitData(rendered.Data)
+	if err != nil {
+		return err
+	}
+	for i, data := range chunks {
+		chunk := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: chunkName(secret.Name, i), Namespace: secret.Namespace}}
+		_, err := ctrl.CreateOrUpdate(ctx, r.Client, chunk, func() error {
+			// an existing Secret of the same name is only overwritten if it is a chunk
+			// written before or owned by the ExternalSecret, also with creationPolicy=Orphan
+			if _, ok := chunk.Annotations[esv1beta1.AnnotationChunkIndex]; chunk.ResourceVersion != "" && !ok && !metav1.IsControlledBy(chunk, es) {
+				return fmt.Errorf(errChunkNotOwned, chunk.Name)
+			}
+			if es.Spec.Target.CreationPolicy != esv1beta1.CreatePolicyOrphan {
+				if err := controllerutil.SetControllerReference(es, &chunk.ObjectMeta, r.Scheme); err != nil {
+					return fmt.Errorf(errSetCtrlReference, err)
+				}
+			}
+			if chunk.Labels == nil {
+				chunk.Labels = make(map[string]string)
+			}
+			utils.MergeStringMap(chunk.Labels, rendered.Labels)
+			if chunk.Annotations == nil {
+				chunk.Annotations = make(map[string]string)
+			}
+			utils.MergeStringMap(chunk.Annotations, rendered.Annotations)
+			chunk.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(data)
+			chunk.Annotations[esv1beta1.AnnotationChunkIndex] = strconv.Itoa(i)
+			chunk.Annotations[esv1beta1.AnnotationChunkCount] = strconv.Itoa(len(chunks))
+			chunk.Type = rendered.Type
+			chunk.Immutable = rendered.Immutable
+			chunk.Data = data
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf(errWriteChunk, chunk.Name, err)
+		}
+	}
+	if err := r.deleteChunks(ctx, es, secret.Name, len(chunks)); err != nil {
+		return err
+	}
+
+	// the unsplit Secret holds stale data
+	var unsplit v1.Secret
+	err = r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, &unsplit)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(&unsplit, es) {
+		return nil
+	}
+	if err := r.Delete(ctx, &unsplit); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf(errDeleteChunk, unsplit.Name, err)
+	}
+	return nil
+}
+
+// deleteSecret deletes the Secret and, if the ExternalSecret splits it, its chunks.
+func (r *Reconciler) deleteSecret(ctx context.Context, es *esv1beta1.ExternalSecret, secret *v1.Secret) error {
+	if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if es.Spec.Target.SizeLimitPolicy != esv1beta1.SizeLimitPolicySplit {
+		return nil
+	}
+	return r.deleteChunks(ctx, es, secret.Name, 0)
+}
+
+// deleteChunks deletes the chunks of the given Secret starting at index from,
+// chunks that are not owned by the ExternalSecret are kept.
+func (r *Reconciler) deleteChunks(ctx context.Context, es *esv1beta1.ExternalSecret, name string, from int) error {
+	for i := from; ; i++ {
+		var chunk v1.Secret
+		err := r.Get(ctx, types.NamespacedName{Name: chunkName(name, i), Namespace: es.Namespace}, &chunk)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := chunk.Annotations[esv1beta1.AnnotationChunkIndex]; !ok || !metav1.IsControlledBy(&chunk, es) {
+			return nil
+		}
+		if err := r.Delete(ctx, &chunk); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf(errDeleteChunk, chunk.Name, err)
+		}
+	}
+}
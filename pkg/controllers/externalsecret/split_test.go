@@ -0,0 +1,281 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func withMaxSecretSize(t *testing.T, size int) {
+	old := maxSecretSize
+	maxSecretSize = size
+	t.Cleanup(func() { maxSecretSize = old })
+}
+
+func TestCheckSecretSize(t *testing.T) {
+	withMaxSecretSize(t, 10)
+	if err := checkSecretSize(map[string][]byte{"a": []byte("12345"), "b": []byte("12345")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := checkSecretSize(map[string][]byte{
+		"a": []byte("1"),
+		"b": []byte("1234"),
+		"c": []byte("123"),
+		"d": []byte("1234"),
+	})
+	var sizeErr *sizeError
+	if !errors.As(err, &sizeErr) {
+		t.Fatalf("expected a sizeError, got %v", err)
+	}
+	want := "secret data is 12 bytes and exceeds the limit of 10 bytes, largest keys: b (4 bytes), d (4 bytes), c (3 bytes)"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestSplitData(t *testing.T) {
+	withMaxSecretSize(t, 10)
+	chunks, err := splitData(map[string][]byte{
+		"a": []byte("123456"),
+		"b": []byte("1234"),
+		"c": []byte("12345678"),
+		"d": []byte("1"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []map[string][]byte{
+		{"a": []byte("123456"), "b": []byte("1234")},
+		{"c": []byte("12345678"), "d": []byte("1")},
+	}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("got %q, want %q", chunks, want)
+	}
+
+	_, err = splitData(map[string][]byte{"big": []byte("12345678901")})
+	if err == nil || !strings.Contains(err.Error(), `key "big"`) {
+		t.Errorf("expected an error for a key that exceeds the limit, got %v", err)
+	}
+}
+
+func TestWriteSplitSecret(t *testing.T) {
+	withMaxSecretSize(t, 10)
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := esv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	es := &esv1beta1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "uid"}}
+	kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(es).Build()
+	r := &Reconciler{Client: kube, Scheme: scheme}
+
+	write := func(data map[string][]byte) {
+		t.Helper()
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default"}}
+		err := r.writeSplitSecret(context.Background(), es, secret, func() error {
+			if err := controllerutil.SetControllerReference(es, secret, scheme); err != nil {
+				return err
+			}
+			secret.Data = data
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	exists := func(name string) bool {
+		t.Helper()
+		err := kube.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &v1.Secret{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			t.Fatal(err)
+		}
+		return err == nil
+	}
+
+	write(map[string][]byte{"a": []byte("123")})
+	if !exists("target") || exists("target-0") {
+		t.Fatalf("expected a single secret")
+	}
+
+	write(map[string][]byte{"a": []byte("123456"), "b": []byte("123456"), "c": []byte("123456")})
+	if exists("target") {
+		t.Errorf("expected the unsplit secret to be deleted")
+	}
+	var chunk v1.Secret
+	if err := kube.Get(context.Background(), types.NamespacedName{Name: "target-2", Namespace: "default"}, &chunk); err != nil {
+		t.Fatalf("expected three chunks: %v", err)
+	}
+	if chunk.Annotations[esv1beta1.AnnotationChunkIndex] != "2" || chunk.Annotations[esv1beta1.AnnotationChunkCount] != "3" {
+		t.Errorf("unexpected chunk annotations: %v", chunk.Annotations)
+	}
+	if !reflect.DeepEqual(chunk.Data, map[string][]byte{"c": []byte("123456")}) {
+		t.Errorf("unexpected chunk data: %q", chunk.Data)
+	}
+
+	write(map[string][]byte{"a": []byte("123456"), "b": []byte("123456")})
+	if !exists("target-1") || exists("target-2") {
+		t.Errorf("expected the stale chunk to be deleted")
+	}
+
+	// a split Secret is only valid if all of its chunks are
+	valid := func() bool {
+		t.Helper()
+		secret, err := r.getSplitSecret(context.Background(), "default", "target")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the fake client does not set the UID checked by isSecretValid
+		return secret.Name != ""
+	}
+	if !valid() {
+		t.Errorf("expected the split secret to be valid")
+	}
+	var last v1.Secret
+	if err := kube.Get(context.Background(), types.NamespacedName{Name: "target-1", Namespace: "default"}, &last); err != nil {
+		t.Fatal(err)
+	}
+	last.Data["b"] = []byte("changed")
+	if err := kube.Update(context.Background(), &last); err != nil {
+		t.Fatal(err)
+	}
+	if valid() {
+		t.Errorf("expected a changed chunk to invalidate the split secret")
+	}
+	if err := kube.Delete(context.Background(), &last); err != nil {
+		t.Fatal(err)
+	}
+	if valid() {
+		t.Errorf("expected a deleted chunk to invalidate the split secret")
+	}
+
+	write(map[string][]byte{"a": []byte("123")})
+	if !exists("target") || exists("target-0") || exists("target-1") {
+		t.Errorf("expected the chunks to be replaced by a single secret")
+	}
+}
+
+func TestDeleteSplitSecret(t *testing.T) {
+	withMaxSecretSize(t, 10)
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := esv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	es := &esv1beta1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "uid"},
+		Spec: esv1beta1.ExternalSecretSpec{
+			Target: esv1beta1.ExternalSecretTarget{SizeLimitPolicy: esv1beta1.SizeLimitPolicySplit},
+		},
+	}
+	kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(es).Build()
+	r := &Reconciler{Client: kube, Scheme: scheme}
+
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default"}}
+	err := r.writeSplitSecret(context.Background(), es, secret, func() error {
+		secret.Data = map[string][]byte{"a": []byte("123456"), "b": []byte("123456")}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.deleteSecret(context.Background(), es, &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var chunks v1.SecretList
+	if err := kube.List(context.Background(), &chunks); err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks.Items) != 0 {
+		t.Errorf("expected the chunks to be deleted, got %d secrets", len(chunks.Items))
+	}
+}
+
+func TestWriteSplitSecretKeepsForeignSecrets(t *testing.T) {
+	withMaxSecretSize(t, 10)
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := esv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	es := &esv1beta1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: "uid"},
+		Spec: esv1beta1.ExternalSecretSpec{
+			Target: esv1beta1.ExternalSecretTarget{CreationPolicy: esv1beta1.CreatePolicyOrphan},
+		},
+	}
+	foreign := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "target-1", Namespace: "default"},
+		Data:       map[string][]byte{"foreign": []byte("value")},
+	}
+	kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(es, foreign).Build()
+	r := &Reconciler{Client: kube, Scheme: scheme}
+
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default"}}
+	err := r.writeSplitSecret(context.Background(), es, secret, func() error {
+		secret.Data = map[string][]byte{"a": []byte("123456"), "b": []byte("123456")}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "is not a chunk of the ExternalSecret") {
+		t.Fatalf("expected the foreign secret to be refused, got %v", err)
+	}
+	var got v1.Secret
+	if err := kube.Get(context.Background(), types.NamespacedName{Name: "target-1", Namespace: "default"}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Data, foreign.Data) {
+		t.Errorf("foreign secret was overwritten: %q", got.Data)
+	}
+}
+
+func TestSplitSecretName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{name: "target-0", want: "target", ok: true},
+		{name: "my-target-12", want: "my-target", ok: true},
+		{name: "target"},
+		{name: "target-x"},
+		{name: "-0"},
+	}
+	for _, tt := range tests {
+		got, ok := splitSecretName(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("splitSecretName(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// startupWindow is the time after the first create event in which create events
+// are collected and enqueued ordered by staleness. When the controller starts,
+// the informer delivers a create event for every existing ExternalSecret within this window.
+var startupWindow = 2 * time.Second
+
+// staleFirstHandler enqueues created ExternalSecrets. The ExternalSecrets created
+// within startupWindow are enqueued at once, ordered by their last refresh,
+// so after a restart the most stale ExternalSecrets are reconciled first.
+// Later create events are enqueued right away. Other events are ignored.
+// Handlers of other watches must be wrapped with afterFlush, otherwise their
+// events enqueue ExternalSecrets ahead of the ordered ones.
+type staleFirstHandler struct {
+	handler.Funcs
+
+	mu      sync.Mutex
+	started bool
+	flushed bool
+	pending []pendingRequest
+}
+
+type pendingRequest struct {
+	request     ctrl.Request
+	refreshTime time.Time
+}
+
+func newStaleFirstHandler() *staleFirstHandler {
+	h := &staleFirstHandler{}
+	h.CreateFunc = h.create
+	return h
+}
+
+func (h *staleFirstHandler) create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	es, ok := evt.Object.(*esv1beta1.ExternalSecret)
+	if !ok {
+		return
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: es.Namespace, Name: es.Name}}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.flushed {
+		q.Add(req)
+		return
+	}
+	h.pending = append(h.pending, pendingRequest{request: req, refreshTime: es.Status.RefreshTime.Time})
+	if !h.started {
+		h.started = true
+		time.AfterFunc(startupWindow, func() { h.flush(q) })
+	}
+}
+
+// flush enqueues the collected requests, ExternalSecrets that were never refreshed first.
+func (h *staleFirstHandler) flush(q workqueue.RateLimitingInterface) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sort.SliceStable(h.pending, func(i, j int) bool {
+		return h.pending[i].refreshTime.Before(h.pending[j].refreshTime)
+	})
+	for _, p := range h.pending {
+		q.Add(p.request)
+	}
+	h.pending = nil
+	h.flushed = true
+}
+
+func (h *staleFirstHandler) isFlushed() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.flushed
+}
+
+// afterFlush drops the events of next until the collected requests are enqueued.
+// The dropped events need no reconcile of their own: every ExternalSecret
+// is enqueued by its create event, either in the ordered flush or right away.
+func (h *staleFirstHandler) afterFlush(next handler.EventHandler) handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+			if h.isFlushed() {
+				next.Create(evt, q)
+			}
+		},
+		UpdateFunc: func(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			if h.isFlushed() {
+				next.Update(evt, q)
+			}
+		},
+		DeleteFunc: func(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+			if h.isFlushed() {
+				next.Delete(evt, q)
+			}
+		},
+		GenericFunc: func(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+			if h.isFlushed() {
+				next.Generic(evt, q)
+			}
+		},
+	}
+}
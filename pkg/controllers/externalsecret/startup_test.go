@@ -0,0 +1,89 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestStaleFirstHandler(t *testing.T) {
+	old := startupWindow
+	startupWindow = 50 * time.Millisecond
+	t.Cleanup(func() { startupWindow = old })
+
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	h := newStaleFirstHandler()
+	create := func(name string, refreshed time.Time) {
+		es := &esv1beta1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		es.Status.RefreshTime = metav1.NewTime(refreshed)
+		h.Create(event.CreateEvent{Object: es}, q)
+	}
+
+	// the owned Secret of an ExternalSecret changes during the startup window
+	secrets := h.afterFlush(handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+		return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}}
+	}))
+	secretEvent := func(name string) {
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		secrets.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: secret}, q)
+	}
+
+	now := time.Now()
+	secretEvent("fresh")
+	create("fresh", now)
+	create("stale", now.Add(-time.Hour))
+	secretEvent("fresh")
+	create("never", time.Time{})
+	create("older", now.Add(-time.Minute))
+	if q.Len() != 0 {
+		t.Fatalf("expected the requests to be collected during the startup window, got %d", q.Len())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Len() < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the requests to be enqueued after the startup window")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	want := []string{"never", "stale", "older", "fresh"}
+	for _, name := range want {
+		item, _ := q.Get()
+		if got := item.(ctrl.Request).Name; got != name {
+			t.Errorf("got %s, want %s", got, name)
+		}
+		q.Done(item)
+	}
+
+	// later create events and Secret events are enqueued right away
+	create("new", time.Time{})
+	secretEvent("fresh")
+	if q.Len() != 2 {
+		t.Errorf("expected the requests to be enqueued right away, got %d", q.Len())
+	}
+}
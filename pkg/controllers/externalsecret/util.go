@@ -14,10 +14,25 @@ limitations under the License.
 package externalsecret
 
 import (
+	"fmt"
+	"time"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	// maxErrorHistory is the number of sync errors kept in the status.
+	maxErrorHistory = 10
+	// minErrorHistoryInterval limits how often a repeated error is recorded.
+	// Every status change triggers another reconcile, so recording each
+	// failure would keep a failing ExternalSecret in a tight loop.
+	minErrorHistoryInterval = time.Minute
 )
 
 // NewExternalSecretCondition a set of default options for creating an External Secret Condition.
@@ -67,6 +82,79 @@ func SetExternalSecretCondition(es *esv1beta1.ExternalSecret, condition esv1beta
 	updateExternalSecretCondition(es, &condition, 1.0)
 }
 
+// isSuspended returns true if the Suspended condition of the ExternalSecret is True.
+func isSuspended(es *esv1beta1.ExternalSecret) bool {
+	cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretSuspended)
+	return cond != nil && cond.Status == v1.ConditionTrue
+}
+
+// AppendSyncError adds a failed sync to the error history of the ExternalSecret
+// and drops the oldest errors once the history is full. If the error equals
+// the most recent one, that entry is counted up instead, at most once per
+// minErrorHistoryInterval.
+func AppendSyncError(es *esv1beta1.ExternalSecret, reason string, err error) {
+	now := metav1.Now()
+	syncErr := esv1beta1.ExternalSecretSyncError{
+		Time:        now,
+		Reason:      reason,
+		MessageHash: utils.ObjectHash(err.Error()),
+		Count:       1,
+	}
+	history := es.Status.ErrorHistory
+	if len(history) > 0 {
+		last := &history[len(history)-1]
+		if last.Reason == syncErr.Reason && last.MessageHash == syncErr.MessageHash {
+			lastTime := last.Time
+			if last.LastTime != nil {
+				lastTime = *last.LastTime
+			}
+			if now.Sub(lastTime.Time) < minErrorHistoryInterval {
+				return
+			}
+			// entries written before the count was introduced have none
+			if last.Count < 1 {
+				last.Count = 1
+			}
+			last.Count++
+			last.LastTime = &now
+			return
+		}
+	}
+	history = append(history, syncErr)
+	if len(history) > maxErrorHistory {
+		history = history[len(history)-maxErrorHistory:]
+	}
+	es.Status.ErrorHistory = history
+}
+
+// clampRefreshInterval sets the refresh interval of the ExternalSecret to the default if it is not set
+// and raises it to min if it is shorter. A refresh interval of 0 disables refreshing and is kept.
+// It returns the requested refresh interval and true if it has been clamped.
+func clampRefreshInterval(es *esv1beta1.ExternalSecret, defaultInterval, min time.Duration) (time.Duration, bool) {
+	if es.Spec.RefreshInterval == nil {
+		es.Spec.RefreshInterval = &metav1.Duration{Duration: defaultInterval}
+	}
+	interval := es.Spec.RefreshInterval.Duration
+	if interval == 0 || interval >= min {
+		return interval, false
+	}
+	es.Spec.RefreshInterval = &metav1.Duration{Duration: min}
+	return interval, true
+}
+
+// jitterRefreshInterval delays the refresh interval by up to the jitter percentage
+// of the ExternalSecret, or defaultPercent if it does not set one.
+func jitterRefreshInterval(es *esv1beta1.ExternalSecret, interval time.Duration, defaultPercent int) time.Duration {
+	percent := defaultPercent
+	if es.Spec.RefreshJitterPercent != nil {
+		percent = int(*es.Spec.RefreshJitterPercent)
+	}
+	if interval <= 0 || percent <= 0 {
+		return interval
+	}
+	return wait.Jitter(interval, float64(percent)/100)
+}
+
 // filterOutCondition returns an empty set of conditions with the provided type.
 func filterOutCondition(conditions []esv1beta1.ExternalSecretStatusCondition, condType esv1beta1.ExternalSecretConditionType) []esv1beta1.ExternalSecretStatusCondition {
 	newConditions := make([]esv1beta1.ExternalSecretStatusCondition, 0, len(conditions))
@@ -78,3 +166,51 @@ func filterOutCondition(conditions []esv1beta1.ExternalSecretStatusCondition, co
 	}
 	return newConditions
 }
+
+// checkKeyPrefixes returns an error if the ExternalSecret reads a key
+// that is not allowed by the allowedKeyPrefixes of the store.
+// Find results are not known upfront, so find operations must be scoped by a path.
+func checkKeyPrefixes(store esv1beta1.GenericStore, es *esv1beta1.ExternalSecret) error {
+	if len(store.GetSpec().AllowedKeyPrefixes) == 0 {
+		return nil
+	}
+	storeName := store.GetName()
+	for i, ref := range es.Spec.DataFrom {
+		if ref.Extract != nil && !secretstore.KeyAllowed(store, ref.Extract.Key) {
+			return fmt.Errorf(errKeyNotAllowed, fmt.Sprintf("spec.dataFrom[%d].extract", i), ref.Extract.Key, storeName)
+		}
+		if ref.Find != nil {
+			if ref.Find.Path == nil {
+				return fmt.Errorf(errFindPathRequired, i, storeName)
+			}
+			// providers match the path by prefix, so find.path=teams/team-a
+			// would also return the keys of teams/team-ab/
+			path := *ref.Find.Path
+			if !secretstore.KeyAllowed(store, path) {
+				return fmt.Errorf(errKeyNotAllowed, fmt.Sprintf("spec.dataFrom[%d].find.path", i), path, storeName)
+			}
+		}
+	}
+	for i, data := range es.Spec.Data {
+		if !secretstore.KeyAllowed(store, data.RemoteRef.Key) {
+			return fmt.Errorf(errKeyNotAllowed, fmt.Sprintf("spec.data[%d].remoteRef", i), data.RemoteRef.Key, storeName)
+		}
+	}
+	return nil
+}
+
+// filterAllowedKeys removes the find results whose key is not allowed
+// by the allowedKeyPrefixes of the store. Providers that do not scope
+// their results by find.path could otherwise return any key.
+// It must run before the keys are converted, providers return the remote names.
+func filterAllowedKeys(store esv1beta1.GenericStore, secretMap map[string][]byte) map[string][]byte {
+	if len(store.GetSpec().AllowedKeyPrefixes) == 0 {
+		return secretMap
+	}
+	for key := range secretMap {
+		if !secretstore.KeyAllowed(store, key) {
+			delete(secretMap, key)
+		}
+	}
+	return secretMap
+}
@@ -0,0 +1,263 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+func TestAppendSyncError(t *testing.T) {
+	es := &esv1beta1.ExternalSecret{}
+	AppendSyncError(es, esv1beta1.ReasonUpdateFailed, errors.New("boom"))
+	if len(es.Status.ErrorHistory) != 1 {
+		t.Fatalf("expected 1 error in history, got %d", len(es.Status.ErrorHistory))
+	}
+
+	// repeated errors within the minimum interval are not recorded
+	AppendSyncError(es, esv1beta1.ReasonUpdateFailed, errors.New("boom"))
+	if got := es.Status.ErrorHistory[0]; len(es.Status.ErrorHistory) != 1 || got.Count != 1 || got.LastTime != nil {
+		t.Fatalf("expected the repeated error to be ignored, got %+v", es.Status.ErrorHistory)
+	}
+
+	// later repeats are counted on the entry
+	first := metav1.NewTime(time.Now().Add(-time.Hour))
+	es.Status.ErrorHistory[0].Time = first
+	AppendSyncError(es, esv1beta1.ReasonUpdateFailed, errors.New("boom"))
+	got := es.Status.ErrorHistory[0]
+	if len(es.Status.ErrorHistory) != 1 || got.Count != 2 || got.LastTime == nil || !got.Time.Equal(&first) {
+		t.Fatalf("expected the repeated error to be counted, got %+v", es.Status.ErrorHistory)
+	}
+
+	// distinct errors are recorded right away
+	AppendSyncError(es, esv1beta1.ReasonUpdateFailed, errors.New("other"))
+	if len(es.Status.ErrorHistory) != 2 {
+		t.Fatalf("expected 2 errors in history, got %d", len(es.Status.ErrorHistory))
+	}
+
+	for i := 0; i < 2*maxErrorHistory; i++ {
+		AppendSyncError(es, esv1beta1.ReasonProviderClientConfig, fmt.Errorf("error %d", i))
+	}
+	if len(es.Status.ErrorHistory) != maxErrorHistory {
+		t.Fatalf("expected %d errors in history, got %d", maxErrorHistory, len(es.Status.ErrorHistory))
+	}
+	last := es.Status.ErrorHistory[maxErrorHistory-1]
+	if last.Reason != esv1beta1.ReasonProviderClientConfig || last.MessageHash == es.Status.ErrorHistory[0].MessageHash {
+		t.Errorf("unexpected history: %+v", es.Status.ErrorHistory)
+	}
+}
+
+func TestClampRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		interval    *metav1.Duration
+		want        time.Duration
+		wantClamped bool
+	}{
+		{
+			name: "unset interval uses the default",
+			want: time.Hour,
+		},
+		{
+			name:     "interval above the minimum is kept",
+			interval: &metav1.Duration{Duration: 10 * time.Minute},
+			want:     10 * time.Minute,
+		},
+		{
+			name:        "interval below the minimum is clamped",
+			interval:    &metav1.Duration{Duration: 10 * time.Second},
+			want:        time.Minute,
+			wantClamped: true,
+		},
+		{
+			name:     "disabled refresh is kept",
+			interval: &metav1.Duration{},
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1beta1.ExternalSecret{Spec: esv1beta1.ExternalSecretSpec{RefreshInterval: tt.interval}}
+			_, clamped := clampRefreshInterval(es, time.Hour, time.Minute)
+			if clamped != tt.wantClamped {
+				t.Errorf("clamped = %v, want %v", clamped, tt.wantClamped)
+			}
+			if got := es.Spec.RefreshInterval.Duration; got != tt.want {
+				t.Errorf("refreshInterval = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJitterRefreshInterval(t *testing.T) {
+	percent := func(p int32) *int32 { return &p }
+	tests := []struct {
+		name           string
+		percent        *int32
+		defaultPercent int
+		interval       time.Duration
+		wantMax        time.Duration
+	}{
+		{name: "disabled", interval: time.Hour, wantMax: time.Hour},
+		{name: "default percent", defaultPercent: 10, interval: time.Hour, wantMax: 66 * time.Minute},
+		{name: "overrides default", percent: percent(50), defaultPercent: 10, interval: time.Hour, wantMax: 90 * time.Minute},
+		{name: "disabled by spec", percent: percent(0), defaultPercent: 10, interval: time.Hour, wantMax: time.Hour},
+		{name: "no refresh", percent: percent(50), interval: 0, wantMax: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1beta1.ExternalSecret{Spec: esv1beta1.ExternalSecretSpec{RefreshJitterPercent: tt.percent}}
+			for i := 0; i < 100; i++ {
+				got := jitterRefreshInterval(es, tt.interval, tt.defaultPercent)
+				if got < tt.interval || got > tt.wantMax {
+					t.Fatalf("jitterRefreshInterval() = %s, want between %s and %s", got, tt.interval, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckKeyPrefixes(t *testing.T) {
+	store := &esv1beta1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec:       esv1beta1.SecretStoreSpec{AllowedKeyPrefixes: []string{"teams/team-a/"}},
+	}
+	data := func(key string) esv1beta1.ExternalSecretData {
+		return esv1beta1.ExternalSecretData{SecretKey: "key", RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: key}}
+	}
+	path := func(p string) *string { return &p }
+
+	tests := []struct {
+		name    string
+		spec    esv1beta1.ExternalSecretSpec
+		wantErr bool
+	}{
+		{
+			name: "keys below an allowed prefix",
+			spec: esv1beta1.ExternalSecretSpec{
+				Data: []esv1beta1.ExternalSecretData{data("teams/team-a/db")},
+				DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{
+					{Extract: &esv1beta1.ExternalSecretDataRemoteRef{Key: "teams/team-a/app"}},
+					{Find: &esv1beta1.ExternalSecretFind{Path: path("teams/team-a/")}},
+				},
+			},
+		},
+		{
+			// providers match the path by prefix, teams/team-a would also find teams/team-ab/
+			name: "find path of the prefix without trailing slash",
+			spec: esv1beta1.ExternalSecretSpec{DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{
+				{Find: &esv1beta1.ExternalSecretFind{Path: path("teams/team-a")}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "data key outside of the prefixes",
+			spec:    esv1beta1.ExternalSecretSpec{Data: []esv1beta1.ExternalSecretData{data("teams/team-b/db")}},
+			wantErr: true,
+		},
+		{
+			name:    "relative segments are rejected",
+			spec:    esv1beta1.ExternalSecretSpec{Data: []esv1beta1.ExternalSecretData{data("teams/team-a/../team-b/db")}},
+			wantErr: true,
+		},
+		{
+			name: "extract key outside of the prefixes",
+			spec: esv1beta1.ExternalSecretSpec{DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{
+				{Extract: &esv1beta1.ExternalSecretDataRemoteRef{Key: "shared/app"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "find without path",
+			spec: esv1beta1.ExternalSecretSpec{DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{
+				{Find: &esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: ".*"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "find path outside of the prefixes",
+			spec: esv1beta1.ExternalSecretSpec{DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{
+				{Find: &esv1beta1.ExternalSecretFind{Path: path("teams")}},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkKeyPrefixes(store, &esv1beta1.ExternalSecret{Spec: tt.spec})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkKeyPrefixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := checkKeyPrefixes(&esv1beta1.SecretStore{}, &esv1beta1.ExternalSecret{Spec: tests[1].spec}); err != nil {
+		t.Errorf("stores without allowedKeyPrefixes must allow all keys, got %v", err)
+	}
+}
+
+func TestFilterAllowedKeys(t *testing.T) {
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{AllowedKeyPrefixes: []string{"teams/team-a/"}},
+	}
+	got := filterAllowedKeys(store, map[string][]byte{
+		"teams/team-a/db":  []byte("a"),
+		"teams/team-ab/db": []byte("b"),
+		"shared/db":        []byte("c"),
+	})
+	if want := map[string][]byte{"teams/team-a/db": []byte("a")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterAllowedKeys() = %v, want %v", got, want)
+	}
+
+	all := map[string][]byte{"shared/db": []byte("c")}
+	if got := filterAllowedKeys(&esv1beta1.SecretStore{}, all); !reflect.DeepEqual(got, all) {
+		t.Errorf("stores without allowedKeyPrefixes must keep all keys, got %v", got)
+	}
+}
+
+func TestGetProviderSecretDataFiltersRemoteKeys(t *testing.T) {
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{AllowedKeyPrefixes: []string{"teams/team-a/"}},
+	}
+	// providers return the remote names, the controller converts them after filtering
+	providerClient := fake.New().WithGetAllSecrets(map[string][]byte{
+		"teams/team-a/db": []byte("a"),
+		"teams/team-b/db": []byte("b"),
+	}, nil)
+	path := "teams/team-a/"
+	es := &esv1beta1.ExternalSecret{
+		Spec: esv1beta1.ExternalSecretSpec{DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{Find: &esv1beta1.ExternalSecretFind{Path: &path, ConversionStrategy: esv1beta1.ExternalSecretConversionDefault}},
+		}},
+	}
+	r := &Reconciler{recorder: record.NewFakeRecorder(10)}
+	got, _, err := r.getProviderSecretData(context.Background(), store, providerClient, es)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string][]byte{"teams_team-a_db": []byte("a")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getProviderSecretData() = %v, want %v", got, want)
+	}
+}
@@ -19,13 +19,17 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/circuitbreaker"
 	// Loading registered providers.
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
 )
@@ -36,6 +40,7 @@ type ClusterStoreReconciler struct {
 	Log             logr.Logger
 	Scheme          *runtime.Scheme
 	ControllerClass string
+	CircuitBreakers *circuitbreaker.Registry
 	RequeueInterval time.Duration
 	recorder        record.EventRecorder
 }
@@ -45,20 +50,39 @@ func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	var css esapi.ClusterSecretStore
 	err := r.Get(ctx, req.NamespacedName, &css)
 	if apierrors.IsNotFound(err) {
+		r.CircuitBreakers.Remove(req.NamespacedName)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get ClusterSecretStore")
 		return ctrl.Result{}, err
 	}
 
-	return reconcile(ctx, req, &css, r.Client, log, r.ControllerClass, r.recorder, r.RequeueInterval)
+	return reconcile(ctx, req, &css, r.Client, log, r.ControllerClass, r.recorder, r.RequeueInterval, r.CircuitBreakers)
 }
 
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
 func (r *ClusterStoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.recorder = mgr.GetEventRecorderFor("cluster-secret-store")
 
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &esapi.ClusterSecretStore{}, credentialSecretsIndex, indexCredentialSecrets)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &esapi.ClusterSecretStore{}, providerConfigIndex, indexProviderConfig)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&esapi.ClusterSecretStore{}).
+		Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			credentialSecretHandler(r.Client, func() client.ObjectList { return &esapi.ClusterSecretStoreList{} }, r.Log),
+			builder.OnlyMetadata,
+		).
+		Watches(
+			&source.Kind{Type: &esapi.ProviderConfig{}},
+			providerConfigHandler(r.Client, func() client.ObjectList { return &esapi.ClusterSecretStoreList{} }, r.Log),
+		).
 		Complete(r)
 }
@@ -25,6 +25,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/circuitbreaker"
+	"github.com/external-secrets/external-secrets/pkg/redact"
 )
 
 const (
@@ -36,11 +38,14 @@ const (
 	errUnableValidateStore = "unable to validate store"
 	errUnableGetProvider   = "unable to get store provider"
 
+	errUnableResolveStore = "unable to resolve inherited store or provider config"
+
 	msgStoreValidated = "store validated"
+	msgStoreUnhealthy = "too many consecutive provider errors, retrying in %s"
 )
 
 func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl client.Client,
-	log logr.Logger, controllerClass string, recorder record.EventRecorder, requeueInterval time.Duration) (ctrl.Result, error) {
+	log logr.Logger, controllerClass string, recorder record.EventRecorder, requeueInterval time.Duration, breakers *circuitbreaker.Registry) (ctrl.Result, error) {
 	if !ShouldProcessStore(ss, controllerClass) {
 		log.V(1).Info("skip store")
 		return ctrl.Result{}, nil
@@ -59,15 +64,45 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 		}
 	}()
 
+	// providers are validated with the configuration inherited from a
+	// ClusterSecretStore and their ProviderConfig, conditions are set on the store itself
+	resolved, err := ResolveStore(ctx, cl, ss)
+	if err != nil {
+		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonInvalidStore, errUnableResolveStore)
+		SetExternalSecretCondition(ss, *cond)
+		recorder.Event(ss, v1.EventTypeWarning, esapi.ReasonInvalidStore, err.Error())
+		log.Error(err, errUnableResolveStore)
+		return ctrl.Result{}, err
+	}
+
+	// a one-shot validation reports every check in status,
+	// independent of the result of validateStore below
+	if request, ok := validationRequested(ss); ok {
+		log.V(1).Info("running requested validation", "request", request)
+		runValidation(ctx, request, req.Namespace, ss, resolved, cl, recorder)
+	}
+
 	// validateStore modifies the store conditions
 	// we have to patch the status
 	log.V(1).Info("validating")
-	err := validateStore(ctx, req.Namespace, ss, cl, recorder)
+	err = validateStore(ctx, req.Namespace, ss, resolved, cl, recorder)
 	if err != nil {
 		log.Error(err, "unable to validate store")
 		return ctrl.Result{}, err
 	}
 
+	// the store is valid but the provider keeps failing
+	if ok, retryIn := allowedByBreaker(breakers, ss); !ok {
+		msg := fmt.Sprintf(msgStoreUnhealthy, retryIn.Round(time.Second))
+		recorder.Event(ss, v1.EventTypeWarning, esapi.ReasonStoreUnhealthy, msg)
+		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonStoreUnhealthy, msg)
+		SetExternalSecretCondition(ss, *cond)
+		if retryIn < requeueInterval {
+			requeueInterval = retryIn
+		}
+		return ctrl.Result{RequeueAfter: requeueInterval}, nil
+	}
+
 	recorder.Event(ss, v1.EventTypeNormal, esapi.ReasonStoreValid, msgStoreValidated)
 	cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionTrue, esapi.ReasonStoreValid, msgStoreValidated)
 	SetExternalSecretCondition(ss, *cond)
@@ -77,11 +112,11 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 	}, err
 }
 
-// validateStore tries to construct a new client
-// if it fails sets a condition and writes events.
-func validateStore(ctx context.Context, namespace string, store esapi.GenericStore,
+// validateStore tries to construct a new client from the resolved store
+// if it fails sets a condition on the store and writes events.
+func validateStore(ctx context.Context, namespace string, store, resolved esapi.GenericStore,
 	client client.Client, recorder record.EventRecorder) error {
-	storeProvider, err := esapi.GetProvider(store)
+	storeProvider, err := esapi.GetProvider(resolved)
 	if err != nil {
 		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonInvalidStore, errUnableGetProvider)
 		SetExternalSecretCondition(store, *cond)
@@ -89,7 +124,8 @@ func validateStore(ctx context.Context, namespace string, store esapi.GenericSto
 		return fmt.Errorf(errStoreProvider, err)
 	}
 
-	cl, err := storeProvider.NewClient(ctx, store, client, namespace)
+	cl, err := storeProvider.NewClient(ctx, resolved, client, namespace)
+	err = redact.Error(err)
 	if err != nil {
 		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonInvalidProviderConfig, errUnableCreateClient)
 		SetExternalSecretCondition(store, *cond)
@@ -99,6 +135,7 @@ func validateStore(ctx context.Context, namespace string, store esapi.GenericSto
 	defer cl.Close(ctx)
 
 	validationResult, err := cl.Validate()
+	err = redact.Error(err)
 	if err != nil && validationResult != esapi.ValidationResultUnknown {
 		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonValidationFailed, errUnableValidateStore)
 		SetExternalSecretCondition(store, *cond)
@@ -106,9 +143,22 @@ func validateStore(ctx context.Context, namespace string, store esapi.GenericSto
 		return fmt.Errorf(errValidationFailed, err)
 	}
 
+	status := store.GetStatus()
+	capabilities := storeProvider.Capabilities(resolved)
+	status.Capabilities = &capabilities
+	store.SetStatus(status)
+
 	return nil
 }
 
+func allowedByBreaker(breakers *circuitbreaker.Registry, store esapi.GenericStore) (bool, time.Duration) {
+	breaker := breakers.ForStore(store)
+	if breaker == nil {
+		return true, 0
+	}
+	return breaker.Allow()
+}
+
 // ShouldProcessStore returns true if the store should be processed.
 func ShouldProcessStore(store esapi.GenericStore, class string) bool {
 	if store.GetSpec().Controller == "" || store.GetSpec().Controller == class {
@@ -116,6 +116,8 @@ var _ = Describe("SecretStore reconcile", func() {
 				return ss.GetStatus().Conditions[0].Reason == esapi.ReasonStoreValid &&
 					ss.GetStatus().Conditions[0].Type == esapi.SecretStoreReady &&
 					ss.GetStatus().Conditions[0].Status == corev1.ConditionTrue &&
+					ss.GetStatus().Capabilities != nil &&
+					ss.GetStatus().Capabilities.Access == esapi.SecretStoreReadOnly &&
 					hasEvent(tc.store.GetTypeMeta().Kind, ss.GetName(), esapi.ReasonStoreValid)
 			}).
 				WithTimeout(time.Second * 10).
@@ -0,0 +1,180 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	// credentialSecretsIndex indexes stores by the Secrets referenced in their provider spec.
+	credentialSecretsIndex = "spec.provider.credentialSecrets"
+
+	// anyNamespace is used as namespace of Secrets referenced by referent ClusterSecretStores.
+	// These Secrets are resolved in the namespace of each ExternalSecret.
+	anyNamespace = "*"
+)
+
+var (
+	secretKeySelectorType = reflect.TypeOf(esmeta.SecretKeySelector{})
+	caProviderType        = reflect.TypeOf(esapi.CAProvider{})
+)
+
+// indexCredentialSecrets returns the index values of a SecretStore or ClusterSecretStore.
+func indexCredentialSecrets(obj client.Object) []string {
+	store, ok := obj.(esapi.GenericStore)
+	if !ok {
+		return nil
+	}
+	return referencedSecrets(store)
+}
+
+// referencedSecrets returns the `namespace/name` of all Secrets referenced in the provider spec of a store.
+func referencedSecrets(store esapi.GenericStore) []string {
+	provider := store.GetSpec().Provider
+	if provider == nil {
+		return nil
+	}
+	storeNamespace := store.GetNamespace()
+	if _, ok := store.(*esapi.ClusterSecretStore); ok {
+		storeNamespace = anyNamespace
+	}
+	seen := make(map[string]struct{})
+	var refs []string
+	add := func(name string, namespace *string) {
+		if name == "" {
+			return
+		}
+		ns := storeNamespace
+		// a SecretStore can only reference Secrets in its own namespace
+		if namespace != nil && *namespace != "" && storeNamespace == anyNamespace {
+			ns = *namespace
+		}
+		ref := types.NamespacedName{Namespace: ns, Name: name}.String()
+		if _, ok := seen[ref]; ok {
+			return
+		}
+		seen[ref] = struct{}{}
+		refs = append(refs, ref)
+	}
+	walkSecretRefs(reflect.ValueOf(provider), add)
+	sort.Strings(refs)
+	return refs
+}
+
+// walkSecretRefs calls add for every SecretKeySelector and Secret CAProvider in v.
+func walkSecretRefs(v reflect.Value, add func(name string, namespace *string)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkSecretRefs(v.Elem(), add)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkSecretRefs(v.Index(i), add)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			walkSecretRefs(iter.Value(), add)
+		}
+	case reflect.Struct:
+		switch v.Type() {
+		case secretKeySelectorType:
+			sel := v.Interface().(esmeta.SecretKeySelector)
+			add(sel.Name, sel.Namespace)
+			return
+		case caProviderType:
+			ca := v.Interface().(esapi.CAProvider)
+			if ca.Type == esapi.CAProviderTypeSecret {
+				add(ca.Name, ca.Namespace)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walkSecretRefs(v.Field(i), add)
+			}
+		}
+	}
+}
+
+// credentialSecretHandler enqueues the stores that reference a changed Secret.
+// Cached provider clients of these stores are invalidated, so the next reconcile
+// builds a new client from the current credentials.
+func credentialSecretHandler(cl client.Client, newList func() client.ObjectList, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+		ctx := context.Background()
+		var requests []ctrl.Request
+		keys := []string{
+			types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String(),
+			types.NamespacedName{Namespace: anyNamespace, Name: obj.GetName()}.String(),
+		}
+		for _, key := range keys {
+			list := newList()
+			if err := cl.List(ctx, list, client.MatchingFields{credentialSecretsIndex: key}); err != nil {
+				log.Error(err, "unable to list stores referencing secret", "secret", key)
+				continue
+			}
+			for _, store := range storesOf(list) {
+				invalidateClients(ctx, store, log)
+				requests = append(requests, ctrl.Request{
+					NamespacedName: types.NamespacedName{Namespace: store.GetNamespace(), Name: store.GetName()},
+				})
+			}
+		}
+		return requests
+	})
+}
+
+func storesOf(list client.ObjectList) []esapi.GenericStore {
+	var stores []esapi.GenericStore
+	switch l := list.(type) {
+	case *esapi.SecretStoreList:
+		for i := range l.Items {
+			stores = append(stores, &l.Items[i])
+		}
+	case *esapi.ClusterSecretStoreList:
+		for i := range l.Items {
+			stores = append(stores, &l.Items[i])
+		}
+	}
+	return stores
+}
+
+// invalidateClients drops the cached provider clients of a store, if the provider caches them.
+func invalidateClients(ctx context.Context, store esapi.GenericStore, log logr.Logger) {
+	provider, err := esapi.GetProvider(store)
+	if err != nil {
+		return
+	}
+	invalidator, ok := provider.(esapi.ClientCacheInvalidator)
+	if !ok {
+		return
+	}
+	log.V(1).Info("credentials changed, invalidating cached clients", "store", store.GetName())
+	invalidator.InvalidateClients(ctx, store)
+}
@@ -0,0 +1,113 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func TestReferencedSecrets(t *testing.T) {
+	vault := func(secretNamespace *string) *esapi.SecretStoreProvider {
+		return &esapi.SecretStoreProvider{
+			Vault: &esapi.VaultProvider{
+				CAProvider: &esapi.CAProvider{
+					Type:      esapi.CAProviderTypeSecret,
+					Name:      "vault-ca",
+					Namespace: secretNamespace,
+				},
+				Auth: esapi.VaultAuth{
+					TokenSecretRef: &esmeta.SecretKeySelector{
+						Name:      "vault-token",
+						Namespace: secretNamespace,
+					},
+					AppRole: &esapi.VaultAppRole{
+						SecretRef: esmeta.SecretKeySelector{
+							Name:      "vault-token",
+							Namespace: secretNamespace,
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name  string
+		store esapi.GenericStore
+		want  []string
+	}{
+		{
+			name: "SecretStore references are namespaced",
+			store: &esapi.SecretStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"},
+				Spec: esapi.SecretStoreSpec{
+					Provider: vault(pointer.String("other")),
+				},
+			},
+			want: []string{"default/vault-ca", "default/vault-token"},
+		},
+		{
+			name: "ClusterSecretStore references use their namespace",
+			store: &esapi.ClusterSecretStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "store"},
+				Spec: esapi.SecretStoreSpec{
+					Provider: vault(pointer.String("vault")),
+				},
+			},
+			want: []string{"vault/vault-ca", "vault/vault-token"},
+		},
+		{
+			name: "referent ClusterSecretStore references match any namespace",
+			store: &esapi.ClusterSecretStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "store"},
+				Spec: esapi.SecretStoreSpec{
+					Provider: vault(nil),
+				},
+			},
+			want: []string{"*/vault-ca", "*/vault-token"},
+		},
+		{
+			name: "ConfigMap CA providers are ignored",
+			store: &esapi.SecretStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"},
+				Spec: esapi.SecretStoreSpec{
+					Provider: &esapi.SecretStoreProvider{
+						Vault: &esapi.VaultProvider{
+							CAProvider: &esapi.CAProvider{
+								Type: esapi.CAProviderTypeConfigMap,
+								Name: "vault-ca",
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := referencedSecrets(tt.store)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("referencedSecrets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	// inheritFromIndex indexes SecretStores by the ClusterSecretStore they inherit from.
+	inheritFromIndex = "spec.inheritFrom.clusterSecretStoreName"
+
+	errInheritClusterStore  = "inheritFrom is not supported by ClusterSecretStores"
+	errGetBaseStore         = "could not get ClusterSecretStore %q to inherit from: %w"
+	errBaseStoreInherits    = "ClusterSecretStore %q must not inherit from another store"
+	errBaseStoreNoProvider  = "ClusterSecretStore %q has no provider"
+	errOverrideNotSupported = "override %s is not supported by provider of ClusterSecretStore %q"
+	errInvalidInherited     = "invalid store inherited from ClusterSecretStore %q: %w"
+	errKeyPrefixNotNarrowed = "allowedKeyPrefixes entry %q is not covered by the allowedKeyPrefixes of ClusterSecretStore %q"
+)
+
+// ResolveInheritance returns the store with the provider configuration of the ClusterSecretStore it inherits from.
+// Stores that do not inherit are returned as they are. The resolved store is a copy,
+// status changes must be made to the original store.
+func ResolveInheritance(ctx context.Context, kube client.Client, store esapi.GenericStore) (esapi.GenericStore, error) {
+	inherit := store.GetSpec().InheritFrom
+	if inherit == nil {
+		return store, nil
+	}
+	ss, ok := store.(*esapi.SecretStore)
+	if !ok {
+		return nil, errors.New(errInheritClusterStore)
+	}
+
+	var base esapi.ClusterSecretStore
+	name := inherit.ClusterSecretStoreName
+	if err := kube.Get(ctx, types.NamespacedName{Name: name}, &base); err != nil {
+		return nil, fmt.Errorf(errGetBaseStore, name, err)
+	}
+	if base.Spec.InheritFrom != nil {
+		return nil, fmt.Errorf(errBaseStoreInherits, name)
+	}
+	if base.Spec.Provider == nil {
+		return nil, fmt.Errorf(errBaseStoreNoProvider, name)
+	}
+
+	resolved := ss.DeepCopy()
+	resolved.Spec.InheritFrom = nil
+	resolved.Spec.Provider = base.Spec.Provider.DeepCopy()
+	if resolved.Spec.RetrySettings == nil {
+		resolved.Spec.RetrySettings = base.Spec.RetrySettings.DeepCopy()
+	}
+	if resolved.Spec.RefreshInterval == 0 {
+		resolved.Spec.RefreshInterval = base.Spec.RefreshInterval
+	}
+	if resolved.Spec.CredentialsRefreshInterval == nil {
+		resolved.Spec.CredentialsRefreshInterval = base.Spec.CredentialsRefreshInterval.DeepCopy()
+	}
+	if resolved.Spec.ProviderConfigRef == nil {
+		resolved.Spec.ProviderConfigRef = base.Spec.ProviderConfigRef.DeepCopy()
+	}
+	// a SecretStore may only tighten the limits of the ClusterSecretStore
+	resolved.Spec.Limits = minLimits(base.Spec.Limits, resolved.Spec.Limits)
+	// a SecretStore may only narrow the keys allowed by the ClusterSecretStore
+	for _, prefix := range resolved.Spec.AllowedKeyPrefixes {
+		if !keyAllowed(base.Spec.AllowedKeyPrefixes, prefix) {
+			return nil, fmt.Errorf(errKeyPrefixNotNarrowed, prefix, name)
+		}
+	}
+	if len(resolved.Spec.AllowedKeyPrefixes) == 0 {
+		resolved.Spec.AllowedKeyPrefixes = append([]string(nil), base.Spec.AllowedKeyPrefixes...)
+	}
+	if err := applyOverrides(resolved.Spec.Provider, inherit.Overrides, name); err != nil {
+		return nil, err
+	}
+
+	// the inherited provider must be valid for a SecretStore,
+	// e.g. it must not reference credentials in other namespaces.
+	provider, err := esapi.GetProvider(resolved)
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidInherited, name, err)
+	}
+	if err := provider.ValidateStore(resolved); err != nil {
+		return nil, fmt.Errorf(errInvalidInherited, name, err)
+	}
+	return resolved, nil
+}
+
+func applyOverrides(provider *esapi.SecretStoreProvider, overrides *esapi.SecretStoreOverrides, base string) error {
+	if overrides == nil {
+		return nil
+	}
+	if overrides.Path != nil {
+		if provider.Vault == nil {
+			return fmt.Errorf(errOverrideNotSupported, "path", base)
+		}
+		path := *overrides.Path
+		provider.Vault.Path = &path
+	}
+	if overrides.ProjectID != nil {
+		if provider.GCPSM == nil {
+			return fmt.Errorf(errOverrideNotSupported, "projectID", base)
+		}
+		provider.GCPSM.ProjectID = *overrides.ProjectID
+	}
+	if overrides.Region != nil {
+		if provider.AWS == nil {
+			return fmt.Errorf(errOverrideNotSupported, "region", base)
+		}
+		provider.AWS.Region = *overrides.Region
+	}
+	return nil
+}
+
+// minLimits returns the stricter value of each limit set in base or own.
+func minLimits(base, own *esapi.SecretStoreLimits) *esapi.SecretStoreLimits {
+	if base == nil {
+		return own.DeepCopy()
+	}
+	if own == nil {
+		return base.DeepCopy()
+	}
+	limits := own.DeepCopy()
+	limits.MaxSecretSize = minQuantity(base.MaxSecretSize, own.MaxSecretSize)
+	limits.MaxTotalSize = minQuantity(base.MaxTotalSize, own.MaxTotalSize)
+	if base.MaxKeys != nil && (own.MaxKeys == nil || *base.MaxKeys < *own.MaxKeys) {
+		maxKeys := *base.MaxKeys
+		limits.MaxKeys = &maxKeys
+	}
+	return limits
+}
+
+func minQuantity(base, own *resource.Quantity) *resource.Quantity {
+	if base != nil && (own == nil || base.Cmp(*own) < 0) {
+		q := base.DeepCopy()
+		return &q
+	}
+	if own == nil {
+		return nil
+	}
+	q := own.DeepCopy()
+	return &q
+}
+
+// indexInheritFrom returns the index value of a SecretStore.
+func indexInheritFrom(obj client.Object) []string {
+	store, ok := obj.(*esapi.SecretStore)
+	if !ok || store.Spec.InheritFrom == nil {
+		return nil
+	}
+	return []string{store.Spec.InheritFrom.ClusterSecretStoreName}
+}
+
+// inheritingStoreHandler enqueues the SecretStores that inherit from a changed ClusterSecretStore.
+func inheritingStoreHandler(cl client.Client, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+		var list esapi.SecretStoreList
+		if err := cl.List(context.Background(), &list, client.MatchingFields{inheritFromIndex: obj.GetName()}); err != nil {
+			log.Error(err, "unable to list stores inheriting from cluster secret store", "clusterSecretStore", obj.GetName())
+			return nil
+		}
+		requests := make([]ctrl.Request, 0, len(list.Items))
+		for i := range list.Items {
+			requests = append(requests, ctrl.Request{
+				NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name},
+			})
+		}
+		return requests
+	})
+}
@@ -0,0 +1,220 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func TestResolveInheritance(t *testing.T) {
+	base := func(name string, tokenNamespace *string) *esapi.ClusterSecretStore {
+		return &esapi.ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: esapi.SecretStoreSpec{
+				RefreshInterval: 60,
+				Provider: &esapi.SecretStoreProvider{
+					Vault: &esapi.VaultProvider{
+						Server: "https://vault.example.com",
+						Path:   pointer.String("secret"),
+						Auth: esapi.VaultAuth{
+							TokenSecretRef: &esmeta.SecretKeySelector{
+								Name:      "vault-token",
+								Key:       "token",
+								Namespace: tokenNamespace,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	inheriting := func(name string, overrides *esapi.SecretStoreOverrides) *esapi.SecretStore {
+		return &esapi.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "team-a"},
+			Spec: esapi.SecretStoreSpec{
+				InheritFrom: &esapi.SecretStoreInheritance{
+					ClusterSecretStoreName: name,
+					Overrides:              overrides,
+				},
+			},
+		}
+	}
+	rotated := base("vault-rotated", nil)
+	rotated.Spec.CredentialsRefreshInterval = &metav1.Duration{Duration: time.Hour}
+	restricted := base("vault-restricted", nil)
+	restricted.Spec.AllowedKeyPrefixes = []string{"teams/"}
+	narrowed := inheriting("vault-restricted", nil)
+	narrowed.Spec.AllowedKeyPrefixes = []string{"teams/team-a/"}
+	widened := inheriting("vault-restricted", nil)
+	widened.Spec.AllowedKeyPrefixes = []string{"shared/"}
+
+	limited := base("vault-limited", nil)
+	limited.Spec.Limits = &esapi.SecretStoreLimits{
+		MaxSecretSize: resource.NewQuantity(1024, resource.BinarySI),
+		MaxKeys:       pointer.Int(10),
+	}
+	tightened := inheriting("vault-limited", nil)
+	tightened.Spec.Limits = &esapi.SecretStoreLimits{
+		MaxSecretSize: resource.NewQuantity(4096, resource.BinarySI),
+		MaxKeys:       pointer.Int(5),
+		MaxTotalSize:  resource.NewQuantity(8192, resource.BinarySI),
+	}
+
+	chained := base("chained", nil)
+	chained.Spec.Provider = nil
+	chained.Spec.InheritFrom = &esapi.SecretStoreInheritance{ClusterSecretStoreName: "vault"}
+
+	scheme := runtime.NewScheme()
+	if err := esapi.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		base("vault", nil),
+		base("vault-namespaced", pointer.String("vault")),
+		rotated,
+		restricted,
+		limited,
+		chained,
+	).Build()
+
+	tests := []struct {
+		name         string
+		store        esapi.GenericStore
+		wantPath     string
+		wantPrefixes []string
+		wantInterval *metav1.Duration
+		wantLimits   *esapi.SecretStoreLimits
+		wantErr      bool
+	}{
+		{
+			name:     "store without inheritance is returned as is",
+			store:    &esapi.SecretStore{Spec: esapi.SecretStoreSpec{Provider: base("", nil).Spec.Provider}},
+			wantPath: "secret",
+		},
+		{
+			name:     "provider is inherited",
+			store:    inheriting("vault", nil),
+			wantPath: "secret",
+		},
+		{
+			name:     "path is overridden",
+			store:    inheriting("vault", &esapi.SecretStoreOverrides{Path: pointer.String("team-a")}),
+			wantPath: "team-a",
+		},
+		{
+			name:    "override of another provider fails",
+			store:   inheriting("vault", &esapi.SecretStoreOverrides{Region: pointer.String("eu-west-1")}),
+			wantErr: true,
+		},
+		{
+			name:    "missing cluster secret store fails",
+			store:   inheriting("missing", nil),
+			wantErr: true,
+		},
+		{
+			name:    "chained inheritance fails",
+			store:   inheriting("chained", nil),
+			wantErr: true,
+		},
+		{
+			name:         "credentials refresh interval is inherited",
+			store:        inheriting("vault-rotated", nil),
+			wantPath:     "secret",
+			wantInterval: &metav1.Duration{Duration: time.Hour},
+		},
+		{
+			name:         "allowed key prefixes are inherited",
+			store:        inheriting("vault-restricted", nil),
+			wantPath:     "secret",
+			wantPrefixes: []string{"teams/"},
+		},
+		{
+			name:         "allowed key prefixes are narrowed",
+			store:        narrowed,
+			wantPath:     "secret",
+			wantPrefixes: []string{"teams/team-a/"},
+		},
+		{
+			name:    "allowed key prefixes must not be widened",
+			store:   widened,
+			wantErr: true,
+		},
+		{
+			name:       "limits are inherited",
+			store:      inheriting("vault-limited", nil),
+			wantPath:   "secret",
+			wantLimits: limited.Spec.Limits,
+		},
+		{
+			// looser limits of the SecretStore must not lift the limits of the ClusterSecretStore
+			name:     "stricter limit of each field applies",
+			store:    tightened,
+			wantPath: "secret",
+			wantLimits: &esapi.SecretStoreLimits{
+				MaxSecretSize: resource.NewQuantity(1024, resource.BinarySI),
+				MaxKeys:       pointer.Int(5),
+				MaxTotalSize:  resource.NewQuantity(8192, resource.BinarySI),
+			},
+		},
+		{
+			name:    "namespaced references are rejected for secret stores",
+			store:   inheriting("vault-namespaced", nil),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveInheritance(context.Background(), kube, tt.store)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveInheritance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			spec := got.GetSpec()
+			if spec.InheritFrom != nil {
+				t.Errorf("resolved store still inherits from %q", spec.InheritFrom.ClusterSecretStoreName)
+			}
+			if path := *spec.Provider.Vault.Path; path != tt.wantPath {
+				t.Errorf("path = %q, want %q", path, tt.wantPath)
+			}
+			if !reflect.DeepEqual(spec.AllowedKeyPrefixes, tt.wantPrefixes) {
+				t.Errorf("allowedKeyPrefixes = %v, want %v", spec.AllowedKeyPrefixes, tt.wantPrefixes)
+			}
+			if !reflect.DeepEqual(spec.CredentialsRefreshInterval, tt.wantInterval) {
+				t.Errorf("credentialsRefreshInterval = %v, want %v", spec.CredentialsRefreshInterval, tt.wantInterval)
+			}
+			if !equality.Semantic.DeepEqual(spec.Limits, tt.wantLimits) {
+				t.Errorf("limits = %+v, want %+v", spec.Limits, tt.wantLimits)
+			}
+			if tt.store.GetSpec().InheritFrom != nil && tt.store.GetSpec().Provider != nil {
+				t.Errorf("original store was modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	// providerConfigIndex indexes stores by the ProviderConfig they reference.
+	providerConfigIndex = "spec.providerConfigRef.name"
+
+	errGetProviderConfig     = "could not get ProviderConfig %q: %w"
+	errInvalidProviderConfig = "invalid store with ProviderConfig %q: %w"
+)
+
+// ResolveStore returns the store with its inheritance and ProviderConfig resolved.
+// Stores that neither inherit nor reference a ProviderConfig are returned as they are.
+// The resolved store is a copy, status changes must be made to the original store.
+func ResolveStore(ctx context.Context, kube client.Client, store esapi.GenericStore) (esapi.GenericStore, error) {
+	resolved, err := ResolveInheritance(ctx, kube, store)
+	if err != nil {
+		return nil, err
+	}
+	return ResolveProviderConfig(ctx, kube, resolved)
+}
+
+// ResolveProviderConfig returns the store with the fields of the ProviderConfig it references applied.
+// Stores that do not reference a ProviderConfig are returned as they are.
+func ResolveProviderConfig(ctx context.Context, kube client.Client, store esapi.GenericStore) (esapi.GenericStore, error) {
+	ref := store.GetSpec().ProviderConfigRef
+	if ref == nil {
+		return store, nil
+	}
+	var pc esapi.ProviderConfig
+	if err := kube.Get(ctx, types.NamespacedName{Name: ref.Name}, &pc); err != nil {
+		return nil, fmt.Errorf(errGetProviderConfig, ref.Name, err)
+	}
+
+	resolved := store.Copy()
+	spec := resolved.GetSpec()
+	if pc.Spec.RetrySettings != nil {
+		spec.RetrySettings = pc.Spec.RetrySettings.DeepCopy()
+	}
+	if spec.Provider != nil {
+		applyCABundle(spec.Provider, pc.Spec.CABundle)
+		applyEndpoints(spec.Provider, pc.Spec.Endpoints)
+	}
+
+	provider, err := esapi.GetProvider(resolved)
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidProviderConfig, ref.Name, err)
+	}
+	if err := provider.ValidateStore(resolved); err != nil {
+		return nil, fmt.Errorf(errInvalidProviderConfig, ref.Name, err)
+	}
+	return resolved, nil
+}
+
+// applyCABundle replaces the CA of the providers that support a caBundle.
+func applyCABundle(provider *esapi.SecretStoreProvider, caBundle []byte) {
+	if len(caBundle) == 0 {
+		return
+	}
+	bundle := func() []byte {
+		return append([]byte(nil), caBundle...)
+	}
+	switch {
+	case provider.Vault != nil:
+		provider.Vault.CABundle, provider.Vault.CAProvider = bundle(), nil
+	case provider.Webhook != nil:
+		provider.Webhook.CABundle, provider.Webhook.CAProvider = bundle(), nil
+	case provider.Kubernetes != nil:
+		provider.Kubernetes.Server.CABundle, provider.Kubernetes.Server.CAProvider = bundle(), nil
+	case provider.Etcd != nil:
+		provider.Etcd.CABundle, provider.Etcd.CAProvider = bundle(), nil
+	case provider.CyberArkCCP != nil:
+		provider.CyberArkCCP.CABundle, provider.CyberArkCCP.CAProvider = bundle(), nil
+	}
+}
+
+// applyEndpoints replaces the endpoints of the provider, endpoints of other providers are ignored.
+func applyEndpoints(provider *esapi.SecretStoreProvider, endpoints *esapi.ProviderConfigEndpoints) {
+	if endpoints == nil {
+		return
+	}
+	if endpoints.Vault != nil && provider.Vault != nil {
+		provider.Vault.Server = *endpoints.Vault
+	}
+	if endpoints.IBM != nil && provider.IBM != nil {
+		url := *endpoints.IBM
+		provider.IBM.ServiceURL = &url
+	}
+	if endpoints.Alibaba != nil && provider.Alibaba != nil {
+		provider.Alibaba.Endpoint = *endpoints.Alibaba
+	}
+	if endpoints.Yandex != nil {
+		if provider.YandexLockbox != nil {
+			provider.YandexLockbox.APIEndpoint = *endpoints.Yandex
+		}
+		if provider.YandexCertificateManager != nil {
+			provider.YandexCertificateManager.APIEndpoint = *endpoints.Yandex
+		}
+	}
+}
+
+// indexProviderConfig returns the index value of a store.
+func indexProviderConfig(obj client.Object) []string {
+	store, ok := obj.(esapi.GenericStore)
+	if !ok || store.GetSpec().ProviderConfigRef == nil {
+		return nil
+	}
+	return []string{store.GetSpec().ProviderConfigRef.Name}
+}
+
+// providerConfigHandler enqueues the stores that reference a changed ProviderConfig.
+func providerConfigHandler(cl client.Client, newList func() client.ObjectList, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+		ctx := context.Background()
+		list := newList()
+		if err := cl.List(ctx, list, client.MatchingFields{providerConfigIndex: obj.GetName()}); err != nil {
+			log.Error(err, "unable to list stores referencing provider config", "providerConfig", obj.GetName())
+			return nil
+		}
+		var requests []ctrl.Request
+		for _, store := range storesOf(list) {
+			invalidateClients(ctx, store, log)
+			requests = append(requests, ctrl.Request{
+				NamespacedName: types.NamespacedName{Namespace: store.GetNamespace(), Name: store.GetName()},
+			})
+		}
+		return requests
+	})
+}
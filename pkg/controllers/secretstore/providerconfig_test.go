@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func TestResolveProviderConfig(t *testing.T) {
+	vault := func(providerConfig string) *esapi.ClusterSecretStore {
+		store := &esapi.ClusterSecretStore{
+			TypeMeta:   metav1.TypeMeta{Kind: esapi.ClusterSecretStoreKind},
+			ObjectMeta: metav1.ObjectMeta{Name: "vault"},
+			Spec: esapi.SecretStoreSpec{
+				Provider: &esapi.SecretStoreProvider{
+					Vault: &esapi.VaultProvider{
+						Server: "https://vault.example.com",
+						Path:   pointer.String("secret"),
+						CAProvider: &esapi.CAProvider{
+							Type: esapi.CAProviderTypeConfigMap,
+							Name: "vault-ca",
+						},
+						Auth: esapi.VaultAuth{
+							TokenSecretRef: &esmeta.SecretKeySelector{
+								Name:      "vault-token",
+								Key:       "token",
+								Namespace: pointer.String("vault"),
+							},
+						},
+					},
+				},
+			},
+		}
+		if providerConfig != "" {
+			store.Spec.ProviderConfigRef = &esapi.ProviderConfigRef{Name: providerConfig}
+		}
+		return store
+	}
+	inheriting := &esapi.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "team-a"},
+		Spec: esapi.SecretStoreSpec{
+			InheritFrom: &esapi.SecretStoreInheritance{ClusterSecretStoreName: "vault-fleet"},
+		},
+	}
+	fleet := vault("fleet")
+	fleet.Name = "vault-fleet"
+	fleet.Spec.Provider.Vault.CAProvider = nil
+	fleet.Spec.Provider.Vault.Auth.TokenSecretRef.Namespace = nil
+
+	scheme := runtime.NewScheme()
+	if err := esapi.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		fleet,
+		&esapi.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+			Spec: esapi.ProviderConfigSpec{
+				RetrySettings: &esapi.SecretStoreRetrySettings{MaxRetries: pointer.Int32(5)},
+				CABundle:      []byte("ca"),
+				Endpoints: &esapi.ProviderConfigEndpoints{
+					Vault:   pointer.String("https://vault.internal:8200"),
+					Alibaba: pointer.String("kms-vpc.cn-hangzhou.aliyuncs.com"),
+				},
+			},
+		},
+	).Build()
+
+	tests := []struct {
+		name       string
+		store      esapi.GenericStore
+		wantServer string
+		wantCA     string
+		wantErr    bool
+	}{
+		{
+			name:       "store without provider config is returned as is",
+			store:      vault(""),
+			wantServer: "https://vault.example.com",
+		},
+		{
+			name:       "provider config replaces the store fields",
+			store:      vault("fleet"),
+			wantServer: "https://vault.internal:8200",
+			wantCA:     "ca",
+		},
+		{
+			name:       "provider config of the inherited store is used",
+			store:      inheriting,
+			wantServer: "https://vault.internal:8200",
+			wantCA:     "ca",
+		},
+		{
+			name:    "missing provider config fails",
+			store:   vault("missing"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveStore(context.Background(), kube, tt.store)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			spec := got.GetSpec()
+			if server := spec.Provider.Vault.Server; server != tt.wantServer {
+				t.Errorf("server = %q, want %q", server, tt.wantServer)
+			}
+			if tt.wantCA == "" {
+				return
+			}
+			if ca := string(spec.Provider.Vault.CABundle); ca != tt.wantCA || spec.Provider.Vault.CAProvider != nil {
+				t.Errorf("caBundle = %q, caProvider = %v, want %q", ca, spec.Provider.Vault.CAProvider, tt.wantCA)
+			}
+			if spec.RetrySettings == nil || *spec.RetrySettings.MaxRetries != 5 {
+				t.Errorf("retrySettings = %v, want maxRetries 5", spec.RetrySettings)
+			}
+			if tt.store.GetSpec().Provider != nil && tt.store.GetSpec().Provider.Vault.Server != "https://vault.example.com" {
+				t.Errorf("original store was modified")
+			}
+		})
+	}
+}
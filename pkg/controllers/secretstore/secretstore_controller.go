@@ -19,13 +19,17 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/circuitbreaker"
 	// Loading registered providers.
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
 )
@@ -38,6 +42,7 @@ type StoreReconciler struct {
 	recorder        record.EventRecorder
 	RequeueInterval time.Duration
 	ControllerClass string
+	CircuitBreakers *circuitbreaker.Registry
 }
 
 func (r *StoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -45,20 +50,47 @@ func (r *StoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	var ss esapi.SecretStore
 	err := r.Get(ctx, req.NamespacedName, &ss)
 	if apierrors.IsNotFound(err) {
+		r.CircuitBreakers.Remove(req.NamespacedName)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get SecretStore")
 		return ctrl.Result{}, err
 	}
 
-	return reconcile(ctx, req, &ss, r.Client, log, r.ControllerClass, r.recorder, r.RequeueInterval)
+	return reconcile(ctx, req, &ss, r.Client, log, r.ControllerClass, r.recorder, r.RequeueInterval, r.CircuitBreakers)
 }
 
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
 func (r *StoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.recorder = mgr.GetEventRecorderFor("secret-store")
 
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &esapi.SecretStore{}, credentialSecretsIndex, indexCredentialSecrets)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &esapi.SecretStore{}, providerConfigIndex, indexProviderConfig)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &esapi.SecretStore{}, inheritFromIndex, indexInheritFrom)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&esapi.SecretStore{}).
+		Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			credentialSecretHandler(r.Client, func() client.ObjectList { return &esapi.SecretStoreList{} }, r.Log),
+			builder.OnlyMetadata,
+		).
+		Watches(
+			&source.Kind{Type: &esapi.ProviderConfig{}},
+			providerConfigHandler(r.Client, func() client.ObjectList { return &esapi.SecretStoreList{} }, r.Log),
+		).
+		Watches(
+			&source.Kind{Type: &esapi.ClusterSecretStore{}},
+			inheritingStoreHandler(r.Client, r.Log),
+		).
 		Complete(r)
 }
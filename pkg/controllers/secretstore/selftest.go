@@ -0,0 +1,214 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/redact"
+)
+
+const (
+	checkWrite  = "Write"
+	checkRead   = "Read"
+	checkDelete = "Delete"
+
+	msgWriteUnsupported = "provider does not support writing secrets"
+	msgNothingWritten   = "skipped, no secret was written"
+	msgNoRemoteRef      = "skipped, spec.remoteRef is not set"
+	msgSecretMissing    = "secret %q does not exist"
+	msgSecretRead       = "read %d bytes"
+	msgKeyNotAllowed    = "key %q is not allowed by the allowedKeyPrefixes of store %s"
+	msgSelfTestRun      = "self test finished: %s"
+
+	errGetSelfTestStore = "could not get store %q: %w"
+)
+
+// SelfTestReconciler runs every SelfTest once against its store.
+type SelfTestReconciler struct {
+	client.Client
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	recorder        record.EventRecorder
+	ControllerClass string
+}
+
+func (r *SelfTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("selftest", req.NamespacedName)
+	var st esapi.SelfTest
+	err := r.Get(ctx, req.NamespacedName, &st)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, "unable to get SelfTest")
+		return ctrl.Result{}, err
+	}
+	if st.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	p := client.MergeFrom(st.DeepCopy())
+	store, resolved, err := r.getStore(ctx, &st)
+	switch {
+	case err != nil:
+		st.Status.Checks = append([]esapi.SecretStoreValidationCheck{
+			{Name: checkValidateStore, Result: esapi.ValidationCheckFailed, Message: err.Error()},
+		}, skippedChecks(checkNewClient, checkValidate, checkWrite, checkRead, checkDelete)...)
+	case !ShouldProcessStore(store, r.ControllerClass):
+		log.V(1).Info("skip selftest, the store belongs to another controller")
+		return ctrl.Result{}, nil
+	default:
+		storeProvider, err := esapi.GetProvider(resolved)
+		if err != nil {
+			st.Status.Checks = append([]esapi.SecretStoreValidationCheck{
+				{Name: checkValidateStore, Result: esapi.ValidationCheckFailed, Message: err.Error()},
+			}, skippedChecks(checkNewClient, checkValidate, checkWrite, checkRead, checkDelete)...)
+			break
+		}
+		st.Status.Checks = selfTestChecks(ctx, storeProvider, resolved, r.Client, st.Namespace, st.Spec.RemoteRef)
+	}
+
+	st.Status.Result = esapi.ValidationCheckPassed
+	eventType := v1.EventTypeNormal
+	for _, check := range st.Status.Checks {
+		if check.Result == esapi.ValidationCheckFailed {
+			st.Status.Result = esapi.ValidationCheckFailed
+			eventType = v1.EventTypeWarning
+		}
+	}
+	now := metav1.Now()
+	st.Status.CompletionTime = &now
+	if err := r.Status().Patch(ctx, &st, p); err != nil {
+		log.Error(err, errPatchStatus)
+		return ctrl.Result{}, err
+	}
+	r.recorder.Event(&st, eventType, esapi.ReasonSelfTestRun, fmt.Sprintf(msgSelfTestRun, st.Status.Result))
+	return ctrl.Result{}, nil
+}
+
+// getStore returns the referenced store and the store resolved
+// with its ProviderConfig and the ClusterSecretStore it inherits from.
+func (r *SelfTestReconciler) getStore(ctx context.Context, st *esapi.SelfTest) (esapi.GenericStore, esapi.GenericStore, error) {
+	ref := types.NamespacedName{Name: st.Spec.SecretStoreRef.Name}
+	var store esapi.GenericStore = &esapi.SecretStore{}
+	if st.Spec.SecretStoreRef.Kind == esapi.ClusterSecretStoreKind {
+		store = &esapi.ClusterSecretStore{}
+	} else {
+		ref.Namespace = st.Namespace
+	}
+	if err := r.Get(ctx, ref, store); err != nil {
+		return nil, nil, fmt.Errorf(errGetSelfTestStore, ref.Name, err)
+	}
+	resolved, err := ResolveStore(ctx, r.Client, store)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errGetSelfTestStore, ref.Name, err)
+	}
+	return store, resolved, nil
+}
+
+// selfTestChecks validates the store, then writes, reads and deletes a secret.
+// Once a check fails, the remaining checks are skipped.
+func selfTestChecks(ctx context.Context, storeProvider esapi.Provider, store esapi.GenericStore, kube client.Client,
+	namespace string, remoteRef *esapi.SelfTestRemoteRef) []esapi.SecretStoreValidationCheck {
+	checks := validationChecks(ctx, storeProvider, store, kube, namespace)
+	for _, check := range checks {
+		if check.Result == esapi.ValidationCheckFailed {
+			return append(checks, skippedChecks(checkWrite, checkRead, checkDelete)...)
+		}
+	}
+
+	// none of the providers can write secrets yet,
+	// the store is only tested with an existing secret
+	checks = append(checks, esapi.SecretStoreValidationCheck{Name: checkWrite, Result: esapi.ValidationCheckSkipped, Message: msgWriteUnsupported})
+	checks = append(checks, readCheck(ctx, storeProvider, store, kube, namespace, remoteRef))
+	return append(checks, esapi.SecretStoreValidationCheck{Name: checkDelete, Result: esapi.ValidationCheckSkipped, Message: msgNothingWritten})
+}
+
+func readCheck(ctx context.Context, storeProvider esapi.Provider, store esapi.GenericStore, kube client.Client, namespace string,
+	remoteRef *esapi.SelfTestRemoteRef) esapi.SecretStoreValidationCheck {
+	check := esapi.SecretStoreValidationCheck{Name: checkRead}
+	if remoteRef == nil {
+		check.Result = esapi.ValidationCheckSkipped
+		check.Message = msgNoRemoteRef
+		return check
+	}
+	// the self test must not read more than an ExternalSecret could
+	if !KeyAllowed(store, remoteRef.Key) {
+		check.Result = esapi.ValidationCheckFailed
+		check.Message = fmt.Sprintf(msgKeyNotAllowed, remoteRef.Key, store.GetName())
+		return check
+	}
+	cl, err := storeProvider.NewClient(ctx, store, kube, namespace)
+	err = redact.Error(err)
+	if err != nil {
+		check.Result = esapi.ValidationCheckFailed
+		check.Message = err.Error()
+		return check
+	}
+	defer cl.Close(ctx)
+
+	data, err := cl.GetSecret(ctx, esapi.ExternalSecretDataRemoteRef{
+		Key:      remoteRef.Key,
+		Version:  remoteRef.Version,
+		Property: remoteRef.Property,
+	})
+	err = redact.Error(err)
+	if err == nil {
+		err = CheckLimits(store, map[string][]byte{remoteRef.Key: data})
+	}
+	switch {
+	case errors.Is(err, esapi.NoSecretErr):
+		check.Result = esapi.ValidationCheckFailed
+		check.Message = fmt.Sprintf(msgSecretMissing, remoteRef.Key)
+	case err != nil:
+		check.Result = esapi.ValidationCheckFailed
+		check.Message = err.Error()
+	default:
+		// the value itself is never reported
+		check.Result = esapi.ValidationCheckPassed
+		check.Message = fmt.Sprintf(msgSecretRead, len(data))
+	}
+	return check
+}
+
+func skippedChecks(names ...string) []esapi.SecretStoreValidationCheck {
+	checks := make([]esapi.SecretStoreValidationCheck, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, esapi.SecretStoreValidationCheck{Name: name, Result: esapi.ValidationCheckSkipped, Message: msgValidationSkipped})
+	}
+	return checks
+}
+
+// SetupWithManager returns a new controller builder that will be started by the provided Manager.
+func (r *SelfTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor("self-test")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&esapi.SelfTest{}).
+		Complete(r)
+}
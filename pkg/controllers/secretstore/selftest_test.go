@@ -0,0 +1,118 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+func TestSelfTestChecks(t *testing.T) {
+	errBoom := errors.New("boom")
+	remoteRef := &esapi.SelfTestRemoteRef{Key: "selftest"}
+	passed, skipped, failed := esapi.ValidationCheckPassed, esapi.ValidationCheckSkipped, esapi.ValidationCheckFailed
+	tests := []struct {
+		name      string
+		provider  esapi.Provider
+		remoteRef *esapi.SelfTestRemoteRef
+		// restricted stores allow teams/team-a/ and values of up to 4 bytes
+		restricted bool
+		want       []esapi.SecretStoreValidationResult
+		message    string
+	}{
+		{
+			name:      "secret is read",
+			provider:  fake.New().WithGetSecret([]byte("value"), nil),
+			remoteRef: remoteRef,
+			want:      []esapi.SecretStoreValidationResult{passed, passed, passed, skipped, passed, skipped},
+			message:   fmt.Sprintf(msgSecretRead, 5),
+		},
+		{
+			name:     "no remoteRef",
+			provider: fake.New(),
+			want:     []esapi.SecretStoreValidationResult{passed, passed, passed, skipped, skipped, skipped},
+			message:  msgNoRemoteRef,
+		},
+		{
+			name:      "secret does not exist",
+			provider:  fake.New().WithGetSecret(nil, esapi.NoSecretErr),
+			remoteRef: remoteRef,
+			want:      []esapi.SecretStoreValidationResult{passed, passed, passed, skipped, failed, skipped},
+			message:   fmt.Sprintf(msgSecretMissing, "selftest"),
+		},
+		{
+			name:      "read fails",
+			provider:  fake.New().WithGetSecret(nil, errBoom),
+			remoteRef: remoteRef,
+			want:      []esapi.SecretStoreValidationResult{passed, passed, passed, skipped, failed, skipped},
+			message:   errBoom.Error(),
+		},
+		{
+			name:       "key not allowed",
+			provider:   fake.New().WithGetSecret([]byte("value"), nil),
+			remoteRef:  &esapi.SelfTestRemoteRef{Key: "teams/team-b/selftest"},
+			restricted: true,
+			want:       []esapi.SecretStoreValidationResult{passed, passed, passed, skipped, failed, skipped},
+			message:    fmt.Sprintf(msgKeyNotAllowed, "teams/team-b/selftest", "store"),
+		},
+		{
+			name:       "secret exceeds limits",
+			provider:   fake.New().WithGetSecret([]byte("value"), nil),
+			remoteRef:  &esapi.SelfTestRemoteRef{Key: "teams/team-a/large"},
+			restricted: true,
+			want:       []esapi.SecretStoreValidationResult{passed, passed, passed, skipped, failed, skipped},
+			message:    fmt.Sprintf(errSecretTooLarge, "teams/team-a/large", 5, 4, "store"),
+		},
+		{
+			name: "client creation fails",
+			provider: fake.New().WithNew(func(context.Context, esapi.GenericStore, client.Client, string) (esapi.SecretsClient, error) {
+				return nil, errBoom
+			}),
+			remoteRef: remoteRef,
+			want:      []esapi.SecretStoreValidationResult{passed, failed, skipped, skipped, skipped, skipped},
+			message:   msgValidationSkipped,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &esapi.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"}}
+			if tt.restricted {
+				store.Spec.AllowedKeyPrefixes = []string{"teams/team-a/"}
+				store.Spec.Limits = &esapi.SecretStoreLimits{MaxSecretSize: resource.NewQuantity(4, resource.DecimalSI)}
+			}
+			checks := selfTestChecks(context.Background(), tt.provider, store, nil, "default", tt.remoteRef)
+			got := make([]esapi.SecretStoreValidationResult, 0, len(checks))
+			for _, check := range checks {
+				got = append(got, check.Result)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selfTestChecks() results = %v, want %v", got, tt.want)
+			}
+			if len(checks) == 6 && checks[4].Message != tt.message {
+				t.Errorf("selfTestChecks() read message = %q, want %q", checks[4].Message, tt.message)
+			}
+		})
+	}
+}
@@ -15,12 +15,21 @@ limitations under the License.
 package secretstore
 
 import (
+	"fmt"
+	"strings"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
 
+const (
+	errTooManyKeys    = "provider data has %d keys, exceeding the limit of %d keys of store %s"
+	errSecretTooLarge = "value of key %q has %d bytes, exceeding the limit of %d bytes of store %s"
+	errTotalTooLarge  = "provider data has %d bytes, exceeding the limit of %d bytes of store %s"
+)
+
 // NewSecretStoreCondition a set of default options for creating an External Secret Condition.
 func NewSecretStoreCondition(condType esapi.SecretStoreConditionType, status v1.ConditionStatus, reason, message string) *esapi.SecretStoreStatusCondition {
 	return &esapi.SecretStoreStatusCondition{
@@ -73,3 +82,52 @@ func filterOutCondition(conditions []esapi.SecretStoreStatusCondition, condType
 	}
 	return newConditions
 }
+
+// KeyAllowed returns true if the key starts with one of the allowed key prefixes of the store.
+// Stores without allowed key prefixes allow all keys.
+func KeyAllowed(store esapi.GenericStore, key string) bool {
+	return keyAllowed(store.GetSpec().AllowedKeyPrefixes, key)
+}
+
+func keyAllowed(prefixes []string, key string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	// relative segments could leave the prefix once the provider resolves the key
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckLimits returns an error if the provider data of an ExternalSecret
+// exceeds the limits of the store.
+func CheckLimits(store esapi.GenericStore, dataMap map[string][]byte) error {
+	limits := store.GetSpec().Limits
+	if limits == nil {
+		return nil
+	}
+	storeName := store.GetName()
+	if limits.MaxKeys != nil && len(dataMap) > *limits.MaxKeys {
+		return fmt.Errorf(errTooManyKeys, len(dataMap), *limits.MaxKeys, storeName)
+	}
+	var total int64
+	for key, value := range dataMap {
+		size := int64(len(value))
+		if limits.MaxSecretSize != nil && size > limits.MaxSecretSize.Value() {
+			return fmt.Errorf(errSecretTooLarge, key, size, limits.MaxSecretSize.Value(), storeName)
+		}
+		total += size
+	}
+	if limits.MaxTotalSize != nil && total > limits.MaxTotalSize.Value() {
+		return fmt.Errorf(errTotalTooLarge, total, limits.MaxTotalSize.Value(), storeName)
+	}
+	return nil
+}
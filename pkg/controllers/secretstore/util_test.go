@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestCheckLimits(t *testing.T) {
+	maxKeys := 2
+	maxSecretSize := resource.MustParse("4")
+	maxTotalSize := resource.MustParse("6")
+	store := &esapi.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "limited"},
+		Spec: esapi.SecretStoreSpec{Limits: &esapi.SecretStoreLimits{
+			MaxKeys:       &maxKeys,
+			MaxSecretSize: &maxSecretSize,
+			MaxTotalSize:  &maxTotalSize,
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		data    map[string][]byte
+		wantErr bool
+	}{
+		{
+			name: "within limits",
+			data: map[string][]byte{"a": []byte("1234"), "b": []byte("12")},
+		},
+		{
+			name:    "too many keys",
+			data:    map[string][]byte{"a": nil, "b": nil, "c": nil},
+			wantErr: true,
+		},
+		{
+			name:    "value too large",
+			data:    map[string][]byte{"a": []byte("12345")},
+			wantErr: true,
+		},
+		{
+			name:    "total too large",
+			data:    map[string][]byte{"a": []byte("1234"), "b": []byte("123")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckLimits(store, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := CheckLimits(&esapi.SecretStore{}, tests[1].data); err != nil {
+		t.Errorf("stores without limits must allow all data, got %v", err)
+	}
+}
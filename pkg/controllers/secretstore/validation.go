@@ -0,0 +1,137 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/redact"
+)
+
+const (
+	checkValidateStore = "ValidateStore"
+	checkNewClient     = "NewClient"
+	checkValidate      = "Validate"
+
+	msgValidationUnsupported = "provider does not support validation"
+	msgValidationSkipped     = "skipped, a previous check failed"
+	msgValidationRun         = "validation %q finished: %s"
+)
+
+// validationRequested returns the value of the validate annotation
+// if it has not been processed yet.
+func validationRequested(store esapi.GenericStore) (string, bool) {
+	request := store.GetObjectMeta().Annotations[esapi.AnnotationValidate]
+	if request == "" {
+		return "", false
+	}
+	if v := store.GetStatus().Validation; v != nil && v.Request == request {
+		return "", false
+	}
+	return request, true
+}
+
+// runValidation runs all validation checks against the resolved store
+// and records the results in status.validation of the store.
+func runValidation(ctx context.Context, request, namespace string, store, resolved esapi.GenericStore,
+	kube client.Client, recorder record.EventRecorder) {
+	validation := &esapi.SecretStoreValidation{
+		Request: request,
+		Time:    metav1.Now(),
+		Result:  esapi.ValidationCheckPassed,
+	}
+
+	storeProvider, err := esapi.GetProvider(resolved)
+	if err != nil {
+		validation.Checks = []esapi.SecretStoreValidationCheck{
+			{Name: checkValidateStore, Result: esapi.ValidationCheckFailed, Message: err.Error()},
+			{Name: checkNewClient, Result: esapi.ValidationCheckSkipped, Message: msgValidationSkipped},
+			{Name: checkValidate, Result: esapi.ValidationCheckSkipped, Message: msgValidationSkipped},
+		}
+	} else {
+		validation.Checks = validationChecks(ctx, storeProvider, resolved, kube, namespace)
+	}
+
+	eventType := v1.EventTypeNormal
+	for _, check := range validation.Checks {
+		if check.Result == esapi.ValidationCheckFailed {
+			validation.Result = esapi.ValidationCheckFailed
+			eventType = v1.EventTypeWarning
+		}
+	}
+
+	status := store.GetStatus()
+	status.Validation = validation
+	store.SetStatus(status)
+	recorder.Event(store, eventType, esapi.ReasonStoreValidationRun, fmt.Sprintf(msgValidationRun, request, validation.Result))
+}
+
+// validationChecks validates the store spec, creates a client and probes the provider.
+// Once a check fails, the remaining checks are skipped.
+func validationChecks(ctx context.Context, storeProvider esapi.Provider, store esapi.GenericStore,
+	kube client.Client, namespace string) []esapi.SecretStoreValidationCheck {
+	skipped := func(name string) esapi.SecretStoreValidationCheck {
+		return esapi.SecretStoreValidationCheck{Name: name, Result: esapi.ValidationCheckSkipped, Message: msgValidationSkipped}
+	}
+
+	if err := storeProvider.ValidateStore(store); err != nil {
+		return []esapi.SecretStoreValidationCheck{
+			{Name: checkValidateStore, Result: esapi.ValidationCheckFailed, Message: err.Error()},
+			skipped(checkNewClient),
+			skipped(checkValidate),
+		}
+	}
+	checks := []esapi.SecretStoreValidationCheck{
+		{Name: checkValidateStore, Result: esapi.ValidationCheckPassed},
+	}
+
+	cl, err := storeProvider.NewClient(ctx, store, kube, namespace)
+	err = redact.Error(err)
+	if err != nil {
+		return append(checks,
+			esapi.SecretStoreValidationCheck{Name: checkNewClient, Result: esapi.ValidationCheckFailed, Message: err.Error()},
+			skipped(checkValidate),
+		)
+	}
+	defer cl.Close(ctx)
+	checks = append(checks, esapi.SecretStoreValidationCheck{Name: checkNewClient, Result: esapi.ValidationCheckPassed})
+
+	check := esapi.SecretStoreValidationCheck{Name: checkValidate}
+	result, err := cl.Validate()
+	err = redact.Error(err)
+	switch {
+	case result == esapi.ValidationResultUnknown:
+		check.Result = esapi.ValidationCheckUnknown
+		check.Message = msgValidationUnsupported
+		if err != nil {
+			check.Message = err.Error()
+		}
+	case err != nil || result == esapi.ValidationResultError:
+		check.Result = esapi.ValidationCheckFailed
+		if err != nil {
+			check.Message = err.Error()
+		}
+	default:
+		check.Result = esapi.ValidationCheckPassed
+	}
+	return append(checks, check)
+}
@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+type validateResultClient struct {
+	*fake.Client
+	result esapi.ValidationResult
+	err    error
+}
+
+func (c *validateResultClient) Validate() (esapi.ValidationResult, error) {
+	return c.result, c.err
+}
+
+func TestValidationChecks(t *testing.T) {
+	errBoom := errors.New("boom")
+	withValidate := func(result esapi.ValidationResult, err error) *fake.Client {
+		p := fake.New()
+		return p.WithNew(func(context.Context, esapi.GenericStore, client.Client, string) (esapi.SecretsClient, error) {
+			return &validateResultClient{Client: p, result: result, err: err}, nil
+		})
+	}
+	tests := []struct {
+		name     string
+		provider esapi.Provider
+		want     []esapi.SecretStoreValidationResult
+		message  string
+	}{
+		{
+			name:     "all checks pass",
+			provider: fake.New(),
+			want:     []esapi.SecretStoreValidationResult{esapi.ValidationCheckPassed, esapi.ValidationCheckPassed, esapi.ValidationCheckPassed},
+		},
+		{
+			name: "client creation fails",
+			provider: fake.New().WithNew(func(context.Context, esapi.GenericStore, client.Client, string) (esapi.SecretsClient, error) {
+				return nil, errBoom
+			}),
+			want:    []esapi.SecretStoreValidationResult{esapi.ValidationCheckPassed, esapi.ValidationCheckFailed, esapi.ValidationCheckSkipped},
+			message: errBoom.Error(),
+		},
+		{
+			name:     "validate fails",
+			provider: withValidate(esapi.ValidationResultError, errBoom),
+			want:     []esapi.SecretStoreValidationResult{esapi.ValidationCheckPassed, esapi.ValidationCheckPassed, esapi.ValidationCheckFailed},
+			message:  errBoom.Error(),
+		},
+		{
+			name:     "validate not supported",
+			provider: withValidate(esapi.ValidationResultUnknown, nil),
+			want:     []esapi.SecretStoreValidationResult{esapi.ValidationCheckPassed, esapi.ValidationCheckPassed, esapi.ValidationCheckUnknown},
+			message:  msgValidationUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &esapi.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"}}
+			checks := validationChecks(context.Background(), tt.provider, store, nil, "default")
+			got := make([]esapi.SecretStoreValidationResult, 0, len(checks))
+			message := ""
+			for _, check := range checks {
+				got = append(got, check.Result)
+				if check.Result == esapi.ValidationCheckFailed || check.Result == esapi.ValidationCheckUnknown {
+					message = check.Message
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validationChecks() results = %v, want %v", got, tt.want)
+			}
+			if message != tt.message {
+				t.Errorf("validationChecks() message = %q, want %q", message, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidationRequested(t *testing.T) {
+	store := &esapi.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"}}
+	if _, ok := validationRequested(store); ok {
+		t.Errorf("validation requested without annotation")
+	}
+	store.Annotations = map[string]string{esapi.AnnotationValidate: "build-42"}
+	if request, ok := validationRequested(store); !ok || request != "build-42" {
+		t.Errorf("validationRequested() = %q, %v, want build-42, true", request, ok)
+	}
+	store.Status.Validation = &esapi.SecretStoreValidation{Request: "build-42"}
+	if _, ok := validationRequested(store); ok {
+		t.Errorf("validation requested twice for the same annotation value")
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentialfile reads credential files mounted into the controller
+// by the cluster administrator, e.g. a Vault agent token.
+package credentialfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is the directory credential files must be located in.
+// Credential files are disabled if it is empty.
+var Dir string
+
+const (
+	errDisabled    = "credential files are disabled, the controller was started without --credential-file-dir"
+	errNotAbsolute = "path %q must be absolute"
+	errOutsideDir  = "path %q is not in the credential file directory %s"
+)
+
+// Validate returns an error if path is not located in Dir.
+func Validate(path string) error {
+	if Dir == "" {
+		return errors.New(errDisabled)
+	}
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf(errNotAbsolute, path)
+	}
+	if !within(filepath.Clean(Dir), filepath.Clean(path)) {
+		return fmt.Errorf(errOutsideDir, path, Dir)
+	}
+	return nil
+}
+
+// Read returns the content of the credential file at path.
+// Symlinks are resolved first, so a link in Dir can not point outside of it.
+func Read(path string) ([]byte, error) {
+	if err := Validate(path); err != nil {
+		return nil, err
+	}
+	dir, err := filepath.EvalSymlinks(Dir)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	if !within(dir, resolved) {
+		return nil, fmt.Errorf(errOutsideDir, path, Dir)
+	}
+	return os.ReadFile(resolved)
+}
+
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,61 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "credentials")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(root, "outside")
+	for path, content := range map[string]string{filepath.Join(dir, "token"): "token", outside: "secret"} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	old := Dir
+	t.Cleanup(func() { Dir = old })
+
+	Dir = ""
+	if _, err := Read(filepath.Join(dir, "token")); err == nil || err.Error() != errDisabled {
+		t.Errorf("expected disabled error, got %v", err)
+	}
+
+	Dir = dir
+	data, err := Read(filepath.Join(dir, "token"))
+	if err != nil || string(data) != "token" {
+		t.Errorf("Read() = %q, %v", data, err)
+	}
+	for _, path := range []string{
+		"token",
+		outside,
+		filepath.Join(dir, "..", "outside"),
+		filepath.Join(dir, "link"),
+	} {
+		if _, err := Read(path); err == nil {
+			t.Errorf("Read(%q) succeeded, want error", path)
+		}
+	}
+}
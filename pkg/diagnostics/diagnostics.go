@@ -0,0 +1,179 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnostics serves pprof and a JSON dump of the controller state
+// to diagnose slow reconciles in production.
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// queueDepthMetric is the reconcile queue depth registered by controller-runtime.
+const queueDepthMetric = "workqueue_depth"
+
+var (
+	mu       sync.Mutex
+	inflight = make(map[string]int)
+	caches   = make(map[string]func() int)
+)
+
+// State is the JSON document served at /debug/state.
+type State struct {
+	// QueueDepth is the number of queued reconcile requests per controller.
+	QueueDepth map[string]int `json:"queueDepth"`
+	// InflightRequests is the number of reconciles using the provider of a store,
+	// keyed by kind/namespace/name of the store.
+	InflightRequests map[string]int `json:"inflightRequests"`
+	// CachedClients is the number of cached provider clients per cache.
+	CachedClients map[string]int `json:"cachedClients"`
+}
+
+// TrackRequest counts a reconcile as inflight for the store until done is called.
+func TrackRequest(store esv1beta1.GenericStore) (done func()) {
+	key := storeKey(store)
+	mu.Lock()
+	inflight[key]++
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if inflight[key]--; inflight[key] <= 0 {
+			delete(inflight, key)
+		}
+	}
+}
+
+// RegisterClientCache registers a provider client cache, size must be safe for concurrent use.
+func RegisterClientCache(name string, size func() int) {
+	mu.Lock()
+	defer mu.Unlock()
+	caches[name] = size
+}
+
+// Snapshot returns the current state, the queue depth is read from the gatherer.
+func Snapshot(gatherer prometheus.Gatherer) (State, error) {
+	state := State{
+		QueueDepth:       make(map[string]int),
+		InflightRequests: make(map[string]int),
+		CachedClients:    make(map[string]int),
+	}
+	families, err := gatherer.Gather()
+	if err != nil {
+		return state, err
+	}
+	for _, family := range families {
+		if family.GetName() != queueDepthMetric {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "name" {
+					state.QueueDepth[label.GetValue()] = int(m.GetGauge().GetValue())
+				}
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for key, n := range inflight {
+		state.InflightRequests[key] = n
+	}
+	for name, size := range caches {
+		state.CachedClients[name] = size()
+	}
+	return state, nil
+}
+
+// Server serves the diagnostics endpoints, it runs on every replica.
+type Server struct {
+	addr     string
+	gatherer prometheus.Gatherer
+}
+
+var _ manager.LeaderElectionRunnable = &Server{}
+
+// NewServer returns a Server listening on addr.
+func NewServer(addr string) *Server {
+	return &Server{addr: addr, gatherer: metrics.Registry}
+}
+
+// Handler returns the handler of the diagnostics endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/state", func(w http.ResponseWriter, r *http.Request) {
+		state, err := Snapshot(s.gatherer)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(state)
+	})
+	return mux
+}
+
+// Start serves the endpoints until the context is canceled.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// NeedLeaderElection returns false, the endpoints are served by standby replicas as well.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func storeKey(store esv1beta1.GenericStore) string {
+	if _, ok := store.(*esv1beta1.ClusterSecretStore); ok {
+		return esv1beta1.ClusterSecretStoreKind + "/" + store.GetName()
+	}
+	return esv1beta1.SecretStoreKind + "/" + store.GetNamespace() + "/" + store.GetName()
+}
@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestState(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: queueDepthMetric}, []string{"name"})
+	registry.MustRegister(depth)
+	depth.WithLabelValues("externalsecret").Set(3)
+
+	RegisterClientCache("test", func() int { return 2 })
+	ss := &esv1beta1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"}}
+	css := &esv1beta1.ClusterSecretStore{ObjectMeta: metav1.ObjectMeta{Name: "store"}}
+	doneSS := TrackRequest(ss)
+	doneSS2 := TrackRequest(ss)
+	doneCSS := TrackRequest(css)
+	doneCSS()
+
+	srv := &Server{gatherer: registry}
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/state", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	var state State
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if got := state.QueueDepth["externalsecret"]; got != 3 {
+		t.Errorf("queue depth = %d, want 3", got)
+	}
+	if got := state.InflightRequests["SecretStore/default/store"]; got != 2 {
+		t.Errorf("inflight requests = %d, want 2", got)
+	}
+	if _, ok := state.InflightRequests["ClusterSecretStore/store"]; ok {
+		t.Errorf("finished requests must be removed, got %v", state.InflightRequests)
+	}
+	if got := state.CachedClients["test"]; got != 2 {
+		t.Errorf("cached clients = %d, want 2", got)
+	}
+
+	doneSS()
+	doneSS2()
+	state, err := Snapshot(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.InflightRequests) != 0 {
+		t.Errorf("expected no inflight requests, got %v", state.InflightRequests)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("pprof index returned status %d", rec.Code)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package execcredential runs binaries installed by the cluster administrator
+// that return short-lived provider credentials.
+package execcredential
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// PluginDir is the directory exec credential binaries are looked up in.
+// Exec credentials are disabled if it is empty.
+var PluginDir string
+
+const defaultTimeout = 10 * time.Second
+
+const (
+	errDisabled       = "exec credentials are disabled, the controller was started without --exec-credential-plugin-dir"
+	errStoreKind      = "exec credentials can only be used with a ClusterSecretStore"
+	errMissingCommand = "exec credential command must not be empty"
+	errInvalidCommand = "exec credential command %q must be a name in the plugin directory, not a path"
+	errRun            = "exec credential command %q failed: %w: %s"
+	errTimeout        = "exec credential command %q did not finish within %s"
+	errDecode         = "could not decode output of exec credential command %q: %w"
+	errNoCredentials  = "exec credential command %q returned no credentials"
+	errEnvNotAllowed  = "exec credential env %q is not allowed, it changes how binaries and libraries are loaded"
+)
+
+// loaderEnv are environment variables that make the binary load other code
+// than the administrator installed. Names prefixed with LD_ or DYLD_
+// and names ending in PATH are rejected as well.
+var loaderEnv = map[string]bool{
+	"BASH_ENV":      true,
+	"ENV":           true,
+	"IFS":           true,
+	"NODE_OPTIONS":  true,
+	"PERL5LIB":      true,
+	"PERL5OPT":      true,
+	"PYTHONHOME":    true,
+	"PYTHONSTARTUP": true,
+	"RUBYLIB":       true,
+	"RUBYOPT":       true,
+}
+
+// Credential is the output of an exec credential binary.
+type Credential struct {
+	// Values are the provider specific credentials.
+	Values map[string]string `json:"credentials"`
+	// Expiration is the time the credentials expire.
+	// The zero time means the credentials do not expire.
+	Expiration time.Time `json:"expirationTimestamp,omitempty"`
+}
+
+// Validate returns an error if src can not be run for store.
+// Exec credentials are run with the permissions of the controller,
+// so only cluster administrators may configure them in a ClusterSecretStore.
+func Validate(store esv1beta1.GenericStore, src *esv1beta1.ExecCredentialSource) error {
+	if store.GetObjectKind().GroupVersionKind().Kind != esv1beta1.ClusterSecretStoreKind {
+		return errors.New(errStoreKind)
+	}
+	if err := ValidateCommand(src.Command); err != nil {
+		return err
+	}
+	return validateEnv(src.Env)
+}
+
+// ValidateCommand returns an error if command can not be looked up in the plugin directory.
+func ValidateCommand(command string) error {
+	if command == "" {
+		return errors.New(errMissingCommand)
+	}
+	if strings.ContainsAny(command, `/\`) || command == "." || command == ".." {
+		return fmt.Errorf(errInvalidCommand, command)
+	}
+	return nil
+}
+
+func validateEnv(env []esv1beta1.ExecEnvVar) error {
+	for _, e := range env {
+		name := strings.ToUpper(e.Name)
+		if loaderEnv[name] || strings.HasPrefix(name, "LD_") || strings.HasPrefix(name, "DYLD_") || strings.HasSuffix(name, "PATH") {
+			return fmt.Errorf(errEnvNotAllowed, e.Name)
+		}
+	}
+	return nil
+}
+
+// Run executes the binary configured in src and decodes the credentials it prints to stdout.
+// The binary only sees the environment configured in src.
+func Run(ctx context.Context, src *esv1beta1.ExecCredentialSource) (*Credential, error) {
+	if PluginDir == "" {
+		return nil, errors.New(errDisabled)
+	}
+	if err := ValidateCommand(src.Command); err != nil {
+		return nil, err
+	}
+	if err := validateEnv(src.Env); err != nil {
+		return nil, err
+	}
+	timeout := defaultTimeout
+	if src.Timeout != nil && src.Timeout.Duration > 0 {
+		timeout = src.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, filepath.Join(PluginDir, src.Command), src.Args...)
+	cmd.Env = make([]string, 0, len(src.Env))
+	for _, e := range src.Env {
+		cmd.Env = append(cmd.Env, e.Name+"="+e.Value)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf(errTimeout, src.Command, timeout)
+		}
+		return nil, fmt.Errorf(errRun, src.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var cred Credential
+	if err := json.Unmarshal(stdout.Bytes(), &cred); err != nil {
+		return nil, fmt.Errorf(errDecode, src.Command, err)
+	}
+	if len(cred.Values) == 0 {
+		return nil, fmt.Errorf(errNoCredentials, src.Command)
+	}
+	return &cred, nil
+}
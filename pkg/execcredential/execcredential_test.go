@@ -0,0 +1,148 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package execcredential
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func withPlugin(t *testing.T, name, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := PluginDir
+	PluginDir = dir
+	t.Cleanup(func() { PluginDir = old })
+}
+
+func TestRun(t *testing.T) {
+	withPlugin(t, "broker", `echo "{\"credentials\":{\"token\":\"$TOKEN-$1\"},\"expirationTimestamp\":\"2030-01-02T03:04:05Z\"}"`)
+	cred, err := Run(context.Background(), &esv1beta1.ExecCredentialSource{
+		Command: "broker",
+		Args:    []string{"arg"},
+		Env:     []esv1beta1.ExecEnvVar{{Name: "TOKEN", Value: "value"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cred.Values["token"] != "value-arg" {
+		t.Errorf("unexpected credentials: %v", cred.Values)
+	}
+	if !cred.Expiration.Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected expiration: %v", cred.Expiration)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		src     esv1beta1.ExecCredentialSource
+		wantErr string
+	}{
+		{
+			name:    "path",
+			src:     esv1beta1.ExecCredentialSource{Command: "../broker"},
+			wantErr: "must be a name in the plugin directory",
+		},
+		{
+			name:    "loader env",
+			src:     esv1beta1.ExecCredentialSource{Command: "broker", Env: []esv1beta1.ExecEnvVar{{Name: "LD_PRELOAD", Value: "/tmp/evil.so"}}},
+			wantErr: "is not allowed",
+		},
+		{
+			name:    "exit code",
+			script:  "echo denied >&2; exit 1",
+			src:     esv1beta1.ExecCredentialSource{Command: "broker"},
+			wantErr: "denied",
+		},
+		{
+			name:    "timeout",
+			script:  "exec sleep 5",
+			src:     esv1beta1.ExecCredentialSource{Command: "broker", Timeout: &metav1.Duration{Duration: 100 * time.Millisecond}},
+			wantErr: "did not finish within",
+		},
+		{
+			name:    "invalid output",
+			script:  "echo nope",
+			src:     esv1beta1.ExecCredentialSource{Command: "broker"},
+			wantErr: "could not decode",
+		},
+		{
+			name:    "no credentials",
+			script:  `echo '{"credentials":{}}'`,
+			src:     esv1beta1.ExecCredentialSource{Command: "broker"},
+			wantErr: "returned no credentials",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withPlugin(t, "broker", tt.script)
+			_, err := Run(context.Background(), &tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunDisabled(t *testing.T) {
+	old := PluginDir
+	PluginDir = ""
+	t.Cleanup(func() { PluginDir = old })
+	if _, err := Run(context.Background(), &esv1beta1.ExecCredentialSource{Command: "broker"}); err == nil {
+		t.Errorf("expected an error if no plugin directory is configured")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	clusterStore := &esv1beta1.ClusterSecretStore{TypeMeta: metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind}}
+	tests := []struct {
+		name    string
+		store   esv1beta1.GenericStore
+		env     []string
+		wantErr bool
+	}{
+		{name: "cluster secret store", store: clusterStore, env: []string{"BROKER_URL", "HOME"}},
+		{name: "secret store", store: &esv1beta1.SecretStore{TypeMeta: metav1.TypeMeta{Kind: esv1beta1.SecretStoreKind}}, wantErr: true},
+		{name: "LD_PRELOAD", store: clusterStore, env: []string{"LD_PRELOAD"}, wantErr: true},
+		{name: "DYLD_INSERT_LIBRARIES", store: clusterStore, env: []string{"DYLD_INSERT_LIBRARIES"}, wantErr: true},
+		{name: "PATH", store: clusterStore, env: []string{"PATH"}, wantErr: true},
+		{name: "path style", store: clusterStore, env: []string{"PythonPath"}, wantErr: true},
+		{name: "shell startup", store: clusterStore, env: []string{"BASH_ENV"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &esv1beta1.ExecCredentialSource{Command: "broker"}
+			for _, name := range tt.env {
+				src.Env = append(src.Env, esv1beta1.ExecEnvVar{Name: name, Value: "value"})
+			}
+			if err := Validate(tt.store, src); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
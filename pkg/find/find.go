@@ -38,3 +38,13 @@ func New(findName esv1beta1.FindName) (*Matcher, error) {
 func (m *Matcher) MatchName(name string) bool {
 	return m.re.MatchString(name)
 }
+
+// CheckLimit returns a TooManyResultsError if another secret would exceed ref.MaxResults.
+// Providers call it before fetching the value of a matching secret
+// so that listing stops as soon as the limit is exceeded.
+func CheckLimit(ref esv1beta1.ExternalSecretFind, found int) error {
+	if ref.MaxResults != nil && found >= *ref.MaxResults {
+		return esv1beta1.TooManyResultsError{MaxResults: *ref.MaxResults}
+	}
+	return nil
+}
@@ -155,6 +155,13 @@ func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
+
 func newClient(_ context.Context, store esv1beta1.GenericStore, kube client.Client, corev1 typedcorev1.CoreV1Interface, namespace string) (esv1beta1.SecretsClient, error) {
 	akl := &akeylessBase{
 		kube:      kube,
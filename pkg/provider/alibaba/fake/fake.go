@@ -15,15 +15,20 @@ limitations under the License.
 package fake
 
 import (
+	"fmt"
+
 	kmssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
+	rmsdk "github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
 )
 
 type AlibabaMockClient struct {
 	getSecretValue func(request *kmssdk.GetSecretValueRequest) (response *kmssdk.GetSecretValueResponse, err error)
+	// Secrets are returned by ListSecrets, one page per entry.
+	Secrets [][]kmssdk.Secret
 }
 
-func (mc *AlibabaMockClient) GetSecretValue(*kmssdk.GetSecretValueRequest) (result *kmssdk.GetSecretValueResponse, err error) {
-	return mc.getSecretValue(&kmssdk.GetSecretValueRequest{})
+func (mc *AlibabaMockClient) GetSecretValue(request *kmssdk.GetSecretValueRequest) (result *kmssdk.GetSecretValueResponse, err error) {
+	return mc.getSecretValue(request)
 }
 
 func (mc *AlibabaMockClient) WithValue(in *kmssdk.GetSecretValueRequest, val *kmssdk.GetSecretValueResponse, err error) {
@@ -33,3 +38,43 @@ func (mc *AlibabaMockClient) WithValue(in *kmssdk.GetSecretValueRequest, val *km
 		}
 	}
 }
+
+func (mc *AlibabaMockClient) ListSecrets(request *kmssdk.ListSecretsRequest) (*kmssdk.ListSecretsResponse, error) {
+	page, err := request.PageNumber.GetValue()
+	if err != nil {
+		return nil, err
+	}
+	resp := &kmssdk.ListSecretsResponse{PageNumber: page}
+	pageSize, _ := request.PageSize.GetValue()
+	resp.TotalCount = len(mc.Secrets) * pageSize
+	if page >= 1 && page <= len(mc.Secrets) {
+		resp.SecretList.Secret = mc.Secrets[page-1]
+	}
+	return resp, nil
+}
+
+// WithSecretValues returns the value of the requested secret.
+func (mc *AlibabaMockClient) WithSecretValues(values map[string]string) {
+	if mc != nil {
+		mc.getSecretValue = func(paramIn *kmssdk.GetSecretValueRequest) (*kmssdk.GetSecretValueResponse, error) {
+			value, ok := values[paramIn.SecretName]
+			if !ok {
+				return nil, fmt.Errorf("secret %s not found", paramIn.SecretName)
+			}
+			return &kmssdk.GetSecretValueResponse{SecretName: paramIn.SecretName, SecretData: value}, nil
+		}
+	}
+}
+
+// AlibabaMockRMClient returns the resources of a single page.
+type AlibabaMockRMClient struct {
+	Resources []rmsdk.Resource
+	Request   *rmsdk.ListResourcesRequest
+}
+
+func (mc *AlibabaMockRMClient) ListResources(request *rmsdk.ListResourcesRequest) (*rmsdk.ListResourcesResponse, error) {
+	mc.Request = request
+	resp := &rmsdk.ListResourcesResponse{TotalCount: len(mc.Resources)}
+	resp.Resources.Resource = mc.Resources
+	return resp, nil
+}
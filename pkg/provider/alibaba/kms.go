@@ -18,15 +18,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/auth/credentials"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	kmssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
+	rmsdk "github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
+	stssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/sts"
 	"github.com/tidwall/gjson"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
 	"github.com/external-secrets/external-secrets/pkg/provider/aws/util"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
@@ -40,6 +48,13 @@ const (
 	errFetchAKIDSecret                         = "could not fetch AccessKeyID secret: %w"
 	errMissingSAK                              = "missing AccessSecretKey"
 	errMissingAKID                             = "missing AccessKeyID"
+	errAssumeRole                              = "could not assume RAM role %s: %w"
+	errListSecrets                             = "could not list secrets: %w"
+	errListResources                           = "could not list secrets of resource group %s: %w"
+	errUnexpectedFindOperator                  = "unexpected find operator, name or tags is required"
+
+	defaultRoleSessionName = "external-secrets"
+	listPageSize           = 100
 )
 
 type Client struct {
@@ -57,12 +72,21 @@ var _ esv1beta1.SecretsClient = &KeyManagementService{}
 var _ esv1beta1.Provider = &KeyManagementService{}
 
 type KeyManagementService struct {
-	Client SMInterface
-	url    string
+	Client          SMInterface
+	RMClient        RMInterface
+	url             string
+	regionID        string
+	resourceGroupID string
 }
 
 type SMInterface interface {
 	GetSecretValue(request *kmssdk.GetSecretValueRequest) (response *kmssdk.GetSecretValueResponse, err error)
+	ListSecrets(request *kmssdk.ListSecretsRequest) (response *kmssdk.ListSecretsResponse, err error)
+}
+
+// RMInterface lists the resources of a resource group.
+type RMInterface interface {
+	ListResources(request *rmsdk.ListResourcesRequest) (response *rmsdk.ListResourcesResponse, err error)
 }
 
 // setAuth creates a new Alibaba session based on a store.
@@ -101,8 +125,6 @@ func (c *Client) setAuth(ctx context.Context) error {
 		objectKey.Namespace = *c.store.Auth.SecretRef.AccessKeySecret.Namespace
 	}
 	c.keyID = credentialsSecret.Data[c.store.Auth.SecretRef.AccessKeyID.Key]
-	fmt.Println(c.keyID)
-	fmt.Println(c.accessKey)
 	if (c.keyID == nil) || (len(c.keyID) == 0) {
 		return fmt.Errorf(errMissingAKID)
 	}
@@ -114,10 +136,129 @@ func (c *Client) setAuth(ctx context.Context) error {
 	return nil
 }
 
-// Empty GetAllSecrets.
+// GetAllSecrets finds secrets by name and tags, the path is matched as a prefix of the secret name.
+// With a resource group only the secrets of the resource group are returned.
 func (kms *KeyManagementService) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	// TO be implemented
-	return nil, fmt.Errorf("GetAllSecrets not implemented")
+	if utils.IsNil(kms.Client) {
+		return nil, fmt.Errorf(errUninitalizedAlibabaProvider)
+	}
+	if ref.Name == nil && len(ref.Tags) == 0 {
+		return nil, fmt.Errorf(errUnexpectedFindOperator)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+	var inGroup map[string]bool
+	if kms.resourceGroupID != "" {
+		group, err := kms.resourceGroupSecrets()
+		if err != nil {
+			return nil, err
+		}
+		inGroup = group
+	}
+
+	secretMap := make(map[string][]byte)
+	for page := 1; ; page++ {
+		request := kmssdk.CreateListSecretsRequest()
+		request.SetScheme("https")
+		request.FetchTags = "true"
+		request.PageNumber = requests.NewInteger(page)
+		request.PageSize = requests.NewInteger(listPageSize)
+		kms.setDomain(request)
+		resp, err := kms.Client.ListSecrets(request)
+		if err != nil {
+			return nil, fmt.Errorf(errListSecrets, util.SanitizeErr(err))
+		}
+		for _, secret := range resp.SecretList.Secret {
+			name := secret.SecretName
+			if matcher != nil && !matcher.MatchName(name) {
+				continue
+			}
+			if ref.Path != nil && !strings.HasPrefix(name, *ref.Path) {
+				continue
+			}
+			if inGroup != nil && !inGroup[name] {
+				continue
+			}
+			if !matchesTags(secret.Tags.Tag, ref.Tags) {
+				continue
+			}
+			if err := find.CheckLimit(ref, len(secretMap)); err != nil {
+				return nil, err
+			}
+			secretMap[name], err = kms.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: name})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(resp.SecretList.Secret) == 0 || page*listPageSize >= resp.TotalCount {
+			break
+		}
+	}
+	return secretMap, nil
+}
+
+// resourceGroupSecrets returns the names of the KMS secrets in the resource group.
+func (kms *KeyManagementService) resourceGroupSecrets() (map[string]bool, error) {
+	if utils.IsNil(kms.RMClient) {
+		return nil, fmt.Errorf(errUninitalizedAlibabaProvider)
+	}
+	names := make(map[string]bool)
+	for page := 1; ; page++ {
+		request := rmsdk.CreateListResourcesRequest()
+		request.SetScheme("https")
+		request.ResourceGroupId = kms.resourceGroupID
+		request.Service = "kms"
+		request.ResourceType = "secret"
+		request.Region = kms.regionID
+		request.PageNumber = requests.NewInteger(page)
+		request.PageSize = requests.NewInteger(listPageSize)
+		resp, err := kms.RMClient.ListResources(request)
+		if err != nil {
+			return nil, fmt.Errorf(errListResources, kms.resourceGroupID, util.SanitizeErr(err))
+		}
+		for _, resource := range resp.Resources.Resource {
+			// the resource ID is either the secret name or its ARN ending with secret/<name>
+			id := resource.ResourceId
+			if i := strings.LastIndex(id, "secret/"); i >= 0 {
+				id = id[i+len("secret/"):]
+			}
+			names[id] = true
+		}
+		if len(resp.Resources.Resource) == 0 || page*listPageSize >= resp.TotalCount {
+			break
+		}
+	}
+	return names, nil
+}
+
+// matchesTags returns true if the secret has all tags.
+func matchesTags(secretTags []kmssdk.Tag, tags map[string]string) bool {
+	for k, v := range tags {
+		found := false
+		for _, tag := range secretTags {
+			if tag.TagKey == k && tag.TagValue == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// setDomain sends the request to the configured KMS endpoint.
+func (kms *KeyManagementService) setDomain(request requests.AcsRequest) {
+	if kms.url != "" {
+		request.SetDomain(kms.url)
+	}
 }
 
 // GetSecret returns a single secret from the provider.
@@ -129,6 +270,7 @@ func (kms *KeyManagementService) GetSecret(ctx context.Context, ref esv1beta1.Ex
 	kmsRequest.VersionId = ref.Version
 	kmsRequest.SecretName = ref.Key
 	kmsRequest.SetScheme("https")
+	kms.setDomain(kmsRequest)
 	secretOut, err := kms.Client.GetSecretValue(kmsRequest)
 	if err != nil {
 		return nil, util.SanitizeErr(err)
@@ -182,17 +324,55 @@ func (kms *KeyManagementService) NewClient(ctx context.Context, store esv1beta1.
 		return nil, err
 	}
 	alibabaRegion := iStore.regionID
-	alibabaKeyID := iStore.keyID
-	alibabaSecretKey := iStore.accessKey
-	keyManagementService, err := kmssdk.NewClientWithAccessKey(alibabaRegion, string(alibabaKeyID), string(alibabaSecretKey))
+	var credential auth.Credential = credentials.NewAccessKeyCredential(string(iStore.keyID), string(iStore.accessKey))
+	if role := alibabaSpec.Auth.RAMRole; role != nil {
+		stsCredential, err := assumeRole(alibabaRegion, alibabaSpec.STSEndpoint, string(iStore.keyID), string(iStore.accessKey), role)
+		if err != nil {
+			return nil, err
+		}
+		credential = stsCredential
+	}
+	keyManagementService, err := kmssdk.NewClientWithOptions(alibabaRegion, sdk.NewConfig(), credential)
 	if err != nil {
 		return nil, fmt.Errorf(errAlibabaClient, err)
 	}
 	kms.Client = keyManagementService
+	if alibabaSpec.ResourceGroupID != "" {
+		resourceManager, err := rmsdk.NewClientWithOptions(alibabaRegion, sdk.NewConfig(), credential)
+		if err != nil {
+			return nil, fmt.Errorf(errAlibabaClient, err)
+		}
+		kms.RMClient = resourceManager
+	}
 	kms.url = alibabaSpec.Endpoint
+	kms.regionID = alibabaRegion
+	kms.resourceGroupID = alibabaSpec.ResourceGroupID
 	return kms, nil
 }
 
+// assumeRole returns temporary credentials of the RAM role, requested from the STS endpoint.
+func assumeRole(regionID, endpoint, keyID, accessKey string, role *esv1beta1.AlibabaRAMRole) (auth.Credential, error) {
+	stsClient, err := stssdk.NewClientWithAccessKey(regionID, keyID, accessKey)
+	if err != nil {
+		return nil, fmt.Errorf(errAlibabaClient, err)
+	}
+	request := stssdk.CreateAssumeRoleRequest()
+	request.SetScheme("https")
+	if endpoint != "" {
+		request.SetDomain(endpoint)
+	}
+	request.RoleArn = role.RoleARN
+	request.RoleSessionName = role.RoleSessionName
+	if request.RoleSessionName == "" {
+		request.RoleSessionName = defaultRoleSessionName
+	}
+	resp, err := stsClient.AssumeRole(request)
+	if err != nil {
+		return nil, fmt.Errorf(errAssumeRole, role.RoleARN, util.SanitizeErr(err))
+	}
+	return credentials.NewStsTokenCredential(resp.Credentials.AccessKeyId, resp.Credentials.AccessKeySecret, resp.Credentials.SecurityToken), nil
+}
+
 func (kms *KeyManagementService) Close(ctx context.Context) error {
 	return nil
 }
@@ -245,9 +425,21 @@ func (kms *KeyManagementService) ValidateStore(store esv1beta1.GenericStore) err
 		return fmt.Errorf("missing alibaba access key secret key")
 	}
 
+	if role := alibabaSpec.Auth.RAMRole; role != nil && role.RoleARN == "" {
+		return fmt.Errorf("missing alibaba RAM role ARN")
+	}
+
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (kms *KeyManagementService) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
+
 func init() {
 	esv1beta1.Register(&KeyManagementService{}, &esv1beta1.SecretStoreProvider{
 		Alibaba: &esv1beta1.AlibabaProvider{},
@@ -23,6 +23,8 @@ import (
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
 	kmssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
+	rmsdk "github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
+	utilpointer "k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
@@ -227,3 +229,82 @@ func ErrorContains(out error, want string) bool {
 	}
 	return strings.Contains(out.Error(), want)
 }
+
+func TestGetAllSecrets(t *testing.T) {
+	secret := func(name string, tags map[string]string) kmssdk.Secret {
+		s := kmssdk.Secret{SecretName: name}
+		for k, v := range tags {
+			s.Tags.Tag = append(s.Tags.Tag, kmssdk.Tag{TagKey: k, TagValue: v})
+		}
+		return s
+	}
+	pages := [][]kmssdk.Secret{
+		{secret("app-db", map[string]string{"team": "a"}), secret("app-api", map[string]string{"team": "b"})},
+		{secret("other-db", map[string]string{"team": "a"})},
+	}
+	values := map[string]string{"app-db": "db", "app-api": "api", "other-db": "other"}
+
+	tbl := []struct {
+		name          string
+		ref           esv1beta1.ExternalSecretFind
+		resourceGroup string
+		resources     []rmsdk.Resource
+		expected      map[string][]byte
+		expectError   string
+	}{
+		{
+			name:     "find by name across pages",
+			ref:      esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "db$"}},
+			expected: map[string][]byte{"app-db": []byte("db"), "other-db": []byte("other")},
+		},
+		{
+			name:     "find by tags with path",
+			ref:      esv1beta1.ExternalSecretFind{Path: utilpointer.StringPtr("app-"), Tags: map[string]string{"team": "a"}},
+			expected: map[string][]byte{"app-db": []byte("db")},
+		},
+		{
+			name:          "find in resource group",
+			ref:           esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: ".*"}},
+			resourceGroup: "rg-team-a",
+			resources: []rmsdk.Resource{
+				{ResourceId: "acs:kms:cn-hangzhou:123456789012:secret/app-api"},
+				{ResourceId: "other-db"},
+			},
+			expected: map[string][]byte{"app-api": []byte("api"), "other-db": []byte("other")},
+		},
+		{
+			name:        "missing find operator",
+			ref:         esv1beta1.ExternalSecretFind{},
+			expectError: errUnexpectedFindOperator,
+		},
+	}
+	for _, row := range tbl {
+		t.Run(row.name, func(t *testing.T) {
+			mockClient := &fakesm.AlibabaMockClient{Secrets: pages}
+			mockClient.WithSecretValues(values)
+			rmClient := &fakesm.AlibabaMockRMClient{Resources: row.resources}
+			kms := &KeyManagementService{
+				Client:          mockClient,
+				RMClient:        rmClient,
+				regionID:        "cn-hangzhou",
+				resourceGroupID: row.resourceGroup,
+			}
+			out, err := kms.GetAllSecrets(context.Background(), row.ref)
+			if row.expectError != "" {
+				if err == nil || err.Error() != row.expectError {
+					t.Fatalf("expected error %q, got %v", row.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(out, row.expected) {
+				t.Errorf("expected %v, got %v", row.expected, out)
+			}
+			if row.resourceGroup != "" && (rmClient.Request.ResourceGroupId != row.resourceGroup || rmClient.Request.Region != "cn-hangzhou") {
+				t.Errorf("unexpected resource manager request: %+v", rmClient.Request)
+			}
+		})
+	}
+}
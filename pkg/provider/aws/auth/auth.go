@@ -17,6 +17,8 @@ package auth
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -34,7 +36,10 @@ import (
 	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/execcredential"
 	"github.com/external-secrets/external-secrets/pkg/provider/aws/util"
+	"github.com/external-secrets/external-secrets/pkg/useragent"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 // Config contains configuration to create a new AWS provider.
@@ -51,9 +56,16 @@ type SessionCache struct {
 	ResourceVersion string
 }
 
+type cachedSession struct {
+	sess    *session.Session
+	created time.Time
+}
+
 var (
-	log         = ctrl.Log.WithName("provider").WithName("aws")
-	sessions    = make(map[SessionCache]*session.Session)
+	log = ctrl.Log.WithName("provider").WithName("aws")
+	// sessions is guarded by sessionsMu.
+	sessions    = make(map[SessionCache]cachedSession)
+	sessionsMu  sync.Mutex
 	EnableCache bool
 )
 
@@ -74,6 +86,7 @@ const (
 // it uses the following authentication mechanisms in order:
 // * service-account token authentication via AssumeRoleWithWebIdentity
 // * static credentials from a Kind=Secret, optionally with doing a AssumeRole.
+// * credentials returned by an exec credential binary, optionally with doing a AssumeRole.
 // * sdk default provider chain, see: https://docs.aws.amazon.com/sdk-for-java/v1/developer-guide/credentials.html#credentials-default
 func New(ctx context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string, assumeRoler STSProvider, jwtProvider jwtProviderFactory) (*session.Session, error) {
 	prov, err := util.GetAWSProvider(store)
@@ -102,6 +115,14 @@ func New(ctx context.Context, store esv1beta1.GenericStore, kube client.Client,
 		}
 	}
 
+	// use credentials from an exec credential binary
+	if prov.Auth.Exec != nil {
+		if err := execcredential.Validate(store, prov.Auth.Exec); err != nil {
+			return nil, err
+		}
+		creds = sessionFromExec(prov.Auth)
+	}
+
 	config := aws.NewConfig().WithEndpointResolver(ResolveEndpoint())
 	if creds != nil {
 		config.WithCredentials(creds)
@@ -110,13 +131,21 @@ func New(ctx context.Context, store esv1beta1.GenericStore, kube client.Client,
 		config.WithRegion(prov.Region)
 	}
 
-	sess, err := getAWSSession(config, EnableCache, store.GetName(), store.GetTypeMeta().Kind, namespace, store.GetObjectMeta().ResourceVersion)
+	sess, err := getAWSSession(config, EnableCache, store, namespace)
 	if err != nil {
 		return nil, err
 	}
 
 	if prov.Role != "" {
-		stsclient := assumeRoler(sess)
+		region, err := stsRegion(prov.Role, prov.Region)
+		if err != nil {
+			return nil, err
+		}
+		stsSess := sess
+		if region != prov.Region {
+			stsSess = sess.Copy(aws.NewConfig().WithRegion(region))
+		}
+		stsclient := assumeRoler(stsSess)
 		sess.Config.WithCredentials(stscreds.NewCredentialsWithClient(stsclient, prov.Role))
 	}
 	log.Info("using aws session", "region", *sess.Config.Region, "credentials", creds)
@@ -188,7 +217,10 @@ func sessionFromServiceAccount(ctx context.Context, auth esv1beta1.AWSAuth, regi
 		return nil, fmt.Errorf("an IAM role must be associated with service account %s (namespace: %s)", name, namespace)
 	}
 
-	tokenAud := sa.Annotations[audienceAnnotation]
+	tokenAud := auth.JWTAuth.Audience
+	if tokenAud == "" {
+		tokenAud = sa.Annotations[audienceAnnotation]
+	}
 	if tokenAud == "" {
 		tokenAud = defaultTokenAudience
 	}
@@ -197,7 +229,11 @@ func sessionFromServiceAccount(ctx context.Context, auth esv1beta1.AWSAuth, regi
 		audiences = append(audiences, auth.JWTAuth.ServiceAccountRef.Audiences...)
 	}
 
-	jwtProv, err := jwtProvider(name, namespace, roleArn, audiences, region)
+	region, err = stsRegion(roleArn, region)
+	if err != nil {
+		return nil, err
+	}
+	jwtProv, err := jwtProvider(name, namespace, roleArn, audiences, auth.JWTAuth.ExpirationSeconds, region)
 	if err != nil {
 		return nil, err
 	}
@@ -206,12 +242,12 @@ func sessionFromServiceAccount(ctx context.Context, auth esv1beta1.AWSAuth, regi
 	return credentials.NewCredentials(jwtProv), nil
 }
 
-type jwtProviderFactory func(name, namespace, roleArn string, aud []string, region string) (credentials.Provider, error)
+type jwtProviderFactory func(name, namespace, roleArn string, aud []string, expirationSeconds *int64, region string) (credentials.Provider, error)
 
 // DefaultJWTProvider returns a credentials.Provider that calls the AssumeRoleWithWebidentity
 // controller-runtime/client does not support TokenRequest or other subresource APIs
 // so we need to construct our own client and use it to fetch tokens.
-func DefaultJWTProvider(name, namespace, roleArn string, aud []string, region string) (credentials.Provider, error) {
+func DefaultJWTProvider(name, namespace, roleArn string, aud []string, expirationSeconds *int64, region string) (credentials.Provider, error) {
 	cfg, err := ctrlcfg.GetConfig()
 	if err != nil {
 		return nil, err
@@ -235,10 +271,11 @@ func DefaultJWTProvider(name, namespace, roleArn string, aud []string, region st
 		return nil, err
 	}
 	tokenFetcher := &authTokenFetcher{
-		Namespace:      namespace,
-		Audiences:      aud,
-		ServiceAccount: name,
-		k8sClient:      clientset.CoreV1(),
+		Namespace:         namespace,
+		Audiences:         aud,
+		ExpirationSeconds: expirationSeconds,
+		ServiceAccount:    name,
+		k8sClient:         clientset.CoreV1(),
 	}
 
 	return stscreds.NewWebIdentityRoleProviderWithOptions(
@@ -253,24 +290,27 @@ func DefaultSTSProvider(sess *session.Session) stsiface.STSAPI {
 
 // getAWSSession check if an AWS session should be reused
 // it returns the aws session or an error.
-func getAWSSession(config *aws.Config, enableCache bool, name, kind, namespace, resourceVersion string) (*session.Session, error) {
+// Sessions are not reused after the credentialsRefreshInterval of the store.
+func getAWSSession(config *aws.Config, enableCache bool, store esv1beta1.GenericStore, namespace string) (*session.Session, error) {
 	tmpSession := SessionCache{
-		Name:            name,
+		Name:            store.GetName(),
 		Namespace:       namespace,
-		Kind:            kind,
-		ResourceVersion: resourceVersion,
+		Kind:            store.GetTypeMeta().Kind,
+		ResourceVersion: store.GetObjectMeta().ResourceVersion,
 	}
 
 	if enableCache {
-		sess, ok := sessions[tmpSession]
-		if ok {
+		sessionsMu.Lock()
+		cached, ok := sessions[tmpSession]
+		sessionsMu.Unlock()
+		if ok && !utils.CredentialsExpired(store, cached.created) {
 			log.Info("reusing aws session", "SecretStore", tmpSession.Name, "namespace", tmpSession.Namespace, "kind", tmpSession.Kind, "resourceversion", tmpSession.ResourceVersion)
-			return sess, nil
+			return cached.sess, nil
 		}
 	}
 
 	handlers := defaults.Handlers()
-	handlers.Build.PushBack(request.WithAppendUserAgent("external-secrets"))
+	handlers.Build.PushBack(request.WithAppendUserAgent(useragent.ForStore(store)))
 	sess, err := session.NewSessionWithOptions(session.Options{
 		Config:            *config,
 		Handlers:          handlers,
@@ -281,7 +321,38 @@ func getAWSSession(config *aws.Config, enableCache bool, name, kind, namespace,
 	}
 
 	if enableCache {
-		sessions[tmpSession] = sess
+		sessionsMu.Lock()
+		// sessions of previous versions of the store are not used anymore
+		removeSessions(store, tmpSession.Namespace)
+		sessions[tmpSession] = cachedSession{sess: sess, created: time.Now()}
+		sessionsMu.Unlock()
 	}
 	return sess, nil
 }
+
+// InvalidateSessions removes the cached sessions of a store,
+// so the next session is built with the current credentials.
+func InvalidateSessions(store esv1beta1.GenericStore) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	removeSessions(store, "")
+}
+
+// removeSessions removes the sessions of store, only those of namespace if it is set.
+// Sessions of a referent ClusterSecretStore are cached per namespace, so there may be more than one.
+// It must be called with sessionsMu held.
+func removeSessions(store esv1beta1.GenericStore, namespace string) {
+	kind := store.GetTypeMeta().Kind
+	for key := range sessions {
+		if key.Name != store.GetName() || key.Kind != kind {
+			continue
+		}
+		if kind != esv1beta1.ClusterSecretStoreKind && key.Namespace != store.GetNamespace() {
+			continue
+		}
+		if namespace != "" && key.Namespace != namespace {
+			continue
+		}
+		delete(sessions, key)
+	}
+}
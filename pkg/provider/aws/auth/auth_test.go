@@ -351,10 +351,12 @@ func TestNewSession(t *testing.T) {
 					},
 				},
 			},
-			jwtProvider: func(name, namespace, roleArn string, aud []string, region string) (credentials.Provider, error) {
+			jwtProvider: func(name, namespace, roleArn string, aud []string, expirationSeconds *int64, region string) (credentials.Provider, error) {
 				assert.Equal(t, myServiceAccountKey, name)
 				assert.Equal(t, otherNsName, namespace)
 				assert.Equal(t, "my-sa-role", roleArn)
+				assert.Equal(t, []string{defaultTokenAudience}, aud)
+				assert.Nil(t, expirationSeconds)
 				return fakesess.CredentialsProvider{
 					RetrieveFunc: func() (credentials.Value, error) {
 						return credentials.Value{
@@ -391,6 +393,61 @@ func TestNewSession(t *testing.T) {
 			expectedKeyID:     "3333",
 			expectedSecretKey: "4444",
 		},
+		{
+			name:      "jwt auth with custom audience and expiration",
+			namespace: esNamespaceKey,
+			sa: &v1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      myServiceAccountKey,
+					Namespace: otherNsName,
+					Annotations: map[string]string{
+						roleARNAnnotation:  "my-sa-role",
+						audienceAnnotation: "annotated-audience",
+					},
+				},
+			},
+			jwtProvider: func(name, namespace, roleArn string, aud []string, expirationSeconds *int64, region string) (credentials.Provider, error) {
+				assert.Equal(t, []string{"https://oidc.example.com", "extra"}, aud)
+				assert.Equal(t, int64(900), *expirationSeconds)
+				return fakesess.CredentialsProvider{
+					RetrieveFunc: func() (credentials.Value, error) {
+						return credentials.Value{
+							AccessKeyID:     "5555",
+							SecretAccessKey: "6666",
+							SessionToken:    "1234",
+							ProviderName:    "fake",
+						}, nil
+					},
+					IsExpiredFunc: func() bool { return false },
+				}, nil
+			},
+			store: &esv1beta1.ClusterSecretStore{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: esv1beta1.ClusterSecretStoreKindAPIVersion,
+					Kind:       esv1beta1.ClusterSecretStoreKind,
+				},
+				Spec: esv1beta1.SecretStoreSpec{
+					Provider: &esv1beta1.SecretStoreProvider{
+						AWS: &esv1beta1.AWSProvider{
+							Auth: esv1beta1.AWSAuth{
+								JWTAuth: &esv1beta1.AWSJWTAuth{
+									ServiceAccountRef: &esmeta.ServiceAccountSelector{
+										Name:      myServiceAccountKey,
+										Namespace: aws.String(otherNsName),
+										Audiences: []string{"extra"},
+									},
+									Audience:          "https://oidc.example.com",
+									ExpirationSeconds: aws.Int64(900),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectProvider:    true,
+			expectedKeyID:     "5555",
+			expectedSecretKey: "6666",
+		},
 	}
 	for i := range rows {
 		row := rows[i]
@@ -520,6 +577,26 @@ func TestSMAssumeRole(t *testing.T) {
 	assert.Equal(t, creds.SecretAccessKey, "4444")
 }
 
+func TestAssumeRolePartitionRegion(t *testing.T) {
+	k8sClient := clientfake.NewClientBuilder().Build()
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "1111")
+	t.Setenv("AWS_ACCESS_KEY_ID", "2222")
+	_, err := New(context.Background(), &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				AWS: &esv1beta1.AWSProvider{
+					Role: "arn:aws-us-gov:iam::123456789012:role/eso",
+				},
+			},
+		},
+	}, k8sClient, "example-ns", func(se *awssess.Session) stsiface.STSAPI {
+		// STS must be called in the partition of the role
+		assert.Equal(t, "us-gov-west-1", *se.Config.Region)
+		return &fakesess.AssumeRoler{}
+	}, nil)
+	assert.Nil(t, err)
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -529,3 +606,47 @@ func ErrorContains(out error, want string) bool {
 	}
 	return strings.Contains(out.Error(), want)
 }
+
+func TestInvalidateSessions(t *testing.T) {
+	t.Cleanup(func() { sessions = make(map[SessionCache]cachedSession) })
+	store := &esv1beta1.ClusterSecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "aws", ResourceVersion: "1"},
+	}
+	other := &esv1beta1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1beta1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "team-a", ResourceVersion: "1"},
+	}
+	config := aws.NewConfig().WithRegion("eu-west-1")
+	getSession := func(store esv1beta1.GenericStore, namespace string) *awssess.Session {
+		sess, err := getAWSSession(config, true, store, namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sess
+	}
+
+	// a referent ClusterSecretStore has one session per namespace
+	teamA := getSession(store, "team-a")
+	getSession(store, "team-b")
+	otherSess := getSession(other, "team-a")
+	if getSession(store, "team-a") != teamA {
+		t.Fatalf("expected the cached session to be reused")
+	}
+
+	// a new version of the store replaces the session of its namespace
+	updated := store.DeepCopy()
+	updated.ResourceVersion = "2"
+	getSession(updated, "team-a")
+	if len(sessions) != 3 {
+		t.Errorf("expected the session of the previous version to be removed, got %d sessions", len(sessions))
+	}
+
+	InvalidateSessions(updated)
+	if len(sessions) != 1 {
+		t.Fatalf("expected only the session of the other store to be left, got %d sessions", len(sessions))
+	}
+	if getSession(other, "team-a") != otherSess {
+		t.Errorf("expected the session of the other store to be kept")
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/execcredential"
+)
+
+const (
+	execProviderName = "ExecCredentialProvider"
+
+	execKeyAccessKeyID     = "accessKeyID"
+	execKeySecretAccessKey = "secretAccessKey"
+	execKeySessionToken    = "sessionToken"
+
+	// execExpiryWindow refreshes the credentials before they actually expire.
+	execExpiryWindow = 30 * time.Second
+
+	errExecMissingKey = "exec credential command %q did not return %s"
+)
+
+// execProvider retrieves credentials by running an exec credential binary.
+// The credentials are retrieved again once they expired,
+// credentials without expiration are retrieved only once.
+type execProvider struct {
+	credentials.Expiry
+	src     *esv1beta1.ExecCredentialSource
+	expires bool
+}
+
+func (p *execProvider) IsExpired() bool {
+	return p.expires && p.Expiry.IsExpired()
+}
+
+func (p *execProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(context.Background())
+}
+
+func (p *execProvider) RetrieveWithContext(ctx context.Context) (credentials.Value, error) {
+	cred, err := execcredential.Run(ctx, p.src)
+	if err != nil {
+		return credentials.Value{ProviderName: execProviderName}, err
+	}
+	value := credentials.Value{
+		AccessKeyID:     cred.Values[execKeyAccessKeyID],
+		SecretAccessKey: cred.Values[execKeySecretAccessKey],
+		SessionToken:    cred.Values[execKeySessionToken],
+		ProviderName:    execProviderName,
+	}
+	if value.AccessKeyID == "" {
+		return credentials.Value{ProviderName: execProviderName}, fmt.Errorf(errExecMissingKey, p.src.Command, execKeyAccessKeyID)
+	}
+	if value.SecretAccessKey == "" {
+		return credentials.Value{ProviderName: execProviderName}, fmt.Errorf(errExecMissingKey, p.src.Command, execKeySecretAccessKey)
+	}
+	p.expires = !cred.Expiration.IsZero()
+	if p.expires {
+		p.SetExpiration(cred.Expiration, execExpiryWindow)
+	}
+	return value, nil
+}
+
+func sessionFromExec(auth esv1beta1.AWSAuth) *credentials.Credentials {
+	log.V(1).Info("using credentials from exec command", "command", auth.Exec.Command)
+	return credentials.NewCredentials(&execProvider{src: auth.Exec})
+}
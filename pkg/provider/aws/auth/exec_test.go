@@ -0,0 +1,78 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/execcredential"
+)
+
+func TestSMExecCredentials(t *testing.T) {
+	dir := t.TempDir()
+	script := `#!/bin/sh
+echo '{"credentials":{"accessKeyID":"3333","secretAccessKey":"4444","sessionToken":"5555"},"expirationTimestamp":"2030-01-01T00:00:00Z"}'
+`
+	err := os.WriteFile(filepath.Join(dir, "broker"), []byte(script), 0o755)
+	assert.Nil(t, err)
+	old := execcredential.PluginDir
+	execcredential.PluginDir = dir
+	t.Cleanup(func() { execcredential.PluginDir = old })
+
+	k8sClient := clientfake.NewClientBuilder().Build()
+	s, err := New(context.Background(), &esv1beta1.ClusterSecretStore{
+		TypeMeta: metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				AWS: &esv1beta1.AWSProvider{
+					Auth: esv1beta1.AWSAuth{
+						Exec: &esv1beta1.ExecCredentialSource{Command: "broker"},
+					},
+				},
+			},
+		},
+	}, k8sClient, "example-ns", DefaultSTSProvider, nil)
+	assert.Nil(t, err)
+	creds, err := s.Config.Credentials.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "3333", creds.AccessKeyID)
+	assert.Equal(t, "4444", creds.SecretAccessKey)
+	assert.Equal(t, "5555", creds.SessionToken)
+	assert.False(t, s.Config.Credentials.IsExpired())
+}
+
+func TestSMExecCredentialsSecretStore(t *testing.T) {
+	_, err := New(context.Background(), &esv1beta1.SecretStore{
+		TypeMeta: metav1.TypeMeta{Kind: esv1beta1.SecretStoreKind},
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				AWS: &esv1beta1.AWSProvider{
+					Auth: esv1beta1.AWSAuth{
+						Exec: &esv1beta1.ExecCredentialSource{Command: "broker"},
+					},
+				},
+			},
+		},
+	}, clientfake.NewClientBuilder().Build(), "example-ns", DefaultSTSProvider, nil)
+	assert.ErrorContains(t, err, "can only be used with a ClusterSecretStore")
+}
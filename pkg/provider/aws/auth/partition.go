@@ -0,0 +1,79 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+const (
+	errInvalidRoleARN    = "invalid role ARN %q: %w"
+	errUnknownPartition  = "role ARN %q has unknown partition %q"
+	errNotARoleARN       = "ARN %q is not an IAM role"
+	errPartitionMismatch = "role ARN %q can not be assumed in region %q of partition %q"
+)
+
+// partitionRegions are the regions STS is called in
+// to assume a role of the partition if no region is configured.
+var partitionRegions = map[string]string{
+	endpoints.AwsPartitionID:      "us-east-1",
+	endpoints.AwsCnPartitionID:    "cn-north-1",
+	endpoints.AwsUsGovPartitionID: "us-gov-west-1",
+}
+
+// ValidateRoleARN returns an error if roleArn is not the ARN of an IAM role
+// or if the role can not be assumed in region.
+func ValidateRoleARN(roleArn, region string) error {
+	parsed, err := arn.Parse(roleArn)
+	if err != nil {
+		return fmt.Errorf(errInvalidRoleARN, roleArn, err)
+	}
+	known := false
+	for _, p := range endpoints.DefaultPartitions() {
+		if p.ID() == parsed.Partition {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf(errUnknownPartition, roleArn, parsed.Partition)
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		return fmt.Errorf(errNotARoleARN, roleArn)
+	}
+	_, err = stsRegion(roleArn, region)
+	return err
+}
+
+// stsRegion returns the region STS is called in to assume roleArn.
+// A role can only be assumed through the STS endpoints of its own partition,
+// so without a region the default region of the role's partition is used.
+// Roles that are not an ARN are passed to STS as they are.
+func stsRegion(roleArn, region string) (string, error) {
+	parsed, err := arn.Parse(roleArn)
+	if err != nil {
+		return region, nil
+	}
+	if region == "" {
+		return partitionRegions[parsed.Partition], nil
+	}
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok && p.ID() != parsed.Partition {
+		return "", fmt.Errorf(errPartitionMismatch, roleArn, region, p.ID())
+	}
+	return region, nil
+}
@@ -0,0 +1,69 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"testing"
+)
+
+func TestValidateRoleARN(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		region  string
+		wantErr string
+	}{
+		{name: "commercial", role: "arn:aws:iam::123456789012:role/eso", region: "eu-central-1"},
+		{name: "govcloud", role: "arn:aws-us-gov:iam::123456789012:role/eso", region: "us-gov-west-1"},
+		{name: "china", role: "arn:aws-cn:iam::123456789012:role/path/eso", region: "cn-northwest-1"},
+		{name: "not an arn", role: "eso", region: "eu-central-1", wantErr: "invalid role ARN"},
+		{name: "unknown partition", role: "arn:aws-mars:iam::123456789012:role/eso", region: "eu-central-1", wantErr: "unknown partition"},
+		{name: "user", role: "arn:aws:iam::123456789012:user/eso", region: "eu-central-1", wantErr: "not an IAM role"},
+		{name: "partition mismatch", role: "arn:aws-us-gov:iam::123456789012:role/eso", region: "us-east-1", wantErr: "can not be assumed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoleARN(tt.role, tt.region)
+			if !ErrorContains(err, tt.wantErr) {
+				t.Errorf("ValidateRoleARN() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSTSRegion(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		region string
+		want   string
+	}{
+		{name: "store region", role: "arn:aws-us-gov:iam::123456789012:role/eso", region: "us-gov-east-1", want: "us-gov-east-1"},
+		{name: "govcloud default", role: "arn:aws-us-gov:iam::123456789012:role/eso", want: "us-gov-west-1"},
+		{name: "china default", role: "arn:aws-cn:iam::123456789012:role/eso", want: "cn-north-1"},
+		{name: "not an arn", role: "eso", region: "eu-west-1", want: "eu-west-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := stsRegion(tt.role, tt.region)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("stsRegion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,9 +30,12 @@ type authTokenFetcher struct {
 	// Audience is the token aud claim
 	// which is verified by the aws oidc provider
 	// see: https://github.com/external-secrets/external-secrets/issues/1251#issuecomment-1161745849
-	Audiences      []string
-	ServiceAccount string
-	k8sClient      corev1.CoreV1Interface
+	Audiences []string
+	// ExpirationSeconds is the requested validity of the token,
+	// the API server default is used if not set.
+	ExpirationSeconds *int64
+	ServiceAccount    string
+	k8sClient         corev1.CoreV1Interface
 }
 
 // FetchToken satisfies the stscreds.TokenFetcher interface
@@ -41,7 +44,8 @@ func (p authTokenFetcher) FetchToken(ctx credentials.Context) ([]byte, error) {
 	log.V(1).Info("fetching token", "ns", p.Namespace, "sa", p.ServiceAccount)
 	tokRsp, err := p.k8sClient.ServiceAccounts(p.Namespace).CreateToken(ctx, p.ServiceAccount, &authv1.TokenRequest{
 		Spec: authv1.TokenRequestSpec{
-			Audiences: p.Audiences,
+			Audiences:         p.Audiences,
+			ExpirationSeconds: p.ExpirationSeconds,
 		},
 	}, metav1.CreateOptions{})
 	if err != nil {
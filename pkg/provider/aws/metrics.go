@@ -0,0 +1,79 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	AWSSubsystem    = "provider_aws"
+	ThrottledKey    = "throttled_requests_total"
+	RetriedKey      = "retried_requests_total"
+	metricsHandlers = "externalsecrets.Metrics"
+)
+
+var (
+	throttledRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: AWSSubsystem,
+		Name:      ThrottledKey,
+		Help:      "Total number of AWS API requests that were throttled",
+	}, []string{"service", "operation"})
+
+	retriedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: AWSSubsystem,
+		Name:      RetriedKey,
+		Help:      "Total number of AWS API request retries",
+	}, []string{"service", "operation"})
+)
+
+// installMetrics counts throttled and retried requests.
+func installMetrics(h *request.Handlers) {
+	h.Retry.PushBackNamed(request.NamedHandler{
+		Name: metricsHandlers,
+		Fn: func(r *request.Request) {
+			if r.IsErrorThrottle() {
+				throttledRequests.With(requestLabels(r)).Inc()
+			}
+		},
+	})
+	// the core AfterRetry handler resets the error if the request will be retried.
+	h.AfterRetry.PushBackNamed(request.NamedHandler{
+		Name: metricsHandlers,
+		Fn: func(r *request.Request) {
+			if r.Error == nil && aws.BoolValue(r.Retryable) {
+				retriedRequests.With(requestLabels(r)).Inc()
+			}
+		},
+	})
+}
+
+func requestLabels(r *request.Request) prometheus.Labels {
+	var operation string
+	if r.Operation != nil {
+		operation = r.Operation.Name
+	}
+	return prometheus.Labels{
+		"service":   r.ClientInfo.ServiceName,
+		"operation": operation,
+	}
+}
+
+func init() {
+	metrics.Registry.MustRegister(throttledRequests, retriedRequests)
+}
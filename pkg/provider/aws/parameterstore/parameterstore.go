@@ -97,6 +97,9 @@ func (pm *ParameterStore) findByName(ref esv1beta1.ExternalSecretFind) (map[stri
 			if !matcher.MatchName(*param.Name) {
 				continue
 			}
+			if err := find.CheckLimit(ref, len(data)); err != nil {
+				return nil, err
+			}
 			err = pm.fetchAndSet(data, *param.Name)
 			if err != nil {
 				return nil, err
@@ -140,6 +143,9 @@ func (pm *ParameterStore) findByTags(ref esv1beta1.ExternalSecretFind) (map[stri
 			return nil, err
 		}
 		for _, param := range it.Parameters {
+			if err := find.CheckLimit(ref, len(data)); err != nil {
+				return nil, err
+			}
 			err = pm.fetchAndSet(data, *param.Name)
 			if err != nil {
 				return nil, err
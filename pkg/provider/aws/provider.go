@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/execcredential"
 	awsauth "github.com/external-secrets/external-secrets/pkg/provider/aws/auth"
 	"github.com/external-secrets/external-secrets/pkg/provider/aws/parameterstore"
 	"github.com/external-secrets/external-secrets/pkg/provider/aws/secretsmanager"
@@ -51,6 +52,12 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 	return newClient(ctx, store, kube, namespace, awsauth.DefaultSTSProvider)
 }
 
+// InvalidateClients removes the cached sessions of a store,
+// so the next session is built with the current credentials.
+func (p *Provider) InvalidateClients(ctx context.Context, store esv1beta1.GenericStore) {
+	awsauth.InvalidateSessions(store)
+}
+
 func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 	prov, err := util.GetAWSProvider(store)
 	if err != nil {
@@ -61,6 +68,12 @@ func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 		return err
 	}
 
+	if prov.Role != "" {
+		if err := awsauth.ValidateRoleARN(prov.Role, prov.Region); err != nil {
+			return fmt.Errorf("invalid Role: %w", err)
+		}
+	}
+
 	// case: static credentials
 	if prov.Auth.SecretRef != nil {
 		if err := utils.ValidateSecretSelector(store, prov.Auth.SecretRef.AccessKeyID); err != nil {
@@ -78,9 +91,24 @@ func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 		}
 	}
 
+	// case: exec credentials
+	if prov.Auth.Exec != nil {
+		if err := execcredential.Validate(store, prov.Auth.Exec); err != nil {
+			return fmt.Errorf("invalid Auth.Exec: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
+
 func validateRegion(prov *esv1beta1.AWSProvider) error {
 	resolver := endpoints.DefaultResolver()
 	partitions := resolver.(endpoints.EnumPartitions).Partitions()
@@ -139,6 +167,14 @@ func newClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Cl
 		cfg = request.WithRetryer(aws.NewConfig(), awsRetryer)
 	}
 
+	// the session may be cached and shared between stores,
+	// handlers must only be added to a copy of it.
+	sess = sess.Copy()
+	installMetrics(&sess.Handlers)
+	if prov.RetryMode == esv1beta1.AWSRetryModeAdaptive {
+		getAdaptiveRateLimiter(store.GetNamespacedName()).install(&sess.Handlers)
+	}
+
 	switch prov.Service {
 	case esv1beta1.AWSServiceSecretsManager:
 		return secretsmanager.New(sess, cfg)
@@ -193,6 +193,114 @@ func TestValidateStore(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid govcloud role",
+			args: args{
+				store: &esv1beta1.SecretStore{
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AWS: &esv1beta1.AWSProvider{
+								Region: "us-gov-west-1",
+								Role:   "arn:aws-us-gov:iam::123456789012:role/eso",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "invalid role / partition mismatch",
+			wantErr: true,
+			args: args{
+				store: &esv1beta1.SecretStore{
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AWS: &esv1beta1.AWSProvider{
+								Region: validRegion,
+								Role:   "arn:aws-cn:iam::123456789012:role/eso",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "invalid exec auth / command with path",
+			wantErr: true,
+			args: args{
+				store: &esv1beta1.SecretStore{
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AWS: &esv1beta1.AWSProvider{
+								Region: validRegion,
+								Auth: esv1beta1.AWSAuth{
+									Exec: &esv1beta1.ExecCredentialSource{Command: "/usr/bin/broker"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "valid exec auth",
+			args: args{
+				store: &esv1beta1.ClusterSecretStore{
+					TypeMeta: v1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AWS: &esv1beta1.AWSProvider{
+								Region: validRegion,
+								Auth: esv1beta1.AWSAuth{
+									Exec: &esv1beta1.ExecCredentialSource{Command: "broker"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "invalid exec auth / secret store",
+			wantErr: true,
+			args: args{
+				store: &esv1beta1.SecretStore{
+					TypeMeta: v1.TypeMeta{Kind: esv1beta1.SecretStoreKind},
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AWS: &esv1beta1.AWSProvider{
+								Region: validRegion,
+								Auth: esv1beta1.AWSAuth{
+									Exec: &esv1beta1.ExecCredentialSource{Command: "broker"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "invalid exec auth / loader env",
+			wantErr: true,
+			args: args{
+				store: &esv1beta1.ClusterSecretStore{
+					TypeMeta: v1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AWS: &esv1beta1.AWSProvider{
+								Region: validRegion,
+								Auth: esv1beta1.AWSAuth{
+									Exec: &esv1beta1.ExecCredentialSource{
+										Command: "broker",
+										Env:     []esv1beta1.ExecEnvVar{{Name: "LD_PRELOAD", Value: "/tmp/evil.so"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:    "invalid static creds auth / AccessKeyID",
 			wantErr: true,
@@ -0,0 +1,169 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// adaptiveMinRate is the lowest request rate (per second) the adaptive limiter backs off to.
+	adaptiveMinRate = 0.5
+	// adaptiveBeta is the factor the request rate is multiplied with when a request is throttled.
+	adaptiveBeta = 0.7
+	// adaptiveRateIncrease is added to the request rate for every successful request.
+	adaptiveRateIncrease = 0.5
+	// adaptiveMeasureWindow is the interval used to measure the actual request rate.
+	adaptiveMeasureWindow = time.Second
+	// maxAdaptiveLimiters bounds the number of stores whose throttling state is kept.
+	maxAdaptiveLimiters = 1000
+)
+
+var (
+	adaptiveLimitersMu sync.Mutex
+	// adaptiveLimiters holds one limiter per store so the throttling state
+	// survives the per-reconcile clients. The limiters of the stores used least
+	// recently, e.g. deleted ones, are dropped. lru.New only fails for a size below 1.
+	adaptiveLimiters, _ = lru.New(maxAdaptiveLimiters)
+)
+
+// getAdaptiveRateLimiter returns the rate limiter shared by all clients of a store.
+func getAdaptiveRateLimiter(key string) *adaptiveRateLimiter {
+	adaptiveLimitersMu.Lock()
+	defer adaptiveLimitersMu.Unlock()
+	if l, ok := adaptiveLimiters.Get(key); ok {
+		return l.(*adaptiveRateLimiter)
+	}
+	l := newAdaptiveRateLimiter()
+	adaptiveLimiters.Add(key, l)
+	return l
+}
+
+// adaptiveRateLimiter implements client side rate limiting similar to the
+// adaptive retry mode of the AWS SDKs: requests are not limited until AWS
+// throttles a request. From then on the allowed request rate is decreased
+// multiplicatively on every throttled request and increased additively on
+// every successful request. Limiting stops once the allowed rate is more than
+// twice the measured request rate.
+type adaptiveRateLimiter struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	limiter  *rate.Limiter
+	enabled  bool
+	fillRate float64
+
+	measuredRate float64
+	windowStart  time.Time
+	windowCount  int
+}
+
+func newAdaptiveRateLimiter() *adaptiveRateLimiter {
+	return &adaptiveRateLimiter{
+		now:     time.Now,
+		limiter: rate.NewLimiter(rate.Inf, 1),
+	}
+}
+
+// install adds the rate limiter to the request lifecycle: every attempt
+// waits for the limiter before it is signed and the allowed rate is updated
+// depending on whether the attempt got throttled.
+func (l *adaptiveRateLimiter) install(h *request.Handlers) {
+	h.Sign.PushFrontNamed(request.NamedHandler{
+		Name: "externalsecrets.AdaptiveRateLimitWait",
+		Fn: func(r *request.Request) {
+			if err := l.wait(r); err != nil {
+				r.Error = err
+			}
+		},
+	})
+	h.Retry.PushBackNamed(request.NamedHandler{
+		Name: "externalsecrets.AdaptiveRateLimitThrottled",
+		Fn: func(r *request.Request) {
+			if r.IsErrorThrottle() {
+				l.update(true)
+			}
+		},
+	})
+	h.Complete.PushBackNamed(request.NamedHandler{
+		Name: "externalsecrets.AdaptiveRateLimitSucceeded",
+		Fn: func(r *request.Request) {
+			if r.Error == nil {
+				l.update(false)
+			}
+		},
+	})
+}
+
+func (l *adaptiveRateLimiter) wait(r *request.Request) error {
+	l.mu.Lock()
+	l.measure()
+	enabled := l.enabled
+	l.mu.Unlock()
+	if !enabled {
+		return nil
+	}
+	return l.limiter.Wait(r.Context())
+}
+
+// update adjusts the allowed request rate after a request attempt.
+func (l *adaptiveRateLimiter) update(throttled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if throttled {
+		base := l.fillRate
+		if !l.enabled {
+			base = l.measuredRate
+			l.enabled = true
+		}
+		l.fillRate = math.Max(base*adaptiveBeta, adaptiveMinRate)
+		l.limiter.SetLimit(rate.Limit(l.fillRate))
+		return
+	}
+	if !l.enabled {
+		return
+	}
+	l.fillRate += adaptiveRateIncrease
+	// stop limiting once the allowed rate exceeds the real request rate
+	if l.fillRate > 2*l.measuredRate && l.measuredRate > 0 {
+		l.enabled = false
+		l.limiter.SetLimit(rate.Inf)
+		return
+	}
+	l.limiter.SetLimit(rate.Limit(l.fillRate))
+}
+
+// measure tracks the rate of outgoing requests, it must be called with l.mu held.
+func (l *adaptiveRateLimiter) measure() {
+	now := l.now()
+	if l.windowStart.IsZero() {
+		l.windowStart = now
+	}
+	l.windowCount++
+	elapsed := now.Sub(l.windowStart)
+	if elapsed < adaptiveMeasureWindow {
+		return
+	}
+	current := float64(l.windowCount) / elapsed.Seconds()
+	// smooth the measured rate to not overreact to single bursts
+	l.measuredRate = 0.8*current + 0.2*l.measuredRate
+	l.windowStart = now
+	l.windowCount = 0
+}
@@ -0,0 +1,120 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awssm "github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestAdaptiveRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newAdaptiveRateLimiter()
+	l.now = func() time.Time { return now }
+
+	// 10 requests per second are measured
+	for i := 0; i < 11; i++ {
+		l.measure()
+		now = now.Add(100 * time.Millisecond)
+	}
+	assert.InDelta(t, 8.8, l.measuredRate, 0.01)
+
+	// requests are not limited until the first throttle
+	l.update(false)
+	assert.False(t, l.enabled)
+	assert.Equal(t, rate.Inf, l.limiter.Limit())
+
+	l.update(true)
+	assert.True(t, l.enabled)
+	first := l.fillRate
+	assert.InDelta(t, l.measuredRate*adaptiveBeta, first, 0.01)
+
+	l.update(true)
+	assert.InDelta(t, first*adaptiveBeta, l.fillRate, 0.01)
+
+	l.update(false)
+	assert.InDelta(t, first*adaptiveBeta+adaptiveRateIncrease, l.fillRate, 0.01)
+	assert.Equal(t, rate.Limit(l.fillRate), l.limiter.Limit())
+
+	// the rate never drops below the minimum
+	for i := 0; i < 20; i++ {
+		l.update(true)
+	}
+	assert.Equal(t, adaptiveMinRate, l.fillRate)
+
+	// limiting stops once we allow far more than we send
+	for i := 0; i < 40; i++ {
+		l.update(false)
+	}
+	assert.False(t, l.enabled)
+	assert.Equal(t, rate.Inf, l.limiter.Limit())
+}
+
+func TestGetAdaptiveRateLimiter(t *testing.T) {
+	a := getAdaptiveRateLimiter("ns/store-a")
+	assert.Same(t, a, getAdaptiveRateLimiter("ns/store-a"))
+	assert.NotSame(t, a, getAdaptiveRateLimiter("ns/store-b"))
+
+	// the limiters of the stores used least recently are dropped
+	for i := 0; i < maxAdaptiveLimiters; i++ {
+		getAdaptiveRateLimiter(fmt.Sprintf("ns/store-%d", i))
+	}
+	assert.Equal(t, maxAdaptiveLimiters, adaptiveLimiters.Len())
+	assert.NotSame(t, a, getAdaptiveRateLimiter("ns/store-a"))
+}
+
+func TestThrottleMetrics(t *testing.T) {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(2),
+		SleepDelay:  func(time.Duration) {},
+	}))
+	installMetrics(&sess.Handlers)
+
+	client := awssm.New(sess)
+	client.Handlers.Send.Clear()
+	client.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{StatusCode: http.StatusBadRequest}
+		r.Error = awserr.New("ThrottlingException", "Rate exceeded", nil)
+	})
+	client.Handlers.Unmarshal.Clear()
+	client.Handlers.UnmarshalMeta.Clear()
+	client.Handlers.UnmarshalError.Clear()
+	client.Handlers.ValidateResponse.Clear()
+
+	labels := []string{awssm.ServiceName, "GetSecretValue"}
+	throttledBefore := testutil.ToFloat64(throttledRequests.WithLabelValues(labels...))
+	retriedBefore := testutil.ToFloat64(retriedRequests.WithLabelValues(labels...))
+
+	_, err := client.GetSecretValue(&awssm.GetSecretValueInput{SecretId: aws.String("foo")})
+	assert.Error(t, err)
+
+	// one initial attempt plus two retries
+	assert.Equal(t, 3.0, testutil.ToFloat64(throttledRequests.WithLabelValues(labels...))-throttledBefore)
+	assert.Equal(t, 2.0, testutil.ToFloat64(retriedRequests.WithLabelValues(labels...))-retriedBefore)
+}
@@ -16,7 +16,9 @@ package fake
 
 import (
 	"fmt"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go/aws"
 	awssm "github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/google/go-cmp/cmp"
 )
@@ -25,12 +27,14 @@ import (
 type Client struct {
 	ExecutionCounter int
 	valFn            map[string]func(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)
+	versions         map[string][]*awssm.ListSecretVersionIdsOutput
 }
 
 // NewClient init a new fake client.
 func NewClient() *Client {
 	return &Client{
-		valFn: make(map[string]func(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)),
+		valFn:    make(map[string]func(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)),
+		versions: make(map[string][]*awssm.ListSecretVersionIdsOutput),
 	}
 }
 
@@ -46,6 +50,32 @@ func (sm *Client) ListSecrets(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput
 	return nil, nil
 }
 
+// ListSecretVersionIds returns the pages registered with WithVersions.
+// The NextToken of a page is its index.
+func (sm *Client) ListSecretVersionIds(in *awssm.ListSecretVersionIdsInput) (*awssm.ListSecretVersionIdsOutput, error) {
+	pages, found := sm.versions[aws.StringValue(in.SecretId)]
+	if !found {
+		return nil, &awssm.ResourceNotFoundException{}
+	}
+	page := 0
+	if in.NextToken != nil {
+		page, _ = strconv.Atoi(*in.NextToken)
+	}
+	if page >= len(pages) {
+		return nil, fmt.Errorf("unexpected next token %q", *in.NextToken)
+	}
+	out := *pages[page]
+	if page+1 < len(pages) {
+		out.NextToken = aws.String(strconv.Itoa(page + 1))
+	}
+	return &out, nil
+}
+
+// WithVersions registers the pages of versions of a secret.
+func (sm *Client) WithVersions(secretID string, pages ...*awssm.ListSecretVersionIdsOutput) {
+	sm.versions[secretID] = pages
+}
+
 func (sm *Client) cacheKeyForInput(in *awssm.GetSecretValueInput) string {
 	var secretID, versionID string
 	if in.SecretId != nil {
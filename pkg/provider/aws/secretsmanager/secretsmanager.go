@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -48,6 +49,7 @@ type SecretsManager struct {
 type SMInterface interface {
 	GetSecretValue(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)
 	ListSecrets(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput, error)
+	ListSecretVersionIds(*awssm.ListSecretVersionIdsInput) (*awssm.ListSecretVersionIdsOutput, error)
 }
 
 const (
@@ -104,6 +106,43 @@ func (sm *SecretsManager) fetch(_ context.Context, ref esv1beta1.ExternalSecretD
 	return secretOut, nil
 }
 
+// ListSecretVersions returns the versions of a secret that have a staging label
+// attached, newest first. AWS deletes unlabeled versions eventually,
+// so they are not listed.
+// Versions are returned as uuid/<VersionId>, labels are the staging labels.
+func (sm *SecretsManager) ListSecretVersions(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]esv1beta1.SecretVersion, error) {
+	var versions []esv1beta1.SecretVersion
+	var nextToken *string
+	for {
+		out, err := sm.client.ListSecretVersionIds(&awssm.ListSecretVersionIdsInput{
+			SecretId:  &ref.Key,
+			NextToken: nextToken,
+		})
+		var nf *awssm.ResourceNotFoundException
+		if errors.As(err, &nf) {
+			return nil, esv1beta1.NoSecretErr
+		}
+		if err != nil {
+			return nil, util.SanitizeErr(err)
+		}
+		for _, v := range out.Versions {
+			versions = append(versions, esv1beta1.SecretVersion{
+				Version:     "uuid/" + aws.StringValue(v.VersionId),
+				Labels:      aws.StringValueSlice(v.VersionStages),
+				CreatedTime: aws.TimeValue(v.CreatedDate),
+			})
+		}
+		nextToken = out.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].CreatedTime.After(versions[j].CreatedTime)
+	})
+	return versions, nil
+}
+
 // GetAllSecrets syncs multiple secrets from aws provider into a single Kubernetes Secret.
 func (sm *SecretsManager) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 	if ref.Name != nil {
@@ -148,6 +187,9 @@ func (sm *SecretsManager) findByName(ctx context.Context, ref esv1beta1.External
 				continue
 			}
 			log.V(1).Info("aws sm findByName matches", "name", *secret.Name)
+			if err := find.CheckLimit(ref, len(data)); err != nil {
+				return nil, err
+			}
 			err = sm.fetchAndSet(ctx, data, *secret.Name)
 			if err != nil {
 				return nil, err
@@ -199,6 +241,9 @@ func (sm *SecretsManager) findByTags(ctx context.Context, ref esv1beta1.External
 		}
 		log.V(1).Info("aws sm findByTag found", "secrets", len(it.SecretList))
 		for _, secret := range it.SecretList {
+			if err := find.CheckLimit(ref, len(data)); err != nil {
+				return nil, err
+			}
 			err = sm.fetchAndSet(ctx, data, *secret.Name)
 			if err != nil {
 				return nil, err
@@ -230,21 +275,39 @@ func (sm *SecretsManager) fetchAndSet(ctx context.Context, data map[string][]byt
 
 // GetSecret returns a single secret from the provider.
 func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	data, _, err := sm.GetSecretWithVersion(ctx, ref)
+	return data, err
+}
+
+// GetSecretWithVersion returns a single secret from the provider
+// along with the VersionId it was read from.
+func (sm *SecretsManager) GetSecretWithVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, string, error) {
+	data, metadata, err := sm.GetSecretWithMetadata(ctx, ref)
+	return data, metadata.Version, err
+}
+
+// GetSecretWithMetadata returns a single secret from the provider
+// along with the VersionId and CreatedDate of the version it was read from.
+func (sm *SecretsManager) GetSecretWithMetadata(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, esv1beta1.SecretMetadata, error) {
 	secretOut, err := sm.fetch(ctx, ref)
 	if errors.Is(err, esv1beta1.NoSecretErr) {
-		return nil, err
+		return nil, esv1beta1.SecretMetadata{}, err
 	}
 	if err != nil {
-		return nil, util.SanitizeErr(err)
+		return nil, esv1beta1.SecretMetadata{}, util.SanitizeErr(err)
+	}
+	metadata := esv1beta1.SecretMetadata{
+		Version:     aws.StringValue(secretOut.VersionId),
+		CreatedTime: aws.TimeValue(secretOut.CreatedDate),
 	}
 	if ref.Property == "" {
 		if secretOut.SecretString != nil {
-			return []byte(*secretOut.SecretString), nil
+			return []byte(*secretOut.SecretString), metadata, nil
 		}
 		if secretOut.SecretBinary != nil {
-			return secretOut.SecretBinary, nil
+			return secretOut.SecretBinary, metadata, nil
 		}
-		return nil, fmt.Errorf("invalid secret received. no secret string nor binary for key: %s", ref.Key)
+		return nil, esv1beta1.SecretMetadata{}, fmt.Errorf("invalid secret received. no secret string nor binary for key: %s", ref.Key)
 	}
 	var payload string
 	if secretOut.SecretString != nil {
@@ -259,14 +322,14 @@ func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1beta1.ExternalS
 		refProperty := strings.ReplaceAll(ref.Property, ".", "\\.")
 		val := gjson.Get(payload, refProperty)
 		if val.Exists() {
-			return []byte(val.String()), nil
+			return []byte(val.String()), metadata, nil
 		}
 	}
 	val := gjson.Get(payload, ref.Property)
 	if !val.Exists() {
-		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
+		return nil, esv1beta1.SecretMetadata{}, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
-	return []byte(val.String()), nil
+	return []byte(val.String()), metadata, nil
 }
 
 // GetSecretMap returns multiple k/v pairs from the provider.
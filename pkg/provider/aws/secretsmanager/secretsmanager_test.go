@@ -16,9 +16,11 @@ package secretsmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awssm "github.com/aws/aws-sdk-go/service/secretsmanager"
@@ -36,6 +38,8 @@ type secretsManagerTestCase struct {
 	apiErr         error
 	expectError    string
 	expectedSecret string
+	// for testing GetSecretWithVersion
+	expectedVersion string
 	// for testing secretmap
 	expectedData map[string][]byte
 	// for testing caching
@@ -169,7 +173,9 @@ func TestSecretsManagerGetSecret(t *testing.T) {
 		smtc.apiInput.VersionId = aws.String("1234-5678")
 		smtc.remoteRef.Version = "uuid/1234-5678"
 		smtc.apiOutput.SecretString = aws.String("myvalue")
+		smtc.apiOutput.VersionId = aws.String("1234-5678")
 		smtc.expectedSecret = "myvalue"
+		smtc.expectedVersion = "1234-5678"
 	}
 
 	successCases := []*secretsManagerTestCase{
@@ -192,15 +198,78 @@ func TestSecretsManagerGetSecret(t *testing.T) {
 			cache:  make(map[string]*awssm.GetSecretValueOutput),
 			client: v.fakeClient,
 		}
-		out, err := sm.GetSecret(context.Background(), *v.remoteRef)
+		out, version, err := sm.GetSecretWithVersion(context.Background(), *v.remoteRef)
 		if !ErrorContains(err, v.expectError) {
 			t.Errorf(unexpectedErrorString, k, err.Error(), v.expectError)
 		}
 		if err == nil && string(out) != v.expectedSecret {
 			t.Errorf("[%d] unexpected secret: expected %s, got %s", k, v.expectedSecret, string(out))
 		}
+		if err == nil && version != v.expectedVersion {
+			t.Errorf("[%d] unexpected version: expected %s, got %s", k, v.expectedVersion, version)
+		}
+	}
+}
+
+func TestGetSecretWithMetadata(t *testing.T) {
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	smtc := makeValidSecretsManagerTestCaseCustom(func(smtc *secretsManagerTestCase) {
+		smtc.apiOutput.SecretString = aws.String("value")
+		smtc.apiOutput.VersionId = aws.String("123")
+		smtc.apiOutput.CreatedDate = aws.Time(created)
+	})
+	sm := SecretsManager{
+		cache:  make(map[string]*awssm.GetSecretValueOutput),
+		client: smtc.fakeClient,
+	}
+	out, metadata, err := sm.GetSecretWithMetadata(context.Background(), *smtc.remoteRef)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != "value" {
+		t.Errorf("unexpected secret: %s", out)
+	}
+	want := esv1beta1.SecretMetadata{Version: "123", CreatedTime: created}
+	if !cmp.Equal(metadata, want) {
+		t.Errorf("unexpected metadata: %s", cmp.Diff(want, metadata))
+	}
+}
+
+func TestListSecretVersions(t *testing.T) {
+	fakeClient := fakesm.NewClient()
+	day := func(d int) *time.Time { return aws.Time(time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC)) }
+	fakeClient.WithVersions("foo",
+		&awssm.ListSecretVersionIdsOutput{Versions: []*awssm.SecretVersionsListEntry{
+			{VersionId: aws.String("a"), VersionStages: aws.StringSlice([]string{"AWSPREVIOUS"}), CreatedDate: day(1)},
+			{VersionId: aws.String("c"), VersionStages: aws.StringSlice([]string{"AWSPENDING"}), CreatedDate: day(3)},
+		}},
+		&awssm.ListSecretVersionIdsOutput{Versions: []*awssm.SecretVersionsListEntry{
+			{VersionId: aws.String("b"), VersionStages: aws.StringSlice([]string{"AWSCURRENT", "v1.2.0"}), CreatedDate: day(2)},
+		}},
+	)
+	sm := SecretsManager{
+		cache:  make(map[string]*awssm.GetSecretValueOutput),
+		client: fakeClient,
+	}
+	versions, err := sm.ListSecretVersions(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []esv1beta1.SecretVersion{
+		{Version: "uuid/c", Labels: []string{"AWSPENDING"}, CreatedTime: *day(3)},
+		{Version: "uuid/b", Labels: []string{"AWSCURRENT", "v1.2.0"}, CreatedTime: *day(2)},
+		{Version: "uuid/a", Labels: []string{"AWSPREVIOUS"}, CreatedTime: *day(1)},
+	}
+	if !cmp.Equal(versions, want) {
+		t.Errorf("unexpected versions: %s", cmp.Diff(want, versions))
+	}
+
+	_, err = sm.ListSecretVersions(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "missing"})
+	if !errors.Is(err, esv1beta1.NoSecretErr) {
+		t.Errorf("expected NoSecretErr, got %v", err)
 	}
 }
+
 func TestCaching(t *testing.T) {
 	fakeClient := fakesm.NewClient()
 
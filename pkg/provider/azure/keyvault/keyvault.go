@@ -42,6 +42,8 @@ import (
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	smmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/useragent"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
@@ -64,6 +66,8 @@ const (
 	errMissingTenant         = "missing tenantID in store config"
 	errMissingSecretRef      = "missing secretRef in provider config"
 	errMissingClientIDSecret = "missing accessKeyID/secretAccessKey in store config"
+	errMultipleClientCreds   = "only one of clientSecret and clientCertificate may be set"
+	errDecodeClientCert      = "unable to decode client certificate: %w"
 	errFindSecret            = "could not find secret %s/%s: %w"
 	errFindDataKey           = "no data for %q in secret '%s/%s'"
 
@@ -73,7 +77,10 @@ const (
 	errInvalidAzureProv          = "invalid azure keyvault provider"
 	errInvalidSecRefClientID     = "invalid AuthSecretRef.ClientID: %w"
 	errInvalidSecRefClientSecret = "invalid AuthSecretRef.ClientSecret: %w"
+	errInvalidSecRefClientCert   = "invalid AuthSecretRef.ClientCertificate: %w"
+	errInvalidSecRefClientPass   = "invalid AuthSecretRef.ClientCertificatePassword: %w"
 	errInvalidSARef              = "invalid ServiceAccountRef: %w"
+	errInvalidMaxConcurrent      = "invalid MaxConcurrentRequests: must be at least 1"
 
 	errMissingWorkloadEnvVars = "missing environment variables. AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE must be set"
 	errReadTokenFile          = "unable to read token file %s: %w"
@@ -125,6 +132,10 @@ func newClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Cl
 	if err != nil {
 		return nil, err
 	}
+	retry, err := newRetryPolicy(store, provider)
+	if err != nil {
+		return nil, err
+	}
 	az := &Azure{
 		crClient:   kube,
 		kubeClient: kubeClient.CoreV1(),
@@ -147,6 +158,8 @@ func newClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Cl
 
 	cl := keyvault.New()
 	cl.Authorizer = authorizer
+	_ = cl.AddToUserAgent(useragent.ForStore(store))
+	cl.SendDecorators = []autorest.SendDecorator{retry.decorator()}
 	az.baseClient = &cl
 
 	return az, err
@@ -187,15 +200,39 @@ func (a *Azure) ValidateStore(store esv1beta1.GenericStore) error {
 				return fmt.Errorf(errInvalidSecRefClientSecret, err)
 			}
 		}
+		if p.AuthSecretRef.ClientCertificate != nil {
+			if p.AuthSecretRef.ClientSecret != nil {
+				return errors.New(errMultipleClientCreds)
+			}
+			if err := utils.ValidateSecretSelector(store, *p.AuthSecretRef.ClientCertificate); err != nil {
+				return fmt.Errorf(errInvalidSecRefClientCert, err)
+			}
+		}
+		if p.AuthSecretRef.ClientCertificatePassword != nil {
+			if err := utils.ValidateSecretSelector(store, *p.AuthSecretRef.ClientCertificatePassword); err != nil {
+				return fmt.Errorf(errInvalidSecRefClientPass, err)
+			}
+		}
 	}
 	if p.ServiceAccountRef != nil {
 		if err := utils.ValidateServiceAccountSelector(store, *p.ServiceAccountRef); err != nil {
 			return fmt.Errorf(errInvalidSARef, err)
 		}
 	}
+	if p.MaxConcurrentRequests != nil && *p.MaxConcurrentRequests < 1 {
+		return errors.New(errInvalidMaxConcurrent)
+	}
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (a *Azure) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind, esv1beta1.FeatureMetadata},
+	}
+}
+
 // Implements store.Client.GetAllSecrets Interface.
 // Retrieves a map[string][]byte with the secret names as key and the secret itself as the calue.
 func (a *Azure) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
@@ -216,6 +253,9 @@ func (a *Azure) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretF
 			if !ok {
 				continue
 			}
+			if err := find.CheckLimit(ref, len(secretsMap)); err != nil {
+				return nil, err
+			}
 
 			secretResp, err := basicClient.GetSecret(context.Background(), *a.provider.VaultURL, secretName, "")
 			if err != nil {
@@ -264,21 +304,21 @@ func getSecretTag(tags map[string]*string, property string) ([]byte, error) {
 }
 
 // Retrieves a property value if specified and the secret value if not.
+// Like the GCP provider, a top-level key that contains dots takes precedence
+// over the nested path, e.g. "foo.bar" matches {"foo.bar": "x"} before {"foo": {"bar": "y"}}.
 func getProperty(secret, property, key string) ([]byte, error) {
 	if property == "" {
 		return []byte(secret), nil
 	}
-	res := gjson.Get(secret, property)
-	if !res.Exists() {
-		idx := strings.Index(property, ".")
-		if idx < 0 {
-			return nil, fmt.Errorf(errPropNotExist, property, key)
-		}
+	if strings.Contains(property, ".") {
 		escaped := strings.ReplaceAll(property, ".", "\\.")
 		jValue := gjson.Get(secret, escaped)
 		if jValue.Exists() {
 			return []byte(jValue.String()), nil
 		}
+	}
+	res := gjson.Get(secret, property)
+	if !res.Exists() {
 		return nil, fmt.Errorf(errPropNotExist, property, key)
 	}
 	return []byte(res.String()), nil
@@ -324,7 +364,11 @@ func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataR
 		if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
 			return getSecretTag(keyResp.Tags, ref.Property)
 		}
-		return json.Marshal(keyResp.Key)
+		keyJSON, err := json.Marshal(keyResp.Key)
+		if err != nil {
+			return nil, err
+		}
+		return getProperty(string(keyJSON), ref.Property, ref.Key)
 	}
 
 	return nil, fmt.Errorf(errUnknownObjectType, secretName)
@@ -546,18 +590,25 @@ func (a *Azure) authorizerForServicePrincipal(ctx context.Context) (autorest.Aut
 	if a.provider.AuthSecretRef == nil {
 		return nil, fmt.Errorf(errMissingSecretRef)
 	}
-	if a.provider.AuthSecretRef.ClientID == nil || a.provider.AuthSecretRef.ClientSecret == nil {
+	auth := a.provider.AuthSecretRef
+	if auth.ClientID == nil || (auth.ClientSecret == nil && auth.ClientCertificate == nil) {
 		return nil, fmt.Errorf(errMissingClientIDSecret)
 	}
+	if auth.ClientSecret != nil && auth.ClientCertificate != nil {
+		return nil, fmt.Errorf(errMultipleClientCreds)
+	}
 	clusterScoped := false
 	if a.store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind {
 		clusterScoped = true
 	}
-	cid, err := a.secretKeyRef(ctx, a.store.GetNamespace(), *a.provider.AuthSecretRef.ClientID, clusterScoped)
+	cid, err := a.secretKeyRef(ctx, a.store.GetNamespace(), *auth.ClientID, clusterScoped)
 	if err != nil {
 		return nil, err
 	}
-	csec, err := a.secretKeyRef(ctx, a.store.GetNamespace(), *a.provider.AuthSecretRef.ClientSecret, clusterScoped)
+	if auth.ClientCertificate != nil {
+		return a.authorizerForClientCertificate(ctx, cid, clusterScoped)
+	}
+	csec, err := a.secretKeyRef(ctx, a.store.GetNamespace(), *auth.ClientSecret, clusterScoped)
 	if err != nil {
 		return nil, err
 	}
@@ -567,8 +618,47 @@ func (a *Azure) authorizerForServicePrincipal(ctx context.Context) (autorest.Aut
 	return clientCredentialsConfig.Authorizer()
 }
 
+// authorizerForClientCertificate authenticates the service principal with the PFX archive of the store.
+func (a *Azure) authorizerForClientCertificate(ctx context.Context, clientID string, clusterScoped bool) (autorest.Authorizer, error) {
+	auth := a.provider.AuthSecretRef
+	pfx, err := a.secretKeyRefData(ctx, a.store.GetNamespace(), *auth.ClientCertificate, clusterScoped)
+	if err != nil {
+		return nil, err
+	}
+	var password string
+	if auth.ClientCertificatePassword != nil {
+		password, err = a.secretKeyRef(ctx, a.store.GetNamespace(), *auth.ClientCertificatePassword, clusterScoped)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cert, key, err := adal.DecodePfxCertificateData(pfx, password)
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeClientCert, err)
+	}
+	oauthConfig, err := adal.NewOAuthConfig(AadEndpointForType(a.provider.EnvironmentType), *a.provider.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	spt, err := adal.NewServicePrincipalTokenFromCertificate(*oauthConfig, clientID, cert, key, kvResourceForProviderConfig(a.provider.EnvironmentType))
+	if err != nil {
+		return nil, err
+	}
+	return autorest.NewBearerAuthorizer(spt), nil
+}
+
 // secretKeyRef fetch a secret key.
 func (a *Azure) secretKeyRef(ctx context.Context, namespace string, secretRef smmeta.SecretKeySelector, clusterScoped bool) (string, error) {
+	keyBytes, err := a.secretKeyRefData(ctx, namespace, secretRef, clusterScoped)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(keyBytes))
+	return value, nil
+}
+
+// secretKeyRefData fetch the raw data of a secret key.
+func (a *Azure) secretKeyRefData(ctx context.Context, namespace string, secretRef smmeta.SecretKeySelector, clusterScoped bool) ([]byte, error) {
 	var secret corev1.Secret
 	ref := types.NamespacedName{
 		Namespace: namespace,
@@ -579,14 +669,13 @@ func (a *Azure) secretKeyRef(ctx context.Context, namespace string, secretRef sm
 	}
 	err := a.crClient.Get(ctx, ref, &secret)
 	if err != nil {
-		return "", fmt.Errorf(errFindSecret, ref.Namespace, ref.Name, err)
+		return nil, fmt.Errorf(errFindSecret, ref.Namespace, ref.Name, err)
 	}
 	keyBytes, ok := secret.Data[secretRef.Key]
 	if !ok {
-		return "", fmt.Errorf(errFindDataKey, secretRef.Key, secretRef.Name, namespace)
+		return nil, fmt.Errorf(errFindDataKey, secretRef.Key, secretRef.Name, namespace)
 	}
-	value := strings.TrimSpace(string(keyBytes))
-	return value, nil
+	return keyBytes, nil
 }
 
 func (a *Azure) Close(ctx context.Context) error {
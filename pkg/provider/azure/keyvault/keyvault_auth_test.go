@@ -16,6 +16,7 @@ package keyvault
 
 import (
 	"context"
+	"encoding/base64"
 	"net/http"
 	"os"
 	"strings"
@@ -37,6 +38,9 @@ import (
 
 var vaultURL = "https://local.vault.url"
 
+// clientCertificatePFX is a self-signed certificate with its private key, encrypted with the password "secret".
+const clientCertificatePFX = `MIIJYQIBAzCCCScGCSqGSIb3DQEHAaCCCRgEggkUMIIJEDCCA8cGCSqGSIb3DQEHBqCCA7gwggO0AgEAMIIDrQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQMwDgQIMeC/aIy8M7ECAggAgIIDgDS4C2RNLhIY2T97+pWcqTX0A9jeLTpIAKVJCnyLthqkxawwdHsHzADB3Cffza+JzylMQwmoOl2PmazbpEbuJ+SYjEw7ZC5v8fs6X0xRqfxbqsV90/+b7CrKub/kosHlI7n2hBJVpvTV/JR8AH3+RCHsQcRPR+4CrYb3o7ScFiWE6VV44hRGQvQP1xvOSiCQnjTNKw9m5Hhbt5PXWnovRx+Kj858+C7P9ftbMreuO6O5zaKWiO+6rRK1ICp2AFYo2VP+IzlsUzL52nN8t/V3kR4IVRo+kVX0BX/yupL/rVhMybj4pRXXGagiuINUEbTcYRBvxvxNzMhnrpb0Cs34mJuwGhgk1qtlX6Tg/FVnizdkXKKeLsoib6jEtU73zsmCOg4SqIDDXallfbS5pAKfFNtqlULlz0lx3vjfVPcU8yppI53PIIwapr5YXfnZllgj0WDiaRmXz4d9kKqNfpkSVnIWqFxqzUDwHsWtoJiiZXWBZpBOpiXuJMzeh0dUHDXM1fld5Avj+Z6EaC4QftTNKTi4bEFDZA3q0q5Gb+jd2d5CqwXWe6DUN6cQ5OE90opeinFBaqdLBa5P5pmfVlqyZcmIuS5lsOzez6nWLcitsmZwmLyeJJcygjhV/miKh40JLfJqE6SNqfazj/BCWAdI8kZ55zx2umcq7EvK1CS60BEDosChXv4n9MCS2eT34xLqkV/wgw3ohTgSGPVdk5Ee69tOzDy7ljMsCnRpuKJRrMoC0AtFApYRpDyvnzpC7NiknUZoJpTzIL4QaVuY4mfsF8RUC7+ERnsFAhZaYKSNX2U3kypmvaz7Ya9iqz96q5LN7fxE1M2bK0V4e+YkBEpeFqDfd+/XYkHikvVCkMnsL6A1W01yDuBu8n0dkYbZFqp7XJkibg5UCznauaVa+Thop29nKJPUf+6tyDcTz3wWKYPjeND9HAIbqe/CWQaUOn1qWUdZ67BXlk/fDn0Lea6bwnaa9a75T44CEbHxURzkBHaSIKcMt5BYD55QUBJW5KsuBRxO1+rJwxSmq5o13XiJMA0nT/rN7Zw2K4cK8ovnHC7bGwEiiWoglFCIO63HX0mbKLyinUYmkFBL+zJen70h9EgWty5/19hIK94J01Qeo6+91Im6wDB+1MKy5X0AbzA5VkCCCvrmjCFGb4p9vb+8u6UxA1wzSLS0kf8KxqTD3ICaMIIFQQYJKoZIhvcNAQcBoIIFMgSCBS4wggUqMIIFJgYLKoZIhvcNAQwKAQKgggTuMIIE6jAcBgoqhkiG9w0BDAEDMA4ECE5uFv8qZYIoAgIIAASCBMgz6N/2GZbyzLxf7PGK+NkFUAHsemaCZwoxeOB3p+OfKQE1mqv6Z+p0NcvnrAb8SWPD20apdQ63ujUpCEaKm2il1TDwya6l07KkJ3j14I70pAX76riF6kNzNkwJrhSRo6gAwMMdN/EDHA0lMbUhh19evADjZnVlajVEan9raIMdplk3tOMhqVEDSYQsRzs6cPhlcqTzq4vlAbGorhm8Umt0Dp9Q1YF3WKaH9bhQWJePfpbDmyyFcp9KbbixrY9Z4usMiDgLK+ayDSsLCzypB+KWDq33B9CZt0aHAYoZhOgVXndbNNtAeuXUYKtCP3DXYZJaDXgbV6tGDwpYmJOkTu5Cawie94Ai9QZGAn3dz2aDG1vdCrYeVZp6cKVZ1rm/1nQSwpR3xkBNpjfRtD1cyBYaZTGVzPCyyLlzwDI7FbGgIZ0kIgiiK/WBhz5zfjmpy57mYk1rbX6eQcKJgtyyYvGhFgfVjUhk8nNP8hUgDhvU9Vrk69A4GAyMl//9vqW6IBsHnEzlcVjdohlayOtw9nwZcmFUdns83Oa5yz221KJw74ikzHmQzUbPdIZWGSn+oCB2qKYyiv5eUe51mq6Ro1eTG0E1wEHLhhrpTL4hGRMEvNLE2Bpi9P4Dv1BeKlufCABOFc31IhTO8Yg8d775AU8S0EXYlCl0tm/sE5LmiMjbxGEkQK7q4F2d0L5p0RhW46UVg52BZkUwPebXSEsmwaVhqV/bopSgptVs0SA4I/wgKqpMF/cyDNmdLKUXUw67xz9r9TnEmbuvhJUH267iJDaLrc89zRR1EW+mSHzlLvbrMXB3/k7/iqH0OCBfL56BCX6tqoIpUkwU6vpSKNbqUET9fhhlN+dOJ/TqbuCUgoIIdYaM5wQewQnCWhYaAjRiRelETmHr3vaUKJqQ35n0FO+MfsmYGy7F61ePoiE8ZSZ/VORb2omNw7PxCar1QxEl2ggfM/S2ig+CsOnPq+X4fnvAHEuSiFrzthc3pDdmDDTkONBq/QLYvUGJ12bA5JdyeACnjYJUoquHfXrJDhf7er68g83Qm7Ie9TZc6x9iy7nYXl3WA/hAZgWTCRMSsdSZqkGOaAnUeKBpuoJBDWi3bjnR9LqmudX081c5ReV3NWlYJr/98LRG9xjdtFZXcK100e+q7P3Api+why1cCPOX6cGRYJPXLYrqFRSThuWXw5JI02teLuHJobW/ekMgljzbw7lNWoNPYD3lQTw1aoipFJ7qo9wo2lbI01qHMLCAnvQoLJmfYAkPb5yR1y4NzerIx7W5bTcCIAtGfF2hk7PrJCrma6P8rhbudvNL7o+EFxJmRmcsep6VgqbMvPS0LZDvd2AR1KC3+WIEBItUm6GjTFCUjX0OGHdU5IMTmbY2aR8sdOS1mu5T4bKxpwXqLAH08WZ37bPUsbs2xvdXuvjxNKbNXH7VMQdx3MV21mzLKVTtqOb2iUDZVN2po/9L3FWal9OFrg2ujPjQD+wHJDv7hjZ3iekaJInb5sXYCQvWdjTTSPq4tgH98yIjykKExT/Xd0U2XXgf+qtM+tXBZpA3hysGqUgRw+UI/EHw+K1+zx66u7NGA2L3XNwfSkKeiQ7ptjtTUNroP/MB7FHCP7/IzN5o/gz4AGnApyUxJTAjBgkqhkiG9w0BCRUxFgQUcHszyd3lE460v1QB+/M+6DKZZhYwMTAhMAkGBSsOAwIaBQAEFC9ATKp+S9grTtJY713MXFHsMOb0BAhb/E/A1ng0rwICCAA=`
+
 func TestNewClientManagedIdentityNoNeedForCredentials(t *testing.T) {
 	namespace := "internal"
 	identityID := "1234"
@@ -212,6 +216,10 @@ func TestAuth(t *testing.T) {
 		},
 	}
 	authType := esv1beta1.AzureServicePrincipal
+	pfx, err := base64.StdEncoding.DecodeString(clientCertificatePFX)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	type testCase struct {
 		name     string
@@ -303,6 +311,72 @@ func TestAuth(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "client certificate",
+			objects: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cert",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"id":       []byte("foo"),
+					"pfx":      pfx,
+					"password": []byte("secret"),
+				},
+			}},
+			store: &defaultStore,
+			provider: &esv1beta1.AzureKVProvider{
+				AuthType: &authType,
+				VaultURL: &vaultURL,
+				TenantID: pointer.StringPtr("mytenant"),
+				AuthSecretRef: &esv1beta1.AzureKVAuth{
+					ClientID:                  &v1.SecretKeySelector{Name: "cert", Key: "id"},
+					ClientCertificate:         &v1.SecretKeySelector{Name: "cert", Key: "pfx"},
+					ClientCertificatePassword: &v1.SecretKeySelector{Name: "cert", Key: "password"},
+				},
+			},
+		},
+		{
+			name:   "client certificate with wrong password",
+			expErr: "unable to decode client certificate: pkcs12: decryption password incorrect",
+			objects: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cert",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"id":       []byte("foo"),
+					"pfx":      pfx,
+					"password": []byte("wrong"),
+				},
+			}},
+			store: &defaultStore,
+			provider: &esv1beta1.AzureKVProvider{
+				AuthType: &authType,
+				VaultURL: &vaultURL,
+				TenantID: pointer.StringPtr("mytenant"),
+				AuthSecretRef: &esv1beta1.AzureKVAuth{
+					ClientID:                  &v1.SecretKeySelector{Name: "cert", Key: "id"},
+					ClientCertificate:         &v1.SecretKeySelector{Name: "cert", Key: "pfx"},
+					ClientCertificatePassword: &v1.SecretKeySelector{Name: "cert", Key: "password"},
+				},
+			},
+		},
+		{
+			name:   "client secret and certificate",
+			expErr: "only one of clientSecret and clientCertificate may be set",
+			store:  &defaultStore,
+			provider: &esv1beta1.AzureKVProvider{
+				AuthType: &authType,
+				VaultURL: &vaultURL,
+				TenantID: pointer.StringPtr("mytenant"),
+				AuthSecretRef: &esv1beta1.AzureKVAuth{
+					ClientID:          &v1.SecretKeySelector{Name: "cert", Key: "id"},
+					ClientSecret:      &v1.SecretKeySelector{Name: "cert", Key: "secret"},
+					ClientCertificate: &v1.SecretKeySelector{Name: "cert", Key: "pfx"},
+				},
+			},
+		},
 	} {
 		t.Run(row.name, func(t *testing.T) {
 			k8sClient := clientfake.NewClientBuilder().WithObjects(row.objects...).Build()
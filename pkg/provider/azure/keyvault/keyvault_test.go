@@ -434,6 +434,34 @@ func TestAzureKeyVaultSecretManagerGetSecret(t *testing.T) {
 		smtc.expectedSecret = bar
 	}
 
+	fetchDottedKeyBeforeNestedPath := func(smtc *secretManagerTestCase) {
+		jsonString := "{\"foo.json\":\"bar\", \"foo\": {\"json\": \"nested\"}}"
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value: &jsonString,
+		}
+		smtc.ref.Property = "foo.json"
+		smtc.expectedSecret = bar
+	}
+
+	fetchNestedSecretProperty := func(smtc *secretManagerTestCase) {
+		jsonString := jsonTestString
+		smtc.secretOutput = keyvault.SecretBundle{
+			Value: &jsonString,
+		}
+		smtc.ref.Property = "Address.Street"
+		smtc.expectedSecret = "Myroad st."
+	}
+
+	setPubRSAKeyWithProperty := func(smtc *secretManagerTestCase) {
+		smtc.secretName = keyName
+		smtc.expectedSecret = "RSA"
+		smtc.keyOutput = keyvault.KeyBundle{
+			Key: newKVJWK([]byte(jwkPubRSA)),
+		}
+		smtc.ref.Key = smtc.secretName
+		smtc.ref.Property = "kty"
+	}
+
 	successCases := []*secretManagerTestCase{
 		makeValidSecretManagerTestCase(),
 		makeValidSecretManagerTestCaseCustom(setSecretString),
@@ -464,6 +492,9 @@ func TestAzureKeyVaultSecretManagerGetSecret(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(fetchNestedDottedJSONTag),
 		makeValidSecretManagerTestCaseCustom(fetchDottedKeyJSONTag),
 		makeValidSecretManagerTestCaseCustom(fetchDottedSecretJSONTag),
+		makeValidSecretManagerTestCaseCustom(fetchDottedKeyBeforeNestedPath),
+		makeValidSecretManagerTestCaseCustom(fetchNestedSecretProperty),
+		makeValidSecretManagerTestCaseCustom(setPubRSAKeyWithProperty),
 	}
 
 	sm := Azure{
@@ -918,6 +949,43 @@ func TestValidateStore(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "invalid client certificate",
+			wantErr: true,
+			args: args{
+				store: &esv1beta1.SecretStore{
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AzureKV: &esv1beta1.AzureKVProvider{
+								AuthSecretRef: &esv1beta1.AzureKVAuth{
+									ClientCertificate: &v1.SecretKeySelector{
+										Namespace: pointer.StringPtr("invalid"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "client secret and certificate",
+			wantErr: true,
+			args: args{
+				store: &esv1beta1.SecretStore{
+					Spec: esv1beta1.SecretStoreSpec{
+						Provider: &esv1beta1.SecretStoreProvider{
+							AzureKV: &esv1beta1.AzureKVProvider{
+								AuthSecretRef: &esv1beta1.AzureKVAuth{
+									ClientSecret:      &v1.SecretKeySelector{Name: "sp", Key: "secret"},
+									ClientCertificate: &v1.SecretKeySelector{Name: "sp", Key: "pfx"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,65 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyvault
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	AzureKVSubsystem = "provider_azurekv"
+	ThrottledKey     = "throttled_requests_total"
+	RetriedKey       = "retried_requests_total"
+	ThrottleDelayKey = "throttle_delay_seconds"
+)
+
+var (
+	throttledRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: AzureKVSubsystem,
+		Name:      ThrottledKey,
+		Help:      "Total number of Azure Key Vault requests that were throttled",
+	}, []string{"operation"})
+
+	retriedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: AzureKVSubsystem,
+		Name:      RetriedKey,
+		Help:      "Total number of Azure Key Vault request retries",
+	}, []string{"operation"})
+
+	throttleDelay = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: AzureKVSubsystem,
+		Name:      ThrottleDelayKey,
+		Help:      "Delay before retrying a throttled Azure Key Vault request",
+		Buckets:   []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60},
+	}, []string{"operation"})
+)
+
+// requestOperation returns the object type a request operates on, e.g. `secrets`.
+func requestOperation(r *http.Request) string {
+	operation := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)[0]
+	switch operation {
+	case "secrets", "keys", "certificates":
+		return operation
+	}
+	return "other"
+}
+
+func init() {
+	metrics.Registry.MustRegister(throttledRequests, retriedRequests, throttleDelay)
+}
@@ -0,0 +1,224 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyvault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	lru "github.com/hashicorp/golang-lru"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	defaultMaxRetries    = 3
+	defaultRetryInterval = time.Second
+
+	// maxRetryDelay is the longest a request is delayed before it is retried.
+	// If Key Vault asks for a longer delay the throttled response is returned,
+	// so the ExternalSecret is requeued instead of blocking a worker.
+	maxRetryDelay = 60 * time.Second
+
+	// maxLimiters bounds the number of stores whose concurrent requests are limited at the same time.
+	// A limiter that is dropped while it is in use only lets the requests of its store exceed the limit briefly.
+	maxLimiters = 1000
+
+	errRetryInterval = "invalid retrySettings.retryInterval: %w"
+)
+
+// retryPolicy retries failed and throttled Key Vault requests.
+// It replaces the default autorest policy, which retries HTTP 429 responses without limit.
+type retryPolicy struct {
+	maxRetries int
+	interval   time.Duration
+	limiter    *requestLimiter
+	sleep      func(ctx context.Context, d time.Duration) bool
+}
+
+func newRetryPolicy(store esv1beta1.GenericStore, provider *esv1beta1.AzureKVProvider) (*retryPolicy, error) {
+	policy := &retryPolicy{
+		maxRetries: defaultMaxRetries,
+		interval:   defaultRetryInterval,
+		sleep:      sleep,
+	}
+	if retry := store.GetSpec().RetrySettings; retry != nil {
+		if retry.MaxRetries != nil {
+			policy.maxRetries = int(*retry.MaxRetries)
+		}
+		if retry.RetryInterval != nil {
+			interval, err := time.ParseDuration(*retry.RetryInterval)
+			if err != nil {
+				return nil, fmt.Errorf(errRetryInterval, err)
+			}
+			policy.interval = interval
+		}
+	}
+	if provider.MaxConcurrentRequests != nil && *provider.MaxConcurrentRequests > 0 {
+		policy.limiter = limiterFor(store, *provider.MaxConcurrentRequests)
+	}
+	return policy, nil
+}
+
+// decorator returns the SendDecorator applying the policy.
+func (p *retryPolicy) decorator() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			return p.send(s, r)
+		})
+	}
+}
+
+func (p *retryPolicy) send(s autorest.Sender, r *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+	operation := requestOperation(r)
+	rr := autorest.NewRetriableRequest(r)
+	for attempt := 0; ; attempt++ {
+		if err = rr.Prepare(); err != nil {
+			return resp, err
+		}
+		autorest.DrainResponseBody(resp)
+		resp, err = p.do(s, rr.Request())
+		if !shouldRetry(resp, err) || attempt >= p.maxRetries {
+			return resp, err
+		}
+
+		delay := backoff(p.interval, attempt)
+		if after, ok := retryAfter(resp, time.Now()); ok {
+			delay = after
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			throttledRequests.WithLabelValues(operation).Inc()
+			throttleDelay.WithLabelValues(operation).Observe(delay.Seconds())
+		}
+		if delay > maxRetryDelay {
+			return resp, err
+		}
+		if !p.sleep(r.Context(), delay) {
+			return resp, r.Context().Err()
+		}
+		retriedRequests.WithLabelValues(operation).Inc()
+	}
+}
+
+// do sends a single request, waiting for a free slot if the store limits concurrent requests.
+func (p *retryPolicy) do(s autorest.Sender, r *http.Request) (*http.Response, error) {
+	if p.limiter != nil {
+		if err := p.limiter.acquire(r.Context()); err != nil {
+			return nil, err
+		}
+		defer p.limiter.release()
+	}
+	return s.Do(r)
+}
+
+// shouldRetry follows autorest.DoRetryForStatusCodes: transient errors and
+// the autorest retry status codes are retried, failed token refreshes are not.
+func shouldRetry(resp *http.Response, err error) bool {
+	if autorest.IsTokenRefreshError(err) {
+		return false
+	}
+	return err != nil || autorest.ResponseHasStatusCode(resp, autorest.StatusCodesForRetry...)
+}
+
+// backoff returns the exponential delay before retry attempt+1.
+func backoff(interval time.Duration, attempt int) time.Duration {
+	delay := interval
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// retryAfter parses the Retry-After header, either in seconds or as HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// requestLimiter limits the number of concurrent requests of a store.
+type requestLimiter struct {
+	limit int
+	slots chan struct{}
+}
+
+func (l *requestLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *requestLimiter) release() {
+	<-l.slots
+}
+
+var (
+	limitersMu sync.Mutex
+	// limiters holds the limiter of each store. The limiters of the stores used
+	// least recently, e.g. deleted ones, are dropped. lru.New only fails for a size below 1.
+	limiters, _ = lru.New(maxLimiters)
+)
+
+// limiterFor returns the limiter shared by all clients of a store.
+// A new limiter is created if the limit of the store changed.
+func limiterFor(store esv1beta1.GenericStore, limit int) *requestLimiter {
+	key := fmt.Sprintf("%s/%s/%s", store.GetObjectKind().GroupVersionKind().Kind, store.GetNamespace(), store.GetName())
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if l, ok := limiters.Get(key); ok && l.(*requestLimiter).limit == limit {
+		return l.(*requestLimiter)
+	}
+	l := &requestLimiter{limit: limit, slots: make(chan struct{}, limit)}
+	limiters.Add(key, l)
+	return l
+}
@@ -0,0 +1,158 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyvault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	throttled := func(retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+	ok := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	tests := []struct {
+		name       string
+		maxRetries int
+		responses  []*http.Response
+		wantStatus int
+		wantDelays []time.Duration
+	}{
+		{
+			name:       "honors retry-after",
+			maxRetries: 3,
+			responses:  []*http.Response{throttled("7"), ok},
+			wantStatus: http.StatusOK,
+			wantDelays: []time.Duration{7 * time.Second},
+		},
+		{
+			name:       "exponential backoff without retry-after",
+			maxRetries: 3,
+			responses:  []*http.Response{throttled(""), throttled(""), ok},
+			wantStatus: http.StatusOK,
+			wantDelays: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:       "throttling is bounded by max retries",
+			maxRetries: 1,
+			responses:  []*http.Response{throttled("1"), throttled("1"), ok},
+			wantStatus: http.StatusTooManyRequests,
+			wantDelays: []time.Duration{time.Second},
+		},
+		{
+			name:       "retry-after above max delay is returned",
+			maxRetries: 3,
+			responses:  []*http.Response{throttled("3600"), ok},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "other errors are not retried",
+			maxRetries: 3,
+			responses:  []*http.Response{{StatusCode: http.StatusForbidden, Header: http.Header{}}},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delays []time.Duration
+			p := &retryPolicy{
+				maxRetries: tt.maxRetries,
+				interval:   time.Second,
+				sleep: func(ctx context.Context, d time.Duration) bool {
+					delays = append(delays, d)
+					return true
+				},
+			}
+			calls := 0
+			sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+				resp := tt.responses[calls]
+				calls++
+				return resp, nil
+			})
+			req, _ := http.NewRequest(http.MethodGet, "https://vault.example.com/secrets/foo", http.NoBody)
+			resp, err := autorest.DecorateSender(sender, p.decorator()).Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if len(delays) != len(tt.wantDelays) {
+				t.Fatalf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+			for i := range delays {
+				if delays[i] != tt.wantDelays[i] {
+					t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestLimiter(t *testing.T) {
+	l := &requestLimiter{limit: 1, slots: make(chan struct{}, 1)}
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(ctx); err == nil {
+		t.Errorf("expected acquire to block while the limit is reached")
+	}
+	l.release()
+	if err := l.acquire(context.Background()); err != nil {
+		t.Errorf("unexpected error after release: %v", err)
+	}
+}
+
+func TestLimiterFor(t *testing.T) {
+	store := func(name string) *esv1beta1.SecretStore {
+		return &esv1beta1.SecretStore{
+			TypeMeta:   metav1.TypeMeta{Kind: esv1beta1.SecretStoreKind},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	a := limiterFor(store("a"), 2)
+	if limiterFor(store("a"), 2) != a {
+		t.Errorf("limiter of a store was not reused")
+	}
+	if limiterFor(store("b"), 2) == a {
+		t.Errorf("stores share a limiter")
+	}
+	if limiterFor(store("a"), 3) == a {
+		t.Errorf("limiter was not recreated for a changed limit")
+	}
+
+	// the limiters of the stores used least recently are dropped
+	for i := 0; i < maxLimiters; i++ {
+		limiterFor(store(fmt.Sprintf("store-%d", i)), 2)
+	}
+	if limiters.Len() != maxLimiters {
+		t.Errorf("limiters.Len() = %d, want %d", limiters.Len(), maxLimiters)
+	}
+}
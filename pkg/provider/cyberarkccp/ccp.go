@@ -0,0 +1,253 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cyberarkccp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultPath    = "/AIMWebService/api/Accounts"
+	defaultTimeout = 30 * time.Second
+	contentField   = "Content"
+
+	// errorCodeNotFound is returned by CCP if no password object matches the query.
+	errorCodeNotFound = "APPAP004E"
+
+	errCCPStore            = "missing or invalid CyberArk CCP SecretStore"
+	errMissingURL          = "url is required"
+	errInvalidURL          = "invalid url: %w"
+	errMissingAppID        = "appID is required"
+	errMissingClientCert   = "auth.cert is required"
+	errRequest             = "unable to get password %q: %w"
+	errResponse            = "unable to get password %q: %s: %s"
+	errUnexpectedStatus    = "unable to get password %q: unexpected status %d"
+	errUnmarshalResponse   = "unable to unmarshal response for %q: %w"
+	errProperty            = "property %q does not exist in password %q"
+	errVersionNotSupported = "versions are not supported by the CyberArk CCP provider"
+	errFindNotSupported    = "find is not supported by the CyberArk CCP provider"
+)
+
+// https://github.com/external-secrets/external-secrets/issues/644
+var _ esv1beta1.SecretsClient = &Client{}
+var _ esv1beta1.Provider = &Provider{}
+
+// Provider implements the esv1beta1.Provider interface for CyberArk CCP.
+type Provider struct{}
+
+// Client retrieves passwords with the GetPassword REST API of CCP.
+type Client struct {
+	http   *http.Client
+	url    string
+	appID  string
+	safe   string
+	folder string
+}
+
+// errorResponse is returned by CCP if a request fails.
+type errorResponse struct {
+	ErrorCode string `json:"ErrorCode"`
+	ErrorMsg  string `json:"ErrorMsg"`
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		CyberArkCCP: &esv1beta1.CyberArkCCPProvider{},
+	})
+}
+
+// NewClient constructs a CCP client authenticated with a TLS client certificate.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.CyberArkCCP == nil {
+		return nil, fmt.Errorf(errCCPStore)
+	}
+	ccpSpec := storeSpec.Provider.CyberArkCCP
+	if ccpSpec.Auth.Cert == nil {
+		return nil, fmt.Errorf(errMissingClientCert)
+	}
+	tlsConfig, err := utils.NewCertResolver(kube, store, namespace).ClientTLSConfig(ctx, ccpSpec.Auth.Cert, ccpSpec.CABundle, ccpSpec.CAProvider)
+	if err != nil {
+		return nil, err
+	}
+	path := ccpSpec.Path
+	if path == "" {
+		path = defaultPath
+	}
+	return &Client{
+		http: &http.Client{
+			Timeout:   defaultTimeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		},
+		url:    strings.TrimSuffix(ccpSpec.URL, "/") + path,
+		appID:  ccpSpec.AppID,
+		safe:   ccpSpec.Safe,
+		folder: ccpSpec.Folder,
+	}, nil
+}
+
+// ValidateStore checks the CCP store configuration.
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.CyberArkCCP == nil {
+		return fmt.Errorf(errCCPStore)
+	}
+	ccpSpec := storeSpec.Provider.CyberArkCCP
+	if ccpSpec.URL == "" {
+		return fmt.Errorf(errMissingURL)
+	}
+	if _, err := url.ParseRequestURI(ccpSpec.URL); err != nil {
+		return fmt.Errorf(errInvalidURL, err)
+	}
+	if ccpSpec.AppID == "" {
+		return fmt.Errorf(errMissingAppID)
+	}
+	if ccpSpec.Auth.Cert == nil {
+		return fmt.Errorf(errMissingClientCert)
+	}
+	if err := utils.ValidateSecretSelector(store, ccpSpec.Auth.Cert.ClientCert); err != nil {
+		return err
+	}
+	if err := utils.ValidateSecretSelector(store, ccpSpec.Auth.Cert.ClientKey); err != nil {
+		return err
+	}
+	if store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind &&
+		ccpSpec.CAProvider != nil &&
+		ccpSpec.CAProvider.Namespace == nil {
+		return fmt.Errorf("CAProvider.namespace must not be empty with ClusterSecretStore")
+	}
+	return nil
+}
+
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
+
+// GetSecret returns the password of the object,
+// or another field of the GetPassword response if ref.Property is set.
+func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	data, err := c.GetSecretMap(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	property := ref.Property
+	if property == "" {
+		property = contentField
+	}
+	val, ok := data[property]
+	if !ok {
+		return nil, fmt.Errorf(errProperty, property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns all fields of the GetPassword response,
+// e.g. Content, UserName and Address.
+func (c *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if ref.Version != "" {
+		return nil, fmt.Errorf(errVersionNotSupported)
+	}
+	body, err := c.getPassword(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &kv); err != nil {
+		return nil, fmt.Errorf(errUnmarshalResponse, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+			continue
+		}
+		secretData[k] = v
+	}
+	return secretData, nil
+}
+
+// getPassword queries CCP for the key.
+// A key that contains "=" is a CCP query, e.g. "Safe=Prod;Object=db",
+// other keys are object names in the safe and folder of the store.
+func (c *Client) getPassword(ctx context.Context, key string) ([]byte, error) {
+	query := url.Values{}
+	query.Set("AppID", c.appID)
+	if strings.Contains(key, "=") {
+		query.Set("Query", key)
+	} else {
+		query.Set("Object", key)
+		if c.safe != "" {
+			query.Set("Safe", c.safe)
+		}
+		if c.folder != "" {
+			query.Set("Folder", c.folder)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf(errRequest, key, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(errRequest, key, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf(errRequest, key, err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return body, nil
+	}
+	var errResp errorResponse
+	if err := json.Unmarshal(body, &errResp); err != nil || errResp.ErrorCode == "" {
+		return nil, fmt.Errorf(errUnexpectedStatus, key, resp.StatusCode)
+	}
+	if errResp.ErrorCode == errorCodeNotFound {
+		return nil, esv1beta1.NoSecretErr
+	}
+	return nil, fmt.Errorf(errResponse, key, errResp.ErrorCode, errResp.ErrorMsg)
+}
+
+// GetAllSecrets is not supported: the GetPassword API returns a single password.
+func (c *Client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	return nil, fmt.Errorf(errFindNotSupported)
+}
+
+// Validate is not able to check the AppID without a password object to query.
+func (c *Client) Validate() (esv1beta1.ValidationResult, error) {
+	return esv1beta1.ValidationResultUnknown, nil
+}
+
+func (c *Client) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cyberarkccp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func newCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "external-secrets"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func newCCPServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != defaultPath || q.Get("AppID") != "eso" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"ErrorCode":"APPAP306E","ErrorMsg":"Authentication failed"}`))
+			return
+		}
+		switch {
+		case q.Get("Object") == "db" && q.Get("Safe") == "Prod":
+			_, _ = w.Write([]byte(`{"Content":"s3cr3t","UserName":"admin","Address":"db.example.com","PasswordChangeInProcess":false}`))
+		case q.Get("Query") == "Safe=Dev;Object=db":
+			_, _ = w.Write([]byte(`{"Content":"dev","UserName":"dev"}`))
+		case q.Get("Object") == "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"ErrorCode":"APPAP004E","ErrorMsg":"Password object matching query was not found"}`))
+		}
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, appID string) esv1beta1.SecretsClient {
+	srv := newCCPServer(t)
+	certPEM, keyPEM := newCertificate(t)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ccp-client", Namespace: "default"},
+		Data: map[string][]byte{
+			"tls.crt": certPEM,
+			"tls.key": keyPEM,
+		},
+	}).Build()
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				CyberArkCCP: &esv1beta1.CyberArkCCPProvider{
+					URL:      srv.URL,
+					AppID:    appID,
+					Safe:     "Prod",
+					CABundle: caPEM,
+					Auth: esv1beta1.CyberArkCCPAuth{
+						Cert: &esv1beta1.CertAuth{
+							ClientCert: esmeta.SecretKeySelector{Name: "ccp-client", Key: "tls.crt"},
+							ClientKey:  esmeta.SecretKeySelector{Name: "ccp-client", Key: "tls.key"},
+						},
+					},
+				},
+			},
+		},
+	}
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	assert.NoError(t, err)
+	return c
+}
+
+func TestGetSecret(t *testing.T) {
+	c := newTestClient(t, "eso")
+	ctx := context.Background()
+
+	got, err := c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(got))
+
+	got, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "UserName"})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", string(got))
+
+	got, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "Safe=Dev;Object=db"})
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", string(got))
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "missing"})
+	assert.True(t, errors.Is(err, esv1beta1.NoSecretErr))
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "broken"})
+	assert.ErrorContains(t, err, "unexpected status 500")
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "Folder"})
+	assert.ErrorContains(t, err, `property "Folder" does not exist`)
+}
+
+func TestGetSecretMap(t *testing.T) {
+	c := newTestClient(t, "eso")
+	got, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"Content":                 []byte("s3cr3t"),
+		"UserName":                []byte("admin"),
+		"Address":                 []byte("db.example.com"),
+		"PasswordChangeInProcess": []byte("false"),
+	}, got)
+}
+
+func TestErrorResponse(t *testing.T) {
+	c := newTestClient(t, "other")
+	_, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	assert.EqualError(t, err, `unable to get password "db": APPAP306E: Authentication failed`)
+}
+
+func TestValidateStore(t *testing.T) {
+	p := &Provider{}
+	cert := &esv1beta1.CertAuth{
+		ClientCert: esmeta.SecretKeySelector{Name: "ccp-client", Key: "tls.crt"},
+		ClientKey:  esmeta.SecretKeySelector{Name: "ccp-client", Key: "tls.key"},
+	}
+	newStore := func(provider *esv1beta1.CyberArkCCPProvider) *esv1beta1.SecretStore {
+		return &esv1beta1.SecretStore{
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider: &esv1beta1.SecretStoreProvider{CyberArkCCP: provider},
+			},
+		}
+	}
+	assert.NoError(t, p.ValidateStore(newStore(&esv1beta1.CyberArkCCPProvider{
+		URL:   "https://ccp.example.com",
+		AppID: "eso",
+		Auth:  esv1beta1.CyberArkCCPAuth{Cert: cert},
+	})))
+	assert.EqualError(t, p.ValidateStore(newStore(&esv1beta1.CyberArkCCPProvider{
+		AppID: "eso",
+		Auth:  esv1beta1.CyberArkCCPAuth{Cert: cert},
+	})), errMissingURL)
+	assert.EqualError(t, p.ValidateStore(newStore(&esv1beta1.CyberArkCCPProvider{
+		URL:  "https://ccp.example.com",
+		Auth: esv1beta1.CyberArkCCPAuth{Cert: cert},
+	})), errMissingAppID)
+	assert.EqualError(t, p.ValidateStore(newStore(&esv1beta1.CyberArkCCPProvider{
+		URL:   "https://ccp.example.com",
+		AppID: "eso",
+	})), errMissingClientCert)
+}
@@ -163,7 +163,7 @@ func (c *Client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecret
 		return nil, err
 	}
 
-	if ref.Name == nil && ref.Path == nil {
+	if ref.Name == nil && ref.Path == nil && ref.MaxResults == nil {
 		return secrets, nil
 	}
 
@@ -180,6 +180,9 @@ func (c *Client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecret
 		if (matcher != nil && !matcher.MatchName(key)) || (ref.Path != nil && !strings.HasPrefix(key, *ref.Path)) {
 			continue
 		}
+		if err := find.CheckLimit(ref, len(selected)); err != nil {
+			return nil, err
+		}
 		selected[key] = value
 	}
 
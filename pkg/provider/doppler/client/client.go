@@ -296,6 +296,7 @@ func (c *DopplerClient) performRequest(path, method string, headers headers, par
 	}
 
 	httpClient.Transport = &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
 		DisableKeepAlives: true,
 		TLSClientConfig:   tlsConfig,
 	}
@@ -112,3 +112,11 @@ func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 
 	return nil
 }
+
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
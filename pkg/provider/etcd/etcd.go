@@ -0,0 +1,254 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	dialTimeout = 5 * time.Second
+
+	errEtcdStore          = "missing or invalid etcd SecretStore"
+	errMissingEndpoints   = "at least one endpoint is required"
+	errMissingClientCert  = "auth.cert is required"
+	errNewClient          = "unable to create etcd client: %w"
+	errInvalidVersion     = "version %q must be a mod revision: %w"
+	errRevisionMismatch   = "key %q was not modified at revision %d"
+	errGetKey             = "unable to get key %q: %w"
+	errProperty           = "property %q does not exist in key %q"
+	errUnmarshalSecretMap = "unable to unmarshal secret %q: %w"
+	errTagsNotSupported   = "find by tags is not supported by the etcd provider"
+	errValidate           = "unable to read from etcd: %w"
+)
+
+// https://github.com/external-secrets/external-secrets/issues/644
+var _ esv1beta1.SecretsClient = &Client{}
+var _ esv1beta1.Provider = &Provider{}
+
+// KV is the subset of the etcd KV API used by the provider.
+type KV interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+}
+
+// Provider implements the esv1beta1.Provider interface for etcd.
+type Provider struct{}
+
+// Client reads secrets from etcd.
+type Client struct {
+	kv     KV
+	close  func() error
+	prefix string
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Etcd: &esv1beta1.EtcdProvider{},
+	})
+}
+
+// NewClient constructs an etcd client authenticated with a TLS client certificate.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.Etcd == nil {
+		return nil, fmt.Errorf(errEtcdStore)
+	}
+	etcdSpec := storeSpec.Provider.Etcd
+	if etcdSpec.Auth.Cert == nil {
+		return nil, fmt.Errorf(errMissingClientCert)
+	}
+	tlsConfig, err := utils.NewCertResolver(kube, store, namespace).ClientTLSConfig(ctx, etcdSpec.Auth.Cert, etcdSpec.CABundle, etcdSpec.CAProvider)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   etcdSpec.Endpoints,
+		DialTimeout: dialTimeout,
+		TLS:         tlsConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errNewClient, err)
+	}
+	return &Client{
+		kv:     cli,
+		close:  cli.Close,
+		prefix: etcdSpec.Prefix,
+	}, nil
+}
+
+// ValidateStore checks the etcd store configuration.
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.Etcd == nil {
+		return fmt.Errorf(errEtcdStore)
+	}
+	etcdSpec := storeSpec.Provider.Etcd
+	if len(etcdSpec.Endpoints) == 0 {
+		return fmt.Errorf(errMissingEndpoints)
+	}
+	if etcdSpec.Auth.Cert == nil {
+		return fmt.Errorf(errMissingClientCert)
+	}
+	if err := utils.ValidateSecretSelector(store, etcdSpec.Auth.Cert.ClientCert); err != nil {
+		return err
+	}
+	if err := utils.ValidateSecretSelector(store, etcdSpec.Auth.Cert.ClientKey); err != nil {
+		return err
+	}
+	if store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind &&
+		etcdSpec.CAProvider != nil &&
+		etcdSpec.CAProvider.Namespace == nil {
+		return fmt.Errorf("CAProvider.namespace must not be empty with ClusterSecretStore")
+	}
+	return nil
+}
+
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
+
+// GetSecret returns the value of a key.
+// If ref.Version is set, it is the mod revision the key is pinned to:
+// the value is read at that revision and the key must have been modified at it.
+func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	key := c.prefix + ref.Key
+	opts := []clientv3.OpOption{}
+	var rev int64
+	if ref.Version != "" {
+		var err error
+		rev, err = strconv.ParseInt(ref.Version, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf(errInvalidVersion, ref.Version, err)
+		}
+		opts = append(opts, clientv3.WithRev(rev))
+	}
+	resp, err := c.kv.Get(ctx, key, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(errGetKey, key, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, esv1beta1.NoSecretErr
+	}
+	kv := resp.Kvs[0]
+	if rev != 0 && kv.ModRevision != rev {
+		return nil, fmt.Errorf(errRevisionMismatch, key, rev)
+	}
+	if ref.Property == "" {
+		return kv.Value, nil
+	}
+	val := gjson.GetBytes(kv.Value, ref.Property)
+	if !val.Exists() {
+		return nil, fmt.Errorf(errProperty, ref.Property, ref.Key)
+	}
+	if val.Type == gjson.String {
+		return []byte(val.String()), nil
+	}
+	return []byte(val.Raw), nil
+}
+
+// GetSecretMap returns the JSON object stored in a key as a map.
+func (c *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	data, err := c.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &kv); err != nil {
+		return nil, fmt.Errorf(errUnmarshalSecretMap, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+			continue
+		}
+		secretData[k] = v
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets reads all keys below the store prefix and ref.Path
+// with a single range request and filters them by name.
+// The returned keys are relative to the store prefix.
+func (c *Client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errTagsNotSupported)
+	}
+	prefix := c.prefix
+	if ref.Path != nil {
+		prefix += *ref.Path
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		var err error
+		matcher, err = find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resp, err := c.kv.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf(errGetKey, prefix, err)
+	}
+	data := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		name := strings.TrimPrefix(string(kv.Key), c.prefix)
+		if matcher != nil && !matcher.MatchName(name) {
+			continue
+		}
+		if err := find.CheckLimit(ref, len(data)); err != nil {
+			return nil, err
+		}
+		data[name] = kv.Value
+	}
+	return data, nil
+}
+
+// Validate checks that the client is able to read from etcd.
+func (c *Client) Validate() (esv1beta1.ValidationResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	_, err := c.kv.Get(ctx, c.prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return esv1beta1.ValidationResultError, fmt.Errorf(errValidate, err)
+	}
+	return esv1beta1.ValidationResultReady, nil
+}
+
+// Close closes the connection to etcd.
+func (c *Client) Close(ctx context.Context) error {
+	if c.close == nil {
+		return nil
+	}
+	return c.close()
+}
@@ -0,0 +1,209 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// fakeKV keeps every revision of its keys.
+type fakeKV struct {
+	revisions []*mvccpb.KeyValue
+}
+
+func (f *fakeKV) put(key, value string) {
+	f.revisions = append(f.revisions, &mvccpb.KeyValue{
+		Key:         []byte(key),
+		Value:       []byte(value),
+		ModRevision: int64(len(f.revisions) + 1),
+	})
+}
+
+func (f *fakeKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	op := clientv3.OpGet(key, opts...)
+	rev := op.Rev()
+	if rev == 0 {
+		rev = int64(len(f.revisions))
+	}
+	latest := make(map[string]*mvccpb.KeyValue)
+	var keys []string
+	for _, kv := range f.revisions {
+		if kv.ModRevision > rev {
+			break
+		}
+		k := string(kv.Key)
+		if k != key && (op.RangeBytes() == nil || !strings.HasPrefix(k, key)) {
+			continue
+		}
+		if _, ok := latest[k]; !ok {
+			keys = append(keys, k)
+		}
+		latest[k] = kv
+	}
+	resp := &clientv3.GetResponse{}
+	for _, k := range keys {
+		resp.Kvs = append(resp.Kvs, latest[k])
+	}
+	return resp, nil
+}
+
+func newTestClient() *Client {
+	kv := &fakeKV{}
+	kv.put("/appliance/db", `{"user":"admin","password":"old"}`)
+	kv.put("/appliance/db", `{"user":"admin","password":"new","port":5432}`)
+	kv.put("/appliance/api/token", "t0ken")
+	kv.put("/appliance/api/url", "https://api")
+	kv.put("/other/key", "nope")
+	return &Client{kv: kv, prefix: "/appliance/"}
+}
+
+func TestGetSecret(t *testing.T) {
+	c := newTestClient()
+	tests := []struct {
+		name    string
+		ref     esv1beta1.ExternalSecretDataRemoteRef
+		want    string
+		wantErr string
+	}{
+		{
+			name: "latest value",
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "api/token"},
+			want: "t0ken",
+		},
+		{
+			name: "property",
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "password"},
+			want: "new",
+		},
+		{
+			name: "pinned mod revision",
+			ref:  esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "password", Version: "1"},
+			want: "old",
+		},
+		{
+			name:    "key not modified at revision",
+			ref:     esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Version: "3"},
+			wantErr: "was not modified at revision 3",
+		},
+		{
+			name:    "invalid version",
+			ref:     esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Version: "latest"},
+			wantErr: "must be a mod revision",
+		},
+		{
+			name:    "missing property",
+			ref:     esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "host"},
+			wantErr: `property "host" does not exist`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetSecret(context.Background(), tt.ref)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	_, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "missing"})
+	assert.True(t, errors.Is(err, esv1beta1.NoSecretErr))
+}
+
+func TestGetSecretMap(t *testing.T) {
+	c := newTestClient()
+	got, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"user":     []byte("admin"),
+		"password": []byte("new"),
+		"port":     []byte("5432"),
+	}, got)
+
+	_, err = c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "api/token"})
+	assert.ErrorContains(t, err, "unable to unmarshal secret")
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	c := newTestClient()
+	path := "api/"
+	// the keys are converted by the controller after the allowedKeyPrefixes of the store are checked
+	got, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Path:               &path,
+		ConversionStrategy: esv1beta1.ExternalSecretConversionDefault,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"api/token": []byte("t0ken"),
+		"api/url":   []byte("https://api"),
+	}, got)
+
+	got, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Name: &esv1beta1.FindName{RegExp: "token$"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"api/token": []byte("t0ken")}, got)
+
+	maxResults := 2
+	got, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: &path, MaxResults: &maxResults})
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	maxResults = 1
+	_, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: &path, MaxResults: &maxResults})
+	assert.ErrorIs(t, err, esv1beta1.TooManyResultsError{MaxResults: 1})
+
+	_, err = c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Tags: map[string]string{"foo": "bar"}})
+	assert.ErrorContains(t, err, errTagsNotSupported)
+}
+
+func TestValidateStore(t *testing.T) {
+	p := &Provider{}
+	cert := &esv1beta1.CertAuth{
+		ClientCert: esmeta.SecretKeySelector{Name: "etcd-client", Key: "tls.crt"},
+		ClientKey:  esmeta.SecretKeySelector{Name: "etcd-client", Key: "tls.key"},
+	}
+	newStore := func(provider *esv1beta1.EtcdProvider) *esv1beta1.SecretStore {
+		return &esv1beta1.SecretStore{
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider: &esv1beta1.SecretStoreProvider{Etcd: provider},
+			},
+		}
+	}
+
+	assert.NoError(t, p.ValidateStore(newStore(&esv1beta1.EtcdProvider{
+		Endpoints: []string{"https://etcd:2379"},
+		Auth:      esv1beta1.EtcdAuth{Cert: cert},
+	})))
+	assert.EqualError(t, p.ValidateStore(newStore(&esv1beta1.EtcdProvider{
+		Auth: esv1beta1.EtcdAuth{Cert: cert},
+	})), errMissingEndpoints)
+	assert.EqualError(t, p.ValidateStore(newStore(&esv1beta1.EtcdProvider{
+		Endpoints: []string{"https://etcd:2379"},
+	})), errMissingClientCert)
+	assert.EqualError(t, p.ValidateStore(newStore(nil)), errEtcdStore)
+}
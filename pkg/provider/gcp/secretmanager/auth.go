@@ -24,6 +24,7 @@ import (
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/credentialfile"
 )
 
 func NewTokenSource(ctx context.Context, auth esv1beta1.GCPSMAuth, projectID string, isClusterKind bool, kube kclient.Client, namespace string) (oauth2.TokenSource, error) {
@@ -31,11 +32,20 @@ func NewTokenSource(ctx context.Context, auth esv1beta1.GCPSMAuth, projectID str
 	if ts != nil || err != nil {
 		return ts, err
 	}
+	ts, err = credentialConfigTokenSource(ctx, auth, isClusterKind, kube, namespace)
+	if ts != nil || err != nil {
+		return ts, err
+	}
+	ts, err = credentialsFileTokenSource(ctx, auth)
+	if ts != nil || err != nil {
+		return ts, err
+	}
 	wi, err := newWorkloadIdentity(ctx, projectID)
 	if err != nil {
-		useMu.Unlock()
 		return nil, fmt.Errorf("unable to initialize workload identity")
 	}
+	// the returned token source does not use the IAM client of wi
+	defer func() { _ = wi.Close() }()
 	ts, err = wi.TokenSource(ctx, auth, isClusterKind, kube, namespace)
 	if ts != nil || err != nil {
 		return ts, err
@@ -77,3 +87,53 @@ func serviceAccountTokenSource(ctx context.Context, auth esv1beta1.GCPSMAuth, is
 	}
 	return config.TokenSource(ctx), nil
 }
+
+// credentialConfigTokenSource builds the token source from a credential configuration stored in a Secret.
+// Besides service account keys this supports external account configurations of workload identity federation.
+// Only ClusterSecretStores may use it: an external account configuration reads a file or URL
+// of the controller, e.g. its service account token, and sends it to the configured token URL.
+func credentialConfigTokenSource(ctx context.Context, auth esv1beta1.GCPSMAuth, isClusterKind bool, kube kclient.Client, namespace string) (oauth2.TokenSource, error) {
+	ref := auth.CredentialConfigSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+	if !isClusterKind {
+		return nil, fmt.Errorf(errCredConfigKind)
+	}
+	if ref.Namespace == nil {
+		return nil, fmt.Errorf(errInvalidClusterStoreMissingCredConfigNamespace)
+	}
+	objectKey := types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: *ref.Namespace,
+	}
+	credentialsSecret := &v1.Secret{}
+	if err := kube.Get(ctx, objectKey, credentialsSecret); err != nil {
+		return nil, fmt.Errorf(errFetchCredConfigSecret, err)
+	}
+	config := credentialsSecret.Data[ref.Key]
+	if len(config) == 0 {
+		return nil, fmt.Errorf(errMissingCredConfig)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, config, CloudPlatformRole)
+	if err != nil {
+		return nil, fmt.Errorf(errUnableProcessJSONCredentials, err)
+	}
+	return creds.TokenSource, nil
+}
+
+// credentialsFileTokenSource builds the token source from an Application Default Credentials file of the controller.
+func credentialsFileTokenSource(ctx context.Context, auth esv1beta1.GCPSMAuth) (oauth2.TokenSource, error) {
+	if auth.CredentialsFile == "" {
+		return nil, nil
+	}
+	config, err := credentialfile.Read(auth.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf(errReadCredentialsFile, err)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, config, CloudPlatformRole)
+	if err != nil {
+		return nil, fmt.Errorf(errUnableProcessJSONCredentials, err)
+	}
+	return creds.TokenSource, nil
+}
@@ -0,0 +1,98 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/credentialfile"
+)
+
+const externalAccountConfig = `{
+  "type": "external_account",
+  "audience": "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/provider",
+  "subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+  "token_url": "https://sts.googleapis.com/v1/token",
+  "credential_source": {"file": "/var/run/secrets/tokens/gcp"}
+}`
+
+func TestCredentialConfigTokenSource(t *testing.T) {
+	kube := clientfake.NewClientBuilder().WithObjects(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcp-config", Namespace: "default"},
+		Data: map[string][]byte{
+			"config.json": []byte(externalAccountConfig),
+			"invalid":     []byte("{}"),
+		},
+	}).Build()
+	auth := func(key string, namespace *string) esv1beta1.GCPSMAuth {
+		return esv1beta1.GCPSMAuth{
+			CredentialConfigSecretRef: &esmeta.SecretKeySelector{Name: "gcp-config", Key: key, Namespace: namespace},
+		}
+	}
+
+	ns := pointer.String("default")
+	ts, err := credentialConfigTokenSource(context.Background(), auth("config.json", ns), true, kube, "other")
+	assert.NoError(t, err)
+	assert.NotNil(t, ts)
+
+	_, err = credentialConfigTokenSource(context.Background(), auth("missing", ns), true, kube, "other")
+	assert.EqualError(t, err, errMissingCredConfig)
+
+	_, err = credentialConfigTokenSource(context.Background(), auth("invalid", ns), true, kube, "other")
+	assert.Error(t, err)
+
+	_, err = credentialConfigTokenSource(context.Background(), auth("config.json", nil), true, kube, "default")
+	assert.EqualError(t, err, errInvalidClusterStoreMissingCredConfigNamespace)
+
+	// the configuration of a namespaced store is controlled by its tenant
+	_, err = credentialConfigTokenSource(context.Background(), auth("config.json", nil), false, kube, "default")
+	assert.EqualError(t, err, errCredConfigKind)
+
+	ts, err = credentialConfigTokenSource(context.Background(), esv1beta1.GCPSMAuth{}, false, kube, "default")
+	assert.NoError(t, err)
+	assert.Nil(t, ts)
+}
+
+func TestCredentialsFileTokenSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "credentials.json")
+	assert.NoError(t, os.WriteFile(path, []byte(externalAccountConfig), 0600))
+	old := credentialfile.Dir
+	t.Cleanup(func() { credentialfile.Dir = old })
+	credentialfile.Dir = dir
+
+	ts, err := credentialsFileTokenSource(context.Background(), esv1beta1.GCPSMAuth{CredentialsFile: path})
+	assert.NoError(t, err)
+	assert.NotNil(t, ts)
+
+	_, err = credentialsFileTokenSource(context.Background(), esv1beta1.GCPSMAuth{CredentialsFile: path + ".missing"})
+	assert.Error(t, err)
+
+	// files outside of the credential file directory can not be read
+	credentialfile.Dir = t.TempDir()
+	_, err = credentialsFileTokenSource(context.Background(), esv1beta1.GCPSMAuth{CredentialsFile: path})
+	assert.ErrorContains(t, err, "not in the credential file directory")
+}
@@ -20,12 +20,17 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"github.com/googleapis/gax-go/v2"
 	"github.com/tidwall/gjson"
 	"google.golang.org/api/iterator"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	ctrl "sigs.k8s.io/controller-runtime"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,21 +40,26 @@ import (
 )
 
 const (
-	CloudPlatformRole                         = "https://www.googleapis.com/auth/cloud-platform"
-	defaultVersion                            = "latest"
-	errGCPSMStore                             = "received invalid GCPSM SecretStore resource"
-	errUnableGetCredentials                   = "unable to get credentials: %w"
-	errClientClose                            = "unable to close SecretManager client: %w"
-	errMissingStoreSpec                       = "invalid: missing store spec"
-	errInvalidClusterStoreMissingSAKNamespace = "invalid ClusterSecretStore: missing GCP SecretAccessKey Namespace"
-	errInvalidClusterStoreMissingSANamespace  = "invalid ClusterSecretStore: missing GCP Service Account Namespace"
-	errFetchSAKSecret                         = "could not fetch SecretAccessKey secret: %w"
-	errMissingSAK                             = "missing SecretAccessKey"
-	errUnableProcessJSONCredentials           = "failed to process the provided JSON credentials: %w"
-	errUnableCreateGCPSMClient                = "failed to create GCP secretmanager client: %w"
-	errUninitalizedGCPProvider                = "provider GCP is not initialized"
-	errClientGetSecretAccess                  = "unable to access Secret from SecretManager Client: %w"
-	errJSONSecretUnmarshal                    = "unable to unmarshal secret: %w"
+	CloudPlatformRole                                = "https://www.googleapis.com/auth/cloud-platform"
+	defaultVersion                                   = "latest"
+	errGCPSMStore                                    = "received invalid GCPSM SecretStore resource"
+	errUnableGetCredentials                          = "unable to get credentials: %w"
+	errClientClose                                   = "unable to close SecretManager client: %w"
+	errMissingStoreSpec                              = "invalid: missing store spec"
+	errInvalidClusterStoreMissingSAKNamespace        = "invalid ClusterSecretStore: missing GCP SecretAccessKey Namespace"
+	errInvalidClusterStoreMissingSANamespace         = "invalid ClusterSecretStore: missing GCP Service Account Namespace"
+	errFetchSAKSecret                                = "could not fetch SecretAccessKey secret: %w"
+	errMissingSAK                                    = "missing SecretAccessKey"
+	errInvalidClusterStoreMissingCredConfigNamespace = "invalid ClusterSecretStore: missing GCP credential config Namespace"
+	errFetchCredConfigSecret                         = "could not fetch credential config secret: %w"
+	errMissingCredConfig                             = "missing credential config"
+	errReadCredentialsFile                           = "could not read credentials file: %w"
+	errUnableProcessJSONCredentials                  = "failed to process the provided JSON credentials: %w"
+	errUnableCreateGCPSMClient                       = "failed to create GCP secretmanager client: %w"
+	errUninitalizedGCPProvider                       = "provider GCP is not initialized"
+	errClientGetSecretAccess                         = "unable to access Secret from SecretManager Client: %w"
+	errClientListVersions                            = "unable to list Secret versions from SecretManager Client: %w"
+	errJSONSecretUnmarshal                           = "unable to unmarshal secret: %w"
 
 	errInvalidStore           = "invalid store"
 	errInvalidStoreSpec       = "invalid store spec"
@@ -57,22 +67,56 @@ const (
 	errInvalidGCPProv         = "invalid gcp secrets manager provider"
 	errInvalidAuthSecretRef   = "invalid auth secret ref: %w"
 	errInvalidWISARef         = "invalid workload identity service account reference: %w"
+	errInvalidCredConfigRef   = "invalid credential config secret ref: %w"
+	errCredentialsFileKind    = "credentialsFile can only be used with a ClusterSecretStore"
+	errCredConfigKind         = "credentialConfigSecretRef can only be used with a ClusterSecretStore"
+	errCredentialsFilePath    = "invalid credentialsFile: %w"
+	errAllowedProject         = "invalid allowed project %q"
 	errUnexpectedFindOperator = "unexpected find operator"
+
+	// listSecretsFieldMask limits the ListSecrets response to the fields
+	// needed to match secrets, so the API doesn't return full secret objects.
+	listSecretsFieldMask = "secrets.name,secrets.labels,nextPageToken"
+	fieldMaskHeader      = "x-goog-fieldmask"
+
+	// permissionVersionsAccess is required to access the payload of a secret version.
+	permissionVersionsAccess = "secretmanager.versions.access"
+)
+
+var (
+	// permissionDeniedRetryWindow is the time after the creation of a store in which
+	// PERMISSION_DENIED errors are retried quickly: IAM bindings that were granted
+	// together with the store take several minutes to propagate.
+	permissionDeniedRetryWindow = 10 * time.Minute
+	// permissionDeniedRetryInterval is the interval PERMISSION_DENIED errors are
+	// retried in during permissionDeniedRetryWindow.
+	permissionDeniedRetryInterval = 10 * time.Second
+
+	// DiagnosePermissions enables testing the IAM permissions of a secret
+	// if accessing it fails with PERMISSION_DENIED.
+	DiagnosePermissions bool
 )
 
 type Client struct {
 	smClient GoogleSecretManagerClient
+	projects ProjectsClient
 	kube     kclient.Client
 	store    *esv1beta1.GCPSMProvider
 
 	// namespace of the external secret
 	namespace        string
 	workloadIdentity *workloadIdentity
+	// storeCreated is the creation time of the store
+	storeCreated time.Time
+	// pooled is the pooled client smClient and projects are taken from
+	pooled *pooledClient
 }
 
 type GoogleSecretManagerClient interface {
 	AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
 	ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator
+	ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, opts ...gax.CallOption) *secretmanager.SecretVersionIterator
+	TestIamPermissions(ctx context.Context, req *iampb.TestIamPermissionsRequest, opts ...gax.CallOption) (*iampb.TestIamPermissionsResponse, error)
 	Close() error
 }
 
@@ -95,14 +139,12 @@ func (c *Client) findByName(ctx context.Context, ref esv1beta1.ExternalSecretFin
 	if err != nil {
 		return nil, err
 	}
-	req := &secretmanagerpb.ListSecretsRequest{
-		Parent: fmt.Sprintf("projects/%s", c.store.ProjectID),
-	}
+	var filter string
 	if ref.Path != nil {
-		req.Filter = fmt.Sprintf("name:%s", *ref.Path)
+		filter = fmt.Sprintf("name:%s", *ref.Path)
 	}
 	// Call the API.
-	it := c.smClient.ListSecrets(ctx, req)
+	it := c.listSecrets(ctx, filter)
 	secretMap := make(map[string][]byte)
 	for {
 		resp, err := it.Next()
@@ -110,7 +152,7 @@ func (c *Client) findByName(ctx context.Context, ref esv1beta1.ExternalSecretFin
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list secrets: %w", err)
+			return nil, c.retryPermissionDenied(fmt.Errorf("failed to list secrets: %w", err), err)
 		}
 		log.V(1).Info("gcp sm findByName found", "secrets", strconv.Itoa(it.PageInfo().Remaining()))
 		key := c.trimName(resp.Name)
@@ -124,13 +166,16 @@ func (c *Client) findByName(ctx context.Context, ref esv1beta1.ExternalSecretFin
 			continue
 		}
 		log.V(1).Info("gcp sm findByName matches", "name", resp.Name)
+		if err := find.CheckLimit(ref, len(secretMap)); err != nil {
+			return nil, err
+		}
 		secretMap[key], err = c.getData(ctx, key)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return utils.ConvertKeys(ref.ConversionStrategy, secretMap)
+	return secretMap, nil
 }
 
 func (c *Client) getData(ctx context.Context, key string) ([]byte, error) {
@@ -153,13 +198,9 @@ func (c *Client) findByTags(ctx context.Context, ref esv1beta1.ExternalSecretFin
 	if ref.Path != nil {
 		tagFilter = fmt.Sprintf("%s name:%s", tagFilter, *ref.Path)
 	}
-	req := &secretmanagerpb.ListSecretsRequest{
-		Parent: fmt.Sprintf("projects/%s", c.store.ProjectID),
-	}
 	log.V(1).Info("gcp sm findByTags", "tagFilter", tagFilter)
-	req.Filter = tagFilter
 	// Call the API.
-	it := c.smClient.ListSecrets(ctx, req)
+	it := c.listSecrets(ctx, tagFilter)
 	secretMap := make(map[string][]byte)
 	for {
 		resp, err := it.Next()
@@ -167,41 +208,96 @@ func (c *Client) findByTags(ctx context.Context, ref esv1beta1.ExternalSecretFin
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list secrets: %w", err)
+			return nil, c.retryPermissionDenied(fmt.Errorf("failed to list secrets: %w", err), err)
 		}
 		key := c.trimName(resp.Name)
 		if ref.Path != nil && !strings.HasPrefix(key, *ref.Path) {
 			continue
 		}
 		log.V(1).Info("gcp sm findByTags matches tags", "name", resp.Name)
+		if err := find.CheckLimit(ref, len(secretMap)); err != nil {
+			return nil, err
+		}
 		secretMap[key], err = c.getData(ctx, key)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return utils.ConvertKeys(ref.ConversionStrategy, secretMap)
+	return secretMap, nil
 }
 
-func (c *Client) trimName(name string) string {
-	projectIDNumuber := c.extractProjectIDNumber(name)
-	key := strings.TrimPrefix(name, fmt.Sprintf("projects/%s/secrets/", projectIDNumuber))
-	return key
+// retryPermissionDenied returns a RetryAfterError wrapping err if apiErr is a
+// PERMISSION_DENIED error of a store created less than permissionDeniedRetryWindow ago.
+func (c *Client) retryPermissionDenied(err, apiErr error) error {
+	if status.Code(apiErr) != codes.PermissionDenied || c.storeCreated.IsZero() || time.Since(c.storeCreated) > permissionDeniedRetryWindow {
+		return err
+	}
+	return esv1beta1.RetryAfterError{Err: err, RetryAfter: permissionDeniedRetryInterval}
 }
 
-// extractProjectIDNumber grabs the project id from the full name returned by gcp api
-// gcp api seems to always return the number and not the project name
-// (and users would always use the name, while requests accept both).
-func (c *Client) extractProjectIDNumber(secretFullName string) string {
-	s := strings.Split(secretFullName, "/")
-	projectIDNumuber := s[1]
-	return projectIDNumuber
+// diagnosePermissionDenied tests whether permission is granted on resource if apiErr
+// is a PERMISSION_DENIED error and DiagnosePermissions is enabled.
+// It returns a PermissionDeniedError wrapping apiErr that names the permission,
+// or err if the permission can not be tested.
+func (c *Client) diagnosePermissionDenied(ctx context.Context, resource, permission string, err, apiErr error) error {
+	if !DiagnosePermissions || status.Code(apiErr) != codes.PermissionDenied {
+		return err
+	}
+	resp, testErr := c.smClient.TestIamPermissions(ctx, &iampb.TestIamPermissionsRequest{
+		Resource:    resource,
+		Permissions: []string{permission},
+	})
+	if testErr != nil {
+		log.V(1).Info("unable to test permissions", "resource", resource, "error", testErr.Error())
+		return err
+	}
+	granted := false
+	for _, p := range resp.Permissions {
+		if p == permission {
+			granted = true
+		}
+	}
+	return esv1beta1.PermissionDeniedError{
+		Permission: permission,
+		Resource:   resource,
+		Granted:    granted,
+		Err:        apiErr,
+	}
+}
+
+// listSecrets lists the secrets of the store project matching the given filter.
+// Only the name and labels of each secret are requested, using the configured page size.
+func (c *Client) listSecrets(ctx context.Context, filter string) *secretmanager.SecretIterator {
+	req := &secretmanagerpb.ListSecretsRequest{
+		Parent:   fmt.Sprintf("projects/%s", c.store.ProjectID),
+		Filter:   filter,
+		PageSize: c.store.ListPageSize,
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, fieldMaskHeader, listSecretsFieldMask)
+	return c.smClient.ListSecrets(ctx, req)
+}
+
+// trimName returns the secret name of the full name returned by the gcp api,
+// which contains the project number instead of the project ID.
+func (c *Client) trimName(name string) string {
+	if _, secret, ok := splitSecretName(name); ok {
+		return secret
+	}
+	return name
 }
 
 // GetSecret returns a single secret from the provider.
 func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	data, _, err := c.GetSecretWithVersion(ctx, ref)
+	return data, err
+}
+
+// GetSecretWithVersion returns a single secret from the provider
+// along with the number of the version it was read from.
+func (c *Client) GetSecretWithVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, string, error) {
 	if utils.IsNil(c.smClient) || c.store.ProjectID == "" {
-		return nil, fmt.Errorf(errUninitalizedGCPProvider)
+		return nil, "", fmt.Errorf(errUninitalizedGCPProvider)
 	}
 
 	version := ref.Version
@@ -209,19 +305,26 @@ func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretData
 		version = defaultVersion
 	}
 
+	name, err := c.secretResourceName(ctx, ref.Key)
+	if err != nil {
+		return nil, "", err
+	}
 	req := &secretmanagerpb.AccessSecretVersionRequest{
-		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/%s", c.store.ProjectID, ref.Key, version),
+		Name: fmt.Sprintf("%s/versions/%s", name, version),
 	}
 	result, err := c.smClient.AccessSecretVersion(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf(errClientGetSecretAccess, err)
+		accessErr := c.diagnosePermissionDenied(ctx, name, permissionVersionsAccess, fmt.Errorf(errClientGetSecretAccess, err), err)
+		return nil, "", c.retryPermissionDenied(accessErr, err)
 	}
+	// the name of the accessed version ends with its number, also when an alias like latest was requested
+	resultVersion := result.Name[strings.LastIndex(result.Name, "/")+1:]
 
 	if ref.Property == "" {
 		if result.Payload.Data != nil {
-			return result.Payload.Data, nil
+			return result.Payload.Data, resultVersion, nil
 		}
-		return nil, fmt.Errorf("invalid secret received. no secret string for key: %s", ref.Key)
+		return nil, "", fmt.Errorf("invalid secret received. no secret string for key: %s", ref.Key)
 	}
 
 	var payload string
@@ -234,14 +337,48 @@ func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretData
 		refProperty = strings.ReplaceAll(refProperty, ".", "\\.")
 		val := gjson.Get(payload, refProperty)
 		if val.Exists() {
-			return []byte(val.String()), nil
+			return []byte(val.String()), resultVersion, nil
 		}
 	}
 	val := gjson.Get(payload, ref.Property)
 	if !val.Exists() {
-		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
+		return nil, "", fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
+	}
+	return []byte(val.String()), resultVersion, nil
+}
+
+// ListSecretVersions returns the enabled versions of a secret.
+// The API lists versions newest first.
+func (c *Client) ListSecretVersions(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]esv1beta1.SecretVersion, error) {
+	if utils.IsNil(c.smClient) || c.store.ProjectID == "" {
+		return nil, fmt.Errorf(errUninitalizedGCPProvider)
+	}
+	name, err := c.secretResourceName(ctx, ref.Key)
+	if err != nil {
+		return nil, err
 	}
-	return []byte(val.String()), nil
+	it := c.smClient.ListSecretVersions(ctx, &secretmanagerpb.ListSecretVersionsRequest{
+		Parent:   name,
+		PageSize: c.store.ListPageSize,
+	})
+	var versions []esv1beta1.SecretVersion
+	for {
+		resp, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, c.retryPermissionDenied(fmt.Errorf(errClientListVersions, err), err)
+		}
+		if resp.State != secretmanagerpb.SecretVersion_ENABLED {
+			continue
+		}
+		versions = append(versions, esv1beta1.SecretVersion{
+			Version:     resp.Name[strings.LastIndex(resp.Name, "/")+1:],
+			CreatedTime: resp.CreateTime.AsTime(),
+		})
+	}
+	return versions, nil
 }
 
 // GetSecretMap returns multiple k/v pairs from the provider.
@@ -275,21 +412,30 @@ func (c *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretD
 	return secretData, nil
 }
 
+// Close returns a pooled Secret Manager client to the pool of its store.
 func (c *Client) Close(ctx context.Context) error {
 	var err error
-	if c.smClient != nil {
+	if c.pooled != nil {
+		clients.release(c.pooled)
+		c.pooled = nil
+	} else if c.smClient != nil {
 		err = c.smClient.Close()
 	}
 	if c.workloadIdentity != nil {
 		err = c.workloadIdentity.Close()
 	}
-	useMu.Unlock()
 	if err != nil {
 		return fmt.Errorf(errClientClose, err)
 	}
 	return nil
 }
 
+func (c *Client) use(pooled *pooledClient) {
+	c.smClient = pooled.smClient
+	c.projects = pooled.projects
+	c.pooled = pooled
+}
+
 func (c *Client) Validate() (esv1beta1.ValidationResult, error) {
 	return esv1beta1.ValidationResultReady, nil
 }
@@ -15,12 +15,21 @@ package secretmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/gax-go/v2"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
@@ -37,6 +46,8 @@ type secretManagerTestCase struct {
 	apiErr         error
 	expectError    string
 	expectedSecret string
+	// for testing GetSecretWithVersion
+	expectedVersion string
 	// for testing secretmap
 	expectedData map[string][]byte
 }
@@ -157,10 +168,21 @@ func TestSecretManagerGetSecret(t *testing.T) {
 		smtc.expectedSecret = "FOOBA!"
 	}
 
+	// good case: the accessed version number is reported
+	setLatestVersion := func(smtc *secretManagerTestCase) {
+		smtc.ref.Version = "latest"
+		smtc.apiInput.Name = "projects/default/secrets//baz/versions/latest"
+		smtc.apiOutput.Name = "projects/123456/secrets/baz/versions/7"
+		smtc.apiOutput.Payload.Data = []byte("FOOBA!")
+		smtc.expectedSecret = "FOOBA!"
+		smtc.expectedVersion = "7"
+	}
+
 	successCases := []*secretManagerTestCase{
 		makeValidSecretManagerTestCase(),
 		makeValidSecretManagerTestCaseCustom(setSecretString),
 		makeValidSecretManagerTestCaseCustom(setCustomVersion),
+		makeValidSecretManagerTestCaseCustom(setLatestVersion),
 		makeValidSecretManagerTestCaseCustom(setAPIErr),
 		makeValidSecretManagerTestCaseCustom(setCustomRef),
 		makeValidSecretManagerTestCaseCustom(setDotRef),
@@ -171,13 +193,16 @@ func TestSecretManagerGetSecret(t *testing.T) {
 	for k, v := range successCases {
 		sm.store = &esv1beta1.GCPSMProvider{ProjectID: v.projectID}
 		sm.smClient = v.mockClient
-		out, err := sm.GetSecret(context.Background(), *v.ref)
+		out, version, err := sm.GetSecretWithVersion(context.Background(), *v.ref)
 		if !ErrorContains(err, v.expectError) {
 			t.Errorf("[%d] unexpected error: %s, expected: '%s'", k, err.Error(), v.expectError)
 		}
 		if err == nil && string(out) != v.expectedSecret {
 			t.Errorf("[%d] unexpected secret: expected %s, got %s", k, v.expectedSecret, string(out))
 		}
+		if err == nil && version != v.expectedVersion {
+			t.Errorf("[%d] unexpected version: expected %s, got %s", k, v.expectedVersion, version)
+		}
 	}
 }
 
@@ -223,6 +248,38 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestListSecretsRequest(t *testing.T) {
+	var gotReq *secretmanagerpb.ListSecretsRequest
+	var gotMD metadata.MD
+	mc := &fakesm.MockSMClient{
+		ListSecretsFn: func(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator {
+			gotReq = req
+			gotMD, _ = metadata.FromOutgoingContext(ctx)
+			return &secretmanager.SecretIterator{}
+		},
+	}
+	sm := Client{
+		smClient: mc,
+		store: &esv1beta1.GCPSMProvider{
+			ProjectID:    "default",
+			ListPageSize: 500,
+		},
+	}
+	sm.listSecrets(context.Background(), "labels.foo=bar")
+
+	wantReq := &secretmanagerpb.ListSecretsRequest{
+		Parent:   "projects/default",
+		Filter:   "labels.foo=bar",
+		PageSize: 500,
+	}
+	if !cmp.Equal(gotReq, wantReq, cmpopts.IgnoreUnexported(secretmanagerpb.ListSecretsRequest{})) {
+		t.Errorf("unexpected list request: %s", cmp.Diff(wantReq, gotReq, cmpopts.IgnoreUnexported(secretmanagerpb.ListSecretsRequest{})))
+	}
+	if got := gotMD.Get(fieldMaskHeader); len(got) != 1 || got[0] != listSecretsFieldMask {
+		t.Errorf("unexpected field mask: %v", got)
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -235,7 +292,8 @@ func ErrorContains(out error, want string) bool {
 
 func TestValidateStore(t *testing.T) {
 	type args struct {
-		auth esv1beta1.GCPSMAuth
+		auth            esv1beta1.GCPSMAuth
+		allowedProjects []string
 	}
 
 	tests := []struct {
@@ -275,6 +333,52 @@ func TestValidateStore(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "invalid credential config ref",
+			wantErr: true,
+			args: args{
+				auth: esv1beta1.GCPSMAuth{
+					CredentialConfigSecretRef: &v1.SecretKeySelector{
+						Name:      "foo",
+						Namespace: pointer.StringPtr("invalid"),
+					},
+				},
+			},
+		},
+		{
+			name:    "credential config in secret store",
+			wantErr: true,
+			args: args{
+				auth: esv1beta1.GCPSMAuth{
+					CredentialConfigSecretRef: &v1.SecretKeySelector{
+						Name: "foo",
+					},
+				},
+			},
+		},
+		{
+			name:    "credentials file in secret store",
+			wantErr: true,
+			args: args{
+				auth: esv1beta1.GCPSMAuth{
+					CredentialsFile: "/var/run/secrets/gcp/credentials.json",
+				},
+			},
+		},
+		{
+			name:    "allowed projects",
+			wantErr: false,
+			args: args{
+				allowedProjects: []string{"org-secrets", "123456789012"},
+			},
+		},
+		{
+			name:    "invalid allowed project",
+			wantErr: true,
+			args: args{
+				allowedProjects: []string{"projects/org-secrets"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -283,7 +387,8 @@ func TestValidateStore(t *testing.T) {
 				Spec: esv1beta1.SecretStoreSpec{
 					Provider: &esv1beta1.SecretStoreProvider{
 						GCPSM: &esv1beta1.GCPSMProvider{
-							Auth: tt.args.auth,
+							Auth:            tt.args.auth,
+							AllowedProjects: tt.args.allowedProjects,
 						},
 					},
 				},
@@ -294,3 +399,295 @@ func TestValidateStore(t *testing.T) {
 		})
 	}
 }
+
+func newFakeServerClient(t *testing.T, srv *fakesm.Server, pageSize int32) *Client {
+	t.Helper()
+	smClient, err := srv.NewClient(context.Background())
+	if err != nil {
+		t.Fatalf("unable to create fake client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = smClient.Close()
+		srv.Stop()
+	})
+	return &Client{
+		smClient: smClient,
+		store: &esv1beta1.GCPSMProvider{
+			ProjectID:    "my-project",
+			ListPageSize: pageSize,
+		},
+	}
+}
+
+func TestGetAllSecretsFakeServer(t *testing.T) {
+	srv := fakesm.NewServer()
+	srv.SetProjectNumber("my-project", "123456")
+	srv.AddSecret("my-project", "db-user", map[string]string{"app": "db"})
+	srv.AddVersion("my-project", "db-user", []byte("admin"))
+	srv.AddSecret("my-project", "db-password", map[string]string{"app": "db"})
+	srv.AddVersion("my-project", "db-password", []byte("old"))
+	srv.AddVersion("my-project", "db-password", []byte("new"))
+	srv.AddSecret("my-project", "api-token", map[string]string{"app": "api"})
+	srv.AddVersion("my-project", "api-token", []byte("token"))
+	srv.AddVersion("other-project", "db-other", []byte("other"))
+
+	// a page size of one makes every secret a page of its own
+	sm := newFakeServerClient(t, srv, 1)
+
+	got, err := sm.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Name: &esv1beta1.FindName{RegExp: "^db-"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"db-user":     []byte("admin"),
+		"db-password": []byte("new"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected find by name result: %s", cmp.Diff(want, got))
+	}
+
+	got, err = sm.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		Tags: map[string]string{"app": "api"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = map[string][]byte{
+		"api-token": []byte("token"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected find by tags result: %s", cmp.Diff(want, got))
+	}
+}
+
+func TestGetSecretFakeServer(t *testing.T) {
+	srv := fakesm.NewServer()
+	srv.AddVersion("my-project", "foo", []byte("v1"))
+	srv.AddVersion("my-project", "foo", []byte("v2"))
+	srv.AddVersion("my-project", "denied", []byte("nope"))
+	srv.WithError("my-project", "denied", status.Error(codes.PermissionDenied, "Permission 'secretmanager.versions.access' denied"))
+	if err := srv.SetVersionState("my-project", "foo", 2, secretmanagerpb.SecretVersion_DISABLED); err != nil {
+		t.Fatalf("unable to disable version: %v", err)
+	}
+	sm := newFakeServerClient(t, srv, 0)
+
+	tests := []struct {
+		name        string
+		ref         esv1beta1.ExternalSecretDataRemoteRef
+		wantData    string
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name:        "latest skips disabled versions",
+			ref:         esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"},
+			wantData:    "v1",
+			wantVersion: "1",
+		},
+		{
+			name:    "disabled version",
+			ref:     esv1beta1.ExternalSecretDataRemoteRef{Key: "foo", Version: "2"},
+			wantErr: "FailedPrecondition",
+		},
+		{
+			name:    "missing version",
+			ref:     esv1beta1.ExternalSecretDataRemoteRef{Key: "foo", Version: "3"},
+			wantErr: "NotFound",
+		},
+		{
+			name:    "permission denied",
+			ref:     esv1beta1.ExternalSecretDataRemoteRef{Key: "denied"},
+			wantErr: "PermissionDenied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, version, err := sm.GetSecretWithVersion(context.Background(), tt.ref)
+			if !ErrorContains(err, tt.wantErr) {
+				t.Fatalf("unexpected error: %v, expected: '%s'", err, tt.wantErr)
+			}
+			if string(data) != tt.wantData || version != tt.wantVersion {
+				t.Errorf("unexpected secret: got %q@%q, expected %q@%q", data, version, tt.wantData, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestListSecretVersionsFakeServer(t *testing.T) {
+	srv := fakesm.NewServer()
+	srv.AddVersion("my-project", "foo", []byte("v1"))
+	srv.AddVersion("my-project", "foo", []byte("v2"))
+	srv.AddVersion("my-project", "foo", []byte("v3"))
+	if err := srv.SetVersionState("my-project", "foo", 2, secretmanagerpb.SecretVersion_DISABLED); err != nil {
+		t.Fatalf("unable to disable version: %v", err)
+	}
+	sm := newFakeServerClient(t, srv, 0)
+
+	versions, err := sm.ListSecretVersions(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.Version)
+	}
+	if want := []string{"3", "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected versions: got %v, expected %v", got, want)
+	}
+
+	if _, err := sm.ListSecretVersions(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "missing"}); !ErrorContains(err, "NotFound") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRetryPermissionDenied(t *testing.T) {
+	srv := fakesm.NewServer()
+	srv.AddVersion("my-project", "denied", []byte("nope"))
+	srv.WithError("my-project", "denied", status.Error(codes.PermissionDenied, "Permission 'secretmanager.versions.access' denied"))
+	srv.AddVersion("my-project", "missing", []byte("nope"))
+	srv.WithError("my-project", "missing", status.Error(codes.NotFound, "not found"))
+	sm := newFakeServerClient(t, srv, 0)
+
+	tests := []struct {
+		name      string
+		created   time.Time
+		key       string
+		wantRetry bool
+	}{
+		{name: "new store", created: time.Now().Add(-time.Minute), key: "denied", wantRetry: true},
+		{name: "old store", created: time.Now().Add(-time.Hour), key: "denied"},
+		{name: "other error", created: time.Now().Add(-time.Minute), key: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm.storeCreated = tt.created
+			_, err := sm.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: tt.key})
+			var retryErr esv1beta1.RetryAfterError
+			if errors.As(err, &retryErr) != tt.wantRetry {
+				t.Fatalf("unexpected error: %v, expected retry: %v", err, tt.wantRetry)
+			}
+			if tt.wantRetry && retryErr.RetryAfter != permissionDeniedRetryInterval {
+				t.Errorf("unexpected retry interval: %v", retryErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestDiagnosePermissionDenied(t *testing.T) {
+	denied := status.Error(codes.PermissionDenied, "Permission 'secretmanager.versions.access' denied")
+	srv := fakesm.NewServer()
+	srv.AddVersion("my-project", "missing", []byte("nope"))
+	srv.WithError("my-project", "missing", denied)
+	srv.WithPermissions("my-project", "missing", "secretmanager.versions.list")
+	srv.AddVersion("my-project", "conditional", []byte("nope"))
+	srv.WithError("my-project", "conditional", denied)
+	sm := newFakeServerClient(t, srv, 0)
+
+	tests := []struct {
+		name     string
+		diagnose bool
+		key      string
+		wantErr  string
+	}{
+		{name: "disabled", key: "missing", wantErr: "PermissionDenied"},
+		{name: "missing permission", diagnose: true, key: "missing", wantErr: "permission secretmanager.versions.access is missing on projects/my-project/secrets/missing"},
+		{name: "granted permission", diagnose: true, key: "conditional", wantErr: "check the conditions of the grant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			DiagnosePermissions = tt.diagnose
+			defer func() { DiagnosePermissions = false }()
+			_, err := sm.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: tt.key})
+			if !ErrorContains(err, tt.wantErr) {
+				t.Fatalf("unexpected error: %v, expected: '%s'", err, tt.wantErr)
+			}
+			var permErr esv1beta1.PermissionDeniedError
+			if errors.As(err, &permErr) != tt.diagnose {
+				t.Errorf("unexpected error type: %T", err)
+			}
+		})
+	}
+}
+
+func TestSecretResourceName(t *testing.T) {
+	projects := &fakesm.MockProjectsClient{IDs: map[string]string{
+		"24690001": "my-project",
+		"24700001": "shared-project",
+	}}
+	sm := Client{
+		store: &esv1beta1.GCPSMProvider{
+			ProjectID:       "my-project",
+			AllowedProjects: []string{"org-secrets", "24700001"},
+		},
+		projects: projects,
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "secret name",
+			key:  "foo",
+			want: "projects/my-project/secrets/foo",
+		},
+		{
+			name: "qualified by project id",
+			key:  "projects/my-project/secrets/foo",
+			want: "projects/my-project/secrets/foo",
+		},
+		{
+			name: "qualified by project number",
+			key:  "projects/24690001/secrets/foo",
+			want: "projects/24690001/secrets/foo",
+		},
+		{
+			name: "allowed project",
+			key:  "projects/org-secrets/secrets/foo",
+			want: "projects/org-secrets/secrets/foo",
+		},
+		{
+			name: "allowed project given by number",
+			key:  "projects/shared-project/secrets/foo",
+			want: "projects/shared-project/secrets/foo",
+		},
+		{
+			name:    "other project",
+			key:     "projects/other-project/secrets/foo",
+			wantErr: "is not in project",
+		},
+		{
+			name:    "unknown project number",
+			key:     "projects/24690002/secrets/foo",
+			wantErr: "unable to resolve project number 24690002",
+		},
+		{
+			name:    "malformed name",
+			key:     "projects/my-project/foo",
+			wantErr: "invalid secret name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sm.secretResourceName(context.Background(), tt.key)
+			if !ErrorContains(err, tt.wantErr) {
+				t.Fatalf("unexpected error: %v, expected: '%s'", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected name: got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+
+	lookups := projects.Lookups
+	if _, err := sm.secretResourceName(context.Background(), "projects/24690001/secrets/foo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if projects.Lookups != lookups {
+		t.Errorf("project number was looked up again, expected a cached project id")
+	}
+}
@@ -22,12 +22,15 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/googleapis/gax-go/v2"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
 )
 
 type MockSMClient struct {
-	accessSecretFn func(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
-	ListSecretsFn  func(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator
-	closeFn        func() error
+	accessSecretFn       func(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
+	ListSecretsFn        func(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator
+	ListSecretVersionsFn func(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, opts ...gax.CallOption) *secretmanager.SecretVersionIterator
+	TestIamPermissionsFn func(ctx context.Context, req *iampb.TestIamPermissionsRequest, opts ...gax.CallOption) (*iampb.TestIamPermissionsResponse, error)
+	closeFn              func() error
 }
 
 func (mc *MockSMClient) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
@@ -37,6 +40,15 @@ func (mc *MockSMClient) AccessSecretVersion(ctx context.Context, req *secretmana
 func (mc *MockSMClient) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator {
 	return mc.ListSecretsFn(ctx, req)
 }
+
+func (mc *MockSMClient) ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, opts ...gax.CallOption) *secretmanager.SecretVersionIterator {
+	return mc.ListSecretVersionsFn(ctx, req)
+}
+
+func (mc *MockSMClient) TestIamPermissions(ctx context.Context, req *iampb.TestIamPermissionsRequest, opts ...gax.CallOption) (*iampb.TestIamPermissionsResponse, error) {
+	return mc.TestIamPermissionsFn(ctx, req)
+}
+
 func (mc *MockSMClient) Close() error {
 	return mc.closeFn()
 }
@@ -59,3 +71,18 @@ func (mc *MockSMClient) WithValue(ctx context.Context, req *secretmanagerpb.Acce
 		}
 	}
 }
+
+// MockProjectsClient resolves project numbers with a static map and counts the lookups.
+type MockProjectsClient struct {
+	IDs     map[string]string
+	Lookups int
+}
+
+func (mc *MockProjectsClient) GetProjectID(ctx context.Context, projectNumber string) (string, error) {
+	mc.Lookups++
+	id, ok := mc.IDs[projectNumber]
+	if !ok {
+		return "", fmt.Errorf("project %s not found", projectNumber)
+	}
+	return id, nil
+}
@@ -0,0 +1,532 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"google.golang.org/api/option"
+	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	iampb "google.golang.org/genproto/googleapis/iam/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxPageSize is the page size used by the Secret Manager API
+	// if the request does not specify one.
+	maxPageSize = 25000
+
+	bufSize = 1024 * 1024
+)
+
+// Server is an in-memory implementation of the Secret Manager API.
+// It supports secrets with labels, versions and their states,
+// aliases like latest, list filters and pagination.
+// Errors, e.g. IAM permission errors, can be injected per secret.
+//
+// Use NewClient to obtain a *secretmanager.Client that talks to the server,
+// it satisfies the GoogleSecretManagerClient interface of the provider.
+type Server struct {
+	secretmanagerpb.UnimplementedSecretManagerServiceServer
+
+	mu             sync.Mutex
+	secrets        map[string]*secret
+	projectNumbers map[string]string
+	errors         map[string]error
+	permissions    map[string][]string
+	grpcServers    []*grpc.Server
+}
+
+type secret struct {
+	secret   *secretmanagerpb.Secret
+	versions []*version
+}
+
+type version struct {
+	version *secretmanagerpb.SecretVersion
+	data    []byte
+}
+
+// NewServer returns an empty Server.
+func NewServer() *Server {
+	return &Server{
+		secrets:        make(map[string]*secret),
+		projectNumbers: make(map[string]string),
+		errors:         make(map[string]error),
+		permissions:    make(map[string][]string),
+	}
+}
+
+// NewClient starts serving s on an in-memory connection
+// and returns a Secret Manager client connected to it.
+func (s *Server) NewClient(ctx context.Context) (*secretmanager.Client, error) {
+	lis := bufconn.Listen(bufSize)
+	srv := grpc.NewServer()
+	secretmanagerpb.RegisterSecretManagerServiceServer(srv, s)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	s.mu.Lock()
+	s.grpcServers = append(s.grpcServers, srv)
+	s.mu.Unlock()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return secretmanager.NewClient(ctx, option.WithGRPCConn(conn))
+}
+
+// Stop stops serving all clients created with NewClient.
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, srv := range s.grpcServers {
+		srv.Stop()
+	}
+	s.grpcServers = nil
+}
+
+// SetProjectNumber maps a project id to its number.
+// Like the Secret Manager API, the server returns resource names with the project number
+// and accepts both the project id and the number in requests.
+func (s *Server) SetProjectNumber(projectID, number string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.projectNumbers[projectID] = number
+}
+
+// AddSecret creates a secret with the given labels and no versions.
+// It replaces an existing secret of the same name.
+func (s *Server) AddSecret(projectID, secretID string, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := s.secretName(projectID, secretID)
+	s.secrets[name] = &secret{
+		secret: &secretmanagerpb.Secret{
+			Name:       name,
+			Labels:     labels,
+			CreateTime: timestamppb.Now(),
+		},
+	}
+}
+
+// AddVersion adds an enabled version with the given payload to a secret
+// and returns its version number. The secret is created without labels if it does not exist.
+func (s *Server) AddVersion(projectID, secretID string, data []byte) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := s.secretName(projectID, secretID)
+	sec, ok := s.secrets[name]
+	if !ok {
+		sec = &secret{
+			secret: &secretmanagerpb.Secret{
+				Name:       name,
+				CreateTime: timestamppb.Now(),
+			},
+		}
+		s.secrets[name] = sec
+	}
+	sec.addVersion(data)
+	return int64(len(sec.versions))
+}
+
+// SetVersionState changes the state of a secret version, e.g. to disable or destroy it.
+func (s *Server) SetVersionState(projectID, secretID string, number int64, state secretmanagerpb.SecretVersion_State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.getVersion(fmt.Sprintf("%s/versions/%d", s.secretName(projectID, secretID), number))
+	if err != nil {
+		return err
+	}
+	v.setState(state)
+	return nil
+}
+
+// WithError makes every request on a secret and its versions fail with err,
+// e.g. status.Error(codes.PermissionDenied, "...") to simulate missing IAM permissions.
+// A nil err removes the injected error.
+func (s *Server) WithError(projectID, secretID string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := s.secretName(projectID, secretID)
+	if err == nil {
+		delete(s.errors, name)
+		return
+	}
+	s.errors[name] = err
+}
+
+// WithPermissions sets the IAM permissions the caller has on a secret,
+// as reported by TestIamPermissions. All permissions are granted on secrets
+// without permissions set.
+func (s *Server) WithPermissions(projectID, secretID string, permissions ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.permissions[s.secretName(projectID, secretID)] = permissions
+}
+
+// TestIamPermissions returns the subset of the requested permissions
+// that is granted on a secret.
+func (s *Server) TestIamPermissions(ctx context.Context, req *iampb.TestIamPermissionsRequest) (*iampb.TestIamPermissionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parts := strings.Split(req.Resource, "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[2] != "secrets" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid resource %q", req.Resource)
+	}
+	granted, ok := s.permissions[s.secretName(parts[1], parts[3])]
+	if !ok {
+		return &iampb.TestIamPermissionsResponse{Permissions: req.Permissions}, nil
+	}
+	resp := &iampb.TestIamPermissionsResponse{}
+	for _, p := range req.Permissions {
+		for _, g := range granted {
+			if p == g {
+				resp.Permissions = append(resp.Permissions, p)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// ListSecrets lists the secrets of a project.
+// Filters support space separated `labels.<key>=<value>`, `labels.<key>:*`
+// and `name:<substring>` terms, which all have to match.
+func (s *Server) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest) (*secretmanagerpb.ListSecretsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project, err := s.parseProject(req.Parent)
+	if err != nil {
+		return nil, err
+	}
+	match, err := parseFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("projects/%s/secrets/", project)
+	names := make([]string, 0, len(s.secrets))
+	for name, sec := range s.secrets {
+		if strings.HasPrefix(name, prefix) && match(sec.secret) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	offset := 0
+	if req.PageToken != "" {
+		offset, err = strconv.Atoi(req.PageToken)
+		if err != nil || offset < 0 || offset > len(names) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", req.PageToken)
+		}
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 || pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	end := offset + pageSize
+	if end > len(names) {
+		end = len(names)
+	}
+	resp := &secretmanagerpb.ListSecretsResponse{
+		TotalSize: int32(len(names)),
+	}
+	for _, name := range names[offset:end] {
+		resp.Secrets = append(resp.Secrets, proto.Clone(s.secrets[name].secret).(*secretmanagerpb.Secret))
+	}
+	if end < len(names) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+// CreateSecret creates a secret without versions.
+func (s *Server) CreateSecret(ctx context.Context, req *secretmanagerpb.CreateSecretRequest) (*secretmanagerpb.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project, err := s.parseProject(req.Parent)
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("projects/%s/secrets/%s", project, req.SecretId)
+	if err := s.errors[name]; err != nil {
+		return nil, err
+	}
+	if _, ok := s.secrets[name]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "Secret [%s] already exists.", name)
+	}
+	sec := &secretmanagerpb.Secret{
+		Name:       name,
+		CreateTime: timestamppb.Now(),
+	}
+	if req.Secret != nil {
+		sec.Labels = req.Secret.Labels
+		sec.Replication = req.Secret.Replication
+	}
+	s.secrets[name] = &secret{secret: sec}
+	return proto.Clone(sec).(*secretmanagerpb.Secret), nil
+}
+
+// AddSecretVersion adds an enabled version to a secret.
+func (s *Server) AddSecretVersion(ctx context.Context, req *secretmanagerpb.AddSecretVersionRequest) (*secretmanagerpb.SecretVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, err := s.getSecret(req.Parent)
+	if err != nil {
+		return nil, err
+	}
+	v := sec.addVersion(req.GetPayload().GetData())
+	return proto.Clone(v.version).(*secretmanagerpb.SecretVersion), nil
+}
+
+// GetSecret returns the metadata of a secret.
+func (s *Server) GetSecret(ctx context.Context, req *secretmanagerpb.GetSecretRequest) (*secretmanagerpb.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, err := s.getSecret(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(sec.secret).(*secretmanagerpb.Secret), nil
+}
+
+// DeleteSecret deletes a secret and all of its versions.
+func (s *Server) DeleteSecret(ctx context.Context, req *secretmanagerpb.DeleteSecretRequest) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, err := s.getSecret(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.secrets, sec.secret.Name)
+	return &emptypb.Empty{}, nil
+}
+
+// ListSecretVersions lists the versions of a secret, newest first.
+// Pagination is not supported, all versions are returned at once.
+func (s *Server) ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest) (*secretmanagerpb.ListSecretVersionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sec, err := s.getSecret(req.Parent)
+	if err != nil {
+		return nil, err
+	}
+	resp := &secretmanagerpb.ListSecretVersionsResponse{
+		TotalSize: int32(len(sec.versions)),
+	}
+	for i := len(sec.versions) - 1; i >= 0; i-- {
+		resp.Versions = append(resp.Versions, proto.Clone(sec.versions[i].version).(*secretmanagerpb.SecretVersion))
+	}
+	return resp, nil
+}
+
+// GetSecretVersion returns the metadata of a secret version.
+func (s *Server) GetSecretVersion(ctx context.Context, req *secretmanagerpb.GetSecretVersionRequest) (*secretmanagerpb.SecretVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.getVersion(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(v.version).(*secretmanagerpb.SecretVersion), nil
+}
+
+// AccessSecretVersion returns the payload of an enabled secret version.
+func (s *Server) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.getVersion(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if v.version.State != secretmanagerpb.SecretVersion_ENABLED {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is in %s state.", v.version.Name, v.version.State)
+	}
+	return &secretmanagerpb.AccessSecretVersionResponse{
+		Name: v.version.Name,
+		Payload: &secretmanagerpb.SecretPayload{
+			Data: append([]byte(nil), v.data...),
+		},
+	}, nil
+}
+
+// DisableSecretVersion disables a secret version.
+func (s *Server) DisableSecretVersion(ctx context.Context, req *secretmanagerpb.DisableSecretVersionRequest) (*secretmanagerpb.SecretVersion, error) {
+	return s.changeVersionState(req.Name, secretmanagerpb.SecretVersion_DISABLED)
+}
+
+// EnableSecretVersion enables a secret version.
+func (s *Server) EnableSecretVersion(ctx context.Context, req *secretmanagerpb.EnableSecretVersionRequest) (*secretmanagerpb.SecretVersion, error) {
+	return s.changeVersionState(req.Name, secretmanagerpb.SecretVersion_ENABLED)
+}
+
+// DestroySecretVersion destroys a secret version and its payload.
+func (s *Server) DestroySecretVersion(ctx context.Context, req *secretmanagerpb.DestroySecretVersionRequest) (*secretmanagerpb.SecretVersion, error) {
+	return s.changeVersionState(req.Name, secretmanagerpb.SecretVersion_DESTROYED)
+}
+
+func (s *Server) changeVersionState(name string, state secretmanagerpb.SecretVersion_State) (*secretmanagerpb.SecretVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.getVersion(name)
+	if err != nil {
+		return nil, err
+	}
+	if v.version.State == secretmanagerpb.SecretVersion_DESTROYED {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s is in DESTROYED state.", v.version.Name)
+	}
+	v.setState(state)
+	return proto.Clone(v.version).(*secretmanagerpb.SecretVersion), nil
+}
+
+func (sec *secret) addVersion(data []byte) *version {
+	v := &version{
+		version: &secretmanagerpb.SecretVersion{
+			Name:       fmt.Sprintf("%s/versions/%d", sec.secret.Name, len(sec.versions)+1),
+			CreateTime: timestamppb.Now(),
+			State:      secretmanagerpb.SecretVersion_ENABLED,
+		},
+		data: append([]byte(nil), data...),
+	}
+	sec.versions = append(sec.versions, v)
+	return v
+}
+
+func (v *version) setState(state secretmanagerpb.SecretVersion_State) {
+	v.version.State = state
+	if state == secretmanagerpb.SecretVersion_DESTROYED {
+		v.version.DestroyTime = timestamppb.Now()
+		v.data = nil
+	}
+}
+
+// secretName returns the name of a secret using the project number, if known.
+func (s *Server) secretName(projectID, secretID string) string {
+	if number, ok := s.projectNumbers[projectID]; ok {
+		projectID = number
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s", projectID, secretID)
+}
+
+// parseProject returns the project of a `projects/<project>` parent.
+func (s *Server) parseProject(parent string) (string, error) {
+	project := strings.TrimPrefix(parent, "projects/")
+	if project == parent || project == "" || strings.Contains(project, "/") {
+		return "", status.Errorf(codes.InvalidArgument, "invalid parent %q", parent)
+	}
+	if number, ok := s.projectNumbers[project]; ok {
+		return number, nil
+	}
+	return project, nil
+}
+
+func (s *Server) getSecret(name string) (*secret, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[2] != "secrets" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid secret name %q", name)
+	}
+	full := s.secretName(parts[1], parts[3])
+	if err := s.errors[full]; err != nil {
+		return nil, err
+	}
+	sec, ok := s.secrets[full]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found or has no versions.", full)
+	}
+	return sec, nil
+}
+
+// getVersion returns a version by number or the latest enabled version for the latest alias.
+func (s *Server) getVersion(name string) (*version, error) {
+	idx := strings.LastIndex(name, "/versions/")
+	if idx < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid secret version name %q", name)
+	}
+	sec, err := s.getSecret(name[:idx])
+	if err != nil {
+		return nil, err
+	}
+	ref := name[idx+len("/versions/"):]
+	if ref == "latest" {
+		for i := len(sec.versions) - 1; i >= 0; i-- {
+			if sec.versions[i].version.State == secretmanagerpb.SecretVersion_ENABLED {
+				return sec.versions[i], nil
+			}
+		}
+		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found or has no versions.", sec.secret.Name)
+	}
+	number, err := strconv.Atoi(ref)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid secret version %q", ref)
+	}
+	if number < 1 || number > len(sec.versions) {
+		return nil, status.Errorf(codes.NotFound, "Secret Version [%s/versions/%d] not found.", sec.secret.Name, number)
+	}
+	return sec.versions[number-1], nil
+}
+
+// parseFilter returns a matcher for a subset of the Secret Manager list filter syntax.
+func parseFilter(filter string) (func(*secretmanagerpb.Secret) bool, error) {
+	var matchers []func(*secretmanagerpb.Secret) bool
+	for _, term := range strings.Fields(filter) {
+		switch {
+		case strings.HasPrefix(term, "labels.") && strings.HasSuffix(term, ":*"):
+			key := strings.TrimSuffix(strings.TrimPrefix(term, "labels."), ":*")
+			matchers = append(matchers, func(sec *secretmanagerpb.Secret) bool {
+				_, ok := sec.Labels[key]
+				return ok
+			})
+		case strings.HasPrefix(term, "labels.") && strings.Contains(term, "="):
+			key, value, _ := strings.Cut(strings.TrimPrefix(term, "labels."), "=")
+			matchers = append(matchers, func(sec *secretmanagerpb.Secret) bool {
+				v, ok := sec.Labels[key]
+				return ok && v == value
+			})
+		case strings.HasPrefix(term, "name:"):
+			sub := strings.TrimPrefix(term, "name:")
+			matchers = append(matchers, func(sec *secretmanagerpb.Secret) bool {
+				return strings.Contains(sec.Name[strings.LastIndex(sec.Name, "/")+1:], sub)
+			})
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported filter term %q", term)
+		}
+	}
+	return func(sec *secretmanagerpb.Secret) bool {
+		for _, m := range matchers {
+			if !m(sec) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
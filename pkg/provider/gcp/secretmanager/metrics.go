@@ -0,0 +1,109 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+	"strings"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"github.com/googleapis/gax-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	GCPSMSubsystem  = "provider_gcpsm"
+	RequestsKey     = "api_requests_total"
+	ErrorsKey       = "api_request_errors_total"
+	SecretAccessKey = "secret_access_total"
+
+	opAccessSecretVersion = "AccessSecretVersion"
+	opListSecrets         = "ListSecrets"
+	opListSecretVersions  = "ListSecretVersions"
+)
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: GCPSMSubsystem,
+		Name:      RequestsKey,
+		Help:      "Total number of GCP Secret Manager API requests",
+	}, []string{"project_id", "operation"})
+
+	apiRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: GCPSMSubsystem,
+		Name:      ErrorsKey,
+		Help:      "Total number of failed GCP Secret Manager API requests",
+	}, []string{"project_id", "operation", "code"})
+
+	secretAccess = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: GCPSMSubsystem,
+		Name:      SecretAccessKey,
+		Help:      "Total number of accesses of a GCP Secret Manager secret",
+	}, []string{"project_id", "secret"})
+)
+
+// metricsClient counts the requests of the wrapped Secret Manager client.
+type metricsClient struct {
+	GoogleSecretManagerClient
+}
+
+func withMetrics(cl GoogleSecretManagerClient) GoogleSecretManagerClient {
+	return &metricsClient{GoogleSecretManagerClient: cl}
+}
+
+func (c *metricsClient) AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	project, secret := parseResourceName(req.Name)
+	apiRequests.WithLabelValues(project, opAccessSecretVersion).Inc()
+	secretAccess.WithLabelValues(project, secret).Inc()
+	resp, err := c.GoogleSecretManagerClient.AccessSecretVersion(ctx, req, opts...)
+	if err != nil {
+		apiRequestErrors.WithLabelValues(project, opAccessSecretVersion, status.Code(err).String()).Inc()
+	}
+	return resp, err
+}
+
+// ListSecrets counts the call only, pages and errors are fetched by the iterator.
+func (c *metricsClient) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator {
+	project, _ := parseResourceName(req.Parent)
+	apiRequests.WithLabelValues(project, opListSecrets).Inc()
+	return c.GoogleSecretManagerClient.ListSecrets(ctx, req, opts...)
+}
+
+// ListSecretVersions counts the call only, pages and errors are fetched by the iterator.
+func (c *metricsClient) ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, opts ...gax.CallOption) *secretmanager.SecretVersionIterator {
+	project, _ := parseResourceName(req.Parent)
+	apiRequests.WithLabelValues(project, opListSecretVersions).Inc()
+	return c.GoogleSecretManagerClient.ListSecretVersions(ctx, req, opts...)
+}
+
+// parseResourceName returns the project and secret of a resource name like
+// `projects/<project>/secrets/<secret>/versions/<version>`.
+func parseResourceName(name string) (project, secret string) {
+	parts := strings.Split(name, "/")
+	if len(parts) >= 2 && parts[0] == "projects" {
+		project = parts[1]
+	}
+	if len(parts) >= 4 && parts[2] == "secrets" {
+		secret = parts[3]
+	}
+	return project, secret
+}
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestErrors, secretAccess)
+}
@@ -0,0 +1,64 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	fakesm "github.com/external-secrets/external-secrets/pkg/provider/gcp/secretmanager/fake"
+)
+
+func TestMetricsClient(t *testing.T) {
+	req := &secretmanagerpb.AccessSecretVersionRequest{Name: "projects/metrics-project/secrets/db-password/versions/latest"}
+	fake := &fakesm.MockSMClient{}
+	fake.WithValue(context.Background(), req, nil, status.Error(codes.PermissionDenied, "denied"))
+	cl := withMetrics(fake)
+
+	requests := apiRequests.WithLabelValues("metrics-project", opAccessSecretVersion)
+	errs := apiRequestErrors.WithLabelValues("metrics-project", opAccessSecretVersion, codes.PermissionDenied.String())
+	access := secretAccess.WithLabelValues("metrics-project", "db-password")
+	requestsBefore, errsBefore, accessBefore := testutil.ToFloat64(requests), testutil.ToFloat64(errs), testutil.ToFloat64(access)
+
+	_, err := cl.AccessSecretVersion(context.Background(), req)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := testutil.ToFloat64(requests) - requestsBefore; got != 1 {
+		t.Errorf("requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(errs) - errsBefore; got != 1 {
+		t.Errorf("errors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(access) - accessBefore; got != 1 {
+		t.Errorf("secret access = %v, want 1", got)
+	}
+}
+
+func TestParseResourceName(t *testing.T) {
+	project, secret := parseResourceName("projects/p1/secrets/s1/versions/3")
+	if project != "p1" || secret != "s1" {
+		t.Errorf("got %q %q, want p1 s1", project, secret)
+	}
+	project, secret = parseResourceName("projects/p1")
+	if project != "p1" || secret != "" {
+		t.Errorf("got %q %q, want p1 and no secret", project, secret)
+	}
+}
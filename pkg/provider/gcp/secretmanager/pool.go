@@ -0,0 +1,212 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package secretmanager
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+// ClientIdleTimeout is the time after which a pooled client that was not used is closed.
+var ClientIdleTimeout = 2 * time.Hour
+
+// tokenExpiryDelta is the time before the expiry of its token
+// from which a pooled client is not handed out anymore.
+const tokenExpiryDelta = 5 * time.Minute
+
+// clients holds the Secret Manager clients shared by all reconciles of a store.
+// The underlying gRPC clients are safe for concurrent use, so every reconcile
+// of a store uses the same connection while stores never share credentials.
+var clients = newClientPool()
+
+type clientPool struct {
+	mu      sync.Mutex
+	clients map[poolKey]*pooledClient
+	// entries is updated after each change, so it can be read without the lock.
+	entries atomic.Int64
+}
+
+// poolKey identifies the clients of a store built with the same credentials.
+// Clients of a referent ClusterSecretStore are pooled per namespace.
+type poolKey struct {
+	StoreUID    types.UID
+	Namespace   string
+	Credentials string
+}
+
+type pooledClient struct {
+	smClient GoogleSecretManagerClient
+	projects ProjectsClient
+	created  time.Time
+	// tokenExpiry is the expiry of the token the client was built with,
+	// zero if it is unknown. Workload identity tokens are not refreshed.
+	tokenExpiry time.Time
+
+	// guarded by clientPool.mu
+	lastUsed time.Time
+	refs     int
+	removed  bool
+}
+
+func newClientPool() *clientPool {
+	return &clientPool{clients: make(map[poolKey]*pooledClient)}
+}
+
+// newPoolKey returns the key of the clients of store, the credentials are hashed
+// from everything NewTokenSource reads from the store.
+func newPoolKey(store esv1beta1.GenericStore, clusterProjectID, namespace string) (poolKey, error) {
+	raw, err := json.Marshal(struct {
+		Kind             string
+		Namespace        string
+		ClusterProjectID string
+		Auth             esv1beta1.GCPSMAuth
+	}{
+		Kind:             store.GetObjectKind().GroupVersionKind().Kind,
+		Namespace:        namespace,
+		ClusterProjectID: clusterProjectID,
+		Auth:             store.GetSpec().Provider.GCPSM.Auth,
+	})
+	if err != nil {
+		return poolKey{}, err
+	}
+	return poolKey{
+		StoreUID:    store.GetObjectMeta().UID,
+		Namespace:   namespace,
+		Credentials: fmt.Sprintf("%x", sha256.Sum256(raw)),
+	}, nil
+}
+
+// acquire returns the pooled client of key or nil if there is none that can be used.
+// Clients of the store built with other credentials for the same namespace are removed.
+// Every acquired client must be released.
+func (p *clientPool) acquire(store esv1beta1.GenericStore, key poolKey) *pooledClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.updateEntries()
+	now := time.Now()
+	p.evictIdle(now)
+	for k, c := range p.clients {
+		if k.StoreUID == key.StoreUID && k.Namespace == key.Namespace && k != key {
+			p.remove(k, c)
+		}
+	}
+	c, ok := p.clients[key]
+	if !ok {
+		return nil
+	}
+	if c.expired(store, now) {
+		p.remove(key, c)
+		return nil
+	}
+	c.refs++
+	c.lastUsed = now
+	return c
+}
+
+// add pools c and acquires it. If another reconcile pooled a client
+// for key in the meantime, c is closed and the other client is acquired instead.
+func (p *clientPool) add(store esv1beta1.GenericStore, key poolKey, c *pooledClient) *pooledClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.updateEntries()
+	now := time.Now()
+	if existing, ok := p.clients[key]; ok && !existing.expired(store, now) {
+		closePooledClient(c)
+		existing.refs++
+		existing.lastUsed = now
+		return existing
+	} else if ok {
+		p.remove(key, existing)
+	}
+	c.refs = 1
+	c.lastUsed = now
+	p.clients[key] = c
+	return c
+}
+
+// release returns c to the pool. A client that was removed
+// while it was in use is closed once it is released by everyone.
+func (p *clientPool) release(c *pooledClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c.refs--
+	c.lastUsed = time.Now()
+	if c.removed && c.refs == 0 {
+		closePooledClient(c)
+	}
+}
+
+// removeStore removes all clients of the store with the given UID.
+func (p *clientPool) removeStore(uid types.UID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.updateEntries()
+	for k, c := range p.clients {
+		if k.StoreUID == uid {
+			p.remove(k, c)
+		}
+	}
+}
+
+// evictIdle removes the clients that were not used for ClientIdleTimeout,
+// e.g. because their store was deleted.
+func (p *clientPool) evictIdle(now time.Time) {
+	for k, c := range p.clients {
+		if c.refs == 0 && now.Sub(c.lastUsed) >= ClientIdleTimeout {
+			p.remove(k, c)
+		}
+	}
+}
+
+// remove must be called with the lock held, c is closed right away if it is not in use.
+func (p *clientPool) remove(key poolKey, c *pooledClient) {
+	delete(p.clients, key)
+	c.removed = true
+	if c.refs == 0 {
+		closePooledClient(c)
+	}
+}
+
+// len returns the number of pooled clients, it does not wait for the lock.
+func (p *clientPool) len() int {
+	return int(p.entries.Load())
+}
+
+func (p *clientPool) updateEntries() {
+	p.entries.Store(int64(len(p.clients)))
+}
+
+// expired returns true if the client must not be handed out anymore
+// because its token expires soon or the credentialsRefreshInterval of the store passed.
+func (c *pooledClient) expired(store esv1beta1.GenericStore, now time.Time) bool {
+	if !c.tokenExpiry.IsZero() && now.After(c.tokenExpiry.Add(-tokenExpiryDelta)) {
+		return true
+	}
+	return utils.CredentialsExpired(store, c.created)
+}
+
+func closePooledClient(c *pooledClient) {
+	if err := c.smClient.Close(); err != nil {
+		log.Error(err, "unable to close pooled client")
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package secretmanager
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	fakesm "github.com/external-secrets/external-secrets/pkg/provider/gcp/secretmanager/fake"
+)
+
+type closeCounter struct {
+	fakesm.MockSMClient
+	mu     sync.Mutex
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed++
+	return nil
+}
+
+func (c *closeCounter) closeCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func newPoolTestStore(uid string) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1beta1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "gcp", Namespace: "default", UID: types.UID("uid-" + uid)},
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				GCPSM: &esv1beta1.GCPSMProvider{ProjectID: "project"},
+			},
+		},
+	}
+}
+
+func mustPoolKey(t *testing.T, store esv1beta1.GenericStore, namespace string) poolKey {
+	t.Helper()
+	key, err := newPoolKey(store, "project", namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestNewPoolKey(t *testing.T) {
+	store := newPoolTestStore("a")
+	key := mustPoolKey(t, store, "default")
+	if again := mustPoolKey(t, newPoolTestStore("a"), "default"); again != key {
+		t.Errorf("key of an equal store differs: %v != %v", again, key)
+	}
+	if other := mustPoolKey(t, newPoolTestStore("b"), "default"); other == key {
+		t.Errorf("stores with different UIDs share key %v", key)
+	}
+	if other := mustPoolKey(t, store, "other"); other == key {
+		t.Errorf("namespaces share key %v", key)
+	}
+	rotated := newPoolTestStore("a")
+	rotated.Spec.Provider.GCPSM.Auth.WorkloadIdentity = &esv1beta1.GCPWorkloadIdentity{ClusterName: "cluster"}
+	if other := mustPoolKey(t, rotated, "default"); other.StoreUID != key.StoreUID || other == key {
+		t.Errorf("changed auth must change the credentials of key %v", key)
+	}
+}
+
+func TestClientPoolReuse(t *testing.T) {
+	pool := newClientPool()
+	store := newPoolTestStore("a")
+	key := mustPoolKey(t, store, "default")
+
+	if c := pool.acquire(store, key); c != nil {
+		t.Fatalf("empty pool returned a client")
+	}
+	sm := &closeCounter{}
+	first := pool.add(store, key, &pooledClient{smClient: sm, created: time.Now()})
+
+	// a reconcile running at the same time shares the client
+	second := pool.acquire(store, key)
+	if second != first {
+		t.Fatalf("pooled client was not reused")
+	}
+	pool.release(first)
+	pool.release(second)
+	if first.refs != 0 || sm.closeCount() != 0 {
+		t.Errorf("released client: refs = %d, closed = %d, want 0, 0", first.refs, sm.closeCount())
+	}
+	if pool.len() != 1 {
+		t.Errorf("len() = %d, want 1", pool.len())
+	}
+
+	// a client built concurrently for the same key is closed in favor of the pooled one
+	duplicate := &closeCounter{}
+	if c := pool.add(store, key, &pooledClient{smClient: duplicate, created: time.Now()}); c != first {
+		t.Errorf("add() did not return the pooled client")
+	}
+	if duplicate.closeCount() != 1 {
+		t.Errorf("duplicate client was not closed")
+	}
+}
+
+func TestClientPoolRemoval(t *testing.T) {
+	store := newPoolTestStore("a")
+	key := mustPoolKey(t, store, "default")
+
+	tests := []struct {
+		name   string
+		client func() *pooledClient
+		remove func(pool *clientPool)
+	}{
+		{
+			name:   "invalidated store",
+			remove: func(pool *clientPool) { pool.removeStore(store.UID) },
+		},
+		{
+			name: "changed credentials",
+			remove: func(pool *clientPool) {
+				rotated := store.DeepCopy()
+				rotated.Spec.Provider.GCPSM.Auth.WorkloadIdentity = &esv1beta1.GCPWorkloadIdentity{ClusterName: "cluster"}
+				pool.acquire(rotated, mustPoolKey(t, rotated, "default"))
+			},
+		},
+		{
+			name: "expiring token",
+			client: func() *pooledClient {
+				return &pooledClient{created: time.Now(), tokenExpiry: time.Now().Add(time.Minute)}
+			},
+			remove: func(pool *clientPool) { pool.acquire(store, key) },
+		},
+		{
+			name: "credentials refresh interval passed",
+			client: func() *pooledClient {
+				return &pooledClient{created: time.Now().Add(-2 * time.Hour)}
+			},
+			remove: func(pool *clientPool) {
+				refreshed := store.DeepCopy()
+				refreshed.Spec.CredentialsRefreshInterval = &metav1.Duration{Duration: time.Hour}
+				pool.acquire(refreshed, key)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newClientPool()
+			c := &pooledClient{created: time.Now()}
+			if tt.client != nil {
+				c = tt.client()
+			}
+			sm := &closeCounter{}
+			c.smClient = sm
+			// add does not check the client it pools
+			inUse := pool.add(store, key, c)
+
+			tt.remove(pool)
+			if sm.closeCount() != 0 {
+				t.Fatalf("client in use was closed")
+			}
+			if _, ok := pool.clients[key]; ok {
+				t.Fatalf("client was not removed")
+			}
+			pool.release(inUse)
+			if sm.closeCount() != 1 {
+				t.Errorf("removed client was closed %d times, want 1", sm.closeCount())
+			}
+		})
+	}
+}
+
+func TestClientPoolNamespaces(t *testing.T) {
+	pool := newClientPool()
+	store := newPoolTestStore("a")
+	store.TypeMeta.Kind = esv1beta1.ClusterSecretStoreKind
+	keyA := mustPoolKey(t, store, "team-a")
+	keyB := mustPoolKey(t, store, "team-b")
+	smA := &closeCounter{}
+	smB := &closeCounter{}
+	pool.release(pool.add(store, keyA, &pooledClient{smClient: smA, created: time.Now()}))
+	pool.release(pool.add(store, keyB, &pooledClient{smClient: smB, created: time.Now()}))
+
+	// alternating reconciles of both namespaces reuse their clients
+	for i := 0; i < 2; i++ {
+		for _, key := range []poolKey{keyA, keyB} {
+			c := pool.acquire(store, key)
+			if c == nil {
+				t.Fatalf("client of namespace %q was not pooled", key.Namespace)
+			}
+			pool.release(c)
+		}
+	}
+	if smA.closeCount() != 0 || smB.closeCount() != 0 {
+		t.Errorf("clients of a store used from two namespaces were closed")
+	}
+	if pool.len() != 2 {
+		t.Errorf("len() = %d, want 2", pool.len())
+	}
+}
+
+func TestClientPoolIdleEviction(t *testing.T) {
+	defer func(timeout time.Duration) { ClientIdleTimeout = timeout }(ClientIdleTimeout)
+	ClientIdleTimeout = time.Minute
+
+	pool := newClientPool()
+	idleStore := newPoolTestStore("idle")
+	busyStore := newPoolTestStore("busy")
+	idle := &closeCounter{}
+	busy := &closeCounter{}
+	idleClient := pool.add(idleStore, mustPoolKey(t, idleStore, "default"), &pooledClient{smClient: idle, created: time.Now()})
+	pool.release(idleClient)
+	pool.add(busyStore, mustPoolKey(t, busyStore, "default"), &pooledClient{smClient: busy, created: time.Now()})
+
+	pool.mu.Lock()
+	for _, c := range pool.clients {
+		c.lastUsed = time.Now().Add(-time.Hour)
+	}
+	pool.mu.Unlock()
+
+	other := newPoolTestStore("other")
+	pool.acquire(other, mustPoolKey(t, other, "default"))
+	if idle.closeCount() != 1 {
+		t.Errorf("idle client was not closed")
+	}
+	if busy.closeCount() != 0 {
+		t.Errorf("client in use was closed")
+	}
+	if pool.len() != 1 {
+		t.Errorf("len() = %d, want 1", pool.len())
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/cloudresourcemanager/v3"
+)
+
+const (
+	errInvalidSecretName  = "invalid secret name %q, expected projects/<project>/secrets/<secret>"
+	errResolveProject     = "unable to resolve project number %s: %w"
+	errSecretNotInProject = "secret %q is not in project %q of the store or its allowed projects"
+)
+
+// ProjectsClient looks up projects in Cloud Resource Manager.
+type ProjectsClient interface {
+	// GetProjectID returns the ID of the project with the given number.
+	GetProjectID(ctx context.Context, projectNumber string) (string, error)
+}
+
+type crmProjectsClient struct {
+	svc *cloudresourcemanager.Service
+}
+
+func (c *crmProjectsClient) GetProjectID(ctx context.Context, projectNumber string) (string, error) {
+	project, err := c.svc.Projects.Get("projects/" + projectNumber).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	return project.ProjectId, nil
+}
+
+// projectIDs caches the IDs of project numbers, project numbers are never reused.
+var projectIDs = struct {
+	sync.RWMutex
+	ids map[string]string
+}{ids: make(map[string]string)}
+
+// resolveProjectID returns the ID of a project given by number or ID.
+func (c *Client) resolveProjectID(ctx context.Context, project string) (string, error) {
+	if !isProjectNumber(project) {
+		return project, nil
+	}
+	projectIDs.RLock()
+	id, ok := projectIDs.ids[project]
+	projectIDs.RUnlock()
+	if ok {
+		return id, nil
+	}
+	if c.projects == nil {
+		return "", fmt.Errorf(errResolveProject, project, fmt.Errorf("no resource manager client"))
+	}
+	id, err := c.projects.GetProjectID(ctx, project)
+	if err != nil {
+		return "", fmt.Errorf(errResolveProject, project, err)
+	}
+	projectIDs.Lock()
+	projectIDs.ids[project] = id
+	projectIDs.Unlock()
+	return id, nil
+}
+
+// secretResourceName returns the resource name of the secret of a remoteRef key.
+// Keys are either secret names in the store project or fully qualified
+// as projects/<project>/secrets/<secret>, which must name the store project
+// or one of its allowed projects.
+func (c *Client) secretResourceName(ctx context.Context, key string) (string, error) {
+	if !strings.HasPrefix(key, "projects/") {
+		return fmt.Sprintf("projects/%s/secrets/%s", c.store.ProjectID, key), nil
+	}
+	project, secret, ok := splitSecretName(key)
+	if !ok {
+		return "", fmt.Errorf(errInvalidSecretName, key)
+	}
+	allowed, err := c.isAllowedProject(ctx, project)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return "", fmt.Errorf(errSecretNotInProject, key, c.store.ProjectID)
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s", project, secret), nil
+}
+
+// isAllowedProject reports whether secrets of the project may be read through the store.
+// Projects are compared by name first, so numbers are only resolved when needed.
+func (c *Client) isAllowedProject(ctx context.Context, project string) (bool, error) {
+	projects := append([]string{c.store.ProjectID}, c.store.AllowedProjects...)
+	for _, p := range projects {
+		if p == project {
+			return true, nil
+		}
+	}
+	id, err := c.resolveProjectID(ctx, project)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range projects {
+		allowedID, err := c.resolveProjectID(ctx, p)
+		if err != nil {
+			return false, err
+		}
+		if allowedID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// splitSecretName splits projects/<project>/secrets/<secret> into its project and secret.
+func splitSecretName(name string) (project, secret string, ok bool) {
+	rest := strings.TrimPrefix(name, "projects/")
+	project, secret, ok = strings.Cut(rest, "/secrets/")
+	if !ok || project == "" || secret == "" || strings.Contains(project, "/") {
+		return "", "", false
+	}
+	return project, secret, true
+}
+
+func isProjectNumber(project string) bool {
+	if project == "" {
+		return false
+	}
+	for _, r := range project {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
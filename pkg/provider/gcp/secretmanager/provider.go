@@ -16,13 +16,18 @@ package secretmanager
 import (
 	"context"
 	"fmt"
-	"sync"
+	"strings"
+	"time"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"google.golang.org/api/cloudresourcemanager/v3"
 	"google.golang.org/api/option"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/credentialfile"
+	"github.com/external-secrets/external-secrets/pkg/diagnostics"
+	"github.com/external-secrets/external-secrets/pkg/useragent"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
@@ -33,23 +38,18 @@ type Provider struct{}
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &Client{}
 var _ esv1beta1.Provider = &Provider{}
+var _ esv1beta1.ClientCacheInvalidator = &Provider{}
 
 func init() {
+	diagnostics.RegisterClientCache("gcpsm", clients.len)
 	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
 		GCPSM: &esv1beta1.GCPSMProvider{},
 	})
 }
 
-/*
-Currently, GCPSM client has a limitation around how concurrent connections work
-This limitation causes memory leaks due to random disconnects from living clients
-and also payload switches when sending a call (such as using a credential from one
-thread to ask secrets from another thread).
-A Mutex was implemented to make sure only one connection can be in place at a time.
-*/
-var useMu = sync.Mutex{}
-
 // NewClient constructs a GCP Provider.
+// The Secret Manager client is taken from the pool of the store if it holds
+// a client built with the same credentials, otherwise a new client is pooled.
 func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
 	storeSpec := store.GetSpec()
 	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.GCPSM == nil {
@@ -57,24 +57,28 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 	}
 	gcpStore := storeSpec.Provider.GCPSM
 
-	useMu.Lock()
-
 	client := &Client{
 		kube:      kube,
 		store:     gcpStore,
 		namespace: namespace,
+		// IAM bindings granted together with the store may not have propagated yet
+		storeCreated: store.GetObjectMeta().CreationTimestamp.Time,
 	}
-	defer func() {
-		if client.smClient == nil {
-			_ = client.Close(ctx)
-		}
-	}()
 
 	// this project ID is used for authentication (currently only relevant for workload identity)
 	clusterProjectID, err := clusterProjectID(storeSpec)
 	if err != nil {
 		return nil, err
 	}
+	key, err := newPoolKey(store, clusterProjectID, namespace)
+	if err != nil {
+		return nil, fmt.Errorf(errUnableCreateGCPSMClient, err)
+	}
+	if pooled := clients.acquire(store, key); pooled != nil {
+		client.use(pooled)
+		return client, nil
+	}
+
 	isClusterKind := store.GetObjectKind().GroupVersionKind().Kind == esv1beta1.ClusterSecretStoreKind
 	ts, err := NewTokenSource(ctx, gcpStore.Auth, clusterProjectID, isClusterKind, kube, namespace)
 	if err != nil {
@@ -82,19 +86,41 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 	}
 
 	// check if we can get credentials
-	_, err = ts.Token()
+	token, err := ts.Token()
 	if err != nil {
 		return nil, fmt.Errorf(errUnableGetCredentials, err)
 	}
 
-	clientGCPSM, err := secretmanager.NewClient(ctx, option.WithTokenSource(ts))
+	userAgent := option.WithUserAgent(useragent.ForStore(store))
+	clientGCPSM, err := secretmanager.NewClient(ctx, option.WithTokenSource(ts), userAgent)
 	if err != nil {
 		return nil, fmt.Errorf(errUnableCreateGCPSMClient, err)
 	}
-	client.smClient = clientGCPSM
+	// project numbers of fully qualified secret names are resolved to IDs
+	crm, err := cloudresourcemanager.NewService(ctx, option.WithTokenSource(ts), userAgent)
+	if err != nil {
+		_ = clientGCPSM.Close()
+		return nil, fmt.Errorf(errUnableCreateGCPSMClient, err)
+	}
+	pooled := &pooledClient{
+		smClient: withMetrics(clientGCPSM),
+		projects: &crmProjectsClient{svc: crm},
+		created:  time.Now(),
+	}
+	// workload identity tokens are not refreshed, the client is built again before the token expires
+	if gcpStore.Auth.WorkloadIdentity != nil {
+		pooled.tokenExpiry = token.Expiry
+	}
+	client.use(clients.add(store, key, pooled))
 	return client, nil
 }
 
+// InvalidateClients removes the pooled clients of a store,
+// so the next client is built with the current credentials.
+func (p *Provider) InvalidateClients(ctx context.Context, store esv1beta1.GenericStore) {
+	clients.removeStore(store.GetObjectMeta().UID)
+}
+
 func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 	if store == nil {
 		return fmt.Errorf(errInvalidStore)
@@ -120,9 +146,41 @@ func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 			return fmt.Errorf(errInvalidWISARef, err)
 		}
 	}
+	if g.Auth.CredentialConfigSecretRef != nil {
+		// external account configurations make the controller read files and URLs
+		// and send the result to the configured token URL
+		if store.GetObjectKind().GroupVersionKind().Kind != esv1beta1.ClusterSecretStoreKind {
+			return fmt.Errorf(errCredConfigKind)
+		}
+		if err := utils.ValidateSecretSelector(store, *g.Auth.CredentialConfigSecretRef); err != nil {
+			return fmt.Errorf(errInvalidCredConfigRef, err)
+		}
+	}
+	if g.Auth.CredentialsFile != "" {
+		// the file is read from the controller, namespaced stores must not access it
+		if store.GetObjectKind().GroupVersionKind().Kind != esv1beta1.ClusterSecretStoreKind {
+			return fmt.Errorf(errCredentialsFileKind)
+		}
+		if err := credentialfile.Validate(g.Auth.CredentialsFile); err != nil {
+			return fmt.Errorf(errCredentialsFilePath, err)
+		}
+	}
+	for _, project := range g.AllowedProjects {
+		if project == "" || strings.Contains(project, "/") {
+			return fmt.Errorf(errAllowedProject, project)
+		}
+	}
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
+
 func clusterProjectID(spec *esv1beta1.SecretStoreSpec) (string, error) {
 	if spec.Provider.GCPSM.Auth.WorkloadIdentity != nil && spec.Provider.GCPSM.Auth.WorkloadIdentity.ClusterProjectID != "" {
 		return spec.Provider.GCPSM.Auth.WorkloadIdentity.ClusterProjectID, nil
@@ -152,9 +152,13 @@ func (w *workloadIdentity) TokenSource(ctx context.Context, auth esv1beta1.GCPSM
 	if err != nil {
 		return nil, fmt.Errorf(errGenAccessToken, err)
 	}
-	return oauth2.StaticTokenSource(&oauth2.Token{
+	token := &oauth2.Token{
 		AccessToken: gcpSAResp.GetAccessToken(),
-	}), nil
+	}
+	if gcpSAResp.GetExpireTime() != nil {
+		token.Expiry = gcpSAResp.GetExpireTime().AsTime()
+	}
+	return oauth2.StaticTokenSource(token), nil
 }
 
 func (w *workloadIdentity) Close() error {
@@ -260,5 +264,12 @@ func (g *gcpIDBindTokenGenerator) Generate(ctx context.Context, client *http.Cli
 	if err := json.Unmarshal(respBody, idBindToken); err != nil {
 		return nil, err
 	}
+	// the lifetime is returned as expires_in, which oauth2.Token does not decode
+	var lifetime struct {
+		ExpiresIn int64 `json:"expires_in"`
+	}
+	if err := json.Unmarshal(respBody, &lifetime); err == nil && lifetime.ExpiresIn > 0 {
+		idBindToken.Expiry = time.Now().Add(time.Duration(lifetime.ExpiresIn) * time.Second)
+	}
 	return idBindToken, nil
 }
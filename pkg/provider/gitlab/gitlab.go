@@ -186,7 +186,8 @@ func (g *Gitlab) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretData
 	}
 
 	if ref.Property == "" {
-		if data.Value != "" {
+		// file variables hold the file content as-is, which may be empty
+		if data.Value != "" || data.VariableType == gitlab.FileVariableType {
 			return []byte(data.Value), nil
 		}
 		return nil, fmt.Errorf("invalid secret received. no secret string for key: %s", ref.Key)
@@ -263,3 +264,10 @@ func (g *Gitlab) ValidateStore(store esv1beta1.GenericStore) error {
 	}
 	return nil
 }
+
+// Capabilities returns the capabilities of the provider.
+func (g *Gitlab) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
@@ -164,8 +164,35 @@ func TestGitlabSecretManagerGetSecret(t *testing.T) {
 		smtc.expectedSecret = secretValue
 	}
 
+	setFileVariable := func(smtc *secretManagerTestCase) {
+		smtc.apiOutput = &gitlab.ProjectVariable{
+			Key:          "testkey",
+			Value:        "line1\n  line2\n",
+			VariableType: gitlab.FileVariableType,
+		}
+		smtc.expectedSecret = "line1\n  line2\n"
+	}
+
+	setEmptyFileVariable := func(smtc *secretManagerTestCase) {
+		smtc.apiOutput = &gitlab.ProjectVariable{
+			Key:          "testkey",
+			VariableType: gitlab.FileVariableType,
+		}
+	}
+
+	setEmptyEnvVariable := func(smtc *secretManagerTestCase) {
+		smtc.apiOutput = &gitlab.ProjectVariable{
+			Key:          "testkey",
+			VariableType: gitlab.EnvVariableType,
+		}
+		smtc.expectError = "no secret string for key"
+	}
+
 	successCases := []*secretManagerTestCase{
 		makeValidSecretManagerTestCaseCustom(setSecretString),
+		makeValidSecretManagerTestCaseCustom(setFileVariable),
+		makeValidSecretManagerTestCaseCustom(setEmptyFileVariable),
+		makeValidSecretManagerTestCaseCustom(setEmptyEnvVariable),
 		makeValidSecretManagerTestCaseCustom(setAPIErr),
 		makeValidSecretManagerTestCaseCustom(setNilMockClient),
 	}
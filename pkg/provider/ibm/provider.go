@@ -17,6 +17,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -46,6 +47,13 @@ const (
 	errFetchSAKSecret                        = "could not fetch SecretAccessKey secret: %w"
 	errMissingSAK                            = "missing SecretAccessKey"
 	errJSONSecretUnmarshal                   = "unable to unmarshal secret: %w"
+	errInvalidServiceURL                     = "invalid serviceURL: %w"
+	errInvalidIAMEndpoint                    = "invalid iamEndpoint %q, must be an absolute URL"
+
+	iamEndpoint        = "https://iam.cloud.ibm.com"
+	privateIAMEndpoint = "https://private.iam.cloud.ibm.com"
+	// publicServiceDomain is the domain of the public endpoints of Secrets Manager instances.
+	publicServiceDomain = ".secrets-manager.appdomain.cloud"
 )
 
 // https://github.com/external-secrets/external-secrets/issues/644
@@ -551,6 +559,11 @@ func (ibm *providerIBM) ValidateStore(store esv1beta1.GenericStore) error {
 	if ibmSpec.ServiceURL == nil {
 		return fmt.Errorf("serviceURL is required")
 	}
+	if ibmSpec.IAMEndpoint != "" {
+		if u, err := url.Parse(ibmSpec.IAMEndpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf(errInvalidIAMEndpoint, ibmSpec.IAMEndpoint)
+		}
+	}
 
 	containerRef := ibmSpec.Auth.ContainerAuth
 	secretKeyRef := ibmSpec.Auth.SecretRef.SecretAPIKey
@@ -578,6 +591,13 @@ func (ibm *providerIBM) ValidateStore(store esv1beta1.GenericStore) error {
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (ibm *providerIBM) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
+
 func (ibm *providerIBM) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
 	storeSpec := store.GetSpec()
 	ibmSpec := storeSpec.Provider.IBM
@@ -589,6 +609,16 @@ func (ibm *providerIBM) NewClient(ctx context.Context, store esv1beta1.GenericSt
 		storeKind: store.GetObjectKind().GroupVersionKind().Kind,
 	}
 
+	serviceURL := *ibmSpec.ServiceURL
+	if ibmSpec.PrivateEndpoint {
+		privateURL, err := privateServiceURL(serviceURL)
+		if err != nil {
+			return nil, fmt.Errorf(errIBMClient, err)
+		}
+		serviceURL = privateURL
+	}
+	authEndpoint := iamAuthEndpoint(ibmSpec)
+
 	var err error
 	var secretsManager *sm.SecretsManagerV1
 	containerAuthProfile := iStore.store.Auth.ContainerAuth.Profile
@@ -602,8 +632,7 @@ func (ibm *providerIBM) NewClient(ctx context.Context, store esv1beta1.GenericSt
 			containerAuthToken = "/var/run/secrets/tokens/vault-token"
 		}
 		if containerAuthEndpoint == "" {
-			// API default path
-			containerAuthEndpoint = "https://iam.cloud.ibm.com"
+			containerAuthEndpoint = authEndpoint
 		}
 
 		authenticator, err := core.NewContainerAuthenticatorBuilder().
@@ -615,7 +644,7 @@ func (ibm *providerIBM) NewClient(ctx context.Context, store esv1beta1.GenericSt
 			return nil, fmt.Errorf(errIBMClient, err)
 		}
 		secretsManager, err = sm.NewSecretsManagerV1(&sm.SecretsManagerV1Options{
-			URL:           *storeSpec.Provider.IBM.ServiceURL,
+			URL:           serviceURL,
 			Authenticator: authenticator,
 		})
 		if err != nil {
@@ -628,9 +657,10 @@ func (ibm *providerIBM) NewClient(ctx context.Context, store esv1beta1.GenericSt
 		}
 
 		secretsManager, err = sm.NewSecretsManagerV1(&sm.SecretsManagerV1Options{
-			URL: *storeSpec.Provider.IBM.ServiceURL,
+			URL: serviceURL,
 			Authenticator: &core.IamAuthenticator{
 				ApiKey: string(iStore.credentials),
+				URL:    authEndpoint,
 			},
 		})
 	}
@@ -665,6 +695,38 @@ func (ibm *providerIBM) NewClient(ctx context.Context, store esv1beta1.GenericSt
 	return ibm, nil
 }
 
+// iamAuthEndpoint returns the IAM endpoint of the store.
+func iamAuthEndpoint(ibmSpec *esv1beta1.IBMProvider) string {
+	if ibmSpec.IAMEndpoint != "" {
+		return ibmSpec.IAMEndpoint
+	}
+	if ibmSpec.PrivateEndpoint {
+		return privateIAMEndpoint
+	}
+	return iamEndpoint
+}
+
+// privateServiceURL rewrites the public endpoint of a Secrets Manager instance,
+// https://<instance>.<region>.secrets-manager.appdomain.cloud, to its private endpoint
+// https://<instance>.private.<region>.secrets-manager.appdomain.cloud.
+// Private endpoints and other hosts, e.g. virtual private endpoints, are returned unchanged.
+func privateServiceURL(serviceURL string) (string, error) {
+	u, err := url.Parse(serviceURL)
+	if err != nil {
+		return "", fmt.Errorf(errInvalidServiceURL, err)
+	}
+	labels := strings.Split(u.Hostname(), ".")
+	if !strings.HasSuffix(u.Hostname(), publicServiceDomain) || len(labels) < 5 || labels[1] == "private" {
+		return serviceURL, nil
+	}
+	host := labels[0] + ".private." + strings.Join(labels[1:], ".")
+	if u.Port() != "" {
+		host += ":" + u.Port()
+	}
+	u.Host = host
+	return u.String(), nil
+}
+
 func init() {
 	esv1beta1.Register(&providerIBM{}, &esv1beta1.SecretStoreProvider{
 		IBM: &esv1beta1.IBMProvider{},
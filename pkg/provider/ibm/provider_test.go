@@ -714,3 +714,63 @@ func ErrorContains(out error, want string) bool {
 	}
 	return strings.Contains(out.Error(), want)
 }
+
+func TestPrivateServiceURL(t *testing.T) {
+	tbl := []struct {
+		serviceURL string
+		expected   string
+	}{
+		{
+			serviceURL: "https://instance.us-south.secrets-manager.appdomain.cloud",
+			expected:   "https://instance.private.us-south.secrets-manager.appdomain.cloud",
+		},
+		{
+			serviceURL: "https://instance.private.us-south.secrets-manager.appdomain.cloud",
+			expected:   "https://instance.private.us-south.secrets-manager.appdomain.cloud",
+		},
+		{
+			serviceURL: "https://instance.us-south.secrets-manager.appdomain.cloud:8443/api",
+			expected:   "https://instance.private.us-south.secrets-manager.appdomain.cloud:8443/api",
+		},
+		{
+			// virtual private endpoints are used as they are
+			serviceURL: "https://secrets-manager.vpe.example.internal",
+			expected:   "https://secrets-manager.vpe.example.internal",
+		},
+	}
+	for _, row := range tbl {
+		got, err := privateServiceURL(row.serviceURL)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", row.serviceURL, err)
+		}
+		if got != row.expected {
+			t.Errorf("%s: expected %s, got %s", row.serviceURL, row.expected, got)
+		}
+	}
+}
+
+func TestIAMAuthEndpoint(t *testing.T) {
+	if got := iamAuthEndpoint(&esv1beta1.IBMProvider{}); got != iamEndpoint {
+		t.Errorf("expected %s, got %s", iamEndpoint, got)
+	}
+	if got := iamAuthEndpoint(&esv1beta1.IBMProvider{PrivateEndpoint: true}); got != privateIAMEndpoint {
+		t.Errorf("expected %s, got %s", privateIAMEndpoint, got)
+	}
+	custom := "https://iam.example.internal"
+	if got := iamAuthEndpoint(&esv1beta1.IBMProvider{PrivateEndpoint: true, IAMEndpoint: custom}); got != custom {
+		t.Errorf("expected %s, got %s", custom, got)
+	}
+
+	p := providerIBM{}
+	serviceURL := "my-url"
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				IBM: &esv1beta1.IBMProvider{ServiceURL: &serviceURL, IAMEndpoint: "iam"},
+			},
+		},
+	}
+	if err := p.ValidateStore(store); !ErrorContains(err, "invalid iamEndpoint") {
+		t.Errorf("expected invalid iamEndpoint error, got %v", err)
+	}
+}
@@ -18,12 +18,12 @@ import (
 	"encoding/json"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	"github.com/external-secrets/external-secrets/pkg/find"
-	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
@@ -50,11 +50,7 @@ func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretData
 }
 
 func (c *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
-	secret, err := c.userSecretClient.Get(ctx, ref.Key, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
-	}
-	return secret.Data, nil
+	return c.getData(ctx, ref.Key)
 }
 
 func (c *Client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
@@ -73,42 +69,115 @@ func (c *Client) findByTags(ctx context.Context, ref esv1beta1.ExternalSecretFin
 	if err != nil {
 		return nil, fmt.Errorf("unable to validate selector tags: %w", err)
 	}
-	secrets, err := c.userSecretClient.List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+	opts := metav1.ListOptions{LabelSelector: sel.String()}
+	if ref.MaxResults != nil {
+		// one more than the limit is enough to tell that it is exceeded
+		opts.Limit = int64(*ref.MaxResults) + 1
+	}
+	objects, err := c.listData(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to list secrets: %w", err)
+		return nil, err
 	}
 	data := make(map[string][]byte)
-	for _, secret := range secrets.Items {
-		jsonStr, err := json.Marshal(convertMap(secret.Data))
+	for _, obj := range objects {
+		if err := find.CheckLimit(ref, len(data)); err != nil {
+			return nil, err
+		}
+		jsonStr, err := json.Marshal(convertMap(obj.data))
 		if err != nil {
 			return nil, err
 		}
-		data[secret.Name] = jsonStr
+		data[obj.name] = jsonStr
 	}
-	return utils.ConvertKeys(ref.ConversionStrategy, data)
+	return data, nil
 }
 
 func (c *Client) findByName(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	secrets, err := c.userSecretClient.List(ctx, metav1.ListOptions{})
+	objects, err := c.listData(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("unable to list secrets: %w", err)
+		return nil, err
 	}
 	matcher, err := find.New(*ref.Name)
 	if err != nil {
 		return nil, err
 	}
 	data := make(map[string][]byte)
-	for _, secret := range secrets.Items {
-		if !matcher.MatchName(secret.Name) {
+	for _, obj := range objects {
+		if !matcher.MatchName(obj.name) {
 			continue
 		}
-		jsonStr, err := json.Marshal(convertMap(secret.Data))
+		if err := find.CheckLimit(ref, len(data)); err != nil {
+			return nil, err
+		}
+		jsonStr, err := json.Marshal(convertMap(obj.data))
+		if err != nil {
+			return nil, err
+		}
+		data[obj.name] = jsonStr
+	}
+	return data, nil
+}
+
+// object is the name and data of a Secret or ConfigMap.
+type object struct {
+	name string
+	data map[string][]byte
+}
+
+func (c *Client) fetchesConfigMaps() bool {
+	return c.store != nil && c.store.SourceType == esv1beta1.KubernetesSourceConfigMap
+}
+
+// getData returns the data of the Secret or ConfigMap with the given name.
+func (c *Client) getData(ctx context.Context, name string) (map[string][]byte, error) {
+	if c.fetchesConfigMaps() {
+		cm, err := c.userConfigMapClient.Get(ctx, name, metav1.GetOptions{})
 		if err != nil {
 			return nil, err
 		}
-		data[secret.Name] = jsonStr
+		return configMapData(cm), nil
+	}
+	secret, err := c.userSecretClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+// listData returns the Secrets or ConfigMaps matching opts.
+func (c *Client) listData(ctx context.Context, opts metav1.ListOptions) ([]object, error) {
+	if c.fetchesConfigMaps() {
+		cms, err := c.userConfigMapClient.List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list configmaps: %w", err)
+		}
+		objects := make([]object, 0, len(cms.Items))
+		for i := range cms.Items {
+			objects = append(objects, object{name: cms.Items[i].Name, data: configMapData(&cms.Items[i])})
+		}
+		return objects, nil
+	}
+	secrets, err := c.userSecretClient.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list secrets: %w", err)
+	}
+	objects := make([]object, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		objects = append(objects, object{name: secret.Name, data: secret.Data})
+	}
+	return objects, nil
+}
+
+// configMapData merges the data and binaryData of a ConfigMap.
+func configMapData(cm *corev1.ConfigMap) map[string][]byte {
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		data[k] = v
 	}
-	return utils.ConvertKeys(ref.ConversionStrategy, data)
+	return data
 }
 
 func (c Client) Close(ctx context.Context) error {
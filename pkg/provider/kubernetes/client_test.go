@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -258,6 +259,41 @@ func TestGetAllSecrets(t *testing.T) {
 				"other": []byte(`{"token":"bar"}`),
 			},
 		},
+		{
+			name: "exceed maxResults",
+			fields: fields{
+				Client: fakeClient{
+					t: t,
+					secretMap: map[string]corev1.Secret{
+						"mysec": {
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "mysec",
+							},
+							Data: map[string][]byte{
+								"token": []byte(`foo`),
+							},
+						},
+						"other": {
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "other",
+							},
+							Data: map[string][]byte{
+								"token": []byte(`bar`),
+							},
+						},
+					},
+				},
+			},
+			args: args{
+				ref: esv1beta1.ExternalSecretFind{
+					Name: &esv1beta1.FindName{
+						RegExp: ".*",
+					},
+					MaxResults: pointer.Int(1),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -277,3 +313,51 @@ func TestGetAllSecrets(t *testing.T) {
 		})
 	}
 }
+
+type fakeConfigMapClient struct {
+	configMaps map[string]corev1.ConfigMap
+}
+
+func (fk fakeConfigMapClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	cm, ok := fk.configMaps[name]
+	if !ok {
+		return nil, errors.New(errSomethingWentWrong)
+	}
+	return &cm, nil
+}
+
+func (fk fakeConfigMapClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error) {
+	list := &corev1.ConfigMapList{}
+	for _, v := range fk.configMaps {
+		list.Items = append(list.Items, v)
+	}
+	return list, nil
+}
+
+func TestConfigMapSource(t *testing.T) {
+	p := &Client{
+		store: &esv1beta1.KubernetesProvider{SourceType: esv1beta1.KubernetesSourceConfigMap},
+		userConfigMapClient: fakeConfigMapClient{configMaps: map[string]corev1.ConfigMap{
+			"app-config": {
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+				Data:       map[string]string{"log_level": "debug"},
+				BinaryData: map[string][]byte{"logo": {0xff, 0x00}},
+			},
+		}},
+	}
+
+	got, err := p.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "app-config"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"log_level": []byte("debug"), "logo": {0xff, 0x00}}, got)
+
+	value, err := p.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "app-config", Property: "log_level"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("debug"), value)
+
+	all, err := p.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "app-.*"}})
+	assert.NoError(t, err)
+	assert.Contains(t, all, "app-config")
+
+	_, err = p.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "missing"})
+	assert.Error(t, err)
+}
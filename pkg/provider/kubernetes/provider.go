@@ -39,6 +39,11 @@ type KClient interface {
 	List(ctx context.Context, opts metav1.ListOptions) (*corev1.SecretList, error)
 }
 
+type CMClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error)
+}
+
 type RClient interface {
 	Create(ctx context.Context, selfSubjectRulesReview *authv1.SelfSubjectRulesReview, opts metav1.CreateOptions) (*authv1.SelfSubjectRulesReview, error)
 }
@@ -59,6 +64,10 @@ type Client struct {
 	// userSecretClient is a client-go CoreV1().Secrets() client
 	// with user-defined scope.
 	userSecretClient KClient
+	// userConfigMapClient is a client-go CoreV1().ConfigMaps() client
+	// with user-defined scope, it is used instead of userSecretClient
+	// if the store fetches ConfigMaps.
+	userConfigMapClient CMClient
 	// userReviewClient is a SelfSubjectAccessReview client with
 	// user-defined scope.
 	userReviewClient RClient
@@ -136,6 +145,7 @@ func (p *Provider) newClient(ctx context.Context, store esv1beta1.GenericStore,
 		return nil, fmt.Errorf("error configuring clientset: %w", err)
 	}
 	client.userSecretClient = userClientset.CoreV1().Secrets(client.store.RemoteNamespace)
+	client.userConfigMapClient = userClientset.CoreV1().ConfigMaps(client.store.RemoteNamespace)
 	client.userReviewClient = userClientset.AuthorizationV1().SelfSubjectRulesReviews()
 	return client, nil
 }
@@ -102,6 +102,14 @@ func (provider *ProviderOnePassword) ValidateStore(store esv1beta1.GenericStore)
 	return validateStore(store)
 }
 
+// Capabilities returns the capabilities of the provider.
+func (provider *ProviderOnePassword) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
+
 func validateStore(store esv1beta1.GenericStore) error {
 	// check nils
 	storeSpec := store.GetSpec()
@@ -313,6 +321,9 @@ func (provider *ProviderOnePassword) getAllFields(item onepassword.Item, ref esv
 			}
 		}
 		if _, ok := secretData[field.Label]; !ok {
+			if err := find.CheckLimit(ref, len(secretData)); err != nil {
+				return err
+			}
 			secretData[field.Label] = []byte(field.Value)
 		}
 	}
@@ -364,6 +375,9 @@ func (provider *ProviderOnePassword) getAllFiles(item onepassword.Item, ref esv1
 			}
 		}
 		if _, ok := secretData[file.Name]; !ok {
+			if err := find.CheckLimit(ref, len(secretData)); err != nil {
+				return err
+			}
 			contents, err := provider.client.GetFileContent(file)
 			if err != nil {
 				return err
@@ -15,8 +15,12 @@ package fake
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
 
 	secrets "github.com/oracle/oci-go-sdk/v56/secrets"
+	vault "github.com/oracle/oci-go-sdk/v56/vault"
 )
 
 type OracleMockClient struct {
@@ -34,3 +38,44 @@ func (mc *OracleMockClient) WithValue(input secrets.GetSecretBundleByNameRequest
 		}
 	}
 }
+
+// WithSecretValues returns the value of the requested secret, missing secrets return an error.
+func (mc *OracleMockClient) WithSecretValues(values map[string]string) {
+	if mc != nil {
+		mc.getSecret = func(ctx context.Context, paramReq secrets.GetSecretBundleByNameRequest) (secrets.GetSecretBundleByNameResponse, error) {
+			value, ok := values[*paramReq.SecretName]
+			if !ok {
+				return secrets.GetSecretBundleByNameResponse{}, fmt.Errorf("secret %s not found", *paramReq.SecretName)
+			}
+			content := base64.StdEncoding.EncodeToString([]byte(value))
+			return secrets.GetSecretBundleByNameResponse{
+				SecretBundle: secrets.SecretBundle{
+					SecretBundleContent: secrets.Base64SecretBundleContentDetails{Content: &content},
+				},
+			}, nil
+		}
+	}
+}
+
+// OracleMockVaultClient returns the pages of secret summaries in order.
+type OracleMockVaultClient struct {
+	Pages    [][]vault.SecretSummary
+	Requests []vault.ListSecretsRequest
+}
+
+func (mc *OracleMockVaultClient) ListSecrets(ctx context.Context, request vault.ListSecretsRequest) (vault.ListSecretsResponse, error) {
+	mc.Requests = append(mc.Requests, request)
+	page := 0
+	if request.Page != nil {
+		page, _ = strconv.Atoi(*request.Page)
+	}
+	if page >= len(mc.Pages) {
+		return vault.ListSecretsResponse{}, nil
+	}
+	resp := vault.ListSecretsResponse{Items: mc.Pages[page]}
+	if page+1 < len(mc.Pages) {
+		next := strconv.Itoa(page + 1)
+		resp.OpcNextPage = &next
+	}
+	return resp, nil
+}
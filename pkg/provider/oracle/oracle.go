@@ -18,10 +18,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/oracle/oci-go-sdk/v56/common"
 	"github.com/oracle/oci-go-sdk/v56/common/auth"
 	"github.com/oracle/oci-go-sdk/v56/secrets"
+	"github.com/oracle/oci-go-sdk/v56/vault"
 	"github.com/tidwall/gjson"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -29,6 +31,7 @@ import (
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
 	"github.com/external-secrets/external-secrets/pkg/provider/aws/util"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
@@ -52,6 +55,9 @@ const (
 	errJSONSecretUnmarshal                   = "unable to unmarshal secret: %w"
 	errMissingKey                            = "missing Key in secret: %s"
 	errUnexpectedContent                     = "unexpected secret bundle content"
+	errMissingCompartment                    = "compartment is required to find secrets"
+	errUnexpectedFindOperator                = "unexpected find operator, name or tags is required"
+	errListSecrets                           = "could not list secrets: %w"
 )
 
 // https://github.com/external-secrets/external-secrets/issues/644
@@ -59,18 +65,102 @@ var _ esv1beta1.SecretsClient = &VaultManagementService{}
 var _ esv1beta1.Provider = &VaultManagementService{}
 
 type VaultManagementService struct {
-	Client VMInterface
-	vault  string
+	Client      VMInterface
+	VaultClient VaultInterface
+	vault       string
+	compartment string
 }
 
 type VMInterface interface {
 	GetSecretBundleByName(ctx context.Context, request secrets.GetSecretBundleByNameRequest) (secrets.GetSecretBundleByNameResponse, error)
 }
 
-// Empty GetAllSecrets.
+// VaultInterface lists the secrets of a vault.
+type VaultInterface interface {
+	ListSecrets(ctx context.Context, request vault.ListSecretsRequest) (vault.ListSecretsResponse, error)
+}
+
+// GetAllSecrets finds the active secrets of the vault in the compartment by name and tags.
+// Tags in the form namespace.key match defined tags, all other tags match freeform tags.
+// The path is matched as a prefix of the secret name.
 func (vms *VaultManagementService) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
-	// TO be implemented
-	return nil, fmt.Errorf("GetAllSecrets not implemented")
+	if utils.IsNil(vms.Client) || utils.IsNil(vms.VaultClient) {
+		return nil, fmt.Errorf(errUninitalizedOracleProvider)
+	}
+	if vms.compartment == "" {
+		return nil, fmt.Errorf(errMissingCompartment)
+	}
+	if ref.Name == nil && len(ref.Tags) == 0 {
+		return nil, fmt.Errorf(errUnexpectedFindOperator)
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+
+	secretMap := make(map[string][]byte)
+	var page *string
+	for {
+		resp, err := vms.VaultClient.ListSecrets(ctx, vault.ListSecretsRequest{
+			CompartmentId:  &vms.compartment,
+			VaultId:        &vms.vault,
+			LifecycleState: vault.SecretSummaryLifecycleStateActive,
+			Page:           page,
+		})
+		if err != nil {
+			return nil, fmt.Errorf(errListSecrets, util.SanitizeErr(err))
+		}
+		for _, summary := range resp.Items {
+			if summary.SecretName == nil {
+				continue
+			}
+			name := *summary.SecretName
+			if matcher != nil && !matcher.MatchName(name) {
+				continue
+			}
+			if ref.Path != nil && !strings.HasPrefix(name, *ref.Path) {
+				continue
+			}
+			if !matchesTags(summary, ref.Tags) {
+				continue
+			}
+			if err := find.CheckLimit(ref, len(secretMap)); err != nil {
+				return nil, err
+			}
+			secretMap[name], err = vms.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: name})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if resp.OpcNextPage == nil {
+			break
+		}
+		page = resp.OpcNextPage
+	}
+	return secretMap, nil
+}
+
+// matchesTags returns true if the secret has all tags, a tag in the form
+// namespace.key is looked up in the defined tags first.
+func matchesTags(summary vault.SecretSummary, tags map[string]string) bool {
+	for k, v := range tags {
+		if namespace, key, ok := strings.Cut(k, "."); ok {
+			if defined, ok := summary.DefinedTags[namespace][key]; ok {
+				if fmt.Sprint(defined) != v {
+					return false
+				}
+				continue
+			}
+		}
+		if freeform, ok := summary.FreeformTags[k]; !ok || freeform != v {
+			return false
+		}
+	}
+	return true
 }
 
 func (vms *VaultManagementService) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
@@ -160,9 +250,18 @@ func (vms *VaultManagementService) NewClient(ctx context.Context, store esv1beta
 
 	secretManagementService.SetRegion(oracleSpec.Region)
 
+	vaultClient, err := vault.NewVaultsClientWithConfigurationProvider(configurationProvider)
+	if err != nil {
+		return nil, fmt.Errorf(errOracleClient, err)
+	}
+
+	vaultClient.SetRegion(oracleSpec.Region)
+
 	return &VaultManagementService{
-		Client: secretManagementService,
-		vault:  oracleSpec.Vault,
+		Client:      secretManagementService,
+		VaultClient: vaultClient,
+		vault:       oracleSpec.Vault,
+		compartment: oracleSpec.Compartment,
 	}, nil
 }
 
@@ -290,6 +389,14 @@ func (vms *VaultManagementService) ValidateStore(store esv1beta1.GenericStore) e
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (vms *VaultManagementService) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
+
 func init() {
 	esv1beta1.Register(&VaultManagementService{}, &esv1beta1.SecretStoreProvider{
 		Oracle: &esv1beta1.OracleProvider{},
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	secrets "github.com/oracle/oci-go-sdk/v56/secrets"
+	vault "github.com/oracle/oci-go-sdk/v56/vault"
 	utilpointer "k8s.io/utils/pointer"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
@@ -304,3 +305,94 @@ func TestValidateStore(t *testing.T) {
 		}
 	}
 }
+
+func TestGetAllSecrets(t *testing.T) {
+	summary := func(name string, freeform map[string]string, defined map[string]map[string]interface{}) vault.SecretSummary {
+		return vault.SecretSummary{SecretName: utilpointer.StringPtr(name), FreeformTags: freeform, DefinedTags: defined}
+	}
+	pages := [][]vault.SecretSummary{
+		{
+			summary("app-db", map[string]string{"team": "a"}, nil),
+			summary("app-api", map[string]string{"team": "b"}, nil),
+		},
+		{
+			summary("other-db", nil, map[string]map[string]interface{}{"ops": {"env": "prod"}}),
+		},
+	}
+	values := map[string]string{"app-db": "db", "app-api": "api", "other-db": "other"}
+
+	tbl := []struct {
+		name        string
+		ref         esv1beta1.ExternalSecretFind
+		compartment string
+		expected    map[string][]byte
+		expectError string
+	}{
+		{
+			name:        "find by name across pages",
+			ref:         esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "db$"}},
+			compartment: "compartment-OCID",
+			expected:    map[string][]byte{"app-db": []byte("db"), "other-db": []byte("other")},
+		},
+		{
+			name:        "find by name with path",
+			ref:         esv1beta1.ExternalSecretFind{Path: utilpointer.StringPtr("app-"), Name: &esv1beta1.FindName{RegExp: ".*"}},
+			compartment: "compartment-OCID",
+			expected:    map[string][]byte{"app-db": []byte("db"), "app-api": []byte("api")},
+		},
+		{
+			name:        "find by freeform tags",
+			ref:         esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "b"}},
+			compartment: "compartment-OCID",
+			expected:    map[string][]byte{"app-api": []byte("api")},
+		},
+		{
+			name:        "find by defined tags",
+			ref:         esv1beta1.ExternalSecretFind{Tags: map[string]string{"ops.env": "prod"}},
+			compartment: "compartment-OCID",
+			expected:    map[string][]byte{"other-db": []byte("other")},
+		},
+		{
+			name:        "missing compartment",
+			ref:         esv1beta1.ExternalSecretFind{Tags: map[string]string{"team": "b"}},
+			expectError: errMissingCompartment,
+		},
+		{
+			name:        "missing find operator",
+			ref:         esv1beta1.ExternalSecretFind{},
+			compartment: "compartment-OCID",
+			expectError: errUnexpectedFindOperator,
+		},
+	}
+	for _, row := range tbl {
+		t.Run(row.name, func(t *testing.T) {
+			mockClient := &fakeoracle.OracleMockClient{}
+			mockClient.WithSecretValues(values)
+			vaultClient := &fakeoracle.OracleMockVaultClient{Pages: pages}
+			vms := &VaultManagementService{
+				Client:      mockClient,
+				VaultClient: vaultClient,
+				vault:       vaultOCID,
+				compartment: row.compartment,
+			}
+			out, err := vms.GetAllSecrets(context.Background(), row.ref)
+			if row.expectError != "" {
+				if err == nil || err.Error() != row.expectError {
+					t.Fatalf("expected error %q, got %v", row.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(out, row.expected) {
+				t.Errorf("expected %v, got %v", row.expected, out)
+			}
+			for _, req := range vaultClient.Requests {
+				if *req.CompartmentId != row.compartment || *req.VaultId != vaultOCID {
+					t.Errorf("unexpected request: %+v", req)
+				}
+			}
+		})
+	}
+}
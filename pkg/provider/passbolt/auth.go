@@ -0,0 +1,221 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passbolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/google/uuid"
+)
+
+const (
+	challengeVersion = "1.0.0"
+	challengeExpiry  = 2 * time.Minute
+	messageType      = "PGP MESSAGE"
+
+	errReadPrivateKey    = "unable to read private key: %w"
+	errNoPrivateKey      = "private key is missing"
+	errDecryptPrivateKey = "unable to decrypt private key: %w"
+	errReadServerKey     = "unable to read server key: %w"
+	errEncrypt           = "unable to encrypt message: %w"
+	errDecrypt           = "unable to decrypt message: %w"
+	errChallengeSigner   = "login challenge is not signed by the server"
+	errVerifyToken       = "login challenge contains an unexpected verify token"
+	errLogin             = "unable to login: %w"
+)
+
+// verifyResponse contains the public key of the server.
+type verifyResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	KeyData     string `json:"keydata"`
+}
+
+// challenge is exchanged encrypted and signed with the server during the JWT login.
+// The server responds with the verify token of the request and the tokens of the session.
+type challenge struct {
+	Version           string `json:"version"`
+	Domain            string `json:"domain"`
+	VerifyToken       string `json:"verify_token"`
+	VerifyTokenExpiry int64  `json:"verify_token_expiry,omitempty"`
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+}
+
+type loginRequest struct {
+	UserID    string `json:"user_id"`
+	Challenge string `json:"challenge"`
+}
+
+type loginResponse struct {
+	Challenge string `json:"challenge"`
+}
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// readPrivateKey reads the armored private key of the user
+// and decrypts it and its subkeys with the passphrase.
+func readPrivateKey(armored, passphrase []byte) (*openpgp.Entity, error) {
+	keys, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(armored))
+	if err != nil {
+		return nil, fmt.Errorf(errReadPrivateKey, err)
+	}
+	if len(keys) == 0 || keys[0].PrivateKey == nil {
+		return nil, fmt.Errorf(errNoPrivateKey)
+	}
+	key := keys[0]
+	if key.PrivateKey.Encrypted {
+		if err := key.PrivateKey.Decrypt(passphrase); err != nil {
+			return nil, fmt.Errorf(errDecryptPrivateKey, err)
+		}
+	}
+	for _, sub := range key.Subkeys {
+		if sub.PrivateKey != nil && sub.PrivateKey.Encrypted {
+			if err := sub.PrivateKey.Decrypt(passphrase); err != nil {
+				return nil, fmt.Errorf(errDecryptPrivateKey, err)
+			}
+		}
+	}
+	return key, nil
+}
+
+// encrypt encrypts the message for the recipient, signs it with the signer and armors it.
+func encrypt(msg []byte, to, signer *openpgp.Entity) (string, error) {
+	var buf bytes.Buffer
+	armored, err := armor.Encode(&buf, messageType, nil)
+	if err != nil {
+		return "", fmt.Errorf(errEncrypt, err)
+	}
+	w, err := openpgp.Encrypt(armored, []*openpgp.Entity{to}, signer, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf(errEncrypt, err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return "", fmt.Errorf(errEncrypt, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf(errEncrypt, err)
+	}
+	if err := armored.Close(); err != nil {
+		return "", fmt.Errorf(errEncrypt, err)
+	}
+	return buf.String(), nil
+}
+
+// decrypt decrypts the armored message with the private key of the user.
+// If signer is set the message must carry a valid signature of it.
+func decrypt(msg string, user, signer *openpgp.Entity) ([]byte, error) {
+	block, err := armor.Decode(strings.NewReader(msg))
+	if err != nil {
+		return nil, fmt.Errorf(errDecrypt, err)
+	}
+	keyring := openpgp.EntityList{user}
+	if signer != nil {
+		keyring = append(keyring, signer)
+	}
+	md, err := openpgp.ReadMessage(block.Body, keyring, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errDecrypt, err)
+	}
+	// the signature is checked once the body has been read completely.
+	body, err := io.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		return nil, fmt.Errorf(errDecrypt, err)
+	}
+	if signer == nil {
+		return body, nil
+	}
+	if !md.IsSigned || md.SignedBy == nil || md.SignedBy.Entity != signer {
+		return nil, fmt.Errorf(errChallengeSigner)
+	}
+	if md.SignatureError != nil {
+		return nil, fmt.Errorf(errDecrypt, md.SignatureError)
+	}
+	return body, nil
+}
+
+// login authenticates with the JWT login of Passbolt:
+// a challenge signed by the user and encrypted for the server
+// is exchanged for a challenge signed by the server that contains the access token.
+func (c *Client) login(ctx context.Context, userID string, user *openpgp.Entity) error {
+	var verify verifyResponse
+	if err := c.do(ctx, http.MethodGet, "/auth/verify.json", nil, nil, &verify); err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	serverKeys, err := openpgp.ReadArmoredKeyRing(strings.NewReader(verify.KeyData))
+	if err != nil {
+		return fmt.Errorf(errReadServerKey, err)
+	}
+	if len(serverKeys) == 0 {
+		return fmt.Errorf(errReadServerKey, fmt.Errorf("no key in response"))
+	}
+	server := serverKeys[0]
+
+	verifyToken := uuid.NewString()
+	msg, err := json.Marshal(challenge{
+		Version:           challengeVersion,
+		Domain:            c.host,
+		VerifyToken:       verifyToken,
+		VerifyTokenExpiry: time.Now().Add(challengeExpiry).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	encrypted, err := encrypt(msg, server, user)
+	if err != nil {
+		return err
+	}
+	var login loginResponse
+	req := loginRequest{UserID: userID, Challenge: encrypted}
+	if err := c.do(ctx, http.MethodPost, "/auth/jwt/login.json", nil, req, &login); err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	decrypted, err := decrypt(login.Challenge, user, server)
+	if err != nil {
+		return err
+	}
+	var resp challenge
+	if err := json.Unmarshal(decrypted, &resp); err != nil {
+		return fmt.Errorf(errLogin, err)
+	}
+	if resp.VerifyToken != verifyToken {
+		return fmt.Errorf(errVerifyToken)
+	}
+	c.user = user
+	c.accessToken = resp.AccessToken
+	c.refreshToken = resp.RefreshToken
+	return nil
+}
+
+// logout revokes the refresh token of the session.
+func (c *Client) logout(ctx context.Context) error {
+	if c.refreshToken == "" {
+		return nil
+	}
+	err := c.do(ctx, http.MethodPost, "/auth/jwt/logout.json", nil, logoutRequest{RefreshToken: c.refreshToken}, nil)
+	c.accessToken = ""
+	c.refreshToken = ""
+	return err
+}
@@ -0,0 +1,488 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passbolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	statusSuccess  = "success"
+	folderSep      = "/"
+
+	fieldName        = "name"
+	fieldUsername    = "username"
+	fieldURI         = "uri"
+	fieldDescription = "description"
+	fieldPassword    = "password"
+
+	errPassboltStore                       = "missing or invalid Passbolt SecretStore"
+	errMissingHost                         = "host is required"
+	errInvalidHost                         = "invalid host: %w"
+	errInvalidUserID                       = "auth.userID must be a UUID: %w"
+	errInvalidClusterStoreMissingNamespace = "missing namespace"
+	errFetchCredentials                    = "could not fetch credentials: %w"
+	errMissingCredentials                  = "missing credentials: %q"
+	errRequest                             = "request %s %s failed: %w"
+	errResponse                            = "request %s %s failed with status %d: %s"
+	errUnmarshalResponse                   = "unable to unmarshal response of %s %s: %w"
+	errFolderNotFound                      = "folder %q does not exist"
+	errAmbiguousFolder                     = "folder %q is ambiguous"
+	errAmbiguousResource                   = "resource name %q is ambiguous, use the resource ID instead"
+	errProperty                            = "property %q does not exist in resource %q"
+	errValidate                            = "unable to validate store: %w"
+	errVersionNotSupported                 = "versions are not supported by the Passbolt provider"
+	errTagsNotSupported                    = "find by tags is not supported by the Passbolt provider"
+)
+
+// errNotFound is returned by do if the server responds with 404.
+var errNotFound = errors.New("not found")
+
+// https://github.com/external-secrets/external-secrets/issues/644
+var _ esv1beta1.SecretsClient = &Client{}
+var _ esv1beta1.Provider = &Provider{}
+
+// Provider implements the esv1beta1.Provider interface for Passbolt.
+type Provider struct{}
+
+// Client retrieves resources and their secrets with the Passbolt API.
+type Client struct {
+	http         *http.Client
+	host         string
+	folderID     string
+	user         *openpgp.Entity
+	accessToken  string
+	refreshToken string
+}
+
+// response is the envelope of all Passbolt API responses.
+type response struct {
+	Header struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"header"`
+	Body json.RawMessage `json:"body"`
+}
+
+type resource struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Username       string `json:"username"`
+	URI            string `json:"uri"`
+	Description    string `json:"description"`
+	FolderParentID string `json:"folder_parent_id"`
+}
+
+type folder struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	FolderParentID string `json:"folder_parent_id"`
+}
+
+type secret struct {
+	Data string `json:"data"`
+}
+
+// secretData is the decrypted secret of resource types
+// that encrypt the description along with the password.
+type secretData struct {
+	Password    *string `json:"password"`
+	Description string  `json:"description"`
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		Passbolt: &esv1beta1.PassboltProvider{},
+	})
+}
+
+// NewClient logs in to Passbolt with the private GPG key of the user.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.Passbolt == nil {
+		return nil, fmt.Errorf(errPassboltStore)
+	}
+	passboltSpec := storeSpec.Provider.Passbolt
+	r := &resolver{
+		kube:      kube,
+		namespace: namespace,
+		storeKind: store.GetObjectKind().GroupVersionKind().Kind,
+	}
+	armoredKey, err := r.fetchSecretKey(ctx, passboltSpec.Auth.PrivateKeySecretRef)
+	if err != nil {
+		return nil, err
+	}
+	var passphrase []byte
+	if passboltSpec.Auth.PassphraseSecretRef != nil {
+		passphrase, err = r.fetchSecretKey(ctx, *passboltSpec.Auth.PassphraseSecretRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+	user, err := readPrivateKey(armoredKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		http: &http.Client{Timeout: defaultTimeout},
+		host: strings.TrimSuffix(passboltSpec.Host, "/"),
+	}
+	if err := c.login(ctx, passboltSpec.Auth.UserID, user); err != nil {
+		return nil, err
+	}
+	if passboltSpec.Folder != "" {
+		c.folderID, err = c.resolveFolder(ctx, passboltSpec.Folder)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// ValidateStore checks the Passbolt store configuration.
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.Passbolt == nil {
+		return fmt.Errorf(errPassboltStore)
+	}
+	passboltSpec := storeSpec.Provider.Passbolt
+	if passboltSpec.Host == "" {
+		return fmt.Errorf(errMissingHost)
+	}
+	if _, err := url.ParseRequestURI(passboltSpec.Host); err != nil {
+		return fmt.Errorf(errInvalidHost, err)
+	}
+	if _, err := uuid.Parse(passboltSpec.Auth.UserID); err != nil {
+		return fmt.Errorf(errInvalidUserID, err)
+	}
+	if err := utils.ValidateSecretSelector(store, passboltSpec.Auth.PrivateKeySecretRef); err != nil {
+		return err
+	}
+	if passboltSpec.Auth.PassphraseSecretRef != nil {
+		if err := utils.ValidateSecretSelector(store, *passboltSpec.Auth.PassphraseSecretRef); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access:   esv1beta1.SecretStoreReadOnly,
+		Features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind},
+	}
+}
+
+// GetSecret returns the password of the resource,
+// or another field of the resource if ref.Property is set.
+func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	data, err := c.GetSecretMap(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	property := ref.Property
+	if property == "" {
+		property = fieldPassword
+	}
+	val, ok := data[property]
+	if !ok {
+		return nil, fmt.Errorf(errProperty, property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap returns the name, username, uri, description and password of the resource.
+// The key is either the ID of the resource or its name in the folder of the store.
+func (c *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if ref.Version != "" {
+		return nil, fmt.Errorf(errVersionNotSupported)
+	}
+	res, err := c.getResource(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	password, description, err := c.getSecret(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		fieldName:        []byte(res.Name),
+		fieldUsername:    []byte(res.Username),
+		fieldURI:         []byte(res.URI),
+		fieldDescription: []byte(description),
+		fieldPassword:    []byte(password),
+	}, nil
+}
+
+// GetAllSecrets returns the passwords of the resources in a folder with matching names.
+// The folder is ref.Path if set, the folder of the store otherwise.
+func (c *Client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if len(ref.Tags) > 0 {
+		return nil, fmt.Errorf(errTagsNotSupported)
+	}
+	folderID := c.folderID
+	if ref.Path != nil {
+		var err error
+		folderID, err = c.resolveFolder(ctx, *ref.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		var err error
+		matcher, err = find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resources, err := c.listResources(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(resources))
+	for i := range resources {
+		res := &resources[i]
+		if matcher != nil && !matcher.MatchName(res.Name) {
+			continue
+		}
+		if _, ok := data[res.Name]; ok {
+			return nil, fmt.Errorf(errAmbiguousResource, res.Name)
+		}
+		if err := find.CheckLimit(ref, len(data)); err != nil {
+			return nil, err
+		}
+		password, _, err := c.getSecret(ctx, res)
+		if err != nil {
+			return nil, err
+		}
+		data[res.Name] = []byte(password)
+	}
+	return data, nil
+}
+
+// Validate checks that the access token of the client is accepted.
+func (c *Client) Validate() (esv1beta1.ValidationResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	if err := c.do(ctx, http.MethodGet, "/users/me.json", nil, nil, nil); err != nil {
+		return esv1beta1.ValidationResultError, fmt.Errorf(errValidate, err)
+	}
+	return esv1beta1.ValidationResultReady, nil
+}
+
+// Close logs out of Passbolt.
+func (c *Client) Close(ctx context.Context) error {
+	return c.logout(ctx)
+}
+
+// getResource returns the resource with the ID key,
+// or the resource named key in the folder of the store.
+func (c *Client) getResource(ctx context.Context, key string) (*resource, error) {
+	if _, err := uuid.Parse(key); err == nil {
+		var res resource
+		err := c.do(ctx, http.MethodGet, "/resources/"+key+".json", nil, nil, &res)
+		if errors.Is(err, errNotFound) {
+			return nil, esv1beta1.NoSecretErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &res, nil
+	}
+	resources, err := c.listResources(ctx, c.folderID)
+	if err != nil {
+		return nil, err
+	}
+	var found *resource
+	for i := range resources {
+		if resources[i].Name != key {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf(errAmbiguousResource, key)
+		}
+		found = &resources[i]
+	}
+	if found == nil {
+		return nil, esv1beta1.NoSecretErr
+	}
+	return found, nil
+}
+
+// listResources lists the resources in the folder, or all resources if folderID is empty.
+func (c *Client) listResources(ctx context.Context, folderID string) ([]resource, error) {
+	query := url.Values{}
+	if folderID != "" {
+		query.Set("filter[has-parent]", folderID)
+	}
+	var resources []resource
+	if err := c.do(ctx, http.MethodGet, "/resources.json", query, nil, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// getSecret returns the decrypted password and description of the resource.
+// Resource types that encrypt the description store the secret as JSON,
+// otherwise the secret is the password and the description is not encrypted.
+func (c *Client) getSecret(ctx context.Context, res *resource) (string, string, error) {
+	var s secret
+	err := c.do(ctx, http.MethodGet, "/secrets/resource/"+res.ID+".json", nil, nil, &s)
+	if errors.Is(err, errNotFound) {
+		return "", "", esv1beta1.NoSecretErr
+	}
+	if err != nil {
+		return "", "", err
+	}
+	decrypted, err := decrypt(s.Data, c.user, nil)
+	if err != nil {
+		return "", "", err
+	}
+	var data secretData
+	if err := json.Unmarshal(decrypted, &data); err == nil && data.Password != nil {
+		return *data.Password, data.Description, nil
+	}
+	return string(decrypted), res.Description, nil
+}
+
+// resolveFolder returns the ID of the folder with the path, e.g. "Team/Databases".
+func (c *Client) resolveFolder(ctx context.Context, path string) (string, error) {
+	var folders []folder
+	if err := c.do(ctx, http.MethodGet, "/folders.json", nil, nil, &folders); err != nil {
+		return "", err
+	}
+	parentID := ""
+	for _, name := range strings.Split(strings.Trim(path, folderSep), folderSep) {
+		var id string
+		for _, f := range folders {
+			if f.Name != name || f.FolderParentID != parentID {
+				continue
+			}
+			if id != "" {
+				return "", fmt.Errorf(errAmbiguousFolder, path)
+			}
+			id = f.ID
+		}
+		if id == "" {
+			return "", fmt.Errorf(errFolderNotFound, path)
+		}
+		parentID = id
+	}
+	return parentID, nil
+}
+
+// do sends a request to the Passbolt API and unmarshals the body of the response into out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	u := c.host + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var body io.Reader = http.NoBody
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf(errRequest, method, path, err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf(errRequest, method, path, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf(errRequest, method, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf(errRequest, method, path, err)
+	}
+	var envelope response
+	jsonErr := json.Unmarshal(raw, &envelope)
+	if resp.StatusCode != http.StatusOK || jsonErr != nil || envelope.Header.Status != statusSuccess {
+		return fmt.Errorf(errResponse, method, path, resp.StatusCode, envelope.Header.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Body, out); err != nil {
+		return fmt.Errorf(errUnmarshalResponse, method, path, err)
+	}
+	return nil
+}
+
+// resolver fetches the credentials referenced by the store.
+type resolver struct {
+	kube      kclient.Client
+	namespace string
+	storeKind string
+}
+
+func (r *resolver) fetchSecretKey(ctx context.Context, key esmeta.SecretKeySelector) ([]byte, error) {
+	objectKey := types.NamespacedName{
+		Name:      key.Name,
+		Namespace: r.namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if r.storeKind == esv1beta1.ClusterSecretStoreKind {
+		if key.Namespace == nil {
+			return nil, fmt.Errorf(errInvalidClusterStoreMissingNamespace)
+		}
+		objectKey.Namespace = *key.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := r.kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errFetchCredentials, err)
+	}
+	val, ok := secret.Data[key.Key]
+	if !ok || len(val) == 0 {
+		return nil, fmt.Errorf(errMissingCredentials, key.Key)
+	}
+	return val, nil
+}
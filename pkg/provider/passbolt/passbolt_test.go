@@ -0,0 +1,322 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package passbolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	userID      = "5f3a1c0e-3c4b-4e8e-9f5d-6b7a8c9d0e1f"
+	accessToken = "access-token"
+	passphrase  = "passphrase"
+	teamID      = "0b6c3f9e-1a2b-4c3d-8e4f-5a6b7c8d9e01"
+	databasesID = "0b6c3f9e-1a2b-4c3d-8e4f-5a6b7c8d9e02"
+	dbID        = "8e2d6a10-4f3b-4c5d-9a7e-1b2c3d4e5f01"
+	apiID       = "8e2d6a10-4f3b-4c5d-9a7e-1b2c3d4e5f02"
+	teamDBID    = "8e2d6a10-4f3b-4c5d-9a7e-1b2c3d4e5f03"
+)
+
+func newEntity(t *testing.T, name string) *openpgp.Entity {
+	e, err := openpgp.NewEntity(name, "", name+"@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	assert.NoError(t, err)
+	return e
+}
+
+func armorKey(t *testing.T, blockType string, serialize func(w *bytes.Buffer) error) []byte {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, blockType, nil)
+	assert.NoError(t, err)
+	var raw bytes.Buffer
+	assert.NoError(t, serialize(&raw))
+	_, err = w.Write(raw.Bytes())
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func writeBody(w http.ResponseWriter, body interface{}) {
+	b, _ := json.Marshal(body)
+	_, _ = w.Write([]byte(`{"header":{"status":"success","message":"OK"},"body":` + string(b) + `}`))
+}
+
+func newPassboltServer(t *testing.T, user *openpgp.Entity) *httptest.Server {
+	server := newEntity(t, "server")
+	serverKey := armorKey(t, openpgp.PublicKeyType, func(w *bytes.Buffer) error { return server.Serialize(w) })
+	resources := []resource{
+		{ID: dbID, Name: "db", Username: "admin", URI: "postgres://db.example.com", Description: "plain", FolderParentID: databasesID},
+		{ID: apiID, Name: "api", Username: "bot", FolderParentID: teamID},
+		{ID: teamDBID, Name: "db", Username: "team", FolderParentID: teamID},
+	}
+	secrets := map[string]string{
+		dbID:     "s3cr3t",
+		apiID:    `{"password":"apikey","description":"encrypted"}`,
+		teamDBID: "team",
+	}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/verify.json":
+			writeBody(w, verifyResponse{KeyData: string(serverKey)})
+			return
+		case "/auth/jwt/login.json":
+			var req loginRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			msg, err := decrypt(req.Challenge, server, user)
+			if err != nil || req.UserID != userID {
+				w.WriteHeader(http.StatusForbidden)
+				writeBody(w, nil)
+				return
+			}
+			var c challenge
+			_ = json.Unmarshal(msg, &c)
+			assert.Equal(t, srv.URL, c.Domain)
+			c.AccessToken = accessToken
+			c.RefreshToken = "refresh-token"
+			b, _ := json.Marshal(c)
+			encrypted, _ := encrypt(b, user, server)
+			writeBody(w, loginResponse{Challenge: encrypted})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"header":{"status":"error","message":"Authentication is required"},"body":null}`))
+			return
+		}
+		switch {
+		case r.URL.Path == "/users/me.json" || r.URL.Path == "/auth/jwt/logout.json":
+			writeBody(w, nil)
+		case r.URL.Path == "/folders.json":
+			writeBody(w, []folder{
+				{ID: teamID, Name: "Team"},
+				{ID: databasesID, Name: "Databases", FolderParentID: teamID},
+			})
+		case r.URL.Path == "/resources.json":
+			parent := r.URL.Query().Get("filter[has-parent]")
+			list := []resource{}
+			for _, res := range resources {
+				if parent == "" || res.FolderParentID == parent {
+					list = append(list, res)
+				}
+			}
+			writeBody(w, list)
+		case strings.HasPrefix(r.URL.Path, "/resources/"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/resources/"), ".json")
+			for _, res := range resources {
+				if res.ID == id {
+					writeBody(w, res)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/secrets/resource/"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/secrets/resource/"), ".json")
+			s, ok := secrets[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			encrypted, _ := encrypt([]byte(s), user, nil)
+			writeBody(w, secret{Data: encrypted})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(t *testing.T, folder string) esv1beta1.SecretsClient {
+	user := newEntity(t, "eso")
+	srv := newPassboltServer(t, user)
+	privateKey := armorKey(t, openpgp.PrivateKeyType, func(w *bytes.Buffer) error {
+		assert.NoError(t, user.PrivateKey.Encrypt([]byte(passphrase)))
+		for _, sub := range user.Subkeys {
+			assert.NoError(t, sub.PrivateKey.Encrypt([]byte(passphrase)))
+		}
+		return user.SerializePrivateWithoutSigning(w, nil)
+	})
+	kube := clientfake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "passbolt", Namespace: "default"},
+		Data: map[string][]byte{
+			"private.key": privateKey,
+			"passphrase":  []byte(passphrase),
+		},
+	}).Build()
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Passbolt: &esv1beta1.PassboltProvider{
+					Host:   srv.URL,
+					Folder: folder,
+					Auth: esv1beta1.PassboltAuth{
+						UserID:              userID,
+						PrivateKeySecretRef: esmeta.SecretKeySelector{Name: "passbolt", Key: "private.key"},
+						PassphraseSecretRef: &esmeta.SecretKeySelector{Name: "passbolt", Key: "passphrase"},
+					},
+				},
+			},
+		},
+	}
+	c, err := (&Provider{}).NewClient(context.Background(), store, kube, "default")
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, c.Close(context.Background()))
+	})
+	return c
+}
+
+func TestGetSecret(t *testing.T) {
+	c := newTestClient(t, "Team/Databases")
+	ctx := context.Background()
+
+	got, err := c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(got))
+
+	got, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "username"})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", string(got))
+
+	// resources outside of the store folder are retrieved by ID
+	got, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: apiID})
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey", string(got))
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "api"})
+	assert.True(t, errors.Is(err, esv1beta1.NoSecretErr))
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "8e2d6a10-4f3b-4c5d-9a7e-1b2c3d4e5fff"})
+	assert.True(t, errors.Is(err, esv1beta1.NoSecretErr))
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Property: "totp"})
+	assert.ErrorContains(t, err, `property "totp" does not exist`)
+}
+
+func TestGetSecretAmbiguousName(t *testing.T) {
+	c := newTestClient(t, "")
+	_, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"})
+	assert.EqualError(t, err, `resource name "db" is ambiguous, use the resource ID instead`)
+}
+
+func TestGetSecretMap(t *testing.T) {
+	c := newTestClient(t, "Team")
+	ctx := context.Background()
+
+	got, err := c.GetSecretMap(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "api"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"name":        []byte("api"),
+		"username":    []byte("bot"),
+		"uri":         []byte(""),
+		"description": []byte("encrypted"),
+		"password":    []byte("apikey"),
+	}, got)
+
+	got, err = c.GetSecretMap(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: dbID})
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", string(got["description"]))
+	assert.Equal(t, "postgres://db.example.com", string(got["uri"]))
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	c := newTestClient(t, "Team")
+	ctx := context.Background()
+
+	got, err := c.GetAllSecrets(ctx, esv1beta1.ExternalSecretFind{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"api": []byte("apikey"),
+		"db":  []byte("team"),
+	}, got)
+
+	got, err = c.GetAllSecrets(ctx, esv1beta1.ExternalSecretFind{Name: &esv1beta1.FindName{RegExp: "^a"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"api": []byte("apikey")}, got)
+
+	path := "Team/Databases"
+	got, err = c.GetAllSecrets(ctx, esv1beta1.ExternalSecretFind{Path: &path})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"db": []byte("s3cr3t")}, got)
+
+	path = "Team/Missing"
+	_, err = c.GetAllSecrets(ctx, esv1beta1.ExternalSecretFind{Path: &path})
+	assert.EqualError(t, err, `folder "Team/Missing" does not exist`)
+
+	_, err = c.GetAllSecrets(ctx, esv1beta1.ExternalSecretFind{Tags: map[string]string{"a": "b"}})
+	assert.EqualError(t, err, errTagsNotSupported)
+}
+
+func TestValidate(t *testing.T) {
+	c := newTestClient(t, "")
+	res, err := c.Validate()
+	assert.NoError(t, err)
+	assert.Equal(t, esv1beta1.ValidationResultReady, res)
+}
+
+func TestLoginWrongUser(t *testing.T) {
+	user := newEntity(t, "eso")
+	srv := newPassboltServer(t, newEntity(t, "other"))
+	c := &Client{http: srv.Client(), host: srv.URL}
+	err := c.login(context.Background(), userID, user)
+	assert.ErrorContains(t, err, "unable to login")
+}
+
+func TestValidateStore(t *testing.T) {
+	p := &Provider{}
+	auth := esv1beta1.PassboltAuth{
+		UserID:              userID,
+		PrivateKeySecretRef: esmeta.SecretKeySelector{Name: "passbolt", Key: "private.key"},
+	}
+	newStore := func(provider *esv1beta1.PassboltProvider) *esv1beta1.SecretStore {
+		return &esv1beta1.SecretStore{
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider: &esv1beta1.SecretStoreProvider{Passbolt: provider},
+			},
+		}
+	}
+	assert.NoError(t, p.ValidateStore(newStore(&esv1beta1.PassboltProvider{
+		Host: "https://passbolt.example.com",
+		Auth: auth,
+	})))
+	assert.EqualError(t, p.ValidateStore(newStore(&esv1beta1.PassboltProvider{
+		Auth: auth,
+	})), errMissingHost)
+	assert.ErrorContains(t, p.ValidateStore(newStore(&esv1beta1.PassboltProvider{
+		Host: "https://passbolt.example.com",
+		Auth: esv1beta1.PassboltAuth{
+			UserID:              "eso",
+			PrivateKeySecretRef: auth.PrivateKeySecretRef,
+		},
+	})), "auth.userID must be a UUID")
+}
@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package register
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// findSupport lists for every provider if its GetAllSecrets
+// implements dataFrom.find, new providers must be added here.
+var findSupport = map[string]bool{
+	"aws":                      true,
+	"azurekv":                  true,
+	"akeyless":                 false,
+	"vault":                    true,
+	"gcpsm":                    true,
+	"oracle":                   true,
+	"ibm":                      false,
+	"yandexcertificatemanager": false,
+	"yandexlockbox":            false,
+	"gitlab":                   false,
+	"alibaba":                  true,
+	"onepassword":              true,
+	"webhook":                  false,
+	"kubernetes":               true,
+	"fake":                     false,
+	"senhasegura":              false,
+	"doppler":                  true,
+	"etcd":                     true,
+	"sops":                     false,
+	"cyberarkccp":              false,
+	"passbolt":                 true,
+}
+
+func TestCapabilitiesMatchFindSupport(t *testing.T) {
+	spec := reflect.TypeOf(esv1beta1.SecretStoreProvider{})
+	for i := 0; i < spec.NumField(); i++ {
+		field := spec.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		t.Run(name, func(t *testing.T) {
+			want, ok := findSupport[name]
+			if !ok {
+				t.Fatalf("find support of provider %q is not listed", name)
+			}
+			provider, ok := esv1beta1.GetProviderByName(name)
+			if !ok {
+				t.Fatalf("provider %q is not registered", name)
+			}
+			// the store configures the provider with its zero value
+			storeProvider := &esv1beta1.SecretStoreProvider{}
+			reflect.ValueOf(storeProvider).Elem().Field(i).Set(reflect.New(field.Type.Elem()))
+			store := &esv1beta1.SecretStore{
+				Spec: esv1beta1.SecretStoreSpec{Provider: storeProvider},
+			}
+			got := false
+			for _, feature := range provider.Capabilities(store).Features {
+				if feature == esv1beta1.FeatureFind {
+					got = true
+				}
+			}
+			if got != want {
+				t.Errorf("Capabilities() advertises find: %v, GetAllSecrets implements find: %v", got, want)
+			}
+		})
+	}
+}
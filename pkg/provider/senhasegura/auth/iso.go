@@ -107,6 +107,7 @@ func (s *SenhaseguraIsoSession) GetIsoToken(clientID, clientSecret, systemURL st
 	u.Path = "/iso/oauth2/token"
 
 	tr := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
 		//nolint
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: ignoreSslCertificate},
 	}
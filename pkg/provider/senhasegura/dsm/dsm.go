@@ -166,6 +166,7 @@ func (dsm *DSM) FetchSecrets() (respObj IsoDappResponse, err error) {
 	u.Path = "/iso/dapp/application"
 
 	tr := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
 		//nolint
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: dsm.isoSession.IgnoreSslCertificate},
 	}
@@ -69,6 +69,13 @@ func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 	return validateStore(store)
 }
 
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
+
 func validateStore(store esv1beta1.GenericStore) error {
 	if store == nil {
 		return fmt.Errorf(errNilStore)
@@ -0,0 +1,221 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.mozilla.org/sops/v3/cmd/sops/formats"
+	"go.mozilla.org/sops/v3/decrypt"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errSOPSStore           = "missing or invalid SOPS SecretStore"
+	errMissingSource       = "exactly one of source.http, source.s3 or source.gcs is required"
+	errInvalidURL          = "invalid source.http.url: %w"
+	errMissingBucket       = "source bucket is required"
+	errDecrypt             = "unable to decrypt %q: %w"
+	errUnmarshalDocument   = "unable to unmarshal decrypted document %q: %w"
+	errProperty            = "property %q does not exist in key %q"
+	errFindNotSupported    = "find is not supported by the SOPS provider"
+	errUnexpectedStatus    = "unexpected status %d fetching %q"
+	errFetch               = "unable to fetch %q: %w"
+	errInvalidClusterStore = "missing namespace"
+	errFetchCredentials    = "could not fetch credentials: %w"
+	errMissingCredentials  = "missing credentials: %q"
+	errStoreKind           = "the SOPS provider can only be used with a ClusterSecretStore"
+	errDocumentTooLarge    = "document %q is larger than %d bytes"
+)
+
+// https://github.com/external-secrets/external-secrets/issues/644
+var _ esv1beta1.SecretsClient = &Client{}
+var _ esv1beta1.Provider = &Provider{}
+
+// Provider implements the esv1beta1.Provider interface for SOPS encrypted documents.
+type Provider struct{}
+
+// Client fetches and decrypts SOPS encrypted documents.
+type Client struct {
+	source fetcher
+	format esv1beta1.SOPSFormat
+}
+
+func init() {
+	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
+		SOPS: &esv1beta1.SOPSProvider{},
+	})
+}
+
+// NewClient constructs a client for the source of the store.
+func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube kclient.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.SOPS == nil {
+		return nil, fmt.Errorf(errSOPSStore)
+	}
+	// documents are decrypted with the keys of the controller, which must not be used
+	// to decrypt documents of other tenants into the namespace of a SecretStore
+	if store.GetObjectKind().GroupVersionKind().Kind != esv1beta1.ClusterSecretStoreKind {
+		return nil, fmt.Errorf(errStoreKind)
+	}
+	sopsSpec := storeSpec.Provider.SOPS
+	source, err := newFetcher(ctx, sopsSpec.Source, kube, store.GetObjectKind().GroupVersionKind().Kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		source: source,
+		format: sopsSpec.Format,
+	}, nil
+}
+
+// ValidateStore checks the SOPS store configuration.
+func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
+	storeSpec := store.GetSpec()
+	if storeSpec == nil || storeSpec.Provider == nil || storeSpec.Provider.SOPS == nil {
+		return fmt.Errorf(errSOPSStore)
+	}
+	if store.GetObjectKind().GroupVersionKind().Kind != esv1beta1.ClusterSecretStoreKind {
+		return fmt.Errorf(errStoreKind)
+	}
+	source := storeSpec.Provider.SOPS.Source
+	sources := 0
+	if source.HTTP != nil {
+		sources++
+		if _, err := url.ParseRequestURI(source.HTTP.URL); err != nil {
+			return fmt.Errorf(errInvalidURL, err)
+		}
+		if source.HTTP.BearerToken != nil {
+			if err := utils.ValidateSecretSelector(store, *source.HTTP.BearerToken); err != nil {
+				return err
+			}
+		}
+	}
+	for _, bucket := range []*esv1beta1.SOPSBucketSource{source.S3, source.GCS} {
+		if bucket == nil {
+			continue
+		}
+		sources++
+		if bucket.Bucket == "" {
+			return fmt.Errorf(errMissingBucket)
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf(errMissingSource)
+	}
+	return nil
+}
+
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
+
+// GetSecret returns the decrypted document,
+// or the value of a top-level key of it if ref.Property is set.
+func (c *Client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if ref.Property == "" {
+		return c.decrypt(ctx, ref.Key)
+	}
+	data, err := c.GetSecretMap(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := data[ref.Property]
+	if !ok {
+		return nil, fmt.Errorf(errProperty, ref.Property, ref.Key)
+	}
+	return val, nil
+}
+
+// GetSecretMap maps the top-level keys of the decrypted document to secret properties.
+// Values that are not strings are JSON encoded.
+func (c *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	plain, err := c.decrypt(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is valid YAML, so both formats are converted the same way
+	jsonDoc, err := yaml.YAMLToJSON(plain)
+	if err != nil {
+		return nil, fmt.Errorf(errUnmarshalDocument, ref.Key, err)
+	}
+	kv := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(jsonDoc, &kv); err != nil {
+		return nil, fmt.Errorf(errUnmarshalDocument, ref.Key, err)
+	}
+	secretData := make(map[string][]byte, len(kv))
+	for k, v := range kv {
+		var strVal string
+		if err := json.Unmarshal(v, &strVal); err == nil {
+			secretData[k] = []byte(strVal)
+			continue
+		}
+		secretData[k] = v
+	}
+	return secretData, nil
+}
+
+// GetAllSecrets is not supported: documents can not be listed across sources.
+func (c *Client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	return nil, fmt.Errorf(errFindNotSupported)
+}
+
+// Validate is not able to check the decryption keys without a document.
+func (c *Client) Validate() (esv1beta1.ValidationResult, error) {
+	return esv1beta1.ValidationResultUnknown, nil
+}
+
+func (c *Client) Close(ctx context.Context) error {
+	return nil
+}
+
+func (c *Client) decrypt(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.source.Fetch(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := decrypt.DataWithFormat(data, documentFormat(key, c.format))
+	if err != nil {
+		return nil, fmt.Errorf(errDecrypt, key, err)
+	}
+	return plain, nil
+}
+
+// documentFormat returns the configured format
+// or detects it from the file extension of the key.
+// Keys without a JSON extension are YAML documents.
+func documentFormat(key string, format esv1beta1.SOPSFormat) formats.Format {
+	switch {
+	case format == esv1beta1.SOPSFormatJSON:
+		return formats.Json
+	case format == esv1beta1.SOPSFormatYAML:
+		return formats.Yaml
+	case formats.IsJSONFile(strings.ToLower(key)):
+		return formats.Json
+	default:
+		return formats.Yaml
+	}
+}
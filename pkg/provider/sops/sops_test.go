@@ -0,0 +1,173 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+	"go.mozilla.org/sops/v3/cmd/sops/formats"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// testAgeKey decrypts testdata/db.enc.yaml.
+const testAgeKey = "AGE-SECRET-KEY-1SL9ZV5WPE0YCNHXFHL26W8PRYWWMDUUE3SM5FYCSARYYZAR4U2MQ266M53"
+
+func newTestClient(t *testing.T) *Client {
+	t.Setenv("SOPS_AGE_KEY", testAgeKey)
+	doc, err := os.ReadFile("testdata/db.enc.yaml")
+	assert.NoError(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/secrets/db.enc.yaml":
+			if r.Header.Get("Authorization") != "Bearer t0ken" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write(doc)
+		case "/secrets/plain.yaml":
+			_, _ = w.Write([]byte("username: admin\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &Client{
+		source: &httpFetcher{
+			client:      srv.Client(),
+			url:         srv.URL + "/secrets/",
+			bearerToken: "t0ken",
+		},
+	}
+}
+
+func TestGetSecret(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	got, err := c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db.enc.yaml", Property: "password"})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(got))
+
+	got, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db.enc.yaml"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(got), "username: admin")
+	assert.NotContains(t, string(got), "sops:")
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db.enc.yaml", Property: "host"})
+	assert.ErrorContains(t, err, `property "host" does not exist`)
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "missing.yaml"})
+	assert.True(t, errors.Is(err, esv1beta1.NoSecretErr))
+
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "plain.yaml"})
+	assert.ErrorContains(t, err, "unable to decrypt")
+}
+
+func TestGetSecretMap(t *testing.T) {
+	c := newTestClient(t)
+	got, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db.enc.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("s3cr3t"),
+		"port":     []byte("5432"),
+		"tls":      []byte(`{"enabled":true}`),
+	}, got)
+}
+
+func TestDocumentFormat(t *testing.T) {
+	assert.Equal(t, formats.Json, documentFormat("db.enc.JSON", ""))
+	assert.Equal(t, formats.Yaml, documentFormat("db.enc.yaml", ""))
+	assert.Equal(t, formats.Yaml, documentFormat("db", ""))
+	assert.Equal(t, formats.Json, documentFormat("db", esv1beta1.SOPSFormatJSON))
+}
+
+type fakeS3 struct {
+	s3iface.S3API
+	err error
+}
+
+func (f *fakeS3) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	return nil, f.err
+}
+
+func TestS3Fetcher(t *testing.T) {
+	f := &s3Fetcher{client: &fakeS3{err: awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)}}
+	_, err := f.Fetch(context.Background(), "db.yaml")
+	assert.True(t, errors.Is(err, esv1beta1.NoSecretErr))
+
+	f = &s3Fetcher{client: &fakeS3{err: errors.New("access denied")}}
+	_, err = f.Fetch(context.Background(), "db.yaml")
+	assert.ErrorContains(t, err, "access denied")
+}
+
+func TestValidateStore(t *testing.T) {
+	p := &Provider{}
+	newStore := func(source esv1beta1.SOPSSource) *esv1beta1.ClusterSecretStore {
+		return &esv1beta1.ClusterSecretStore{
+			TypeMeta: metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider: &esv1beta1.SecretStoreProvider{
+					SOPS: &esv1beta1.SOPSProvider{Source: source},
+				},
+			},
+		}
+	}
+	assert.NoError(t, p.ValidateStore(newStore(esv1beta1.SOPSSource{
+		HTTP: &esv1beta1.SOPSHTTPSource{URL: "https://git.example.com/raw/main/secrets"},
+	})))
+	assert.NoError(t, p.ValidateStore(newStore(esv1beta1.SOPSSource{
+		S3: &esv1beta1.SOPSBucketSource{Bucket: "secrets"},
+	})))
+	assert.ErrorContains(t, p.ValidateStore(newStore(esv1beta1.SOPSSource{
+		HTTP: &esv1beta1.SOPSHTTPSource{URL: "not a url"},
+	})), "invalid source.http.url")
+	assert.EqualError(t, p.ValidateStore(newStore(esv1beta1.SOPSSource{
+		GCS: &esv1beta1.SOPSBucketSource{},
+	})), errMissingBucket)
+	assert.EqualError(t, p.ValidateStore(newStore(esv1beta1.SOPSSource{})), errMissingSource)
+
+	// the keys of the controller must not decrypt documents for a single namespace
+	namespaced := &esv1beta1.SecretStore{
+		TypeMeta: metav1.TypeMeta{Kind: esv1beta1.SecretStoreKind},
+		Spec:     newStore(esv1beta1.SOPSSource{S3: &esv1beta1.SOPSBucketSource{Bucket: "secrets"}}).Spec,
+	}
+	assert.EqualError(t, p.ValidateStore(namespaced), errStoreKind)
+	_, err := p.NewClient(context.Background(), namespaced, nil, "default")
+	assert.EqualError(t, err, errStoreKind)
+}
+
+func TestReadDocument(t *testing.T) {
+	doc, err := readDocument(strings.NewReader("data"), "db.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(doc))
+
+	_, err = readDocument(strings.NewReader(strings.Repeat("a", maxDocumentSize+1)), "db.yaml")
+	assert.ErrorContains(t, err, "larger than")
+}
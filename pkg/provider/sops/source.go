@@ -0,0 +1,203 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"golang.org/x/oauth2/google"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	gcsReadOnlyScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+	// httpTimeout limits the time to fetch a document from an HTTP source.
+	httpTimeout = 30 * time.Second
+	// maxDocumentSize limits the size of a fetched document.
+	// Encrypted documents are larger than the Secret created from them,
+	// which can not exceed 1MiB.
+	maxDocumentSize = 4 << 20
+)
+
+// fetcher returns the encrypted document of a key.
+// It returns esv1beta1.NoSecretErr if the document does not exist.
+type fetcher interface {
+	Fetch(ctx context.Context, key string) ([]byte, error)
+}
+
+func newFetcher(ctx context.Context, source esv1beta1.SOPSSource, kube kclient.Client, storeKind, namespace string) (fetcher, error) {
+	switch {
+	case source.HTTP != nil:
+		f := &httpFetcher{
+			client: &http.Client{Timeout: httpTimeout},
+			url:    source.HTTP.URL,
+		}
+		if source.HTTP.BearerToken != nil {
+			token, err := fetchSecretKey(ctx, kube, storeKind, namespace, *source.HTTP.BearerToken)
+			if err != nil {
+				return nil, err
+			}
+			f.bearerToken = string(token)
+		}
+		return f, nil
+	case source.S3 != nil:
+		sess, err := session.NewSessionWithOptions(session.Options{
+			Config:            aws.Config{Region: aws.String(source.S3.Region)},
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &s3Fetcher{
+			client: s3.New(sess),
+			bucket: source.S3.Bucket,
+			prefix: source.S3.Prefix,
+		}, nil
+	case source.GCS != nil:
+		client, err := google.DefaultClient(ctx, gcsReadOnlyScope)
+		if err != nil {
+			return nil, err
+		}
+		return &gcsFetcher{
+			client: client,
+			bucket: source.GCS.Bucket,
+			prefix: source.GCS.Prefix,
+		}, nil
+	}
+	return nil, fmt.Errorf(errMissingSource)
+}
+
+// httpFetcher appends the key to the URL.
+type httpFetcher struct {
+	client      *http.Client
+	url         string
+	bearerToken string
+}
+
+func (f *httpFetcher) Fetch(ctx context.Context, key string) ([]byte, error) {
+	reqURL := strings.TrimSuffix(f.url, "/") + "/" + strings.TrimPrefix(key, "/")
+	return get(ctx, f.client, reqURL, f.bearerToken, key)
+}
+
+// gcsFetcher reads the key below prefix from a GCS bucket using the JSON API.
+type gcsFetcher struct {
+	client *http.Client
+	bucket string
+	prefix string
+}
+
+func (f *gcsFetcher) Fetch(ctx context.Context, key string) ([]byte, error) {
+	reqURL := fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o/%s?alt=media",
+		url.PathEscape(f.bucket), url.PathEscape(f.prefix+key))
+	return get(ctx, f.client, reqURL, "", key)
+}
+
+func get(ctx context.Context, client *http.Client, reqURL, bearerToken, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf(errFetch, key, err)
+	}
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(errFetch, key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf(errUnexpectedStatus, resp.StatusCode, key)
+	}
+	return readDocument(resp.Body, key)
+}
+
+// s3Fetcher reads the key below prefix from an S3 bucket.
+type s3Fetcher struct {
+	client s3iface.S3API
+	bucket string
+	prefix string
+}
+
+func (f *s3Fetcher) Fetch(ctx context.Context, key string) ([]byte, error) {
+	out, err := f.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(f.bucket),
+		Key:    aws.String(f.prefix + key),
+	})
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errFetch, key, err)
+	}
+	defer out.Body.Close()
+	return readDocument(out.Body, key)
+}
+
+// readDocument reads at most maxDocumentSize bytes from r.
+func readDocument(r io.Reader, key string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf(errFetch, key, err)
+	}
+	if len(data) > maxDocumentSize {
+		return nil, fmt.Errorf(errDocumentTooLarge, key, maxDocumentSize)
+	}
+	return data, nil
+}
+
+func fetchSecretKey(ctx context.Context, kube kclient.Client, storeKind, namespace string, key esmeta.SecretKeySelector) ([]byte, error) {
+	objectKey := types.NamespacedName{
+		Name:      key.Name,
+		Namespace: namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if storeKind == esv1beta1.ClusterSecretStoreKind {
+		if key.Namespace == nil {
+			return nil, fmt.Errorf(errInvalidClusterStore)
+		}
+		objectKey.Namespace = *key.Namespace
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errFetchCredentials, err)
+	}
+	val, ok := secret.Data[key.Key]
+	if !ok || len(val) == 0 {
+		return nil, fmt.Errorf(errMissingCredentials, key.Key)
+	}
+	return val, nil
+}
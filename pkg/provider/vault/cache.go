@@ -19,10 +19,13 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 type clientCache struct {
@@ -30,6 +33,8 @@ type clientCache struct {
 	Size        int
 	initialized bool
 	mu          sync.Mutex
+	// entries is updated after each change, so it can be read without the lock.
+	entries atomic.Int64
 }
 
 type clientCacheKey struct {
@@ -41,6 +46,7 @@ type clientCacheKey struct {
 type clientCacheValue struct {
 	ResourceVersion string
 	Client          Client
+	Created         time.Time
 }
 
 func (c *clientCache) initialize() error {
@@ -56,13 +62,15 @@ func (c *clientCache) initialize() error {
 }
 
 func (c *clientCache) get(ctx context.Context, store esv1beta1.GenericStore, key clientCacheKey) (Client, bool, error) {
+	defer c.updateEntries()
 	value, ok := c.cache.Get(key)
 	if ok {
 		cachedClient := value.(clientCacheValue)
-		if cachedClient.ResourceVersion == store.GetObjectMeta().ResourceVersion {
+		if cachedClient.ResourceVersion == store.GetObjectMeta().ResourceVersion && !utils.CredentialsExpired(store, cachedClient.Created) {
 			return cachedClient.Client, true, nil
 		}
 		// revoke token and clear old item from cache if resource has been updated
+		// or the credentials refresh interval of the store passed
 		err := revokeTokenIfValid(ctx, cachedClient.Client)
 		if err != nil {
 			return nil, false, err
@@ -73,6 +81,7 @@ func (c *clientCache) get(ctx context.Context, store esv1beta1.GenericStore, key
 }
 
 func (c *clientCache) add(ctx context.Context, store esv1beta1.GenericStore, key clientCacheKey, client Client) error {
+	defer c.updateEntries()
 	// don't let the LRU cache evict items
 	// remove the oldest item manually when needed so we can do some cleanup
 	for c.cache.Len() >= c.Size {
@@ -86,17 +95,53 @@ func (c *clientCache) add(ctx context.Context, store esv1beta1.GenericStore, key
 			return fmt.Errorf(errVaultRevokeToken, err)
 		}
 	}
-	evicted := c.cache.Add(key, clientCacheValue{ResourceVersion: store.GetObjectMeta().ResourceVersion, Client: client})
+	evicted := c.cache.Add(key, clientCacheValue{ResourceVersion: store.GetObjectMeta().ResourceVersion, Client: client, Created: time.Now()})
 	if evicted {
 		return errors.New(errVaultCacheEviction)
 	}
 	return nil
 }
 
+// removeStore removes all clients of a store from the cache and revokes their tokens.
+// Clients of a referent ClusterSecretStore are cached per namespace, so there may be more than one.
+func (c *clientCache) removeStore(ctx context.Context, store esv1beta1.GenericStore) error {
+	defer c.updateEntries()
+	kind := store.GetTypeMeta().Kind
+	for _, k := range c.cache.Keys() {
+		key := k.(clientCacheKey)
+		if key.Name != store.GetObjectMeta().Name || key.Kind != kind {
+			continue
+		}
+		if kind != esv1beta1.ClusterSecretStoreKind && key.Namespace != store.GetObjectMeta().Namespace {
+			continue
+		}
+		value, ok := c.cache.Peek(key)
+		if !ok {
+			continue
+		}
+		c.cache.Remove(key)
+		err := revokeTokenIfValid(ctx, value.(clientCacheValue).Client)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *clientCache) contains(key clientCacheKey) bool {
 	return c.cache.Contains(key)
 }
 
+// len returns the number of cached clients, it does not wait for the lock
+// held while a client is created.
+func (c *clientCache) len() int {
+	return int(c.entries.Load())
+}
+
+func (c *clientCache) updateEntries() {
+	c.entries.Store(int64(c.cache.Len()))
+}
+
 func (c *clientCache) lock() {
 	c.mu.Lock()
 }
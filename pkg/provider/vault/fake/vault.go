@@ -181,6 +181,11 @@ func (c *VaultClient) SetNamespace(namespace string) {
 	c.MockSetNamespace(namespace)
 }
 
+// AddHeader ignores headers if MockAddHeader is not set,
+// as every client adds a User-Agent header.
 func (c *VaultClient) AddHeader(key, value string) {
+	if c.MockAddHeader == nil {
+		return
+	}
 	c.MockAddHeader(key, value)
 }
@@ -23,8 +23,11 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/go-logr/logr"
 	vault "github.com/hashicorp/vault/api"
@@ -44,13 +47,18 @@ import (
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/credentialfile"
+	"github.com/external-secrets/external-secrets/pkg/diagnostics"
 	"github.com/external-secrets/external-secrets/pkg/find"
+	"github.com/external-secrets/external-secrets/pkg/useragent"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 var (
-	_                esv1beta1.Provider      = &connector{}
-	_                esv1beta1.SecretsClient = &client{}
+	_                esv1beta1.Provider              = &connector{}
+	_                esv1beta1.SecretsClient         = &client{}
+	_                esv1beta1.SecretKeysClient      = &client{}
+	_                esv1beta1.MetadataSecretsClient = &client{}
 	EnableCache      bool
 	VaultClientCache clientCache
 )
@@ -78,6 +86,8 @@ const (
 	errJwtNoTokenSource     = "neither `secretRef` nor `kubernetesServiceAccountToken` was supplied as token source for jwt authentication"
 	errUnsupportedKvVersion = "cannot perform find operations with kv version v1"
 	errNotFound             = "secret not found"
+	errUnsupportedSubkeys   = "cannot list secret keys with kv version v1"
+	errSubkeysFormat        = "subkeys not in expected format"
 
 	errGetKubeSA             = "cannot get Kubernetes service account %q: %w"
 	errGetKubeSASecrets      = "cannot find secrets bound to service account: %q"
@@ -89,6 +99,7 @@ const (
 	errConfigMapFmt  = "cannot find config map data for key: %q"
 
 	errClientTLSAuth = "error from Client TLS Auth: %q"
+	errClientTLS     = "unable to load client TLS certificate: %w"
 
 	errVaultRevokeToken = "error while revoking token: %w"
 
@@ -106,8 +117,16 @@ const (
 	errInvalidJwtK8sSA   = "invalid Auth.Jwt.KubernetesServiceAccountToken.ServiceAccountRef: %w"
 	errInvalidKubeSA     = "invalid Auth.Kubernetes.ServiceAccountRef: %w"
 	errInvalidKubeSec    = "invalid Auth.Kubernetes.SecretRef: %w"
+	errInvalidKubeTpl    = "invalid Auth.Kubernetes template: %w"
 	errInvalidLdapSec    = "invalid Auth.Ldap.SecretRef: %w"
 	errInvalidTokenRef   = "invalid Auth.TokenSecretRef: %w"
+	errInvalidTokenFile  = "invalid Auth.TokenFile: %w"
+	errTokenFileKind     = "Auth.TokenFile is only supported by ClusterSecretStores"
+	errReadTokenFile     = "unable to read token file %q: %w"
+	errEmptyTokenFile    = "token file %q is empty"
+
+	errInvalidClientTLSCert = "invalid ClientTLS.CertSecretRef: %w"
+	errInvalidClientTLSKey  = "invalid ClientTLS.KeySecretRef: %w"
 )
 
 // https://github.com/external-secrets/external-secrets/issues/644
@@ -197,6 +216,7 @@ type client struct {
 }
 
 func init() {
+	diagnostics.RegisterClientCache("vault", VaultClientCache.len)
 	esv1beta1.Register(&connector{
 		newVaultClient: newVaultClient,
 	}, &esv1beta1.SecretStoreProvider{
@@ -225,8 +245,10 @@ func newVaultClient(c *vault.Config) (Client, error) {
 	return out, nil
 }
 
-func getVaultClient(ctx context.Context, c *connector, store esv1beta1.GenericStore, cfg *vault.Config) (Client, error) {
-	isStaticToken := store.GetSpec().Provider.Vault.Auth.TokenSecretRef != nil
+func getVaultClient(ctx context.Context, c *connector, store esv1beta1.GenericStore, cfg *vault.Config, namespace string) (Client, error) {
+	// tokens from a file are owned by the process writing the file,
+	// they must be read again for every client.
+	isStaticToken := store.GetSpec().Provider.Vault.Auth.TokenSecretRef != nil || store.GetSpec().Provider.Vault.Auth.TokenFile != nil
 	useCache := EnableCache && !isStaticToken
 
 	if useCache {
@@ -244,6 +266,11 @@ func getVaultClient(ctx context.Context, c *connector, store esv1beta1.GenericSt
 		Namespace: store.GetObjectMeta().Namespace,
 		Kind:      store.GetTypeMeta().Kind,
 	}
+	// the credentials of a referent ClusterSecretStore depend on the namespace
+	// of the ExternalSecret, so the clients must not be shared across namespaces.
+	if key.Kind == esv1beta1.ClusterSecretStoreKind && isReferentSpec(store.GetSpec().Provider.Vault) {
+		key.Namespace = namespace
+	}
 	if useCache {
 		client, ok, err := VaultClientCache.get(ctx, store, key)
 		if err != nil {
@@ -308,7 +335,7 @@ func (c *connector) newClient(ctx context.Context, store esv1beta1.GenericStore,
 		return nil, err
 	}
 
-	client, err := getVaultClient(ctx, c, store, cfg)
+	client, err := getVaultClient(ctx, c, store, cfg, namespace)
 	if err != nil {
 		return nil, fmt.Errorf(errVaultClient, err)
 	}
@@ -317,7 +344,11 @@ func (c *connector) newClient(ctx context.Context, store esv1beta1.GenericStore,
 		client.SetNamespace(*vaultSpec.Namespace)
 	}
 
-	if vaultSpec.ReadYourWrites && vaultSpec.ForwardInconsistent {
+	// only the first User-Agent header is sent, so cached clients do not need to remove it
+	client.AddHeader("User-Agent", useragent.ForStore(store))
+
+	// ForwardInconsistent implies ReadYourWrites, see newConfig
+	if vaultSpec.ForwardInconsistent {
 		client.AddHeader("X-Vault-Inconsistent", "forward-active-node")
 	}
 	vStore.client = client
@@ -365,6 +396,14 @@ func (c *connector) ValidateStore(store esv1beta1.GenericStore) error {
 			return fmt.Errorf(errInvalidCertSec, err)
 		}
 	}
+	if p.ClientTLS != nil {
+		if err := utils.ValidateSecretSelector(store, p.ClientTLS.CertSecretRef); err != nil {
+			return fmt.Errorf(errInvalidClientTLSCert, err)
+		}
+		if err := utils.ValidateSecretSelector(store, p.ClientTLS.KeySecretRef); err != nil {
+			return fmt.Errorf(errInvalidClientTLSKey, err)
+		}
+	}
 	if p.Auth.Jwt != nil {
 		if p.Auth.Jwt.SecretRef != nil {
 			if err := utils.ValidateReferentSecretSelector(store, *p.Auth.Jwt.SecretRef); err != nil {
@@ -389,6 +428,11 @@ func (c *connector) ValidateStore(store esv1beta1.GenericStore) error {
 				return fmt.Errorf(errInvalidKubeSec, err)
 			}
 		}
+		for _, tpl := range []string{p.Auth.Kubernetes.Path, p.Auth.Kubernetes.Role} {
+			if _, err := renderAuthTemplate(tpl, ""); err != nil {
+				return fmt.Errorf(errInvalidKubeTpl, err)
+			}
+		}
 	}
 	if p.Auth.Ldap != nil {
 		if err := utils.ValidateReferentSecretSelector(store, p.Auth.Ldap.SecretRef); err != nil {
@@ -400,9 +444,50 @@ func (c *connector) ValidateStore(store esv1beta1.GenericStore) error {
 			return fmt.Errorf(errInvalidTokenRef, err)
 		}
 	}
+	if p.Auth.TokenFile != nil {
+		if store.GetObjectKind().GroupVersionKind().Kind != esv1beta1.ClusterSecretStoreKind {
+			return errors.New(errTokenFileKind)
+		}
+		if err := credentialfile.Validate(p.Auth.TokenFile.Path); err != nil {
+			return fmt.Errorf(errInvalidTokenFile, err)
+		}
+	}
 	return nil
 }
 
+// InvalidateClients removes the cached clients of a store,
+// so the next client is built with the current credentials.
+func (c *connector) InvalidateClients(ctx context.Context, store esv1beta1.GenericStore) {
+	if !EnableCache {
+		return
+	}
+	VaultClientCache.lock()
+	defer VaultClientCache.unlock()
+	if !VaultClientCache.initialized {
+		return
+	}
+	if err := VaultClientCache.removeStore(ctx, store); err != nil {
+		ctrl.Log.WithName("provider").WithName("vault").Error(err, "unable to invalidate cached clients", "store", store.GetName())
+	}
+}
+
+// Capabilities returns the capabilities of the provider.
+// Secrets can only be listed with the KV secrets engine version 2.
+func (c *connector) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	caps := esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+	if store == nil || store.GetSpec() == nil || store.GetSpec().Provider == nil || store.GetSpec().Provider.Vault == nil {
+		return caps
+	}
+	version := store.GetSpec().Provider.Vault.Version
+	caps.ProviderVersion = string(version)
+	if version != esv1beta1.VaultKVStoreV1 {
+		caps.Features = []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind}
+	}
+	return caps
+}
+
 // Empty GetAllSecrets.
 // GetAllSecrets
 // First load all secrets from secretStore path configuration.
@@ -413,19 +498,20 @@ func (v *client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecret
 	}
 	searchPath := ""
 	if ref.Path != nil {
-		searchPath = *ref.Path + "/"
+		// the path may end with a slash, e.g. to match an allowed key prefix
+		searchPath = strings.TrimSuffix(*ref.Path, "/") + "/"
 	}
 	potentialSecrets, err := v.listSecrets(ctx, searchPath)
 	if err != nil {
 		return nil, err
 	}
 	if ref.Name != nil {
-		return v.findSecretsFromName(ctx, potentialSecrets, *ref.Name)
+		return v.findSecretsFromName(ctx, potentialSecrets, ref)
 	}
-	return v.findSecretsFromTags(ctx, potentialSecrets, ref.Tags)
+	return v.findSecretsFromTags(ctx, potentialSecrets, ref)
 }
 
-func (v *client) findSecretsFromTags(ctx context.Context, candidates []string, tags map[string]string) (map[string][]byte, error) {
+func (v *client) findSecretsFromTags(ctx context.Context, candidates []string, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 	secrets := make(map[string][]byte)
 	for _, name := range candidates {
 		match := true
@@ -433,7 +519,7 @@ func (v *client) findSecretsFromTags(ctx context.Context, candidates []string, t
 		if err != nil {
 			return nil, err
 		}
-		for tk, tv := range tags {
+		for tk, tv := range ref.Tags {
 			p, ok := metadata[tk]
 			if !ok || p != tv {
 				match = false
@@ -441,6 +527,9 @@ func (v *client) findSecretsFromTags(ctx context.Context, candidates []string, t
 			}
 		}
 		if match {
+			if err := find.CheckLimit(ref, len(secrets)); err != nil {
+				return nil, err
+			}
 			secret, err := v.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: name})
 			if err != nil {
 				return nil, err
@@ -453,15 +542,18 @@ func (v *client) findSecretsFromTags(ctx context.Context, candidates []string, t
 	return secrets, nil
 }
 
-func (v *client) findSecretsFromName(ctx context.Context, candidates []string, ref esv1beta1.FindName) (map[string][]byte, error) {
+func (v *client) findSecretsFromName(ctx context.Context, candidates []string, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 	secrets := make(map[string][]byte)
-	matcher, err := find.New(ref)
+	matcher, err := find.New(*ref.Name)
 	if err != nil {
 		return nil, err
 	}
 	for _, name := range candidates {
 		ok := matcher.MatchName(name)
 		if ok {
+			if err := find.CheckLimit(ref, len(secrets)); err != nil {
+				return nil, err
+			}
 			secret, err := v.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: name})
 			if err != nil {
 				return nil, err
@@ -545,36 +637,52 @@ func (v *client) readSecretMetadata(ctx context.Context, path string) (map[strin
 //  2. get a key from the secret.
 //     Nested values are supported by specifying a gjson expression
 func (v *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	data, err := v.readSecret(ctx, ref.Key, ref.Version)
+	data, _, err := v.GetSecretWithVersion(ctx, ref)
+	return data, err
+}
+
+// GetSecretWithVersion works like GetSecret and additionally returns
+// the KV v2 version the secret was read from.
+func (v *client) GetSecretWithVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, string, error) {
+	data, metadata, err := v.GetSecretWithMetadata(ctx, ref)
+	return data, metadata.Version, err
+}
+
+// GetSecretWithMetadata works like GetSecret and additionally returns
+// the KV v2 version and its created_time.
+func (v *client) GetSecretWithMetadata(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, esv1beta1.SecretMetadata, error) {
+	var none esv1beta1.SecretMetadata
+	data, metadata, err := v.readSecretWithMetadata(ctx, ref.Key, ref.Version)
 	if err != nil {
-		return nil, err
+		return nil, none, err
 	}
 	// Return nil if secret value is null
 	if data == nil {
-		return nil, nil
+		return nil, metadata, nil
 	}
 	jsonStr, err := json.Marshal(data)
 	if err != nil {
-		return nil, err
+		return nil, none, err
 	}
 	// (1): return raw json if no property is defined
 	if ref.Property == "" {
-		return jsonStr, nil
+		return jsonStr, metadata, nil
 	}
 
 	// For backwards compatibility we want the
 	// actual keys to take precedence over gjson syntax
 	// (2): extract key from secret with property
 	if _, ok := data[ref.Property]; ok {
-		return getTypedKey(data, ref.Property)
+		value, err := getTypedKey(data, ref.Property)
+		return value, metadata, err
 	}
 
 	// (3): extract key from secret using gjson
 	val := gjson.Get(string(jsonStr), ref.Property)
 	if !val.Exists() {
-		return nil, fmt.Errorf(errSecretKeyFmt, ref.Property)
+		return nil, none, fmt.Errorf(errSecretKeyFmt, ref.Property)
 	}
-	return []byte(val.String()), nil
+	return []byte(val.String()), metadata, nil
 }
 
 // GetSecretMap supports two modes of operation:
@@ -602,6 +710,66 @@ func (v *client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretD
 	return byteMap, nil
 }
 
+// GetSecretKeys reads the KV v2 subkeys endpoint, which returns the structure
+// of a secret with all values replaced by null. The values never leave Vault,
+// so the policy of the store only needs the read capability on
+// <mount>/subkeys/<path>.
+// reference - https://developer.hashicorp.com/vault/api-docs/secret/kv/kv-v2#read-secret-subkeys
+func (v *client) GetSecretKeys(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]string, error) {
+	if v.store.Version == esv1beta1.VaultKVStoreV1 {
+		return nil, errors.New(errUnsupportedSubkeys)
+	}
+	var params map[string][]string
+	if ref.Version != "" {
+		params = map[string][]string{"version": {ref.Version}}
+	}
+	vaultSecret, err := v.logical.ReadWithDataWithContext(ctx, v.buildSubkeysPath(ref.Key), params)
+	if err != nil {
+		return nil, fmt.Errorf(errReadSecret, err)
+	}
+	if vaultSecret == nil {
+		return nil, errors.New(errNotFound)
+	}
+	subkeysInt, ok := vaultSecret.Data["subkeys"]
+	if !ok {
+		return nil, errors.New(errSubkeysFormat)
+	}
+	// subkeys is null if the version was deleted or destroyed
+	if subkeysInt == nil {
+		return nil, nil
+	}
+	subkeys, ok := subkeysInt.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errSubkeysFormat)
+	}
+	// like GetSecret, actual keys take precedence over gjson syntax
+	if ref.Property != "" {
+		nested, ok := subkeys[ref.Property].(map[string]interface{})
+		if !ok {
+			jsonStr, err := json.Marshal(subkeys)
+			if err != nil {
+				return nil, err
+			}
+			val := gjson.Get(string(jsonStr), ref.Property)
+			if !val.IsObject() {
+				return nil, fmt.Errorf(errSecretKeyFmt, ref.Property)
+			}
+			nested = make(map[string]interface{})
+			val.ForEach(func(key, _ gjson.Result) bool {
+				nested[key.String()] = nil
+				return true
+			})
+		}
+		subkeys = nested
+	}
+	keys := make([]string, 0, len(subkeys))
+	for k := range subkeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
 func getTypedKey(data map[string]interface{}, key string) ([]byte, error) {
 	v, ok := data[key]
 	if !ok {
@@ -627,9 +795,9 @@ func getTypedKey(data map[string]interface{}, key string) ([]byte, error) {
 }
 
 func (v *client) Close(ctx context.Context) error {
-	// Revoke the token if we have one set, it wasn't sourced from a TokenSecretRef,
-	// and token caching isn't enabled
-	if !EnableCache && v.client.Token() != "" && v.store.Auth.TokenSecretRef == nil {
+	// Revoke the token if we have one set, it wasn't sourced from a TokenSecretRef
+	// or TokenFile, and token caching isn't enabled
+	if !EnableCache && v.client.Token() != "" && v.store.Auth.TokenSecretRef == nil && v.store.Auth.TokenFile == nil {
 		err := revokeTokenIfValid(ctx, v.client)
 		if err != nil {
 			return err
@@ -651,6 +819,9 @@ func isReferentSpec(prov *esv1beta1.VaultProvider) bool {
 	if prov.Auth.Kubernetes != nil && prov.Auth.Kubernetes.ServiceAccountRef != nil && prov.Auth.Kubernetes.ServiceAccountRef.Namespace == nil {
 		return true
 	}
+	if prov.Auth.Kubernetes != nil && isAuthTemplate(prov.Auth.Kubernetes.Path, prov.Auth.Kubernetes.Role) {
+		return true
+	}
 	if prov.Auth.Ldap != nil && prov.Auth.Ldap.SecretRef.Namespace == nil {
 		return true
 	}
@@ -693,6 +864,16 @@ func (v *client) buildMetadataPath(path string) (string, error) {
 	}
 	return url, nil
 }
+
+// buildSubkeysPath returns the subkeys endpoint of a KV v2 secret,
+// e.g. secret/subkeys/foo for secret/data/foo.
+func (v *client) buildSubkeysPath(path string) string {
+	parts := strings.SplitN(v.buildPath(path), "/", 3)
+	if len(parts) < 3 {
+		return strings.Join(parts, "/")
+	}
+	return fmt.Sprintf("%s/subkeys/%s", parts[0], parts[2])
+}
 func (v *client) buildPath(path string) string {
 	optionalMount := v.store.Path
 	origPath := strings.Split(path, "/")
@@ -721,6 +902,14 @@ func (v *client) buildPath(path string) string {
 }
 
 func (v *client) readSecret(ctx context.Context, path, version string) (map[string]interface{}, error) {
+	data, _, err := v.readSecretWithMetadata(ctx, path, version)
+	return data, err
+}
+
+// readSecretWithMetadata returns the secret data and, for KV v2 stores,
+// the version that was read and its created_time.
+func (v *client) readSecretWithMetadata(ctx context.Context, path, version string) (map[string]interface{}, esv1beta1.SecretMetadata, error) {
+	var secretMetadata esv1beta1.SecretMetadata
 	dataPath := v.buildPath(path)
 
 	// path formated according to vault docs for v1 and v2 API
@@ -733,30 +922,39 @@ func (v *client) readSecret(ctx context.Context, path, version string) (map[stri
 	}
 	vaultSecret, err := v.logical.ReadWithDataWithContext(ctx, dataPath, params)
 	if err != nil {
-		return nil, fmt.Errorf(errReadSecret, err)
+		return nil, secretMetadata, fmt.Errorf(errReadSecret, err)
 	}
 	if vaultSecret == nil {
-		return nil, errors.New(errNotFound)
+		return nil, secretMetadata, errors.New(errNotFound)
 	}
 	secretData := vaultSecret.Data
 	if v.store.Version == esv1beta1.VaultKVStoreV2 {
+		if metadata, ok := vaultSecret.Data["metadata"].(map[string]interface{}); ok {
+			if metadata["version"] != nil {
+				secretMetadata.Version = fmt.Sprint(metadata["version"])
+			}
+			if created, ok := metadata["created_time"].(string); ok {
+				// an unparsable time is not an error, the metadata is informational
+				secretMetadata.CreatedTime, _ = time.Parse(time.RFC3339Nano, created)
+			}
+		}
 		// Vault KV2 has data embedded within sub-field
 		// reference - https://www.vaultproject.io/api/secret/kv/kv-v2#read-secret-version
 		dataInt, ok := vaultSecret.Data["data"]
 
 		if !ok {
-			return nil, errors.New(errDataField)
+			return nil, esv1beta1.SecretMetadata{}, errors.New(errDataField)
 		}
 		if dataInt == nil {
-			return nil, nil
+			return nil, secretMetadata, nil
 		}
 		secretData, ok = dataInt.(map[string]interface{})
 		if !ok {
-			return nil, errors.New(errJSONUnmarshall)
+			return nil, esv1beta1.SecretMetadata{}, errors.New(errJSONUnmarshall)
 		}
 	}
 
-	return secretData, nil
+	return secretData, secretMetadata, nil
 }
 
 func (v *client) newConfig() (*vault.Config, error) {
@@ -764,6 +962,12 @@ func (v *client) newConfig() (*vault.Config, error) {
 	cfg.Address = v.store.Server
 	// In a controller-runtime context, we rely on the reconciliation process for retrying
 	cfg.MaxRetries = 0
+	// If either read-after-write consistency feature is enabled, enable ReadYourWrites
+	cfg.ReadYourWrites = v.store.ReadYourWrites || v.store.ForwardInconsistent
+
+	if err := v.configureClientTLS(cfg); err != nil {
+		return nil, err
+	}
 
 	if len(v.store.CABundle) == 0 && v.store.CAProvider == nil {
 		return cfg, nil
@@ -809,12 +1013,35 @@ func (v *client) newConfig() (*vault.Config, error) {
 		transport.TLSClientConfig.RootCAs = caCertPool
 	}
 
-	// If either read-after-write consistency feature is enabled, enable ReadYourWrites
-	cfg.ReadYourWrites = v.store.ReadYourWrites || v.store.ForwardInconsistent
-
 	return cfg, nil
 }
 
+// configureClientTLS sets the client certificate presented to Vault servers requiring mutual TLS.
+// The certificate is read whenever a client is built; cached clients are
+// invalidated when the referenced Secrets change, e.g. when cert-manager rotates them.
+func (v *client) configureClientTLS(cfg *vault.Config) error {
+	if v.store.ClientTLS == nil {
+		return nil
+	}
+	ctx := context.Background()
+	clientCert, err := v.secretKeyRef(ctx, &v.store.ClientTLS.CertSecretRef)
+	if err != nil {
+		return fmt.Errorf(errClientTLS, err)
+	}
+	clientKey, err := v.secretKeyRef(ctx, &v.store.ClientTLS.KeySecretRef)
+	if err != nil {
+		return fmt.Errorf(errClientTLS, err)
+	}
+	cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+	if err != nil {
+		return fmt.Errorf(errClientTLS, err)
+	}
+	if transport, ok := cfg.HttpClient.Transport.(*http.Transport); ok {
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+	return nil
+}
+
 func getCertFromSecret(v *client) ([]byte, error) {
 	secretRef := esmeta.SecretKeySelector{
 		Name: v.store.CAProvider.Name,
@@ -879,6 +1106,12 @@ func (v *client) setAuth(ctx context.Context, cfg *vault.Config) error {
 		return err
 	}
 
+	tokenExists, err = setTokenFileToken(v)
+	if tokenExists {
+		v.log.V(1).Info("Set token from file")
+		return err
+	}
+
 	tokenExists, err = setAppRoleToken(ctx, v)
 	if tokenExists {
 		v.log.V(1).Info("Retrieved new token using AppRole auth")
@@ -925,6 +1158,28 @@ func setSecretKeyToken(ctx context.Context, v *client) (bool, error) {
 	return false, nil
 }
 
+func setTokenFileToken(v *client) (bool, error) {
+	tokenFile := v.store.Auth.TokenFile
+	if tokenFile == nil {
+		return false, nil
+	}
+	// the file is readable by the controller, which must not hand
+	// its identity to namespaced stores.
+	if v.storeKind != esv1beta1.ClusterSecretStoreKind {
+		return true, errors.New(errTokenFileKind)
+	}
+	data, err := credentialfile.Read(tokenFile.Path)
+	if err != nil {
+		return true, fmt.Errorf(errReadTokenFile, tokenFile.Path, err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return true, fmt.Errorf(errEmptyTokenFile, tokenFile.Path)
+	}
+	v.client.SetToken(token)
+	return true, nil
+}
+
 func setAppRoleToken(ctx context.Context, v *client) (bool, error) {
 	appRole := v.store.Auth.AppRole
 	if appRole != nil {
@@ -1125,7 +1380,15 @@ func (v *client) requestTokenWithKubernetesAuth(ctx context.Context, kubernetesA
 	if err != nil {
 		return err
 	}
-	k, err := authkubernetes.NewKubernetesAuth(kubernetesAuth.Role, authkubernetes.WithServiceAccountToken(jwtString), authkubernetes.WithMountPath(kubernetesAuth.Path))
+	role, err := renderAuthTemplate(kubernetesAuth.Role, v.namespace)
+	if err != nil {
+		return fmt.Errorf(errInvalidKubeTpl, err)
+	}
+	mountPath, err := renderAuthTemplate(kubernetesAuth.Path, v.namespace)
+	if err != nil {
+		return fmt.Errorf(errInvalidKubeTpl, err)
+	}
+	k, err := authkubernetes.NewKubernetesAuth(role, authkubernetes.WithServiceAccountToken(jwtString), authkubernetes.WithMountPath(mountPath))
 	if err != nil {
 		return err
 	}
@@ -1136,6 +1399,36 @@ func (v *client) requestTokenWithKubernetesAuth(ctx context.Context, kubernetesA
 	return nil
 }
 
+// isAuthTemplate reports whether any of the values is a template.
+func isAuthTemplate(values ...string) bool {
+	for _, v := range values {
+		if strings.Contains(v, "{{") {
+			return true
+		}
+	}
+	return false
+}
+
+// renderAuthTemplate renders an auth parameter of the store, e.g. a role
+// like "eso-{{ .namespace }}", with the namespace of the ExternalSecret.
+func renderAuthTemplate(tpl, namespace string) (string, error) {
+	if !isAuthTemplate(tpl) {
+		return tpl, nil
+	}
+	t, err := template.New("auth").Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	err = t.Execute(&out, map[string]string{
+		"namespace": namespace,
+	})
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
 func getJwtString(ctx context.Context, v *client, kubernetesAuth *esv1beta1.VaultKubernetesAuth) (string, error) {
 	if kubernetesAuth.ServiceAccountRef != nil {
 		// Kubernetes <v1.24 fetch token via ServiceAccount.Secrets[]
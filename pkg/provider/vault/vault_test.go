@@ -16,10 +16,14 @@ package vault
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
@@ -32,6 +36,7 @@ import (
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/credentialfile"
 	utilfake "github.com/external-secrets/external-secrets/pkg/provider/util/fake"
 	"github.com/external-secrets/external-secrets/pkg/provider/vault/fake"
 )
@@ -239,6 +244,20 @@ MIIJQgIBADANBgkqhkiG9w0BAQEFAASCCSwwggkoAgEAAoICAQCi4cG2CxHejOXaWW0Xri4PbWyuainu
 MIIFkTCCA3mgAwIBAgIUBEUg3m/WqAsWHG4Q/II3IePFfuowDQYJKoZIhvcNAQELBQAwWDELMAkGA1UEBhMCQVUxEzARBgNVBAgMClNvbWUtU3RhdGUxITAfBgNVBAoMGEludGVybmV0IFdpZGdpdHMgUHR5IEx0ZDERMA8GA1UEAwwIdmF1bHQtY2EwHhcNMjIwNzI5MjEyMjE4WhcNMzkwMTAxMjEyMjE4WjBYMQswCQYDVQQGEwJBVTETMBEGA1UECAwKU29tZS1TdGF0ZTEhMB8GA1UECgwYSW50ZXJuZXQgV2lkZ2l0cyBQdHkgTHRkMREwDwYDVQQDDAh2YXVsdC1jYTCCAiIwDQYJKoZIhvcNAQEBBQADggIPADCCAgoCggIBAKLhwbYLEd6M5dpZbReuLg9tbK5qKe6sJm5Qs8sLSMmwkXTOSrvvw7smV5bOHtCGpUEooiFbos1RVL22YYd8cLu59oev1i1p3NWSIPNAq0DQKBxyj3wJ9ftvOfmlmf/J+dtE39pPLFPnffkMWEdzT8UImfZD/kf1S1fotHcffNtP0ijfZPQHo4BQooH/e7PEAQIXDBIrxSoZR57wkoYGtTXRdEAwbE5zUE5ZikIdZlEfYVEYiBL5vBgc5dSM+/oUfurH7AIzOXGp30sa+JzYKkUTQGISw4siJj+oSqjO93Zo4tWEE+NP/0tNe9FnBf+oDuHhgiyerk3yU3jK2vJvXc3191FWDKZXKQaRoCaHnMDgMiDjxQtbM590/BFDWRWrZNGaPciVwmw2foyAclNtGq8J0kzIDn0VRkL35Ad8LfTuxXZpY+hVPC5TIPek4W7grQtX44ohVDw5M6oClucv104tXHjAVVgTfaVMLi1F3FRTwwqI8ShPX0D5ssw+tkeepcIhHMvDB97AgKBM1g24MdX3c6aokrghdwXxQbTS/6lHD66apc3E2STTHeCt9fnDSwEZlvK6iNgedBDHrBl9SXeYTMnbOWYIHX/YYq1efq0gOZHHN4nZuBopOMb+4K/04a2nVUuafdv/eoL6F7h+/+UaH/N3+LkkxWAxcz0w9GVZAgMBAAGjUzBRMB0GA1UdDgQWBBQuIVwmjMZvkq+jf6ViTelH5KDBVDAfBgNVHSMEGDAWgBQuIVwmjMZvkq+jf6ViTelH5KDBVDAPBgNVHRMBAf8EBTADAQH/MA0GCSqGSIb3DQEBCwUAA4ICAQAk4kNyFzmiKnREmi5PPj7xGAtv2aJIdMEfcZJ9e+H0Nb2aCvMvZsDodduXu6G5+1opd45v0AeTjLBkXDO6/8vnyM32VZEEKCAwMCLcOLD1z0+r+gaurDYMOGU5qr8hQadHKFsxEDYnR/9KdHhBg6A8qE2cOQa1ryu34DnWQ3m0CBApClf1YBRp/4T8BmHumfH6odD96H30HVzINrd9WM2hR9GRE3xqQyfwlvqmGn9S6snSVa+mcJ6w2wNE2LPGx0kOtBeOIUdfSsEgvSRjbowSHz9lohFZ0LxJYyizCA5vnMmYyhhkfJqm7YtjHkGWgXmqpH9BFt0D3gfORlIh787nuWfxtZ+554rDyQmPjYQG/qF4+Awehr4RxiGWTox1C67G/RzA6TOXX09xuFY+3U1ich90/KffvhoHvRVfhzxx+HUUY2qSU3HqQDzgieQQBaMuOhd1i6pua+/kPSXkuXqnIs8daao/goR5iU/lPLs7M8Dy7xZ9adzbIPuNuzHir2UuvtPlW+x/sSvOnVL9r/7TrAuWhdScglQ70EInPDVX7BgDWKrZUh86N4d7fu2f/T+6VoUSGEjq8obCj3BQ61mNEoftKVECUO4MMUdat6pY/4Xh6Dwc+FnbvR2+sX7IzI7FtgOrfO6abT+LCAR0R+UXyvnqZcjK2zkHz4DfXFbCQg==
 -----END CERTIFICATE-----`)
 	secretData := []byte(secretDataString)
+	tokenFile := withTokenFile(t)
+	missingTokenFile := tokenFile + "-missing"
+	_, errMissingTokenFile := credentialfile.Read(missingTokenFile)
+	makeTokenFileStore := func(path string) *esv1beta1.ClusterSecretStore {
+		spec := makeValidSecretStore().Spec
+		spec.Provider.Vault.Auth = esv1beta1.VaultAuth{
+			TokenFile: &esv1beta1.VaultTokenFileAuth{Path: path},
+		}
+		return &esv1beta1.ClusterSecretStore{
+			TypeMeta:   metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+			ObjectMeta: metav1.ObjectMeta{Name: "vault-store"},
+			Spec:       spec,
+		}
+	}
 
 	cases := map[string]testCase{
 		"InvalidVaultStore": {
@@ -450,6 +469,93 @@ MIIFkTCCA3mgAwIBAgIUBEUg3m/WqAsWHG4Q/II3IePFfuowDQYJKoZIhvcNAQELBQAwWDELMAkGA1UE
 				err: fmt.Errorf(errClientTLSAuth, "tls: failed to find any PEM data in certificate input"),
 			},
 		},
+		"SuccessfulVaultStoreWithTokenFile": {
+			reason: "Should return a Vault provider with the token from the token file",
+			args: args{
+				store:         makeTokenFileStore(tokenFile),
+				newClientFunc: clientWithLoginMock,
+			},
+			want: want{
+				err: nil,
+			},
+		},
+		"MissingTokenFileError": {
+			reason: "Should return error if the token file does not exist",
+			args: args{
+				store:         makeTokenFileStore(missingTokenFile),
+				newClientFunc: clientWithLoginMock,
+			},
+			want: want{
+				err: fmt.Errorf(errReadTokenFile, missingTokenFile, errMissingTokenFile),
+			},
+		},
+		"TokenFileInSecretStoreError": {
+			reason: "Should return error if a SecretStore uses a token file",
+			args: args{
+				store: makeSecretStore(func(s *esv1beta1.SecretStore) {
+					s.Spec.Provider.Vault.Auth = esv1beta1.VaultAuth{
+						TokenFile: &esv1beta1.VaultTokenFileAuth{Path: tokenFile},
+					}
+				}),
+				newClientFunc: clientWithLoginMock,
+			},
+			want: want{
+				err: errors.New(errTokenFileKind),
+			},
+		},
+		"SuccessfulVaultStoreWithClientTLS": {
+			reason: "Should return a Vault provider with the client certificate from k8s",
+			args: args{
+				store: makeSecretStore(func(s *esv1beta1.SecretStore) {
+					s.Spec.Provider.Vault.ClientTLS = &esv1beta1.VaultClientTLS{
+						CertSecretRef: esmeta.SecretKeySelector{Name: "vault-client-tls", Key: "tls.crt"},
+						KeySecretRef:  esmeta.SecretKeySelector{Name: "vault-client-tls", Key: "tls.key"},
+					}
+				}),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj kclient.Object) error {
+						if o, ok := obj.(*corev1.Secret); ok {
+							o.Data = map[string][]byte{
+								"tls.key": secretClientKey,
+								"tls.crt": clientCrt,
+							}
+						}
+						return nil
+					}),
+				},
+				corev1:        utilfake.NewCreateTokenMock().WithToken("ok"),
+				newClientFunc: clientWithLoginMock,
+			},
+			want: want{
+				err: nil,
+			},
+		},
+		"ClientTLSKeyFormatError": {
+			reason: "Should return error if the client TLS key is in wrong format.",
+			args: args{
+				store: makeSecretStore(func(s *esv1beta1.SecretStore) {
+					s.Spec.Provider.Vault.ClientTLS = &esv1beta1.VaultClientTLS{
+						CertSecretRef: esmeta.SecretKeySelector{Name: "vault-client-tls", Key: "tls.crt"},
+						KeySecretRef:  esmeta.SecretKeySelector{Name: "vault-client-tls", Key: "tls.key"},
+					}
+				}),
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj kclient.Object) error {
+						if o, ok := obj.(*corev1.Secret); ok {
+							o.Data = map[string][]byte{
+								"tls.key": []byte("key with mistake"),
+								"tls.crt": clientCrt,
+							}
+						}
+						return nil
+					}),
+				},
+				newClientFunc: clientWithLoginMock,
+			},
+			want: want{
+				err: fmt.Errorf(errClientTLS, errors.New("tls: failed to find any PEM data in key input")),
+			},
+		},
 		"GetKeyFormatError": {
 			reason: "Should return error if client key is in wrong format.",
 			args: args{
@@ -481,6 +587,92 @@ MIIFkTCCA3mgAwIBAgIUBEUg3m/WqAsWHG4Q/II3IePFfuowDQYJKoZIhvcNAQELBQAwWDELMAkGA1UE
 	}
 }
 
+// withTokenFile writes a token file to the credential file directory.
+func withTokenFile(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("agent-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := credentialfile.Dir
+	credentialfile.Dir = dir
+	t.Cleanup(func() { credentialfile.Dir = old })
+	return tokenFile
+}
+
+func TestNewVaultForwardInconsistent(t *testing.T) {
+	tokenFile := withTokenFile(t)
+	// every client identifies its store
+	userAgent := "external-secrets/dev (store=ClusterSecretStore/vault-store)"
+	cases := map[string]struct {
+		readYourWrites      bool
+		forwardInconsistent bool
+		wantHeaders         map[string]string
+		wantReadYourWrites  bool
+	}{
+		"Eventual": {
+			wantHeaders: map[string]string{"User-Agent": userAgent},
+		},
+		"ReadYourWrites": {
+			readYourWrites:     true,
+			wantHeaders:        map[string]string{"User-Agent": userAgent},
+			wantReadYourWrites: true,
+		},
+		"ForwardInconsistent": {
+			forwardInconsistent: true,
+			wantHeaders:         map[string]string{"User-Agent": userAgent, "X-Vault-Inconsistent": "forward-active-node"},
+			wantReadYourWrites:  true,
+		},
+		"Both": {
+			readYourWrites:      true,
+			forwardInconsistent: true,
+			wantHeaders:         map[string]string{"User-Agent": userAgent, "X-Vault-Inconsistent": "forward-active-node"},
+			wantReadYourWrites:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			spec := makeValidSecretStore().Spec
+			spec.Provider.Vault.Auth = esv1beta1.VaultAuth{
+				TokenFile: &esv1beta1.VaultTokenFileAuth{Path: tokenFile},
+			}
+			spec.Provider.Vault.ReadYourWrites = tc.readYourWrites
+			spec.Provider.Vault.ForwardInconsistent = tc.forwardInconsistent
+			store := &esv1beta1.ClusterSecretStore{
+				TypeMeta:   metav1.TypeMeta{Kind: esv1beta1.ClusterSecretStoreKind},
+				ObjectMeta: metav1.ObjectMeta{Name: "vault-store"},
+				Spec:       spec,
+			}
+			headers := map[string]string{}
+			var gotReadYourWrites bool
+			conn := &connector{
+				newVaultClient: func(c *vault.Config) (Client, error) {
+					gotReadYourWrites = c.ReadYourWrites
+					cl, err := clientWithLoginMock(c)
+					if err != nil {
+						return nil, err
+					}
+					vc := cl.(VClient)
+					vc.addHeader = func(key, value string) {
+						headers[key] = value
+					}
+					return vc, nil
+				},
+			}
+			if _, err := conn.newClient(context.Background(), store, nil, nil, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantHeaders, headers); diff != "" {
+				t.Errorf("headers: -want, +got:\n%s", diff)
+			}
+			if gotReadYourWrites != tc.wantReadYourWrites {
+				t.Errorf("ReadYourWrites: want %v, got %v", tc.wantReadYourWrites, gotReadYourWrites)
+			}
+		})
+	}
+}
+
 func vaultTest(t *testing.T, name string, tc testCase) {
 	conn := &connector{
 		newVaultClient: tc.args.newClientFunc,
@@ -524,8 +716,10 @@ func TestGetSecret(t *testing.T) {
 	}
 
 	type want struct {
-		err error
-		val []byte
+		err     error
+		val     []byte
+		version string
+		created time.Time
 	}
 
 	cases := map[string]struct {
@@ -549,6 +743,30 @@ func TestGetSecret(t *testing.T) {
 				val: []byte("access_key"),
 			},
 		},
+		"ReadSecretWithVersion": {
+			reason: "Should return the KV v2 version of the secret",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Property: "access_key",
+				},
+				vLogical: &fake.Logical{
+					ReadWithDataWithContextFn: fake.NewReadWithContextFn(map[string]interface{}{
+						"data": secret,
+						"metadata": map[string]interface{}{
+							"version":      json.Number("3"),
+							"created_time": "2023-01-02T03:04:05.123456789Z",
+						},
+					}, nil),
+				},
+			},
+			want: want{
+				err:     nil,
+				val:     []byte("access_key"),
+				version: "3",
+				created: time.Date(2023, 1, 2, 3, 4, 5, 123456789, time.UTC),
+			},
+		},
 		"ReadSecretWithNil": {
 			reason: "Should return the secret with property if it has a nil val",
 			args: args{
@@ -666,12 +884,160 @@ func TestGetSecret(t *testing.T) {
 				store:     tc.args.store,
 				namespace: tc.args.ns,
 			}
-			val, err := vStore.GetSecret(context.Background(), tc.args.data)
+			val, metadata, err := vStore.GetSecretWithMetadata(context.Background(), tc.args.data)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\nvault.GetSecret(...): -want error, +got error:\n%s", tc.reason, diff)
+				t.Errorf("\n%s\nvault.GetSecretWithMetadata(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
 			if diff := cmp.Diff(string(tc.want.val), string(val)); diff != "" {
-				t.Errorf("\n%s\nvault.GetSecret(...): -want val, +got val:\n%s", tc.reason, diff)
+				t.Errorf("\n%s\nvault.GetSecretWithMetadata(...): -want val, +got val:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.version, metadata.Version); diff != "" {
+				t.Errorf("\n%s\nvault.GetSecretWithMetadata(...): -want version, +got version:\n%s", tc.reason, diff)
+			}
+			if !tc.want.created.Equal(metadata.CreatedTime) {
+				t.Errorf("\n%s\nvault.GetSecretWithMetadata(...): want created time %v, got %v", tc.reason, tc.want.created, metadata.CreatedTime)
+			}
+		})
+	}
+}
+
+func TestGetSecretKeys(t *testing.T) {
+	errBoom := errors.New("boom")
+	subkeys := map[string]interface{}{
+		"subkeys": map[string]interface{}{
+			"access_secret": nil,
+			"access_key":    nil,
+			"nested": map[string]interface{}{
+				"foo": nil,
+				"bar": map[string]interface{}{
+					"baz": nil,
+				},
+			},
+		},
+	}
+
+	type args struct {
+		store    *esv1beta1.VaultProvider
+		vLogical Logical
+		data     esv1beta1.ExternalSecretDataRemoteRef
+	}
+
+	type want struct {
+		err  error
+		keys []string
+		path string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ReadSubkeys": {
+			reason: "Should return the sorted keys of the secret",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data:  esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"},
+			},
+			want: want{
+				keys: []string{"access_key", "access_secret", "nested"},
+				path: "secret/subkeys/foo",
+			},
+		},
+		"ReadSubkeysWithProperty": {
+			reason: "Should return the keys of a nested object",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data:  esv1beta1.ExternalSecretDataRemoteRef{Key: "secret/foo", Property: "nested"},
+			},
+			want: want{
+				keys: []string{"bar", "foo"},
+				path: "secret/subkeys/foo",
+			},
+		},
+		"ReadSubkeysWithGjsonProperty": {
+			reason: "Should return the keys of a nested object selected with gjson",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data:  esv1beta1.ExternalSecretDataRemoteRef{Key: "foo", Property: "nested.bar"},
+			},
+			want: want{
+				keys: []string{"baz"},
+				path: "secret/subkeys/foo",
+			},
+		},
+		"ReadSubkeysPropertyNotAnObject": {
+			reason: "Should return error if the property does not select an object",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data:  esv1beta1.ExternalSecretDataRemoteRef{Key: "foo", Property: "access_key"},
+			},
+			want: want{
+				err:  fmt.Errorf(errSecretKeyFmt, "access_key"),
+				path: "secret/subkeys/foo",
+			},
+		},
+		"ReadSubkeysDeletedVersion": {
+			reason: "Should return no keys if the version was deleted",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data:  esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"},
+				vLogical: &fake.Logical{
+					ReadWithDataWithContextFn: fake.NewReadWithContextFn(map[string]interface{}{"subkeys": nil}, nil),
+				},
+			},
+			want: want{},
+		},
+		"ReadSubkeysKVv1": {
+			reason: "Should return error for kv version v1",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV1).Spec.Provider.Vault,
+				data:  esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"},
+			},
+			want: want{
+				err: errors.New(errUnsupportedSubkeys),
+			},
+		},
+		"ReadSubkeysError": {
+			reason: "Should return error if vault client fails to read the subkeys",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data:  esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"},
+				vLogical: &fake.Logical{
+					ReadWithDataWithContextFn: fake.NewReadWithContextFn(nil, errBoom),
+				},
+			},
+			want: want{
+				err: fmt.Errorf(errReadSecret, errBoom),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			logical := tc.args.vLogical
+			if logical == nil {
+				logical = &fake.Logical{
+					ReadWithDataWithContextFn: func(ctx context.Context, p string, data map[string][]string) (*vault.Secret, error) {
+						path = p
+						return &vault.Secret{Data: subkeys}, nil
+					},
+				}
+			}
+			vStore := &client{
+				logical: logical,
+				store:   tc.args.store,
+			}
+			keys, err := vStore.GetSecretKeys(context.Background(), tc.args.data)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvault.GetSecretKeys(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.keys, keys); diff != "" {
+				t.Errorf("\n%s\nvault.GetSecretKeys(...): -want keys, +got keys:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.path, path); diff != "" {
+				t.Errorf("\n%s\nvault.GetSecretKeys(...): -want path, +got path:\n%s", tc.reason, diff)
 			}
 		})
 	}
@@ -938,6 +1304,7 @@ func TestGetAllSecrets(t *testing.T) {
 	path2Bytes := []byte("{\"access_key\":\"path2\",\"access_secret\":\"path2\"}")
 	tagBytes := []byte("{\"access_key\":\"unfetched\",\"access_secret\":\"unfetched\"}")
 	path := "path"
+	pathWithSlash := "path/"
 	secret := map[string]interface{}{
 		"secret1": map[string]interface{}{
 			"metadata": map[string]interface{}{
@@ -1104,6 +1471,29 @@ func TestGetAllSecrets(t *testing.T) {
 				},
 			},
 		},
+		"FilterByPathWithTrailingSlash": {
+			reason: "should accept a path ending with a slash",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				vLogical: &fake.Logical{
+					ListWithContextFn:         newListWithContextFn(secret),
+					ReadWithDataWithContextFn: newReadtWithContextFn(secret),
+				},
+				data: esv1beta1.ExternalSecretFind{
+					Path: &pathWithSlash,
+					Tags: map[string]string{
+						"foo": "path",
+					},
+				},
+			},
+			want: want{
+				err: nil,
+				val: map[string][]byte{
+					"path/1": path1Bytes,
+					"path/2": path2Bytes,
+				},
+			},
+		},
 		"FailIfKv1": {
 			reason: "should not work if using kv1 store",
 			args: args{
@@ -1345,6 +1735,28 @@ func TestValidateStore(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid kubernetes role template",
+			args: args{
+				auth: esv1beta1.VaultAuth{
+					Kubernetes: &esv1beta1.VaultKubernetesAuth{
+						Role: "eso-{{ .namespace",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid kubernetes role template",
+			args: args{
+				auth: esv1beta1.VaultAuth{
+					Kubernetes: &esv1beta1.VaultKubernetesAuth{
+						Path: "kubernetes-{{ .namespace }}",
+						Role: "eso-{{ .namespace }}",
+					},
+				},
+			},
+		},
 		{
 			name: "invalid ldap secret",
 			args: args{
@@ -1358,6 +1770,15 @@ func TestValidateStore(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "token file in SecretStore",
+			args: args{
+				auth: esv1beta1.VaultAuth{
+					TokenFile: &esv1beta1.VaultTokenFileAuth{Path: "/vault/agent/token"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid token secret",
 			args: args{
@@ -1390,3 +1811,66 @@ func TestValidateStore(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderAuthTemplate(t *testing.T) {
+	tests := []struct {
+		tpl     string
+		want    string
+		wantErr bool
+	}{
+		{tpl: "eso-role", want: "eso-role"},
+		{tpl: "eso-{{ .namespace }}", want: "eso-tenant-a"},
+		{tpl: "kubernetes/{{ .namespace }}/{{ .namespace }}", want: "kubernetes/tenant-a/tenant-a"},
+		{tpl: "eso-{{ .name }}", wantErr: true},
+		{tpl: "eso-{{ .namespace", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := renderAuthTemplate(tt.tpl, "tenant-a")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("renderAuthTemplate(%q) error = %v, wantErr %v", tt.tpl, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("renderAuthTemplate(%q) = %q, want %q", tt.tpl, got, tt.want)
+		}
+	}
+
+	// templated auth depends on the namespace of the ExternalSecret
+	prov := &esv1beta1.VaultProvider{
+		Auth: esv1beta1.VaultAuth{
+			Kubernetes: &esv1beta1.VaultKubernetesAuth{
+				Path: "kubernetes",
+				Role: "eso-{{ .namespace }}",
+				ServiceAccountRef: &esmeta.ServiceAccountSelector{
+					Name:      "example-sa",
+					Namespace: pointer.StringPtr("default"),
+				},
+			},
+		},
+	}
+	if !isReferentSpec(prov) {
+		t.Errorf("expected templated kubernetes auth to be referent")
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	c := &connector{}
+	for _, tt := range []struct {
+		version  esv1beta1.VaultKVStoreVersion
+		features []esv1beta1.SecretStoreFeature
+	}{
+		{version: esv1beta1.VaultKVStoreV1},
+		{version: esv1beta1.VaultKVStoreV2, features: []esv1beta1.SecretStoreFeature{esv1beta1.FeatureFind}},
+	} {
+		store := makeValidSecretStoreWithVersion(tt.version)
+		got := c.Capabilities(store)
+		want := esv1beta1.SecretStoreCapabilities{
+			Access:          esv1beta1.SecretStoreReadOnly,
+			Features:        tt.features,
+			ProviderVersion: string(tt.version),
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Capabilities(%s): -want, +got:\n%s", tt.version, diff)
+		}
+	}
+}
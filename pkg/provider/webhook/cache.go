@@ -0,0 +1,242 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultCacheEntries = 100
+	// backgroundRefreshTimeout bounds the refresh of a stale response.
+	backgroundRefreshTimeout = time.Minute
+	// cacheIdleTimeout is the time after which the cache of a store
+	// that built no client is dropped, e.g. because the store was deleted.
+	cacheIdleTimeout = 2 * time.Hour
+)
+
+var (
+	log = ctrl.Log.WithName("provider").WithName("webhook")
+
+	responseCachesMu sync.Mutex
+	// responseCaches holds one cache per store so
+	// cached responses survive the per-reconcile clients.
+	responseCaches = map[string]*responseCache{}
+)
+
+// responseCache caches webhook responses according to
+// their Cache-Control and ETag headers.
+type responseCache struct {
+	now          func() time.Time
+	cache        *lru.Cache
+	size         int
+	defaultTTL   time.Duration
+	maxStaleness time.Duration
+
+	refreshingMu sync.Mutex
+	// refreshing holds the keys of the responses refreshed in the background
+	refreshing map[string]bool
+
+	// guarded by responseCachesMu
+	lastUsed time.Time
+}
+
+type cacheEntry struct {
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+type cacheKey struct {
+	Method  string
+	URL     string
+	Body    string
+	Headers http.Header
+}
+
+// getResponseCache returns the cache shared by all clients of a store.
+// The cache is recreated if its configuration changed.
+func getResponseCache(store esv1beta1.GenericStore, cfg *esv1beta1.WebhookCache) (*responseCache, error) {
+	size := cfg.MaxEntries
+	if size <= 0 {
+		size = defaultCacheEntries
+	}
+	var ttl, maxStaleness time.Duration
+	if cfg.DefaultTTL != nil {
+		ttl = cfg.DefaultTTL.Duration
+	}
+	if cfg.MaxStaleness != nil {
+		maxStaleness = cfg.MaxStaleness.Duration
+	}
+	key := store.GetObjectKind().GroupVersionKind().Kind + "/" + store.GetNamespacedName()
+	responseCachesMu.Lock()
+	defer responseCachesMu.Unlock()
+	now := time.Now()
+	evictIdleCaches(now)
+	c, ok := responseCaches[key]
+	if ok && c.size == size && c.defaultTTL == ttl && c.maxStaleness == maxStaleness {
+		c.lastUsed = now
+		return c, nil
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	c = &responseCache{
+		now:          time.Now,
+		cache:        cache,
+		size:         size,
+		defaultTTL:   ttl,
+		maxStaleness: maxStaleness,
+		refreshing:   map[string]bool{},
+		lastUsed:     now,
+	}
+	responseCaches[key] = c
+	return c, nil
+}
+
+// evictIdleCaches must be called with responseCachesMu held.
+// Clients that still use an evicted cache keep it until they are dropped.
+func evictIdleCaches(now time.Time) {
+	for key, c := range responseCaches {
+		if now.Sub(c.lastUsed) >= cacheIdleTimeout {
+			delete(responseCaches, key)
+		}
+	}
+}
+
+func newCacheKey(method, url string, body []byte, headers http.Header) string {
+	// the hash includes the rendered headers so responses
+	// are never shared between different credentials
+	return utils.ObjectHash(cacheKey{
+		Method:  method,
+		URL:     url,
+		Body:    string(body),
+		Headers: headers,
+	})
+}
+
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	v, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return v.(*cacheEntry), true
+}
+
+func (c *responseCache) fresh(e *cacheEntry) bool {
+	return c.now().Before(e.expires)
+}
+
+// servableStale reports whether an expired entry may still be returned
+// while it is refreshed in the background.
+func (c *responseCache) servableStale(e *cacheEntry) bool {
+	return c.maxStaleness > 0 && c.now().Before(e.expires.Add(c.maxStaleness))
+}
+
+// startRefresh marks key as being refreshed in the background.
+// It returns false if a refresh of key is already running.
+func (c *responseCache) startRefresh(key string) bool {
+	c.refreshingMu.Lock()
+	defer c.refreshingMu.Unlock()
+	if c.refreshing[key] {
+		return false
+	}
+	c.refreshing[key] = true
+	return true
+}
+
+func (c *responseCache) finishRefresh(key string) {
+	c.refreshingMu.Lock()
+	defer c.refreshingMu.Unlock()
+	delete(c.refreshing, key)
+}
+
+// store caches the response body unless the response forbids it.
+func (c *responseCache) store(key string, body []byte, header http.Header) {
+	if c == nil {
+		return
+	}
+	ttl, cacheable := c.ttl(header)
+	if !cacheable {
+		c.cache.Remove(key)
+		return
+	}
+	c.cache.Add(key, &cacheEntry{
+		body:    body,
+		etag:    header.Get("ETag"),
+		expires: c.now().Add(ttl),
+	})
+}
+
+// revalidated updates the expiry of an entry after a 304 Not Modified response.
+func (c *responseCache) revalidated(key string, e *cacheEntry, header http.Header) {
+	ttl, cacheable := c.ttl(header)
+	if !cacheable {
+		c.cache.Remove(key)
+		return
+	}
+	// entries are shared with concurrent readers and never modified
+	etag := e.etag
+	if newETag := header.Get("ETag"); newETag != "" {
+		etag = newETag
+	}
+	c.cache.Add(key, &cacheEntry{
+		body:    e.body,
+		etag:    etag,
+		expires: c.now().Add(ttl),
+	})
+}
+
+// ttl returns how long a response may be used without revalidation
+// and whether it may be cached at all.
+func (c *responseCache) ttl(header http.Header) (time.Duration, bool) {
+	ttl := c.defaultTTL
+	noCache := false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store":
+			return 0, false
+		case "no-cache":
+			noCache = true
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	if noCache {
+		ttl = 0
+	}
+	// a response that can neither be reused nor revalidated is useless
+	if ttl == 0 && header.Get("ETag") == "" {
+		return 0, false
+	}
+	return ttl, true
+}
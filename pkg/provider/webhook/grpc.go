@@ -0,0 +1,231 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errGRPCMethod        = "grpc method is required"
+	errGRPCFieldNumber   = "invalid grpc request field number %d"
+	errGRPCFieldValue    = "invalid value %q of grpc request field %d: %w"
+	errGRPCFieldType     = "unknown type %q of grpc request field %d"
+	errGRPCResponsePath  = "invalid grpc response field path %q"
+	errGRPCFieldNotFound = "field %d of grpc response field path %q not found"
+	errGRPCFieldNotBytes = "field %d of grpc response field path %q is not a message"
+	errGRPCMessage       = "failed to parse grpc response: %w"
+)
+
+// rawCodec sends and receives messages that are already encoded.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return *msg, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+// Name is proto so that servers accept the messages as application/grpc+proto.
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// dialGRPC connects to the gRPC server at provider.URL, the connection is established lazily.
+func (w *WebHook) dialGRPC(provider *esv1beta1.WebhookProvider) (*grpc.ClientConn, error) {
+	if provider.GRPC.Method == "" {
+		return nil, fmt.Errorf(errGRPCMethod)
+	}
+	creds := insecure.NewCredentials()
+	if !provider.GRPC.Insecure {
+		tlsConf := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(provider.CABundle) > 0 || provider.CAProvider != nil {
+			caCertPool, err := w.getCACertPool(provider)
+			if err != nil {
+				return nil, err
+			}
+			tlsConf.RootCAs = caCertPool
+		}
+		creds = credentials.NewTLS(tlsConf)
+	}
+	return grpc.Dial(provider.URL, grpc.WithTransportCredentials(creds))
+}
+
+// getGRPCData calls the gRPC method and returns the value at the response field path.
+func (w *WebHook) getGRPCData(ctx context.Context, provider *esv1beta1.WebhookProvider, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if w.grpc == nil {
+		return nil, fmt.Errorf("grpc client not initialized")
+	}
+	data, err := w.getTemplateData(ctx, ref, provider.Secrets, false)
+	if err != nil {
+		return nil, err
+	}
+	request, err := encodeGRPCRequest(provider.GRPC.Request, data)
+	if err != nil {
+		return nil, err
+	}
+	md := metadata.MD{}
+	for hKey, hValueTpl := range provider.Headers {
+		hValue, err := executeTemplateString(hValueTpl, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse header %s: %w", hKey, err)
+		}
+		md.Append(hKey, hValue)
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	if provider.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, provider.Timeout.Duration)
+		defer cancel()
+	}
+
+	var response []byte
+	if err := w.grpc.Invoke(ctx, provider.GRPC.Method, &request, &response, grpc.ForceCodec(rawCodec{})); err != nil {
+		return nil, fmt.Errorf("failed to call endpoint: %w", err)
+	}
+	return grpcResponseField(response, provider.GRPC.ResponseFieldPath)
+}
+
+// encodeGRPCRequest encodes the templated fields as a protobuf message.
+func encodeGRPCRequest(fields []esv1beta1.WebhookGRPCField, data map[string]map[string]string) ([]byte, error) {
+	var msg []byte
+	for _, field := range fields {
+		num := protowire.Number(field.Number)
+		if !num.IsValid() {
+			return nil, fmt.Errorf(errGRPCFieldNumber, field.Number)
+		}
+		value, err := executeTemplateString(field.Value, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse grpc request field %d: %w", field.Number, err)
+		}
+		switch field.Type {
+		case esv1beta1.WebhookGRPCFieldTypeString, "":
+			msg = protowire.AppendTag(msg, num, protowire.BytesType)
+			msg = protowire.AppendString(msg, value)
+		case esv1beta1.WebhookGRPCFieldTypeInt:
+			i, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf(errGRPCFieldValue, value, field.Number, err)
+			}
+			msg = protowire.AppendTag(msg, num, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, uint64(i))
+		case esv1beta1.WebhookGRPCFieldTypeBool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf(errGRPCFieldValue, value, field.Number, err)
+			}
+			msg = protowire.AppendTag(msg, num, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, protowire.EncodeBool(b))
+		default:
+			return nil, fmt.Errorf(errGRPCFieldType, field.Type, field.Number)
+		}
+	}
+	return msg, nil
+}
+
+// grpcResponseField returns the field at the path of field numbers.
+// Like protobuf, the last occurrence of a field wins. Varint and fixed
+// fields are returned as decimal numbers.
+func grpcResponseField(msg []byte, path string) ([]byte, error) {
+	if path == "" {
+		return msg, nil
+	}
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 32)
+		if err != nil || !protowire.Number(n).IsValid() {
+			return nil, fmt.Errorf(errGRPCResponsePath, path)
+		}
+		num := protowire.Number(n)
+		value, typ, err := lastField(msg, num)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, fmt.Errorf(errGRPCFieldNotFound, num, path)
+		}
+		if i == len(parts)-1 {
+			return fieldValue(value, typ)
+		}
+		if typ != protowire.BytesType {
+			return nil, fmt.Errorf(errGRPCFieldNotBytes, num, path)
+		}
+		msg, _ = protowire.ConsumeBytes(value)
+	}
+	return msg, nil
+}
+
+// lastField returns the encoded value of the last occurrence of the field.
+func lastField(msg []byte, num protowire.Number) ([]byte, protowire.Type, error) {
+	var value []byte
+	var typ protowire.Type
+	for len(msg) > 0 {
+		n, t, tagLen := protowire.ConsumeTag(msg)
+		if tagLen < 0 {
+			return nil, 0, fmt.Errorf(errGRPCMessage, protowire.ParseError(tagLen))
+		}
+		valueLen := protowire.ConsumeFieldValue(n, t, msg[tagLen:])
+		if valueLen < 0 {
+			return nil, 0, fmt.Errorf(errGRPCMessage, protowire.ParseError(valueLen))
+		}
+		if n == num {
+			value, typ = msg[tagLen:tagLen+valueLen], t
+		}
+		msg = msg[tagLen+valueLen:]
+	}
+	return value, typ, nil
+}
+
+func fieldValue(value []byte, typ protowire.Type) ([]byte, error) {
+	switch typ {
+	case protowire.BytesType:
+		b, _ := protowire.ConsumeBytes(value)
+		return b, nil
+	case protowire.VarintType:
+		v, _ := protowire.ConsumeVarint(value)
+		return []byte(strconv.FormatInt(int64(v), 10)), nil
+	case protowire.Fixed32Type:
+		v, _ := protowire.ConsumeFixed32(value)
+		return []byte(strconv.FormatUint(uint64(v), 10)), nil
+	case protowire.Fixed64Type:
+		v, _ := protowire.ConsumeFixed64(value)
+		return []byte(strconv.FormatUint(v, 10)), nil
+	default:
+		return nil, fmt.Errorf(errGRPCMessage, fmt.Errorf("unsupported wire type %d", typ))
+	}
+}
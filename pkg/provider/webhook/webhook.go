@@ -23,18 +23,21 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	tpl "text/template"
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/PaesslerAG/jsonpath"
+	"google.golang.org/grpc"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/redact"
 	"github.com/external-secrets/external-secrets/pkg/template/v2"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
@@ -47,14 +50,24 @@ var _ esv1beta1.Provider = &Provider{}
 type Provider struct{}
 
 type WebHook struct {
-	kube      client.Client
-	store     esv1beta1.GenericStore
-	namespace string
-	storeKind string
-	http      *http.Client
-	url       string
+	kube          client.Client
+	store         esv1beta1.GenericStore
+	namespace     string
+	storeKind     string
+	http          *http.Client
+	grpc          *grpc.ClientConn
+	url           string
+	retries       int
+	retryInterval time.Duration
+	cache         *responseCache
 }
 
+const (
+	defaultRetries       = 3
+	defaultRetryInterval = time.Second
+	maxRetryInterval     = 30 * time.Second
+)
+
 func init() {
 	esv1beta1.Register(&Provider{}, &esv1beta1.SecretStoreProvider{
 		Webhook: &esv1beta1.WebhookProvider{},
@@ -74,10 +87,37 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 	}
 	whClient.url = provider.URL
 
+	if provider.GRPC != nil {
+		whClient.grpc, err = whClient.dialGRPC(provider)
+		if err != nil {
+			return nil, err
+		}
+		return whClient, nil
+	}
+
 	whClient.http, err = whClient.getHTTPClient(provider)
 	if err != nil {
 		return nil, err
 	}
+	if retry := store.GetSpec().RetrySettings; retry != nil {
+		whClient.retries = defaultRetries
+		if retry.MaxRetries != nil {
+			whClient.retries = int(*retry.MaxRetries)
+		}
+		whClient.retryInterval = defaultRetryInterval
+		if retry.RetryInterval != nil {
+			whClient.retryInterval, err = time.ParseDuration(*retry.RetryInterval)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse retry interval: %w", err)
+			}
+		}
+	}
+	if provider.Cache != nil {
+		whClient.cache, err = getResponseCache(store, provider.Cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create response cache: %w", err)
+		}
+	}
 	return whClient, nil
 }
 
@@ -85,6 +125,13 @@ func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 	return nil
 }
 
+// Capabilities returns the capabilities of the provider.
+func (p *Provider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
+
 func getProvider(store esv1beta1.GenericStore) (*esv1beta1.WebhookProvider, error) {
 	spc := store.GetSpec()
 	if spc == nil || spc.Provider == nil || spc.Provider.Webhook == nil {
@@ -122,7 +169,7 @@ func (w *WebHook) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDat
 	if err != nil {
 		return nil, fmt.Errorf("failed to get store: %w", err)
 	}
-	result, err := w.getWebhookData(ctx, provider, ref)
+	result, err := w.getData(ctx, provider, ref)
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +198,7 @@ func (w *WebHook) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecret
 	if err != nil {
 		return nil, fmt.Errorf("failed to get store: %w", err)
 	}
-	result, err := w.getWebhookData(ctx, provider, ref)
+	result, err := w.getData(ctx, provider, ref)
 	if err != nil {
 		return nil, err
 	}
@@ -195,12 +242,17 @@ func (w *WebHook) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecret
 	return values, nil
 }
 
-func (w *WebHook) getTemplateData(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef, secrets []esv1beta1.WebhookSecret) (map[string]map[string]string, error) {
+// getTemplateData returns the template data, the remoteRef is query escaped for HTTP requests.
+func (w *WebHook) getTemplateData(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef, secrets []esv1beta1.WebhookSecret, escape bool) (map[string]map[string]string, error) {
+	escapeFn := url.QueryEscape
+	if !escape {
+		escapeFn = func(s string) string { return s }
+	}
 	data := map[string]map[string]string{
 		"remoteRef": {
-			"key":      url.QueryEscape(ref.Key),
-			"version":  url.QueryEscape(ref.Version),
-			"property": url.QueryEscape(ref.Property),
+			"key":      escapeFn(ref.Key),
+			"version":  escapeFn(ref.Version),
+			"property": escapeFn(ref.Property),
 		},
 	}
 	for _, secref := range secrets {
@@ -218,11 +270,19 @@ func (w *WebHook) getTemplateData(ctx context.Context, ref esv1beta1.ExternalSec
 	return data, nil
 }
 
+// getData calls the gRPC method if configured, the HTTP endpoint otherwise.
+func (w *WebHook) getData(ctx context.Context, provider *esv1beta1.WebhookProvider, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if provider.GRPC != nil {
+		return w.getGRPCData(ctx, provider, ref)
+	}
+	return w.getWebhookData(ctx, provider, ref)
+}
+
 func (w *WebHook) getWebhookData(ctx context.Context, provider *esv1beta1.WebhookProvider, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if w.http == nil {
 		return nil, fmt.Errorf("http client not initialized")
 	}
-	data, err := w.getTemplateData(ctx, ref, provider.Secrets)
+	data, err := w.getTemplateData(ctx, ref, provider.Secrets, true)
 	if err != nil {
 		return nil, err
 	}
@@ -239,27 +299,116 @@ func (w *WebHook) getWebhookData(ctx context.Context, provider *esv1beta1.Webhoo
 		return nil, fmt.Errorf("failed to parse body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, &body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	header := http.Header{}
 	for hKey, hValueTpl := range provider.Headers {
 		hValue, err := executeTemplateString(hValueTpl, data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse header %s: %w", hKey, err)
 		}
-		req.Header.Add(hKey, hValue)
+		header.Add(hKey, hValue)
+	}
+
+	key := newCacheKey(method, url, body.Bytes(), header)
+	cached, ok := w.cache.get(key)
+	if ok && w.cache.fresh(cached) {
+		return cached.body, nil
+	}
+	if ok && w.cache.servableStale(cached) {
+		if w.cache.startRefresh(key) {
+			go func() {
+				defer w.cache.finishRefresh(key)
+				ctx, cancel := context.WithTimeout(context.Background(), backgroundRefreshTimeout)
+				defer cancel()
+				if _, err := w.fetchWebhookData(ctx, key, cached, method, url, body.Bytes(), header); err != nil {
+					// the rendered url may carry secret material, the store is logged instead
+					log.Error(redact.Error(err), "failed to refresh cached webhook response", "store", w.store.GetNamespacedName(), "kind", w.storeKind)
+				}
+			}()
+		}
+		return cached.body, nil
+	}
+	return w.fetchWebhookData(ctx, key, cached, method, url, body.Bytes(), header)
+}
+
+// fetchWebhookData calls the endpoint and caches the response.
+// A cached entry is revalidated with its ETag.
+func (w *WebHook) fetchWebhookData(ctx context.Context, key string, cached *cacheEntry, method, url string, body []byte, header http.Header) ([]byte, error) {
+	if cached != nil && cached.etag != "" {
+		header = header.Clone()
+		header.Set("If-None-Match", cached.etag)
 	}
 
-	resp, err := w.http.Do(req)
+	resp, err := w.doWithRetry(ctx, method, url, body, header)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call endpoint: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		w.cache.revalidated(key, cached, resp.Header)
+		return cached.body, nil
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("endpoint gave error %s", resp.Status)
 	}
-	return io.ReadAll(resp.Body)
+	result, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	w.cache.store(key, result, resp.Header)
+	return result, nil
+}
+
+// doWithRetry calls the endpoint and retries 5xx and 429 responses
+// with exponential backoff. A Retry-After header is honored.
+// Requests with a method that is not idempotent are never retried.
+func (w *WebHook) doWithRetry(ctx context.Context, method, url string, body []byte, header http.Header) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header = header.Clone()
+		resp, err := w.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call endpoint: %w", err)
+		}
+		if attempt >= w.retries || !isIdempotent(method) || !isRetryable(resp.StatusCode) {
+			return resp, nil
+		}
+		delay := retryDelay(resp.Header, w.retryInterval, attempt)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to call endpoint: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isIdempotent reports whether a request with method can be sent
+// again without side effects, see RFC 9110 section 9.2.2.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isRetryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+func retryDelay(header http.Header, interval time.Duration, attempt int) time.Duration {
+	delay := interval << attempt
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+	if delay > maxRetryInterval || delay < 0 {
+		delay = maxRetryInterval
+	}
+	return delay
 }
 
 func (w *WebHook) getHTTPClient(provider *esv1beta1.WebhookProvider) (*http.Client, error) {
@@ -280,7 +429,7 @@ func (w *WebHook) getHTTPClient(provider *esv1beta1.WebhookProvider) (*http.Clie
 		RootCAs:    caCertPool,
 		MinVersion: tls.VersionTLS12,
 	}
-	client.Transport = &http.Transport{TLSClientConfig: tlsConf}
+	client.Transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConf}
 	return client, nil
 }
 
@@ -391,12 +540,19 @@ func (w *WebHook) getCertFromConfigMap(provider *esv1beta1.WebhookProvider) ([]b
 }
 
 func (w *WebHook) Close(ctx context.Context) error {
+	if w.grpc != nil {
+		return w.grpc.Close()
+	}
 	return nil
 }
 
 func (w *WebHook) Validate() (esv1beta1.ValidationResult, error) {
 	timeout := 15 * time.Second
 	url := w.url
+	if w.grpc != nil {
+		// the gRPC target is host:port
+		url = "grpc://" + url
+	}
 
 	if err := utils.NetworkValidate(url, timeout); err != nil {
 		return esv1beta1.ValidationResultError, err
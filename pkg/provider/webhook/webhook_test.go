@@ -17,13 +17,19 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
 	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -339,3 +345,295 @@ func makeClusterSecretStore(url string, args args) *esv1beta1.ClusterSecretStore
 	}
 	return store
 }
+
+func TestWebhookRetry(t *testing.T) {
+	calls := 0
+	failures := []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if len(failures) > 0 {
+			rw.WriteHeader(failures[0])
+			failures = failures[1:]
+			return
+		}
+		rw.Write([]byte("secret-value"))
+	}))
+	defer ts.Close()
+
+	maxRetries := int32(2)
+	retryInterval := "1ms"
+	testStore := makeClusterSecretStore(ts.URL, args{URL: "/api/getsecret"})
+	testStore.Spec.RetrySettings = &esv1beta1.SecretStoreRetrySettings{
+		MaxRetries:    &maxRetries,
+		RetryInterval: &retryInterval,
+	}
+	client, err := (&Provider{}).NewClient(context.Background(), testStore, nil, "testnamespace")
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	secret, err := client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "testkey"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(secret) != "secret-value" || calls != 3 {
+		t.Errorf("expected secret after 3 calls, got %q after %d calls", secret, calls)
+	}
+
+	// retries are exhausted
+	failures = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+	_, err = client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "testkey"})
+	if err == nil || !strings.Contains(err.Error(), "endpoint gave error 503") {
+		t.Errorf("expected error after exhausted retries, got %v", err)
+	}
+
+	// requests that are not idempotent are not retried
+	testStore.Spec.Provider.Webhook.Method = http.MethodPost
+	client, err = (&Provider{}).NewClient(context.Background(), testStore, nil, "testnamespace")
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	calls = 0
+	failures = []int{http.StatusServiceUnavailable}
+	_, err = client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "testkey"})
+	if err == nil || calls != 1 {
+		t.Errorf("expected error after a single POST, got %v after %d calls", err, calls)
+	}
+}
+
+func TestWebhookCache(t *testing.T) {
+	calls := 0
+	revalidations := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.Header().Set("ETag", `"v1"`)
+		rw.Header().Set("Cache-Control", "max-age=60")
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			revalidations++
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Write([]byte("secret-value"))
+	}))
+	defer ts.Close()
+
+	testStore := makeClusterSecretStore(ts.URL, args{URL: "/api/getsecret?id={{ .remoteRef.key }}"})
+	testStore.Name = "webhook-cache-store"
+	testStore.Spec.Provider.Webhook.Cache = &esv1beta1.WebhookCache{}
+	client, err := (&Provider{}).NewClient(context.Background(), testStore, nil, "testnamespace")
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	now := time.Unix(0, 0)
+	client.(*WebHook).cache.now = func() time.Time { return now }
+
+	ref := esv1beta1.ExternalSecretDataRemoteRef{Key: "testkey"}
+	for i := 0; i < 3; i++ {
+		secret, err := client.GetSecret(context.Background(), ref)
+		if err != nil || string(secret) != "secret-value" {
+			t.Fatalf("unexpected result %q, %v", secret, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected fresh responses to be served from cache, got %d calls", calls)
+	}
+
+	// a stale response is revalidated with its ETag
+	now = now.Add(2 * time.Minute)
+	secret, err := client.GetSecret(context.Background(), ref)
+	if err != nil || string(secret) != "secret-value" {
+		t.Fatalf("unexpected result %q, %v", secret, err)
+	}
+	if calls != 2 || revalidations != 1 {
+		t.Errorf("expected one revalidation, got %d calls and %d revalidations", calls, revalidations)
+	}
+
+	// different keys are cached separately
+	_, err = client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "otherkey"})
+	if err != nil || calls != 3 {
+		t.Errorf("expected a new call for a different key, got %d calls, %v", calls, err)
+	}
+}
+
+func TestWebhookCacheMaxStaleness(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Write([]byte(fmt.Sprintf("v%d", n)))
+	}))
+	defer ts.Close()
+
+	testStore := makeClusterSecretStore(ts.URL, args{URL: "/api/getsecret?id={{ .remoteRef.key }}"})
+	testStore.Name = "webhook-stale-cache-store"
+	testStore.Spec.Provider.Webhook.Cache = &esv1beta1.WebhookCache{MaxStaleness: &metav1.Duration{Duration: 5 * time.Minute}}
+	client, err := (&Provider{}).NewClient(context.Background(), testStore, nil, "testnamespace")
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	now := time.Unix(0, 0)
+	client.(*WebHook).cache.now = func() time.Time { return now }
+	ref := esv1beta1.ExternalSecretDataRemoteRef{Key: "testkey"}
+	get := func() string {
+		t.Helper()
+		secret, err := client.GetSecret(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(secret)
+	}
+
+	if got := get(); got != "v1" {
+		t.Fatalf("unexpected result %q", got)
+	}
+
+	// an expired response within maxStaleness is returned right away and refreshed in the background
+	now = now.Add(2 * time.Minute)
+	if got := get(); got != "v1" {
+		t.Fatalf("expected the stale response, got %q", got)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for get() != "v2" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the response to be refreshed in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// responses beyond maxStaleness are fetched before they are returned
+	now = now.Add(10 * time.Minute)
+	if got := get(); got != "v3" {
+		t.Errorf("expected a new response, got %q", got)
+	}
+}
+
+func TestResponseCacheIdleEviction(t *testing.T) {
+	idleStore := makeClusterSecretStore("http://localhost", args{})
+	idleStore.Name = "webhook-idle-cache-store"
+	usedStore := makeClusterSecretStore("http://localhost", args{})
+	usedStore.Name = "webhook-used-cache-store"
+	idle, err := getResponseCache(idleStore, &esv1beta1.WebhookCache{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	used, err := getResponseCache(usedStore, &esv1beta1.WebhookCache{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	responseCachesMu.Lock()
+	idle.lastUsed = time.Now().Add(-cacheIdleTimeout)
+	responseCachesMu.Unlock()
+
+	if c, _ := getResponseCache(usedStore, &esv1beta1.WebhookCache{}); c != used {
+		t.Errorf("cache of a used store was recreated")
+	}
+	responseCachesMu.Lock()
+	defer responseCachesMu.Unlock()
+	for _, c := range responseCaches {
+		if c == idle {
+			t.Errorf("cache of an idle store was not evicted")
+		}
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	c := &responseCache{defaultTTL: time.Minute}
+	tests := []struct {
+		header    http.Header
+		ttl       time.Duration
+		cacheable bool
+	}{
+		{header: http.Header{}, ttl: time.Minute, cacheable: true},
+		{header: http.Header{"Cache-Control": {"max-age=30"}}, ttl: 30 * time.Second, cacheable: true},
+		{header: http.Header{"Cache-Control": {"no-store"}}, cacheable: false},
+		{header: http.Header{"Cache-Control": {"no-cache, max-age=30"}}, cacheable: false},
+		{header: http.Header{"Cache-Control": {"no-cache"}, "Etag": {`"v1"`}}, ttl: 0, cacheable: true},
+	}
+	for _, tt := range tests {
+		ttl, cacheable := c.ttl(tt.header)
+		if ttl != tt.ttl || cacheable != tt.cacheable {
+			t.Errorf("ttl(%v) = %v, %v, want %v, %v", tt.header, ttl, cacheable, tt.ttl, tt.cacheable)
+		}
+	}
+}
+
+func TestWebhookGRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the server decodes the request without a descriptor as well
+	srv := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		if method != "/secrets.v1.Secrets/GetSecret" {
+			return errors.New("unexpected method " + method)
+		}
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		if auth := md.Get("authorization"); len(auth) != 1 || auth[0] != "Bearer token" {
+			return errors.New("missing authorization")
+		}
+		var req []byte
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		key, err := grpcResponseField(req, "1")
+		if err != nil {
+			return err
+		}
+		version, err := grpcResponseField(req, "2")
+		if err != nil {
+			return err
+		}
+		// message Response { Secret secret = 1; } message Secret { string value = 2; }
+		var secret []byte
+		secret = protowire.AppendTag(secret, 2, protowire.BytesType)
+		secret = protowire.AppendString(secret, string(key)+"/"+string(version)+": a b")
+		var resp []byte
+		resp = protowire.AppendTag(resp, 1, protowire.BytesType)
+		resp = protowire.AppendBytes(resp, secret)
+		return stream.SendMsg(&resp)
+	}))
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Webhook: &esv1beta1.WebhookProvider{
+					URL:     lis.Addr().String(),
+					Headers: map[string]string{"authorization": "Bearer token"},
+					GRPC: &esv1beta1.WebhookGRPC{
+						Method:   "/secrets.v1.Secrets/GetSecret",
+						Insecure: true,
+						Request: []esv1beta1.WebhookGRPCField{
+							{Number: 1, Value: "{{ .remoteRef.key }}"},
+							{Number: 2, Type: esv1beta1.WebhookGRPCFieldTypeInt, Value: "{{ .remoteRef.version }}"},
+						},
+						ResponseFieldPath: "1.2",
+					},
+				},
+			},
+		},
+	}
+	client, err := (&Provider{}).NewClient(context.Background(), store, nil, "testnamespace")
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	defer client.Close(context.Background())
+	secret, err := client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db/password", Version: "3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the key is not query escaped and the version is a varint
+	if string(secret) != "db/password/3: a b" {
+		t.Errorf("unexpected secret %q", secret)
+	}
+
+	store.Spec.Provider.Webhook.GRPC.ResponseFieldPath = "1.3"
+	if _, err := client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Version: "1"}); err == nil {
+		t.Errorf("expected error for missing response field")
+	}
+	store.Spec.Provider.Webhook.GRPC.ResponseFieldPath = "1.2"
+	if _, err := client.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db", Version: "latest"}); err == nil {
+		t.Errorf("expected error for invalid int field")
+	}
+}
@@ -258,3 +258,10 @@ func (p *YandexCloudProvider) ValidateStore(store esv1beta1.GenericStore) error
 	}
 	return nil
 }
+
+// Capabilities returns the capabilities of the provider.
+func (p *YandexCloudProvider) Capabilities(store esv1beta1.GenericStore) esv1beta1.SecretStoreCapabilities {
+	return esv1beta1.SecretStoreCapabilities{
+		Access: esv1beta1.SecretStoreReadOnly,
+	}
+}
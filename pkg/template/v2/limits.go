@@ -0,0 +1,260 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	tpl "text/template"
+	"text/template/parse"
+	"time"
+)
+
+var (
+	// ExecutionTimeout is the maximum time a template may take to render.
+	// Zero disables the timeout.
+	ExecutionTimeout = 10 * time.Second
+	// MaxOutputSize is the maximum number of bytes a template may render.
+	// It also bounds the values built by repeat, until and untilStep.
+	// Zero disables the limit.
+	MaxOutputSize = 1 << 20
+)
+
+// maxListLength bounds the lists built by until and untilStep.
+const maxListLength = 100000
+
+// maxRangeIterations bounds the iterations of all range actions of a template,
+// nested ranges over bounded lists could otherwise run for hours without writing.
+var maxRangeIterations = 1000000
+
+// rangeTickFunc is called at the start of every range iteration, see limitRanges.
+const rangeTickFunc = "rangeTick"
+
+const (
+	errTimeout       = "template did not render within %s"
+	errOutputTooLong = "template output exceeds %d bytes"
+	errListTooLong   = "%s would build a list of more than %d elements"
+	errRecursion     = "template %q calls itself recursively"
+	errAborted       = "template execution aborted"
+	errTooManyLoops  = "template exceeds %d range iterations"
+)
+
+// rangeTickNode is the action inserted into every range, it is shared by all templates.
+var rangeTickNode = func() parse.Node {
+	t := tpl.Must(tpl.New(rangeTickFunc).Funcs(tpl.FuncMap{rangeTickFunc: rangeTick}).Parse("{{ " + rangeTickFunc + " }}"))
+	return t.Tree.Root.Nodes[0]
+}()
+
+// rangeTick is replaced for every execution by executeLimited.
+func rangeTick() (string, error) {
+	return "", nil
+}
+
+// limitedBuffer fails writes once max bytes were written or after it was aborted,
+// which stops the execution of the template.
+type limitedBuffer struct {
+	bytes.Buffer
+	max     int
+	aborted atomic.Bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.aborted.Load() {
+		return 0, errors.New(errAborted)
+	}
+	if b.max > 0 && b.Len()+len(p) > b.max {
+		return 0, fmt.Errorf(errOutputTooLong, b.max)
+	}
+	return b.Buffer.Write(p)
+}
+
+// executeLimited renders t within ExecutionTimeout, MaxOutputSize and maxRangeIterations.
+// text/template can not be cancelled: on timeout the rendering goroutine is
+// aborted at its next write or range iteration, so it does not outlive the caller for long.
+// t must have been prepared with limitRanges.
+func executeLimited(t *tpl.Template, data interface{}) ([]byte, error) {
+	buf := &limitedBuffer{max: MaxOutputSize}
+	// only the rendering goroutine counts
+	iterations := 0
+	t.Funcs(tpl.FuncMap{rangeTickFunc: func() (string, error) {
+		if buf.aborted.Load() {
+			return "", errors.New(errAborted)
+		}
+		iterations++
+		if iterations > maxRangeIterations {
+			return "", fmt.Errorf(errTooManyLoops, maxRangeIterations)
+		}
+		return "", nil
+	}})
+	if ExecutionTimeout <= 0 {
+		if err := t.Execute(buf, data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Execute(buf, data)
+	}()
+	timer := time.NewTimer(ExecutionTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case <-timer.C:
+		buf.aborted.Store(true)
+		return nil, fmt.Errorf(errTimeout, ExecutionTimeout)
+	}
+}
+
+// checkRecursion returns an error if a template defined in t
+// calls itself, directly or through other templates.
+func checkRecursion(t *tpl.Template) error {
+	calls := make(map[string][]string)
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+		calls[tmpl.Name()] = templateCalls(tmpl.Tree.Root, nil)
+	}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf(errRecursion, name)
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		for _, callee := range calls[name] {
+			if err := visit(callee); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		return nil
+	}
+	for name := range calls {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limitRanges inserts a call of rangeTickFunc at the start of every
+// range action of t, which bounds and aborts the iterations.
+func limitRanges(t *tpl.Template) {
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree != nil {
+			insertRangeTicks(tmpl.Tree.Root)
+		}
+	}
+}
+
+func insertRangeTicks(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			insertRangeTicks(child)
+		}
+	case *parse.RangeNode:
+		insertRangeTicks(n.List)
+		insertRangeTicks(n.ElseList)
+		n.List.Nodes = append([]parse.Node{rangeTickNode}, n.List.Nodes...)
+	case *parse.IfNode:
+		insertRangeTicks(n.List)
+		insertRangeTicks(n.ElseList)
+	case *parse.WithNode:
+		insertRangeTicks(n.List)
+		insertRangeTicks(n.ElseList)
+	}
+}
+
+// templateCalls returns the names of the templates invoked below node.
+func templateCalls(node parse.Node, calls []string) []string {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return calls
+		}
+		for _, child := range n.Nodes {
+			calls = templateCalls(child, calls)
+		}
+	case *parse.TemplateNode:
+		calls = append(calls, n.Name)
+	case *parse.IfNode:
+		calls = templateCalls(n.List, templateCalls(n.ElseList, calls))
+	case *parse.RangeNode:
+		calls = templateCalls(n.List, templateCalls(n.ElseList, calls))
+	case *parse.WithNode:
+		calls = templateCalls(n.List, templateCalls(n.ElseList, calls))
+	}
+	return calls
+}
+
+// repeat replaces the sprig function, the result is bounded by MaxOutputSize.
+func repeat(count int, str string) (string, error) {
+	if MaxOutputSize > 0 && count > 0 && len(str) > 0 && count > MaxOutputSize/len(str) {
+		return "", fmt.Errorf(errOutputTooLong, MaxOutputSize)
+	}
+	if count < 0 {
+		count = 0
+	}
+	return strings.Repeat(str, count), nil
+}
+
+// until replaces the sprig function, the result is bounded by maxListLength.
+func until(count int) ([]int, error) {
+	step := 1
+	if count < 0 {
+		step = -1
+	}
+	return untilStep(0, count, step)
+}
+
+// untilStep replaces the sprig function, the result is bounded by maxListLength.
+func untilStep(start, stop, step int) ([]int, error) {
+	var v []int
+	if step == 0 || (stop < start && step > 0) || (stop > start && step < 0) {
+		return v, nil
+	}
+	n := (stop - start) / step
+	if (stop-start)%step != 0 {
+		n++
+	}
+	if n > maxListLength {
+		return nil, fmt.Errorf(errListTooLong, "until", maxListLength)
+	}
+	v = make([]int, 0, n)
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		v = append(v, i)
+	}
+	return v, nil
+}
@@ -14,7 +14,6 @@ limitations under the License.
 package template
 
 import (
-	"bytes"
 	"fmt"
 	tpl "text/template"
 
@@ -35,6 +34,7 @@ var tplFuncs = tpl.FuncMap{
 
 	"toYaml":   toYAML,
 	"fromYaml": fromYAML,
+	"toToml":   toTOML,
 }
 
 // So other templating calls can use the same extra functions.
@@ -56,6 +56,13 @@ func init() {
 	sprigFuncs := sprig.TxtFuncMap()
 	delete(sprigFuncs, "env")
 	delete(sprigFuncs, "expandenv")
+	// templates must not reach out to the network
+	delete(sprigFuncs, "getHostByName")
+	// functions building large values are bounded, see limits.go
+	sprigFuncs["repeat"] = repeat
+	sprigFuncs["until"] = until
+	sprigFuncs["untilStep"] = untilStep
+	sprigFuncs[rangeTickFunc] = rangeTick
 
 	for k, v := range sprigFuncs {
 		tplFuncs[k] = v
@@ -89,10 +96,13 @@ func execute(k, val string, data map[string][]byte) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf(errParse, k, err)
 	}
-	buf := bytes.NewBuffer(nil)
-	err = t.Execute(buf, strValData)
+	if err := checkRecursion(t); err != nil {
+		return nil, fmt.Errorf(errParse, k, err)
+	}
+	limitRanges(t)
+	out, err := executeLimited(t, strValData)
 	if err != nil {
 		return nil, fmt.Errorf(errExecute, k, err)
 	}
-	return buf.Bytes(), nil
+	return out, nil
 }
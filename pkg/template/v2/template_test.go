@@ -17,6 +17,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
@@ -206,6 +207,88 @@ func TestExecute(t *testing.T) {
 				"foo": []byte(`{"foo":"bar"}`),
 			},
 		},
+		{
+			name: "fromYaml & set & toYaml func",
+			tpl: map[string][]byte{
+				"config.yaml": []byte(`{{ $cfg := .config | fromYaml }}{{ $_ := set $cfg.db "password" .password }}{{ $cfg | toYaml }}`),
+			},
+			data: map[string][]byte{
+				"config":   []byte("db:\n  host: localhost\n  user: app"),
+				"password": []byte("s3cr3t"),
+			},
+			expetedData: map[string][]byte{
+				"config.yaml": []byte("db:\n  host: localhost\n  password: s3cr3t\n  user: app"),
+			},
+		},
+		{
+			name: "fromJson & merge & toJson func",
+			tpl: map[string][]byte{
+				"foo": []byte(`{{ merge (.override | fromJson) (.secret | fromJson) | toJson }}`),
+			},
+			data: map[string][]byte{
+				"secret":   []byte(`{"foo": "bar", "baz": "bang"}`),
+				"override": []byte(`{"foo": "boom"}`),
+			},
+			expetedData: map[string][]byte{
+				"foo": []byte(`{"baz":"bang","foo":"boom"}`),
+			},
+		},
+		{
+			name: "fromYaml & toToml func",
+			tpl: map[string][]byte{
+				"config.toml": []byte(`{{ .config | fromYaml | toToml }}`),
+			},
+			data: map[string][]byte{
+				"config": []byte(`
+title: "app \"prod\""
+port: 8080
+debug: false
+ratio: 0.5
+hosts: [a, b]
+empty: null
+database:
+  user: app
+  conn.timeout: 30
+  pool:
+    size: 5
+servers:
+- name: alpha
+- name: beta
+  weight: 2
+`),
+			},
+			expetedData: map[string][]byte{
+				"config.toml": []byte(`debug = false
+hosts = ["a", "b"]
+port = 8080
+ratio = 0.5
+title = "app \"prod\""
+
+[database]
+"conn.timeout" = 30
+user = "app"
+
+[database.pool]
+size = 5
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+weight = 2`),
+			},
+		},
+		{
+			name: "toToml with non-table value",
+			tpl: map[string][]byte{
+				"foo": []byte(`{{ list "a" "b" | toToml }}`),
+			},
+			data: map[string][]byte{},
+			expetedData: map[string][]byte{
+				"foo": nil,
+			},
+		},
 		{
 			name: "use sprig functions",
 			tpl: map[string][]byte{
@@ -421,6 +504,77 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteLimits(t *testing.T) {
+	defer func(timeout time.Duration, size, iterations int) {
+		ExecutionTimeout = timeout
+		MaxOutputSize = size
+		maxRangeIterations = iterations
+	}(ExecutionTimeout, MaxOutputSize, maxRangeIterations)
+	ExecutionTimeout = 200 * time.Millisecond
+	MaxOutputSize = 16
+	maxRangeIterations = 1000
+
+	tbl := []struct {
+		name   string
+		tpl    string
+		expErr string
+	}{
+		{
+			name: "within limits",
+			tpl:  `{{ range until 3 }}{{ . }}{{ end }}`,
+		},
+		{
+			name:   "output too long",
+			tpl:    `{{ range until 100 }}{{ . }}{{ end }}`,
+			expErr: "template output exceeds 16 bytes",
+		},
+		{
+			name:   "repeat too long",
+			tpl:    `{{ repeat 100 "x" | len }}`,
+			expErr: "template output exceeds 16 bytes",
+		},
+		{
+			name:   "list too long",
+			tpl:    `{{ until 1000000 | len }}`,
+			expErr: "until would build a list of more than",
+		},
+		{
+			name:   "recursive template",
+			tpl:    `{{ define "a" }}{{ template "b" . }}{{ end }}{{ define "b" }}{{ if . }}{{ template "a" . }}{{ end }}{{ end }}{{ template "a" . }}`,
+			expErr: "calls itself recursively",
+		},
+		{
+			name:   "network lookup",
+			tpl:    `{{ getHostByName "example.com" }}`,
+			expErr: `function "getHostByName" not defined`,
+		},
+		{
+			name:   "too many range iterations",
+			tpl:    `{{ range until 100000 }}{{ range until 100000 }}{{ end }}{{ end }}`,
+			expErr: "template exceeds 1000 range iterations",
+		},
+		{
+			name: "range else",
+			tpl:  `{{ range until 0 }}{{ . }}{{ else }}empty{{ end }}`,
+		},
+		{
+			name:   "timeout",
+			tpl:    `{{ range until 1000 }}{{ $hash := bcrypt "x" }}{{ end }}`,
+			expErr: "template did not render within 200ms",
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.name, func(t *testing.T) {
+			sec := &corev1.Secret{Data: make(map[string][]byte)}
+			err := Execute(map[string][]byte{"out": []byte(row.tpl)}, nil, sec)
+			if !ErrorContains(err, row.expErr) {
+				t.Errorf("unexpected error: %s, expected: %s", err, row.expErr)
+			}
+		})
+	}
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -0,0 +1,195 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var bareTOMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// toTOML takes an interface, marshals it to toml, and returns a string. It will
+// always return a string, even on marshal error (empty string).
+//
+// The value must be a map, e.g. the result of fromYaml or fromJson.
+// Null values are omitted because TOML has no representation for them.
+//
+// This is designed to be called from a template.
+func toTOML(v interface{}) string {
+	// normalize the value to maps, slices and scalars
+	raw, err := json.Marshal(v)
+	if err != nil {
+		// Swallow errors inside of a template.
+		return ""
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var table map[string]interface{}
+	if err := dec.Decode(&table); err != nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	writeTOMLTable(&buf, nil, table)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// writeTOMLTable writes the key/value pairs of the table at path,
+// followed by its sub-tables and arrays of tables.
+func writeTOMLTable(buf *bytes.Buffer, path []string, table map[string]interface{}) {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var tables, tableArrays []string
+	for _, k := range keys {
+		switch t := table[k].(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			tables = append(tables, k)
+		case []interface{}:
+			if isTOMLTableArray(t) {
+				tableArrays = append(tableArrays, k)
+				continue
+			}
+			fmt.Fprintf(buf, "%s = %s\n", tomlKey(k), tomlValue(t))
+		default:
+			fmt.Fprintf(buf, "%s = %s\n", tomlKey(k), tomlValue(t))
+		}
+	}
+	for _, k := range tables {
+		sub := append(append([]string{}, path...), k)
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "[%s]\n", tomlPath(sub))
+		writeTOMLTable(buf, sub, table[k].(map[string]interface{}))
+	}
+	for _, k := range tableArrays {
+		sub := append(append([]string{}, path...), k)
+		for _, item := range table[k].([]interface{}) {
+			if buf.Len() > 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(buf, "[[%s]]\n", tomlPath(sub))
+			writeTOMLTable(buf, sub, item.(map[string]interface{}))
+		}
+	}
+}
+
+// isTOMLTableArray reports whether all elements of a non-empty array are tables.
+func isTOMLTableArray(arr []interface{}) bool {
+	if len(arr) == 0 {
+		return false
+	}
+	for _, item := range arr {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlValue returns the inline representation of a value.
+func tomlValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return tomlString(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case json.Number:
+		return t.String()
+	case []interface{}:
+		items := make([]string, 0, len(t))
+		for _, item := range t {
+			if item == nil {
+				continue
+			}
+			items = append(items, tomlValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			if t[k] != nil {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, tomlKey(k)+" = "+tomlValue(t[k]))
+		}
+		if len(items) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	}
+	return tomlString(fmt.Sprint(v))
+}
+
+func tomlPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, k := range path {
+		keys[i] = tomlKey(k)
+	}
+	return strings.Join(keys, ".")
+}
+
+func tomlKey(k string) string {
+	if bareTOMLKey.MatchString(k) {
+		return k
+	}
+	return tomlString(k)
+}
+
+// tomlString returns s as TOML basic string.
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package useragent builds the user agent providers send to their APIs,
+// so audit logs attribute requests to the cluster and store they were made for.
+package useragent
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const product = "external-secrets"
+
+// Version is the version of external-secrets reported in the user agent.
+// It is set at build time with -ldflags "-X github.com/external-secrets/external-secrets/pkg/useragent.Version=<version>".
+var Version = "dev"
+
+// ClusterName identifies the cluster in the user agent, it is omitted if empty.
+var ClusterName string
+
+// ForStore returns the user agent of requests made on behalf of store, e.g.
+// external-secrets/v0.6.0 (cluster=prod; store=SecretStore/team-a/vault).
+func ForStore(store esv1beta1.GenericStore) string {
+	ref := fmt.Sprintf("%s/%s", esv1beta1.ClusterSecretStoreKind, store.GetName())
+	if store.GetNamespace() != "" {
+		ref = fmt.Sprintf("%s/%s/%s", esv1beta1.SecretStoreKind, store.GetNamespace(), store.GetName())
+	}
+	comments := []string{"store=" + ref}
+	if ClusterName != "" {
+		comments = append([]string{"cluster=" + sanitize(ClusterName)}, comments...)
+	}
+	return fmt.Sprintf("%s/%s (%s)", product, sanitize(Version), strings.Join(comments, "; "))
+}
+
+// sanitize removes characters that would end a user agent comment or header.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '(' || r == ')' || r == ';' || r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
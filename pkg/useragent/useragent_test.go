@@ -0,0 +1,63 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package useragent
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestForStore(t *testing.T) {
+	store := &esv1beta1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "team-a"}}
+	clusterStore := &esv1beta1.ClusterSecretStore{ObjectMeta: metav1.ObjectMeta{Name: "vault"}}
+	tests := []struct {
+		name    string
+		cluster string
+		store   esv1beta1.GenericStore
+		want    string
+	}{
+		{
+			name:  "SecretStore",
+			store: store,
+			want:  "external-secrets/v1.2.3 (store=SecretStore/team-a/vault)",
+		},
+		{
+			name:    "ClusterSecretStore",
+			cluster: "prod",
+			store:   clusterStore,
+			want:    "external-secrets/v1.2.3 (cluster=prod; store=ClusterSecretStore/vault)",
+		},
+		{
+			name:    "sanitized cluster name",
+			cluster: "prod (eu);\n",
+			store:   clusterStore,
+			want:    "external-secrets/v1.2.3 (cluster=prod eu; store=ClusterSecretStore/vault)",
+		},
+	}
+	oldVersion, oldCluster := Version, ClusterName
+	defer func() { Version, ClusterName = oldVersion, oldCluster }()
+	Version = "v1.2.3"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ClusterName = tt.cluster
+			if got := ForStore(tt.store); got != tt.want {
+				t.Errorf("ForStore() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	errCertMissingNamespace   = "missing namespace"
+	errCertFetchCredentials   = "could not fetch credentials: %w"
+	errCertMissingCredentials = "missing credentials: %q"
+	errInvalidClientCert      = "invalid client certificate: %w"
+	errInvalidCA              = "unable to parse CA certificate"
+)
+
+// CertResolver fetches the client certificate and CA referenced by a store
+// from the Secrets and ConfigMaps of the namespace the store is used in.
+type CertResolver struct {
+	Kube      client.Client
+	Namespace string
+	StoreKind string
+}
+
+// NewCertResolver returns a CertResolver for the store used in namespace.
+func NewCertResolver(kube client.Client, store esv1beta1.GenericStore, namespace string) *CertResolver {
+	return &CertResolver{
+		Kube:      kube,
+		Namespace: namespace,
+		StoreKind: store.GetObjectKind().GroupVersionKind().Kind,
+	}
+}
+
+// ClientTLSConfig returns a TLS config authenticating with the client certificate of auth.
+// The server is verified with caBundle or the CA of caProvider if set, the system CAs otherwise.
+func (r *CertResolver) ClientTLSConfig(ctx context.Context, auth *esv1beta1.CertAuth, caBundle []byte, caProvider *esv1beta1.CAProvider) (*tls.Config, error) {
+	certPEM, err := r.FetchSecretKey(ctx, auth.ClientCert)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := r.FetchSecretKey(ctx, auth.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidClientCert, err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	ca, err := r.FetchCA(ctx, caBundle, caProvider)
+	if err != nil {
+		return nil, err
+	}
+	if ca != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf(errInvalidCA)
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// FetchCA returns caBundle if it is set, the CA referenced by caProvider otherwise.
+// It returns nil if neither is set.
+func (r *CertResolver) FetchCA(ctx context.Context, caBundle []byte, caProvider *esv1beta1.CAProvider) ([]byte, error) {
+	if len(caBundle) > 0 {
+		return caBundle, nil
+	}
+	if caProvider == nil {
+		return nil, nil
+	}
+	keySelector := esmeta.SecretKeySelector{
+		Name:      caProvider.Name,
+		Namespace: caProvider.Namespace,
+		Key:       caProvider.Key,
+	}
+	if caProvider.Type == esv1beta1.CAProviderTypeConfigMap {
+		return r.FetchConfigMapKey(ctx, keySelector)
+	}
+	return r.FetchSecretKey(ctx, keySelector)
+}
+
+// FetchSecretKey returns the value of a Secret key, an empty value is an error.
+func (r *CertResolver) FetchSecretKey(ctx context.Context, key esmeta.SecretKeySelector) ([]byte, error) {
+	objectKey, err := r.objectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := r.Kube.Get(ctx, objectKey, secret); err != nil {
+		return nil, fmt.Errorf(errCertFetchCredentials, err)
+	}
+	val, ok := secret.Data[key.Key]
+	if !ok || len(val) == 0 {
+		return nil, fmt.Errorf(errCertMissingCredentials, key.Key)
+	}
+	return val, nil
+}
+
+// FetchConfigMapKey returns the value of a ConfigMap key, an empty value is an error.
+func (r *CertResolver) FetchConfigMapKey(ctx context.Context, key esmeta.SecretKeySelector) ([]byte, error) {
+	objectKey, err := r.objectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := r.Kube.Get(ctx, objectKey, configMap); err != nil {
+		return nil, fmt.Errorf(errCertFetchCredentials, err)
+	}
+	val, ok := configMap.Data[key.Key]
+	if !ok || val == "" {
+		return nil, fmt.Errorf(errCertMissingCredentials, key.Key)
+	}
+	return []byte(val), nil
+}
+
+func (r *CertResolver) objectKey(key esmeta.SecretKeySelector) (types.NamespacedName, error) {
+	objectKey := types.NamespacedName{
+		Name:      key.Name,
+		Namespace: r.Namespace,
+	}
+	// only ClusterStore is allowed to set namespace (and then it's required)
+	if r.StoreKind == esv1beta1.ClusterSecretStoreKind {
+		if key.Namespace == nil {
+			return objectKey, fmt.Errorf(errCertMissingNamespace)
+		}
+		objectKey.Namespace = *key.Namespace
+	}
+	return objectKey, nil
+}
@@ -0,0 +1,117 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func TestCertResolverFetchCA(t *testing.T) {
+	kube := clientfake.NewClientBuilder().WithObjects(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "team-a"},
+			Data:       map[string][]byte{"ca.crt": []byte("secret-ca")},
+		},
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "shared"},
+			Data:       map[string]string{"ca.crt": "configmap-ca"},
+		},
+	).Build()
+	shared := "shared"
+
+	tests := []struct {
+		name       string
+		storeKind  string
+		caBundle   []byte
+		caProvider *esv1beta1.CAProvider
+		want       string
+		wantErr    string
+	}{
+		{name: "no CA"},
+		{
+			name:       "caBundle takes precedence",
+			caBundle:   []byte("bundle"),
+			caProvider: &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeSecret, Name: "ca", Key: "ca.crt"},
+			want:       "bundle",
+		},
+		{
+			name:       "secret in the namespace of the store",
+			storeKind:  esv1beta1.SecretStoreKind,
+			caProvider: &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeSecret, Name: "ca", Key: "ca.crt", Namespace: &shared},
+			want:       "secret-ca",
+		},
+		{
+			name:       "configmap of a cluster store",
+			storeKind:  esv1beta1.ClusterSecretStoreKind,
+			caProvider: &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeConfigMap, Name: "ca", Key: "ca.crt", Namespace: &shared},
+			want:       "configmap-ca",
+		},
+		{
+			name:       "cluster store without namespace",
+			storeKind:  esv1beta1.ClusterSecretStoreKind,
+			caProvider: &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeSecret, Name: "ca", Key: "ca.crt"},
+			wantErr:    errCertMissingNamespace,
+		},
+		{
+			name:       "missing key",
+			storeKind:  esv1beta1.SecretStoreKind,
+			caProvider: &esv1beta1.CAProvider{Type: esv1beta1.CAProviderTypeSecret, Name: "ca", Key: "tls.crt"},
+			wantErr:    `missing credentials: "tls.crt"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &CertResolver{Kube: kube, Namespace: "team-a", StoreKind: tt.storeKind}
+			got, err := r.FetchCA(context.Background(), tt.caBundle, tt.caProvider)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FetchCA() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("FetchCA() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCertResolverClientTLSConfig(t *testing.T) {
+	kube := clientfake.NewClientBuilder().WithObjects(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "team-a"},
+		Data:       map[string][]byte{"tls.crt": []byte("invalid"), "tls.key": []byte("invalid")},
+	}).Build()
+	r := &CertResolver{Kube: kube, Namespace: "team-a", StoreKind: esv1beta1.SecretStoreKind}
+	auth := &esv1beta1.CertAuth{
+		ClientCert: esmeta.SecretKeySelector{Name: "client", Key: "tls.crt"},
+		ClientKey:  esmeta.SecretKeySelector{Name: "client", Key: "tls.key"},
+	}
+	_, err := r.ClientTLSConfig(context.Background(), auth, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid client certificate") {
+		t.Errorf("expected an invalid client certificate error, got %v", err)
+	}
+}
@@ -263,6 +263,13 @@ func ValidateReferentServiceAccountSelector(store esv1beta1.GenericStore, ref es
 	return nil
 }
 
+// CredentialsExpired returns true if a client built for store at created
+// must not be reused because the credentialsRefreshInterval of the store passed.
+func CredentialsExpired(store esv1beta1.GenericStore, created time.Time) bool {
+	interval := store.GetSpec().CredentialsRefreshInterval
+	return interval != nil && interval.Duration > 0 && time.Since(created) >= interval.Duration
+}
+
 func NetworkValidate(endpoint string, timeout time.Duration) error {
 	hostname, err := url.Parse(endpoint)
 
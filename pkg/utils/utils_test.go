@@ -21,6 +21,7 @@ import (
 
 	vault "github.com/oracle/oci-go-sdk/v56/vault"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -493,3 +494,44 @@ func TestRewriteRegexp(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialsExpired(t *testing.T) {
+	store := func(interval *metav1.Duration) esv1beta1.GenericStore {
+		return &esv1beta1.SecretStore{Spec: esv1beta1.SecretStoreSpec{CredentialsRefreshInterval: interval}}
+	}
+	tests := []struct {
+		name    string
+		store   esv1beta1.GenericStore
+		created time.Time
+		want    bool
+	}{
+		{
+			name:    "no interval never expires",
+			store:   store(nil),
+			created: time.Now().Add(-24 * time.Hour),
+		},
+		{
+			name:    "zero interval never expires",
+			store:   store(&metav1.Duration{}),
+			created: time.Now().Add(-24 * time.Hour),
+		},
+		{
+			name:    "within interval",
+			store:   store(&metav1.Duration{Duration: time.Hour}),
+			created: time.Now().Add(-time.Minute),
+		},
+		{
+			name:    "interval passed",
+			store:   store(&metav1.Duration{Duration: time.Hour}),
+			created: time.Now().Add(-2 * time.Hour),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CredentialsExpired(tt.store, tt.created); got != tt.want {
+				t.Errorf("CredentialsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package versionexpr resolves version expressions of ExternalSecret data entries
+// against the versions listed by a provider.
+//
+// Two expressions are supported:
+//   - latest-N selects the Nth version before the latest one.
+//   - semver:<constraint> selects the highest version whose name or label
+//     is a semantic version matching the constraint, e.g. semver:~1.2.
+package versionexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	latestPrefix = "latest-"
+	semverPrefix = "semver:"
+)
+
+const (
+	errInvalidOffset     = "invalid version expression %q: offset must be a positive integer"
+	errInvalidConstraint = "invalid version expression %q: %w"
+	errNotEnoughVersions = "version expression %q can not be resolved: only %d versions exist"
+	errNoMatch           = "version expression %q can not be resolved: no version matches"
+)
+
+// IsExpression returns true if version is a version expression
+// and not a version understood by the provider.
+func IsExpression(version string) bool {
+	if strings.HasPrefix(version, semverPrefix) {
+		return true
+	}
+	if !strings.HasPrefix(version, latestPrefix) {
+		return false
+	}
+	_, err := strconv.Atoi(strings.TrimPrefix(version, latestPrefix))
+	return err == nil
+}
+
+// Resolve returns the provider version selected by expr.
+// versions must be ordered newest first.
+func Resolve(expr string, versions []esv1beta1.SecretVersion) (string, error) {
+	if strings.HasPrefix(expr, semverPrefix) {
+		return resolveSemver(expr, versions)
+	}
+	n, err := offset(expr)
+	if err != nil {
+		return "", err
+	}
+	if n >= len(versions) {
+		return "", fmt.Errorf(errNotEnoughVersions, expr, len(versions))
+	}
+	return versions[n].Version, nil
+}
+
+func offset(expr string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(expr, latestPrefix))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf(errInvalidOffset, expr)
+	}
+	return n, nil
+}
+
+func constraint(expr string) (*semver.Constraints, error) {
+	c, err := semver.NewConstraint(strings.TrimPrefix(expr, semverPrefix))
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidConstraint, expr, err)
+	}
+	return c, nil
+}
+
+// resolveSemver returns the version with the highest matching semantic version.
+// If several versions carry the same semantic version the newest one wins.
+func resolveSemver(expr string, versions []esv1beta1.SecretVersion) (string, error) {
+	c, err := constraint(expr)
+	if err != nil {
+		return "", err
+	}
+	var (
+		best    *semver.Version
+		version string
+	)
+	for _, v := range versions {
+		for _, name := range append([]string{v.Version}, v.Labels...) {
+			sv, err := semver.NewVersion(name)
+			if err != nil || !c.Check(sv) {
+				continue
+			}
+			if best == nil || sv.GreaterThan(best) {
+				best = sv
+				version = v.Version
+			}
+		}
+	}
+	if best == nil {
+		return "", fmt.Errorf(errNoMatch, expr)
+	}
+	return version, nil
+}
@@ -0,0 +1,83 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package versionexpr
+
+import (
+	"strings"
+	"testing"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestIsExpression(t *testing.T) {
+	tests := map[string]bool{
+		"":              false,
+		"5":             false,
+		"latest":        false,
+		"latest-1":      true,
+		"latest-x":      false,
+		"semver:~1.2":   true,
+		"uuid/1234":     false,
+		"AWSPREVIOUS":   false,
+		"latest-1-beta": false,
+	}
+	for version, want := range tests {
+		if got := IsExpression(version); got != want {
+			t.Errorf("IsExpression(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	versions := []esv1beta1.SecretVersion{
+		{Version: "4", Labels: []string{"v2.0.0"}},
+		{Version: "3", Labels: []string{"v1.3.0"}},
+		{Version: "2", Labels: []string{"v1.2.1", "AWSPREVIOUS"}},
+		{Version: "1.2.0"},
+	}
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{expr: "latest-1", want: "3"},
+		{expr: "latest-3", want: "1.2.0"},
+		{expr: "latest-4", wantErr: "only 4 versions exist"},
+		{expr: "latest-0", wantErr: "positive integer"},
+		{expr: "semver:~1.2", want: "2"},
+		{expr: "semver:^1", want: "3"},
+		{expr: "semver:>=1.0.0", want: "4"},
+		{expr: "semver:1.2.0", want: "1.2.0"},
+		{expr: "semver:>4", wantErr: "no version matches"},
+		{expr: "semver:abc", wantErr: "invalid version expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Resolve(tt.expr, versions)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}